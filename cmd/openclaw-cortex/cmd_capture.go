@@ -1,27 +1,31 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/async"
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/extract"
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
 func captureCmd() *cobra.Command {
 	var (
-		userMsg      string
-		assistantMsg string
-		sessionID    string
-		scope        string
+		userMsg        string
+		assistantMsg   string
+		sessionID      string
+		project        string
+		scope          string
+		dedupThreshold float64
 	)
 
 	cmd := &cobra.Command{
@@ -42,6 +46,10 @@ func captureCmd() *cobra.Command {
 				return fmt.Errorf("capture: invalid --scope %q: must be one of %s",
 					scope, validScopesString())
 			}
+			threshold, err := dedupThresholdFromFlag(cmd, "capture", dedupThreshold)
+			if err != nil {
+				return err
+			}
 
 			emb := newEmbedder(logger)
 			st, err := newMemgraphStore(ctx, logger)
@@ -62,8 +70,15 @@ func captureCmd() *cobra.Command {
 				return nil
 			}
 
+			promptTmpl, tmplErr := captureTemplateFromConfig()
+			if tmplErr != nil {
+				return cmdErr("capture: loading capture prompt template", tmplErr)
+			}
 			llmClient := llm.NewClient(cfg.Claude)
-			cap := capture.NewCapturer(llmClient, cfg.Claude.Model, logger)
+			cap := capture.NewCapturer(llmClient, cfg.Claude.Model, logger).
+				WithStructuredPreferences(cfg.CaptureQuality.StructuredPreferences).
+				WithPromptTemplate(promptTmpl).
+				WithMinWords(cfg.CaptureQuality.MinWords)
 			cls := classifier.NewClassifier(logger)
 
 			memories, err := cap.Extract(ctx, userMsg, assistantMsg)
@@ -73,7 +88,17 @@ func captureCmd() *cobra.Command {
 
 			logger.Info("extracted memories", "count", len(memories))
 
-			stored := 0
+			// Memory IDs derive from the turn, so re-running a capture that
+			// died partway skips what the first run already stored. On such a
+			// retry, reworded re-extractions are matched against the turn's
+			// stored memories too.
+			turnID := capture.TurnID(sessionID, userMsg, assistantMsg)
+			retry, retryErr := capture.TurnCaptured(ctx, st, turnID)
+			if retryErr != nil {
+				logger.Warn("retry check failed, proceeding", "error", retryErr)
+			}
+			typeTTL := typeTTLFromConfig(logger)
+			stored, resumed := 0, 0
 			storedMems := make([]extract.StoredMemory, 0, len(memories))
 			for _, cm := range memories {
 				// Classify if not already typed
@@ -81,14 +106,35 @@ func captureCmd() *cobra.Command {
 					cm.Type = cls.Classify(cm.Content)
 				}
 
-				vec, err := emb.Embed(ctx, cm.Content)
+				memID := capture.MemoryID(turnID, cm.Content)
+				if _, getErr := st.Get(ctx, memID); getErr == nil {
+					logger.Info("skipping memory already captured for this turn", "id", memID)
+					resumed++
+					continue
+				} else if !errors.Is(getErr, store.ErrNotFound) {
+					logger.Warn("retry check failed, proceeding", "id", memID, "error", getErr)
+				}
+
+				vec, err := emb.Embed(ctx, embedder.DocumentText(cm.Type, cm.Content))
 				if err != nil {
 					logger.Error("embedding captured memory", "error", err)
 					continue
 				}
 
+				if retry {
+					prior, found, turnErr := capture.CapturedInTurn(ctx, st, turnID, vec)
+					if turnErr != nil {
+						logger.Warn("retry check failed, proceeding", "error", turnErr)
+					} else if found {
+						logger.Info("skipping memory already captured for this turn", "similar_to", prior.ID)
+						resumed++
+						continue
+					}
+				}
+
 				// Dedup check
-				dupes, err := st.FindDuplicates(ctx, vec, cfg.Memory.DedupThreshold)
+				dupes, err := store.FindDuplicatesInScope(ctx, st, vec, threshold,
+					cm.Type, dedupScopeFromConfig(cfg.Memory.DedupScope))
 				if err == nil && len(dupes) > 0 {
 					logger.Info("skipping duplicate", "content", truncate(cm.Content, 60))
 					continue
@@ -96,7 +142,7 @@ func captureCmd() *cobra.Command {
 
 				now := time.Now().UTC()
 				mem := models.Memory{
-					ID:           uuid.New().String(),
+					ID:           memID,
 					Type:         cm.Type,
 					Scope:        ms,
 					Visibility:   models.VisibilityShared,
@@ -104,13 +150,20 @@ func captureCmd() *cobra.Command {
 					Confidence:   cm.Confidence,
 					Source:       "inferred",
 					Tags:         cm.Tags,
+					Project:      project,
 					CreatedAt:    now,
 					UpdatedAt:    now,
 					LastAccessed: now,
 					Metadata: map[string]any{
 						"session_id": sessionID,
+						"turn_id":    turnID,
 					},
 				}
+				if cm.Preference != nil && cm.Type == models.MemoryTypePreference {
+					mem.SetPreference(*cm.Preference)
+				}
+				typeTTL.Apply(&mem)
+				normalizeMemoryTags(&mem)
 
 				if err := st.Upsert(ctx, mem, vec); err != nil {
 					logger.Error("storing captured memory", "error", err)
@@ -130,6 +183,7 @@ func captureCmd() *cobra.Command {
 					Store:       st,
 					GraphClient: gc,
 					Logger:      logger,
+					Embedder:    emb,
 				}, storedMems)
 				logger.Info("post-store extraction", "entities", res.EntitiesExtracted, "facts", res.FactsExtracted)
 			} else {
@@ -138,6 +192,7 @@ func captureCmd() *cobra.Command {
 					item := async.WorkItem{
 						MemoryID:   storedMems[i].ID,
 						Content:    storedMems[i].Content,
+						Project:    project,
 						SessionID:  sessionID,
 						EnqueuedAt: time.Now().UTC(),
 					}
@@ -147,6 +202,10 @@ func captureCmd() *cobra.Command {
 				}
 			}
 
+			if resumed > 0 {
+				fmt.Printf("Captured %d memories from conversation (%d already stored by an earlier run)\n", stored, resumed)
+				return nil
+			}
 			fmt.Printf("Captured %d memories from conversation\n", stored)
 			return nil
 		},
@@ -155,7 +214,9 @@ func captureCmd() *cobra.Command {
 	cmd.Flags().StringVar(&userMsg, "user", "", "user message")
 	cmd.Flags().StringVar(&assistantMsg, "assistant", "", "assistant response")
 	cmd.Flags().StringVar(&sessionID, "session-id", "", "session identifier")
+	cmd.Flags().StringVar(&project, "project", "", "project the captured memories belong to (empty = global)")
 	cmd.Flags().StringVar(&scope, "scope", "permanent", "memory scope (permanent|project|session|ttl)")
+	cmd.Flags().Float64Var(&dedupThreshold, "dedup-threshold", 0, "override cosine similarity dedup threshold for this call (range (0.0, 1.0]; omit to use config default)")
 	_ = cmd.MarkFlagRequired("user")
 	_ = cmd.MarkFlagRequired("assistant")
 	return cmd
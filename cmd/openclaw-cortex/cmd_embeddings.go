@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
+)
+
+// Reasons a stored vector is flagged as broken by embeddings stats.
+const (
+	vectorMissing      = "missing"
+	vectorZero         = "zero"
+	vectorNonFinite    = "non_finite"
+	vectorWrongDim     = "wrong_dimension"
+	maxFlaggedExamples = 20
+)
+
+// flaggedVector is a memory whose stored vector looks broken.
+type flaggedVector struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// vectorStats summarizes the stored vectors of a set of memories.
+type vectorStats struct {
+	Dimension  int             `json:"dimension"` // expected dimension (memory.vector_dimension)
+	Scanned    int             `json:"scanned"`
+	Healthy    int             `json:"healthy"`
+	Broken     map[string]int  `json:"broken"` // reason → count
+	Dimensions map[int]int     `json:"dimensions"`
+	NormMin    float64         `json:"norm_min"`
+	NormMean   float64         `json:"norm_mean"`
+	NormMedian float64         `json:"norm_median"`
+	NormP95    float64         `json:"norm_p95"`
+	NormMax    float64         `json:"norm_max"`
+	Flagged    []flaggedVector `json:"flagged,omitempty"` // first maxFlaggedExamples broken memories
+
+	norms []float64
+}
+
+func newVectorStats(dim int) *vectorStats {
+	return &vectorStats{Dimension: dim, Broken: make(map[string]int), Dimensions: make(map[int]int)}
+}
+
+// add records the vector of memory id. A nil or empty vec counts as missing.
+func (s *vectorStats) add(id string, vec []float32) {
+	s.Scanned++
+	if len(vec) > 0 {
+		s.Dimensions[len(vec)]++
+	}
+	reason := vectorReason(vec, s.Dimension)
+	if reason != "" {
+		s.Broken[reason]++
+		if len(s.Flagged) < maxFlaggedExamples {
+			s.Flagged = append(s.Flagged, flaggedVector{ID: id, Reason: reason})
+		}
+		return
+	}
+	s.Healthy++
+	s.norms = append(s.norms, vecmath.Norm(vec))
+}
+
+// vectorReason returns why vec is unusable for cosine similarity, or "" when
+// it is fine.
+func vectorReason(vec []float32, dim int) string {
+	if len(vec) == 0 {
+		return vectorMissing
+	}
+	for _, x := range vec {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return vectorNonFinite
+		}
+	}
+	if dim > 0 && len(vec) != dim {
+		return vectorWrongDim
+	}
+	if vecmath.Norm(vec) == 0 {
+		return vectorZero
+	}
+	return ""
+}
+
+// finish computes the norm distribution of the healthy vectors.
+func (s *vectorStats) finish() {
+	if len(s.norms) == 0 {
+		return
+	}
+	sort.Float64s(s.norms)
+	var sum float64
+	for _, n := range s.norms {
+		sum += n
+	}
+	s.NormMin = s.norms[0]
+	s.NormMax = s.norms[len(s.norms)-1]
+	s.NormMean = sum / float64(len(s.norms))
+	s.NormMedian = percentile(s.norms, 0.5)
+	s.NormP95 = percentile(s.norms, 0.95)
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+func embeddingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "embeddings",
+		Short: "Inspect stored embedding vectors",
+	}
+	cmd.AddCommand(embeddingsStatsCmd())
+	return cmd
+}
+
+func embeddingsStatsCmd() *cobra.Command {
+	var (
+		sample     int
+		batchSz    int
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report vector norms and find missing or broken embeddings",
+		Long: `Scan stored embedding vectors and report their dimensions and norm
+distribution, plus memories whose vector is missing, all zeros, contains
+NaN/Inf values or has the wrong dimension. Such memories never match in
+recall or search; fix them with "openclaw-cortex repair-embeddings".
+
+Use --sample to scan only the first N memories of a large collection.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if sample < 0 {
+				return fmt.Errorf("embeddings stats: --sample must be >= 0, got %d", sample)
+			}
+			if batchSz <= 0 {
+				return fmt.Errorf("embeddings stats: --batch must be a positive integer, got %d", batchSz)
+			}
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("embeddings stats: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			stats := newVectorStats(int(cfg.Memory.VectorDimension))
+			filters := &store.SearchFilters{IncludeInvalidated: true}
+			cursor := ""
+		scan:
+			for {
+				memories, next, listErr := st.List(ctx, filters, uint64(batchSz), cursor) //nolint:gosec // batchSz validated above
+				if listErr != nil {
+					return cmdErr("embeddings stats: listing memories", listErr)
+				}
+				ids := make([]string, len(memories))
+				for i := range memories {
+					ids[i] = memories[i].ID
+				}
+				vecs, vecErr := st.GetVectors(ctx, ids)
+				if vecErr != nil {
+					return cmdErr("embeddings stats: fetching vectors", vecErr)
+				}
+				for _, id := range ids {
+					if sample > 0 && stats.Scanned >= sample {
+						break scan
+					}
+					stats.add(id, vecs[id])
+				}
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			stats.finish()
+
+			if jsonOutput {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if encErr := enc.Encode(stats); encErr != nil {
+					return cmdErr("embeddings stats: encoding JSON", encErr)
+				}
+				return nil
+			}
+
+			w := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(w, "Scanned:   %d memories (expected dimension %d)\n", stats.Scanned, stats.Dimension)
+			_, _ = fmt.Fprintf(w, "Healthy:   %d\n", stats.Healthy)
+			for _, reason := range []string{vectorMissing, vectorZero, vectorNonFinite, vectorWrongDim} {
+				if n := stats.Broken[reason]; n > 0 {
+					_, _ = fmt.Fprintf(w, "  %-16s %d\n", reason+":", n)
+				}
+			}
+			if len(stats.Dimensions) > 1 {
+				_, _ = fmt.Fprintf(w, "Dimensions: %v\n", stats.Dimensions)
+			}
+			if stats.Healthy > 0 {
+				_, _ = fmt.Fprintf(w, "Norm:      min %.4f  mean %.4f  median %.4f  p95 %.4f  max %.4f\n",
+					stats.NormMin, stats.NormMean, stats.NormMedian, stats.NormP95, stats.NormMax)
+			}
+			if len(stats.Flagged) > 0 {
+				_, _ = fmt.Fprintln(w, "\nBroken embeddings (fix with `openclaw-cortex repair-embeddings`):")
+				for _, f := range stats.Flagged {
+					_, _ = fmt.Fprintf(w, "  %s  %s\n", f.ID, f.Reason)
+				}
+				if broken := stats.Scanned - stats.Healthy; broken > len(stats.Flagged) {
+					_, _ = fmt.Fprintf(w, "  ... and %d more\n", broken-len(stats.Flagged))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&sample, "sample", 0, "scan at most this many memories (0 = all)")
+	cmd.Flags().IntVar(&batchSz, "batch", 500, "number of memories to fetch per page")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
@@ -11,28 +11,70 @@ import (
 
 func exportCmd() *cobra.Command {
 	var (
-		format string
-		output string
+		format   string
+		output   string
+		memType  string
+		memScope string
+		project  string
+		tagsFlag string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "export",
-		Short: "Export all memories to JSON or CSV",
+		Short: "Export memories to JSON, JSONL, CSV or a snapshot",
+		Long: `Export memories to JSON, JSONL or CSV. --type, --scope, --project and
+--tags limit the export to matching memories.
+
+--format jsonl streams one memory per line without holding the collection in
+memory, and round-trips with "import --format jsonl" (memories are
+re-embedded on import).
+
+--format snapshot writes JSONL with every memory field and its stored embedding
+vector. Restoring it with "import --format snapshot" skips re-embedding when
+the target uses the same embedding model, which makes it the fast path for
+backups and moving memories between cortex instances.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := newLogger()
 			ctx := cmd.Context()
 
+			filters, err := buildSearchFilters("export", memType, memScope, project, "", tagsFlag)
+			if err != nil {
+				return err
+			}
+
 			st, err := newMemgraphStore(ctx, logger)
 			if err != nil {
 				return cmdErr("export: connecting to store", err)
 			}
 			defer func() { _ = st.Close() }()
 
+			if format == "snapshot" || format == "jsonl" {
+				w, createErr := createExportOutput(output)
+				if createErr != nil {
+					return cmdErr("export: creating output file", createErr)
+				}
+				if w != os.Stdout {
+					defer func() { _ = w.Close() }()
+				}
+				write := writeJSONL
+				if format == "snapshot" {
+					write = writeSnapshot
+				}
+				n, writeErr := write(ctx, st, filters, w)
+				if writeErr != nil {
+					return cmdErr("export", writeErr)
+				}
+				if w != os.Stdout {
+					fmt.Fprintf(os.Stderr, "Exported %d memories to %s\n", n, output)
+				}
+				return nil
+			}
+
 			// Paginate through all memories.
 			var all []map[string]any
 			cursor := ""
 			for {
-				memories, next, listErr := st.List(ctx, nil, 500, cursor)
+				memories, next, listErr := st.List(ctx, filters, 500, cursor)
 				if listErr != nil {
 					return cmdErr("export: listing memories", listErr)
 				}
@@ -59,14 +101,11 @@ func exportCmd() *cobra.Command {
 				cursor = next
 			}
 
-			var w *os.File
-			if output == "" || output == "-" {
-				w = os.Stdout
-			} else {
-				w, err = os.Create(output)
-				if err != nil {
-					return cmdErr("export: creating output file", err)
-				}
+			w, err := createExportOutput(output)
+			if err != nil {
+				return cmdErr("export: creating output file", err)
+			}
+			if w != os.Stdout {
 				defer func() { _ = w.Close() }()
 			}
 
@@ -105,7 +144,7 @@ func exportCmd() *cobra.Command {
 					return cmdErr("export: flushing CSV", flushErr)
 				}
 			default:
-				return fmt.Errorf("export: unsupported format %q (use json or csv)", format)
+				return fmt.Errorf("export: unsupported format %q (use json, jsonl, csv or snapshot)", format)
 			}
 
 			if output != "" && output != "-" {
@@ -115,7 +154,19 @@ func exportCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&format, "format", "json", "output format: json or csv")
+	cmd.Flags().StringVar(&format, "format", "json", "output format: json, jsonl, csv or snapshot (JSONL with vectors)")
 	cmd.Flags().StringVarP(&output, "output", "o", "-", "output file path (- for stdout)")
+	cmd.Flags().StringVar(&memType, "type", "", "only export memories of this type")
+	cmd.Flags().StringVar(&memScope, "scope", "", "only export memories in this scope")
+	cmd.Flags().StringVar(&project, "project", "", "only export memories of this project")
+	cmd.Flags().StringVar(&tagsFlag, "tags", "", "filter by tags (comma-separated)")
 	return cmd
 }
+
+// createExportOutput opens output for writing; "" and "-" mean stdout.
+func createExportOutput(output string) (*os.File, error) {
+	if output == "" || output == "-" {
+		return os.Stdout, nil
+	}
+	return os.Create(output)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
+	"github.com/ajitpratap0/openclaw-cortex/internal/extract"
+	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
+	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+// forEachMemory calls fn for the memories named by ids or, when ids is empty,
+// for every memory matching filters, paging batchSize at a time. limit > 0
+// stops after that many memories.
+func forEachMemory(ctx context.Context, st store.Store, ids []string, filters *store.SearchFilters, batchSize uint64, limit int, fn func(*models.Memory) error) error {
+	if len(ids) > 0 {
+		for _, id := range ids {
+			mem, err := st.Get(ctx, id)
+			if err != nil {
+				return fmt.Errorf("fetching memory %s: %w", id, err)
+			}
+			if err := fn(mem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	seen := 0
+	var cursor string
+	for {
+		memories, next, err := st.List(ctx, filters, batchSize, cursor)
+		if err != nil {
+			return fmt.Errorf("listing memories: %w", err)
+		}
+		for i := range memories {
+			if limit > 0 && seen >= limit {
+				return nil
+			}
+			seen++
+			if err := fn(&memories[i]); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func extractEntitiesCmd() *cobra.Command {
+	var (
+		memType  string
+		scope    string
+		project  string
+		tagsFlag string
+		limit    int
+		batchSz  int
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "extract-entities [memory-id...]",
+		Short: "Extract entities and facts from existing memories",
+		Long: `Run entity and fact extraction over memories that are already stored and
+write the results to the graph: entities are upserted and linked to the memory
+they came from, as capture and store --extract-entities do for new memories.
+Use it to backfill the graph for a collection seeded before entity extraction
+existed.
+
+Pass memory IDs to process just those memories, or select memories with
+--type, --scope, --project and --tags (all memories when none is set).
+Extraction runs synchronously and needs an LLM (ANTHROPIC_API_KEY or the
+gateway); use "graph rebuild" to hand every memory to the async worker instead.
+
+Use --dry-run to print the entities found in each memory without writing them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchSz <= 0 {
+				return fmt.Errorf("extract-entities: --batch must be a positive integer, got %d", batchSz)
+			}
+			if limit < 0 {
+				return fmt.Errorf("extract-entities: --limit must be >= 0, got %d", limit)
+			}
+			if len(args) > 0 && (memType != "" || scope != "" || project != "" || tagsFlag != "") {
+				return fmt.Errorf("extract-entities: memory IDs and filter flags are mutually exclusive")
+			}
+			filters, err := buildSearchFilters("extract-entities", memType, scope, project, "", tagsFlag)
+			if err != nil {
+				return err
+			}
+
+			llmClient := llm.NewClient(cfg.Claude)
+			if llmClient == nil {
+				return fmt.Errorf("extract-entities: no LLM configured (set ANTHROPIC_API_KEY or claude.gateway_url)")
+			}
+
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("extract-entities: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			var (
+				processed int
+				entities  int
+				facts     int
+				errored   int
+			)
+
+			var extractor *capture.EntityExtractor
+			var deps extract.Deps
+			if dryRun {
+				extractor = capture.NewEntityExtractor(llmClient, cfg.Claude.Model, logger)
+			} else {
+				emb := newEmbedder(logger)
+				gc := memgraph.NewGraphAdapter(st)
+				gc.SetEmbedder(emb)
+				deps = extract.Deps{
+					LLMClient:   llmClient,
+					Model:       cfg.Claude.Model,
+					Store:       st,
+					GraphClient: gc,
+					Logger:      logger,
+					Embedder:    emb,
+				}
+			}
+
+			iterErr := forEachMemory(ctx, st, args, filters, uint64(batchSz), limit, func(mem *models.Memory) error { //nolint:gosec // batchSz validated above
+				processed++
+				if dryRun {
+					found, extractErr := extractor.Extract(ctx, mem.Content)
+					if extractErr != nil {
+						logger.Warn("extract-entities: extraction failed", "id", mem.ID, "error", extractErr)
+						errored++
+						return nil
+					}
+					names := make([]string, len(found))
+					for i := range found {
+						names[i] = fmt.Sprintf("%s (%s)", found[i].Name, found[i].Type)
+					}
+					entities += len(found)
+					fmt.Printf("[dry-run] %s: %s\n", mem.ID, strings.Join(names, ", "))
+					return nil
+				}
+
+				res := extract.Run(ctx, deps, []extract.StoredMemory{{ID: mem.ID, Content: mem.Content}})
+				entities += res.EntitiesExtracted
+				facts += res.FactsExtracted
+				if res.Errors > 0 {
+					errored++
+				}
+				fmt.Printf("%s: %d entities, %d facts\n", mem.ID, res.EntitiesExtracted, res.FactsExtracted)
+				return ctx.Err()
+			})
+			if iterErr != nil {
+				return cmdErr("extract-entities", iterErr)
+			}
+
+			if dryRun {
+				fmt.Printf("Found %d entities in %d memories (dry run — nothing written, %d errored)\n", entities, processed, errored)
+			} else {
+				fmt.Printf("Extracted %d entities and %d facts from %d memories (%d errored)\n", entities, facts, processed, errored)
+			}
+			if errored > 0 {
+				return fmt.Errorf("extract-entities: extraction failed for %d memor%s (see warnings above)",
+					errored, map[bool]string{true: "y", false: "ies"}[errored == 1])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&memType, "type", "", "only memories of this type")
+	cmd.Flags().StringVar(&scope, "scope", "", "only memories in this scope")
+	cmd.Flags().StringVar(&project, "project", "", "only memories in this project")
+	cmd.Flags().StringVar(&tagsFlag, "tags", "", "only memories with all of these comma-separated tags")
+	cmd.Flags().IntVar(&limit, "limit", 0, "stop after this many memories (0 = all)")
+	cmd.Flags().IntVar(&batchSz, "batch", 50, "number of memories to fetch per page")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the entities found without writing them")
+	return cmd
+}
@@ -76,7 +76,13 @@ func healthCmd() *cobra.Command {
 				result.Errors["memgraph"] = err.Error()
 			} else {
 				defer func() { _ = st.Close() }()
-				if err := st.EnsureCollection(ctx); err != nil {
+				if err := st.Ping(ctx); err != nil {
+					result.Memgraph = false
+					if result.Errors == nil {
+						result.Errors = make(map[string]string)
+					}
+					result.Errors["memgraph"] = err.Error()
+				} else if err := st.EnsureCollection(ctx); err != nil {
 					result.Memgraph = false
 					if result.Errors == nil {
 						result.Errors = make(map[string]string)
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/hooks"
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
@@ -23,7 +25,36 @@ import (
 	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
 )
 
-const hookTimeout = 30 * time.Second
+// defaultHookTimeout bounds a hook invocation when hooks.timeout_seconds is 0.
+const defaultHookTimeout = 30 * time.Second
+
+// hookTimeout returns the configured per-invocation hook timeout.
+func hookTimeout() time.Duration {
+	if cfg.Hooks.TimeoutSeconds > 0 {
+		return time.Duration(cfg.Hooks.TimeoutSeconds) * time.Second
+	}
+	return defaultHookTimeout
+}
+
+// hookTimedOut reports whether a hook failed because it ran out of time
+// rather than because a dependency returned an error.
+func hookTimedOut(ctx context.Context, err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// hookPreDiagnostic is the stderr line the pre-turn hook prints when it
+// injects no memories, so a slow recall is not mistaken for an empty one.
+// err is the recall error, nil when recall succeeded with no matches.
+func hookPreDiagnostic(ctx context.Context, err error, timeout time.Duration) string {
+	switch {
+	case err == nil:
+		return "openclaw-cortex hook: no relevant memories for this prompt"
+	case hookTimedOut(ctx, err):
+		return fmt.Sprintf("openclaw-cortex hook: memory recall timed out after %s (raise hooks.timeout_seconds if embedding is slow), continuing without memory context", timeout)
+	default:
+		return fmt.Sprintf("openclaw-cortex hook: memory recall failed (%v), continuing without memory context", err)
+	}
+}
 
 // hookPreInput is the JSON input shape for `cortex hook pre`.
 // It matches the Claude Code UserPromptSubmit hook stdin payload.
@@ -86,7 +117,8 @@ func hookPreCmd() *cobra.Command {
 		SilenceUsage:  true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			logger := newLogger()
-			ctx, cancel := context.WithTimeout(cmd.Context(), hookTimeout)
+			timeout := hookTimeout()
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
 			defer cancel()
 
 			// Decode input from stdin.
@@ -101,7 +133,11 @@ func hookPreCmd() *cobra.Command {
 			st, storeErr := newMemgraphStore(ctx, logger)
 			if storeErr != nil {
 				logger.Error("hook pre: connecting to store", "error", storeErr)
-				_, _ = fmt.Fprintf(os.Stderr, "openclaw-cortex hook: services unavailable (Memgraph: %v), continuing without memory context\n", storeErr)
+				if hookTimedOut(ctx, storeErr) {
+					_, _ = fmt.Fprintln(os.Stderr, hookPreDiagnostic(ctx, storeErr, timeout))
+				} else {
+					_, _ = fmt.Fprintf(os.Stderr, "openclaw-cortex hook: services unavailable (Memgraph: %v), continuing without memory context\n", storeErr)
+				}
 				writePreOutput(hookPreOutput{})
 				return nil
 			}
@@ -114,13 +150,21 @@ func hookPreCmd() *cobra.Command {
 				project = filepath.Base(input.Cwd)
 			}
 
-			recaller := recall.NewRecaller(recallWeightsFromConfig(cfg.Recall.Weights), logger)
+			recaller := newRecaller(logger)
 
 			// Wire graph client — MemgraphStore implements graph.Client.
 			gc := memgraph.NewGraphAdapter(st)
 			recaller.SetGraphClient(gc, st, cfg.Recall.GraphBudgetMs)
 
-			preTurnHook := hooks.NewPreTurnHook(emb, st, recaller, logger)
+			preTurnHook := hooks.NewPreTurnHook(emb, st, recaller, logger).
+				WithMinMemories(cfg.Recall.MinMemories).
+				WithTrackAccess(cfg.Recall.TrackAccess).
+				WithBroadenWhenEmpty(cfg.Recall.BroadenWhenEmpty).
+				WithPerMemoryMaxTokens(cfg.Recall.PerMemoryMaxTokens).
+				WithAttachmentRefs(cfg.Recall.IncludeAttachments).
+				WithOnConflict(cfg.Recall.OnConflict).
+				WithConflictCheck(recallConflictCheckerFromConfig(logger)).
+				WithRelevanceFilter(newRelevanceFilter(logger))
 
 			if cfg.Claude.APIKey != "" {
 				llmClient := llm.NewClient(cfg.Claude)
@@ -143,7 +187,7 @@ func hookPreCmd() *cobra.Command {
 				for i := range cached {
 					contents = append(contents, cached[i].Memory.Content)
 				}
-				formatted, count := tokenizer.FormatMemoriesWithBudget(contents, budget)
+				formatted, count := tokenizer.FormatMemoriesWithMinimum(contents, budget, cfg.Recall.MinMemories)
 				writePreOutput(hookPreOutput{
 					Context:     formatted,
 					MemoryCount: count,
@@ -161,10 +205,13 @@ func hookPreCmd() *cobra.Command {
 			if execErr != nil {
 				sentry.CaptureException(execErr)
 				logger.Error("hook pre: executing hook", "error", execErr)
-				_, _ = fmt.Fprintf(os.Stderr, "openclaw-cortex hook: memory recall failed (%v), continuing without memory context\n", execErr)
+				_, _ = fmt.Fprintln(os.Stderr, hookPreDiagnostic(ctx, execErr, timeout))
 				writePreOutput(hookPreOutput{})
 				return nil
 			}
+			if out.MemoryCount == 0 {
+				_, _ = fmt.Fprintln(os.Stderr, hookPreDiagnostic(ctx, nil, timeout))
+			}
 
 			writePreOutput(hookPreOutput{
 				Context:     out.Context,
@@ -187,7 +234,7 @@ func hookPostCmd() *cobra.Command {
 		SilenceUsage:  true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			logger := newLogger()
-			ctx, cancel := context.WithTimeout(cmd.Context(), hookTimeout)
+			ctx, cancel := context.WithTimeout(cmd.Context(), hookTimeout())
 			defer cancel()
 
 			// Decode input from stdin.
@@ -239,16 +286,31 @@ func hookPostCmd() *cobra.Command {
 				}
 			}
 
+			promptTmpl, tmplErr := captureTemplateFromConfig()
+			if tmplErr != nil {
+				logger.Error("hook post: loading capture prompt template", "error", tmplErr)
+				writePostOutput(hookPostOutput{Stored: false})
+				return nil
+			}
 			llmClient := llm.NewClient(cfg.Claude)
-			cap := capture.NewCapturer(llmClient, cfg.Claude.Model, logger)
+			cap := capture.NewCapturer(llmClient, cfg.Claude.Model, logger).
+				WithStructuredPreferences(cfg.CaptureQuality.StructuredPreferences).
+				WithPromptTemplate(promptTmpl).
+				WithMinWords(cfg.CaptureQuality.MinWords)
 			cls := classifier.NewClassifier(logger)
 
 			postHook := hooks.NewPostTurnHook(cap, cls, emb, st, logger, cfg.Memory.DedupThresholdHook, cfg.Hooks.PostTurnConcurrency).
-				WithReinforcement(cfg.CaptureQuality.ReinforcementThreshold, cfg.CaptureQuality.ReinforcementConfidenceBoost)
+				WithReinforcement(cfg.CaptureQuality.ReinforcementThreshold, cfg.CaptureQuality.ReinforcementConfidenceBoost).
+				WithDedupScope(dedupScopeFromConfig(cfg.Memory.DedupScope)).
+				WithTypeTTL(typeTTLFromConfig(logger)).
+				WithTagNormalizer(tagNormalizerFromConfig())
 			if cfg.Claude.APIKey != "" {
 				cd := capture.NewConflictDetector(llmClient, cfg.Claude.Model, logger)
 				postHook = postHook.WithConflictDetector(cd)
 			}
+			if cfg.Memory.FastDedup {
+				postHook = postHook.WithFastDedup(cfg.Memory.FastDedupThreshold)
+			}
 			hook := postHook
 
 			priorTurns := lastNTurnsFromTranscript(input.TranscriptPath, cfg.CaptureQuality.ContextWindowTurns)
@@ -279,7 +341,8 @@ func hookPostCmd() *cobra.Command {
 					prewarmCtx, prewarmCancel := context.WithTimeout(context.Background(),
 						time.Duration(cfg.Recall.RerankLatencyBudgetHooksMs*10)*time.Millisecond)
 					defer prewarmCancel()
-					vec, embedErr := emb.Embed(prewarmCtx, userMsg)
+					prewarmRecaller := newRecaller(logger)
+					vec, embedErr := emb.Embed(prewarmCtx, embedder.QueryText(prewarmRecaller.ExpandQuery(userMsg)))
 					if embedErr != nil {
 						return
 					}
@@ -287,7 +350,6 @@ func hookPostCmd() *cobra.Command {
 					if searchErr != nil {
 						return
 					}
-					prewarmRecaller := recall.NewRecaller(recallWeightsFromConfig(cfg.Recall.Weights), logger)
 					ranked := prewarmRecaller.Rank(results, input.Project, userMsg)
 					prewarmLLMClient := llm.NewClient(cfg.Claude)
 					reasoner := recall.NewReasoner(prewarmLLMClient, cfg.Claude.Model, logger)
@@ -295,6 +357,9 @@ func hookPostCmd() *cobra.Command {
 					if rerankErr != nil {
 						return
 					}
+					if filter := newRelevanceFilter(logger); filter != nil {
+						reranked = filter.Filter(prewarmCtx, userMsg, reranked, 0)
+					}
 					hooks.WriteRerankCache(postHomeDir, input.SessionID, reranked)
 				}()
 			}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +12,11 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
 // newJSONLScanner returns a Scanner pre-configured with a 10 MB buffer
@@ -24,8 +29,10 @@ func newJSONLScanner(r io.Reader) *bufio.Scanner {
 
 func importCmd() *cobra.Command {
 	var (
-		filePath string
-		format   string
+		filePath       string
+		format         string
+		dedupThreshold float64
+		strict         bool
 	)
 
 	cmd := &cobra.Command{
@@ -36,7 +43,30 @@ func importCmd() *cobra.Command {
 The JSON format is a JSON array of memory objects matching the models.Memory struct.
 The JSONL format is one memory object per line.
 
-Use - as the file path to read from stdin.`,
+The snapshot format is the output of "export --format snapshot": each memory
+is restored with every field and its stored vector. Vectors produced by the
+configured embedding model are written as-is; the rest are re-embedded.
+
+To migrate from another memory system, pass --format mem0, zep or langchain
+with that system's export (a JSON array, object or JSONL). Records are mapped
+onto memories with the type inferred from content; records that cannot be
+mapped are skipped and malformed fields are repaired, with a warning for each.
+See docs/import-formats.md for the field mappings.
+
+Use - as the file path to read from stdin.
+
+Memories are restored as-is by default. Pass --dedup-threshold to skip
+records that are near-duplicates of a different memory already in the store;
+with memory.fast_dedup enabled, records repeating an earlier record almost
+verbatim are also dropped before they are embedded.
+
+By default records with empty content are skipped and missing timestamps are
+filled in. With --strict every record is validated before anything is
+written: a missing ID, content, type, scope or created_at, an unknown type,
+scope, visibility or conflict status, a confidence outside [0, 1], a
+valid_to before valid_from or a repeated ID fails the whole import with a
+report listing every problem. Records a foreign-format mapper had to skip or
+repair also fail a strict import.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := newLogger()
 			ctx := cmd.Context()
@@ -56,7 +86,9 @@ Use - as the file path to read from stdin.`,
 
 			// Parse memories from the chosen format.
 			var memories []models.Memory
-			switch strings.ToLower(format) {
+			var vectors [][]float32 // snapshot only: stored vectors aligned with memories
+			var adapterSkipped int
+			switch f := strings.ToLower(format); f {
 			case "json":
 				dec := json.NewDecoder(r)
 				if _, tokErr := dec.Token(); tokErr != nil {
@@ -70,23 +102,48 @@ Use - as the file path to read from stdin.`,
 					memories = append(memories, m)
 				}
 			case "jsonl":
-				scanner := newJSONLScanner(r)
-				for scanner.Scan() {
-					line := strings.TrimSpace(scanner.Text())
-					if line == "" {
-						continue
-					}
-					var m models.Memory
-					if unmarshalErr := json.Unmarshal([]byte(line), &m); unmarshalErr != nil {
-						return cmdErr("import: decoding JSONL line", unmarshalErr)
-					}
-					memories = append(memories, m)
+				var jsonlErr error
+				memories, jsonlErr = readJSONL(r)
+				if jsonlErr != nil {
+					return cmdErr("import", jsonlErr)
+				}
+			case "snapshot":
+				var snapErr error
+				memories, vectors, snapErr = readSnapshot(r)
+				if snapErr != nil {
+					return cmdErr("import", snapErr)
+				}
+			case "mem0", "zep", "langchain":
+				mapped, mapper, mapErr := mapForeignExport(r, f, classifier.NewClassifier(logger), logger)
+				if mapErr != nil {
+					return cmdErr("import", mapErr)
+				}
+				memories, adapterSkipped = mapped, mapper.skipped
+				if strict && (mapper.skipped > 0 || mapper.repaired > 0) {
+					return fmt.Errorf("import: --strict: %d %s records could not be mapped and %d fields needed repair (see warnings above)",
+						mapper.skipped, f, mapper.repaired)
 				}
-				if scanErr := scanner.Err(); scanErr != nil {
-					return cmdErr("import: reading JSONL", scanErr)
+				if mapper.repaired > 0 {
+					fmt.Printf("Repaired %d malformed fields while mapping %s records\n", mapper.repaired, f)
 				}
 			default:
-				return fmt.Errorf("import: unsupported format %q (use json or jsonl)", format)
+				return fmt.Errorf("import: unsupported format %q (use json, jsonl, snapshot, mem0, zep or langchain)", format)
+			}
+
+			if strict {
+				if problems := validateImportRecords(memories); len(problems) > 0 {
+					for _, p := range problems {
+						_, _ = fmt.Fprintln(cmd.ErrOrStderr(), p)
+					}
+					return fmt.Errorf("import: --strict: %d problem%s found, nothing imported",
+						len(problems), map[bool]string{true: "", false: "s"}[len(problems) == 1])
+				}
+			}
+
+			dedup := cmd.Flags().Changed("dedup-threshold")
+			threshold, err := dedupThresholdFromFlag(cmd, "import", dedupThreshold)
+			if err != nil {
+				return err
 			}
 
 			// Connect to services.
@@ -101,8 +158,17 @@ Use - as the file path to read from stdin.`,
 				return cmdErr("import: ensuring collection", err)
 			}
 
+			// Textual near-duplicates of a record seen earlier in this import
+			// are dropped before they are embedded.
+			var shingles *store.ShingleIndex
+			if dedup && cfg.Memory.FastDedup {
+				shingles = store.SharedShingleIndex(cfg.Memory.FastDedupThreshold)
+			}
+
 			// Upsert each memory.
-			var imported, skipped int
+			currentModel := embedder.ModelID(cfg.Ollama, cfg.Embedder)
+			dim := int(cfg.Memory.VectorDimension)
+			imported, skipped, reembedded := 0, adapterSkipped, 0
 			now := time.Now().UTC()
 			for i := range memories {
 				m := &memories[i]
@@ -123,10 +189,35 @@ Use - as the file path to read from stdin.`,
 					m.LastAccessed = now
 				}
 
-				vec, embedErr := emb.Embed(ctx, m.Content)
-				if embedErr != nil {
-					fmt.Printf("Import stopped after %d memories (%d skipped): %v\n", imported, skipped, embedErr)
-					return cmdErr("import: embedding memory", embedErr)
+				normalizeMemoryTags(m)
+
+				if shingles != nil && shingles.CheckAndAdd(m.Content) {
+					logger.Info("import: skipping near-verbatim duplicate", "id", m.ID, "content", truncate(m.Content, 60))
+					metrics.Inc(metrics.FastDedupSkipped)
+					skipped++
+					continue
+				}
+
+				var vec []float32
+				if vectors != nil {
+					vec = reusableVector(m, vectors[i], currentModel, dim)
+					if vec == nil {
+						reembedded++
+					}
+				}
+				if vec == nil {
+					var embedErr error
+					vec, embedErr = emb.Embed(ctx, embedder.DocumentText(m.Type, m.Content))
+					if embedErr != nil {
+						fmt.Printf("Import stopped after %d memories (%d skipped): %v\n", imported, skipped, embedErr)
+						return cmdErr("import: embedding memory", embedErr)
+					}
+				}
+
+				if dedup && isImportDuplicate(ctx, st, m, vec, threshold) {
+					logger.Info("import: skipping duplicate", "id", m.ID, "content", truncate(m.Content, 60))
+					skipped++
+					continue
 				}
 
 				if upsertErr := st.Upsert(ctx, *m, vec); upsertErr != nil {
@@ -138,11 +229,89 @@ Use - as the file path to read from stdin.`,
 			}
 
 			fmt.Printf("Imported %d memories (%d skipped)\n", imported, skipped)
+			if vectors != nil && reembedded > 0 {
+				fmt.Printf("Re-embedded %d memories whose snapshot vector was missing or from another embedding model\n", reembedded)
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&filePath, "file", "f", "-", "path to input file (- for stdin)")
-	cmd.Flags().StringVar(&format, "format", "json", "input format: json or jsonl")
+	cmd.Flags().StringVar(&format, "format", "json", "input format: json, jsonl, snapshot (from export --format snapshot), or another system's export: mem0, zep, langchain")
+	cmd.Flags().Float64Var(&dedupThreshold, "dedup-threshold", 0, "skip records whose cosine similarity to a different stored memory is at least this value (range (0.0, 1.0]; omit to import without dedup)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "validate every record first and fail without writing anything if any is invalid")
 	return cmd
 }
+
+// isImportDuplicate reports whether m is a near-duplicate of a different
+// memory already in st. A match on m's own ID is a re-import of the same
+// record, not a duplicate. Lookup errors fail open.
+func isImportDuplicate(ctx context.Context, st store.Store, m *models.Memory, vec []float32, threshold float64) bool {
+	dupes, err := store.FindDuplicatesInScope(ctx, st, vec, threshold, m.Type, dedupScopeFromConfig(cfg.Memory.DedupScope))
+	if err != nil {
+		return false
+	}
+	for i := range dupes {
+		if dupes[i].Memory.ID != m.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateImportRecords checks memories for everything a non-strict import
+// would silently skip or fix, and returns one line per problem found. An
+// empty result means every record is valid.
+func validateImportRecords(memories []models.Memory) []string {
+	var problems []string
+	seen := make(map[string]int, len(memories))
+	for i := range memories {
+		m := &memories[i]
+		label := fmt.Sprintf("record %d", i+1)
+		if m.ID != "" {
+			label += fmt.Sprintf(" (%s)", m.ID)
+		}
+		report := func(format string, args ...any) {
+			problems = append(problems, label+": "+fmt.Sprintf(format, args...))
+		}
+
+		if m.ID == "" {
+			report("missing id")
+		} else if first, dup := seen[m.ID]; dup {
+			report("duplicate id, first seen in record %d", first)
+		} else {
+			seen[m.ID] = i + 1
+		}
+		if strings.TrimSpace(m.Content) == "" {
+			report("missing content")
+		}
+		switch {
+		case m.Type == "":
+			report("missing type")
+		case !m.Type.IsValid():
+			report("invalid type %q", m.Type)
+		}
+		switch {
+		case m.Scope == "":
+			report("missing scope")
+		case !m.Scope.IsValid():
+			report("invalid scope %q", m.Scope)
+		}
+		if m.Visibility != "" && !m.Visibility.IsValid() {
+			report("invalid visibility %q", m.Visibility)
+		}
+		if m.ConflictStatus != "" && !m.ConflictStatus.IsValid() {
+			report("invalid conflict_status %q", m.ConflictStatus)
+		}
+		if m.Confidence < 0 || m.Confidence > 1 {
+			report("confidence %g outside [0, 1]", m.Confidence)
+		}
+		if m.CreatedAt.IsZero() {
+			report("missing created_at")
+		}
+		if m.ValidTo != nil && !m.ValidFrom.IsZero() && m.ValidTo.Before(m.ValidFrom) {
+			report("valid_to %s is before valid_from %s", m.ValidTo.Format(time.RFC3339), m.ValidFrom.Format(time.RFC3339))
+		}
+	}
+	return problems
+}
@@ -33,7 +33,12 @@ func indexCmd() *cobra.Command {
 				return cmdErr("index: ensuring collection", err)
 			}
 
-			idx := indexer.NewIndexer(emb, st, cfg.Memory.ChunkSize, cfg.Memory.ChunkOverlap, logger)
+			idx := indexer.NewIndexer(emb, st, cfg.Memory.ChunkSize, cfg.Memory.ChunkOverlap, logger).
+				WithDedupScope(dedupScopeFromConfig(cfg.Memory.DedupScope)).
+				WithMaxChunkTokens(cfg.Memory.IndexMaxChunkTokens, cfg.Memory.IndexOversize)
+			if cfg.Memory.FastDedup {
+				idx = idx.WithFastDedup(cfg.Memory.FastDedupThreshold)
+			}
 
 			if path == "" {
 				path = cfg.Memory.MemoryDir
@@ -45,6 +50,10 @@ func indexCmd() *cobra.Command {
 			}
 
 			fmt.Printf("Indexed %d chunks from %s\n", count, path)
+			if stats := idx.Stats(); stats != (indexer.IndexStats{}) {
+				fmt.Printf("Oversized chunks: %d split, %d truncated; %d chunk(s) dropped (see log)\n",
+					stats.Split, stats.Truncated, stats.Dropped)
+			}
 
 			// Optionally generate section summary memories via Claude.
 			if summarize {
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
+)
+
+// inspectComponent is one row of the inspect score breakdown.
+type inspectComponent struct {
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// inspectReport is the JSON output of the inspect command.
+type inspectReport struct {
+	MemoryID            string             `json:"memory_id"`
+	Query               string             `json:"query"`
+	Components          []inspectComponent `json:"components"`
+	WeightedSum         float64            `json:"weighted_sum"`
+	WriteRecencyBoost   float64            `json:"write_recency_boost"`
+	QueryTypeBoost      float64            `json:"query_type_boost"`
+	ManualBoost         float64            `json:"manual_boost"`
+	SupersessionPenalty float64            `json:"supersession_penalty"`
+	ConflictPenalty     float64            `json:"conflict_penalty"`
+	FinalScore          float64            `json:"final_score"`
+}
+
+func inspectCmd() *cobra.Command {
+	var (
+		query      string
+		project    string
+		outputJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inspect [memory-id]",
+		Short: "Show how a memory scores against a query",
+		Long: `Embed --query, compare it with the stored vector of one memory, and print
+every recall scoring component with the active recall.weights.
+
+Graph proximity and the supersession penalty depend on the other results of a
+real recall and are reported as they would be for a memory recalled alone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if query == "" {
+				return fmt.Errorf("inspect: --query is required")
+			}
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			emb := newEmbedder(logger)
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("inspect: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			mem, err := st.Get(ctx, args[0])
+			if err != nil {
+				return cmdErr("inspect", err)
+			}
+			memVec, err := st.GetVector(ctx, mem.ID)
+			if err != nil {
+				return cmdErr("inspect: reading vector", err)
+			}
+			if len(memVec) == 0 {
+				return fmt.Errorf("inspect: memory %s has no stored embedding; run reembed first", mem.ID)
+			}
+			queryVec, err := emb.Embed(ctx, embedder.QueryText(query))
+			if err != nil {
+				return cmdErr("inspect: embedding query", err)
+			}
+
+			recaller := newRecaller(logger)
+			sim := vecmath.CosineSimilarity(queryVec, memVec)
+			ranked := recaller.Rank([]models.SearchResult{{Memory: *mem, Score: sim}}, project, query)
+			report := buildInspectReport(mem.ID, query, recaller.Weights(), &ranked[0])
+
+			if outputJSON {
+				out, marshalErr := json.MarshalIndent(report, "", "  ")
+				if marshalErr != nil {
+					return cmdErr("inspect: marshaling JSON", marshalErr)
+				}
+				fmt.Println(string(out))
+				return nil
+			}
+
+			fmt.Printf("Memory: %s [%s/%s]\n", mem.ID, mem.Type, mem.Scope)
+			fmt.Printf("Query:  %s\n\n", query)
+			fmt.Printf("%-16s  %8s  %8s  %12s\n", "COMPONENT", "SCORE", "WEIGHT", "CONTRIBUTION")
+			for _, c := range report.Components {
+				fmt.Printf("%-16s  %8.4f  %8.3f  %12.4f\n", c.Name, c.Score, c.Weight, c.Contribution)
+			}
+			fmt.Printf("%-16s  %8s  %8s  %12.4f\n", "weighted sum", "", "", report.WeightedSum)
+			fmt.Printf("%-16s  %8s  %8s  %12.4f\n", "+ write recency", "", "", report.WriteRecencyBoost)
+			fmt.Printf("%-16s  %8s  %8s  %12.4f\n", "+ query type", "", "", report.QueryTypeBoost)
+			fmt.Printf("%-16s  %8.2f\n", "supersession", report.SupersessionPenalty)
+			fmt.Printf("%-16s  %8.2f\n", "conflict", report.ConflictPenalty)
+			fmt.Printf("%-16s  %8.2f\n", "boost", report.ManualBoost)
+			fmt.Printf("%-16s  %8s  %8s  %12.4f\n", "final", "", "", report.FinalScore)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&query, "query", "", "query to score the memory against (required)")
+	cmd.Flags().StringVar(&project, "project", "", "project context for scope boosting")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+	return cmd
+}
+
+// buildInspectReport pairs each ranking signal of rr with its weight.
+func buildInspectReport(id, query string, w recall.Weights, rr *models.RecallResult) inspectReport {
+	names, weights, scores := recall.WeightNames(), w.Values(), recall.Signals(rr).Values()
+	report := inspectReport{
+		MemoryID:            id,
+		Query:               query,
+		Components:          make([]inspectComponent, len(names)),
+		SupersessionPenalty: rr.SupersessionPenalty,
+		WriteRecencyBoost:   rr.WriteRecencyBoost,
+		QueryTypeBoost:      rr.QueryTypeBoost,
+		ManualBoost:         rr.ManualBoost,
+		ConflictPenalty:     rr.ConflictPenalty,
+		FinalScore:          rr.FinalScore,
+	}
+	for i := range names {
+		c := inspectComponent{Name: names[i], Score: scores[i], Weight: weights[i], Contribution: scores[i] * weights[i]}
+		report.Components[i] = c
+		report.WeightedSum += c.Contribution
+	}
+	return report
+}
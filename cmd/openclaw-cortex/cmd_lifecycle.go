@@ -7,7 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
 func lifecycleCmd() *cobra.Command {
@@ -18,13 +18,16 @@ func lifecycleCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "lifecycle",
-		Short: "Run all lifecycle operations (TTL expiry, session decay, consolidation, fact retirement, conflict resolution)",
+		Short: "Run all lifecycle operations (TTL expiry, session decay, scope eviction, consolidation, fact retirement, conflict resolution, trash purge)",
 		Long: `Run memory lifecycle management. This executes all lifecycle phases in order:
-  1. TTL expiry     — delete memories past their time-to-live
-  2. Session decay  — remove session memories not accessed within 24h
-  3. Consolidation  — merge near-duplicate permanent memories
-  4. Fact retirement — delete memories whose ValidUntil has passed
-  5. Conflict resolution — pick winners in active conflict groups
+  1. TTL expiry     — delete memories past their time-to-live, and memories of
+                     any scope past their expires_at date
+  2. Session decay  — remove session memories not accessed within lifecycle.session_decay_hours (default 24)
+  3. Scope eviction — delete least recently accessed memories of scopes over memory.max_per_scope
+  4. Consolidation  — merge near-duplicate permanent memories
+  5. Fact retirement — delete memories whose ValidUntil has passed
+  6. Conflict resolution — pick winners in active conflict groups
+  7. Trash purge    — permanently delete forgotten memories past memory.trash_retention_hours
 
 Use --dry-run to preview what would change without modifying data.
 Use --json for machine-readable output.`,
@@ -38,7 +41,7 @@ Use --json for machine-readable output.`,
 			}
 			defer func() { _ = st.Close() }()
 
-			lm := lifecycle.NewManager(st, newEmbedder(logger), logger)
+			lm := newLifecycleManager(st, newEmbedder(logger), logger)
 			report, runErr := lm.Run(ctx, dryRun)
 			if runErr != nil {
 				// Report is still usable with partial results even when some phases fail.
@@ -57,10 +60,13 @@ Use --json for machine-readable output.`,
 			w := cmd.OutOrStdout()
 			_, _ = fmt.Fprintf(w, "Lifecycle report:\n")
 			_, _ = fmt.Fprintf(w, "  Expired (TTL):       %d\n", report.Expired)
+			_, _ = fmt.Fprintf(w, "  Expired (date):      %d\n", report.ExpiredAt)
 			_, _ = fmt.Fprintf(w, "  Decayed (session):   %d\n", report.Decayed)
+			_, _ = fmt.Fprintf(w, "  Evicted (scope cap): %d\n", report.Evicted)
 			_, _ = fmt.Fprintf(w, "  Consolidated:        %d\n", report.Consolidated)
 			_, _ = fmt.Fprintf(w, "  Retired (facts):     %d\n", report.Retired)
 			_, _ = fmt.Fprintf(w, "  Conflicts resolved:  %d\n", report.ConflictsResolved)
+			_, _ = fmt.Fprintf(w, "  Purged (trash):      %d\n", report.Purged)
 			if dryRun {
 				_, _ = fmt.Fprintln(w, "  (dry run — no changes applied)")
 			}
@@ -90,7 +96,7 @@ func consolidateCmd() *cobra.Command {
 			}
 			defer func() { _ = st.Close() }()
 
-			lm := lifecycle.NewManager(st, newEmbedder(logger), logger)
+			lm := newLifecycleManager(st, newEmbedder(logger), logger)
 			report, err := lm.Run(ctx, dryRun)
 			if err != nil {
 				return cmdErr("consolidate: running lifecycle", err)
@@ -99,7 +105,9 @@ func consolidateCmd() *cobra.Command {
 			fmt.Printf("Lifecycle report:\n")
 			fmt.Printf("  Expired (TTL):  %d\n", report.Expired)
 			fmt.Printf("  Decayed:        %d\n", report.Decayed)
+			fmt.Printf("  Evicted:        %d\n", report.Evicted)
 			fmt.Printf("  Consolidated:   %d\n", report.Consolidated)
+			fmt.Printf("  Purged (trash): %d\n", report.Purged)
 			if dryRun {
 				fmt.Println("  (dry run — no changes applied)")
 			}
@@ -113,16 +121,30 @@ func consolidateCmd() *cobra.Command {
 }
 
 func forgetCmd() *cobra.Command {
-	var yes bool
+	var (
+		yes       bool
+		permanent bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "forget [memory-id]",
 		Short: "Delete a memory by ID",
-		Args:  cobra.ExactArgs(1),
+		Long: `Delete a memory by ID (or unambiguous ID prefix).
+
+When memory.trash_retention_hours is greater than zero (default 72), the memory
+is moved to the trash instead of being deleted: it disappears from recall and
+search but can be brought back with 'openclaw-cortex restore <id>' until the
+lifecycle command purges it. Use --permanent to skip the trash.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id := args[0]
+			useTrash := !permanent && cfg.Memory.TrashRetentionHours > 0
 			if !yes {
-				fmt.Printf("Delete memory %s? [y/N] ", id)
+				verb := "Delete"
+				if useTrash {
+					verb = "Trash"
+				}
+				fmt.Printf("%s memory %s? [y/N] ", verb, id)
 				var response string
 				if _, err := fmt.Scanln(&response); err != nil || strings.ToLower(strings.TrimSpace(response)) != "y" {
 					fmt.Println("Aborted.")
@@ -139,6 +161,16 @@ func forgetCmd() *cobra.Command {
 			}
 			defer func() { _ = st.Close() }()
 
+			if useTrash {
+				trashedID, trashErr := st.Trash(ctx, id)
+				if trashErr != nil {
+					return cmdErr("forget: trashing memory", trashErr)
+				}
+				fmt.Printf("Moved memory %s to trash (restore within %dh with: openclaw-cortex restore %s)\n",
+					trashedID, cfg.Memory.TrashRetentionHours, trashedID)
+				return nil
+			}
+
 			if err := st.Delete(ctx, id); err != nil {
 				return cmdErr("forget: deleting memory", err)
 			}
@@ -149,5 +181,124 @@ func forgetCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip confirmation prompt")
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "delete immediately instead of moving to the trash")
+	return cmd
+}
+
+// forgetAllLimit caps how many memories one forget-all run deletes.
+const forgetAllLimit = 10000
+
+func forgetAllCmd() *cobra.Command {
+	var (
+		project   string
+		memType   string
+		memScope  string
+		tagsFlag  string
+		yes       bool
+		permanent bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "forget-all",
+		Short: "Delete every memory matching a filter",
+		Long: `Delete every memory matching --project, --type, --scope and --tags (at least
+one is required). As with 'forget', memories go to the trash when
+memory.trash_retention_hours is greater than zero, unless --permanent is set.
+At most 10000 memories are deleted per run; run again to continue.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			filters, err := buildSearchFilters("forget-all", memType, memScope, project, "", tagsFlag)
+			if err != nil {
+				return err
+			}
+			if filters == nil {
+				return fmt.Errorf("forget-all: at least one of --project, --type, --scope or --tags is required")
+			}
+
+			useTrash := !permanent && cfg.Memory.TrashRetentionHours > 0
+			if !yes {
+				verb := "Delete"
+				if useTrash {
+					verb = "Trash"
+				}
+				fmt.Printf("%s all memories matching %s? [y/N] ", verb, describeFilters(project, memType, memScope, tagsFlag))
+				var response string
+				if _, scanErr := fmt.Scanln(&response); scanErr != nil || strings.ToLower(strings.TrimSpace(response)) != "y" {
+					fmt.Println("Aborted.")
+					return nil
+				}
+			}
+
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("forget-all: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			deleted, more, err := store.DeleteByFilter(ctx, st, filters, forgetAllLimit, useTrash)
+			if err != nil {
+				return cmdErr(fmt.Sprintf("forget-all: deleted %d before failing", deleted), err)
+			}
+
+			verb := "Deleted"
+			if useTrash {
+				verb = "Moved to trash"
+			}
+			fmt.Printf("%s %d memories\n", verb, deleted)
+			if more {
+				fmt.Printf("More matching memories remain; run forget-all again to continue.\n")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&project, "project", "", "delete memories of this project")
+	cmd.Flags().StringVar(&memType, "type", "", "delete memories of this type (rule|fact|episode|procedure|preference)")
+	cmd.Flags().StringVar(&memScope, "scope", "", "delete memories of this scope (permanent|project|session|ttl)")
+	cmd.Flags().StringVar(&tagsFlag, "tags", "", "delete memories with these tags (comma-separated)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip confirmation prompt")
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "delete immediately instead of moving to the trash")
 	return cmd
 }
+
+// describeFilters renders the forget-all filter flags for the confirmation prompt.
+func describeFilters(project, memType, memScope, tags string) string {
+	var parts []string
+	for _, kv := range [][2]string{{"project", project}, {"type", memType}, {"scope", memScope}, {"tags", tags}} {
+		if kv[1] != "" {
+			parts = append(parts, kv[0]+"="+kv[1])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func restoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore [memory-id]",
+		Short: "Restore a forgotten memory from the trash",
+		Long: `Restore a memory previously removed with 'forget' while it is still in the
+trash (within memory.trash_retention_hours). The ID may be an unambiguous prefix.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("restore: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			restoredID, err := st.Restore(ctx, args[0])
+			if err != nil {
+				return cmdErr("restore: restoring memory", err)
+			}
+
+			fmt.Printf("Restored memory %s\n", restoredID)
+			return nil
+		},
+	}
+}
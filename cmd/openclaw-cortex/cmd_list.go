@@ -14,6 +14,8 @@ func listCmd() *cobra.Command {
 		memType string
 		scope   string
 		limit   uint64
+		since   string
+		until   string
 	)
 
 	cmd := &cobra.Command{
@@ -30,7 +32,7 @@ func listCmd() *cobra.Command {
 			defer func() { _ = st.Close() }()
 
 			var filters *store.SearchFilters
-			if memType != "" || scope != "" {
+			if memType != "" || scope != "" || since != "" || until != "" {
 				filters = &store.SearchFilters{}
 				if memType != "" {
 					mt := models.MemoryType(memType)
@@ -40,6 +42,9 @@ func listCmd() *cobra.Command {
 					sc := models.MemoryScope(scope)
 					filters.Scope = &sc
 				}
+				if rangeErr := applyCreatedRange("list", filters, since, until); rangeErr != nil {
+					return rangeErr
+				}
 			}
 
 			memories, _, err := st.List(ctx, filters, limit, "")
@@ -64,5 +69,7 @@ func listCmd() *cobra.Command {
 	cmd.Flags().StringVar(&memType, "type", "", "filter by type")
 	cmd.Flags().StringVar(&scope, "scope", "", "filter by scope")
 	cmd.Flags().Uint64Var(&limit, "limit", 50, "max results")
+	cmd.Flags().StringVar(&since, "since", "", "only memories created at or after this time (YYYY-MM-DD, RFC3339, or a duration ago such as 7d)")
+	cmd.Flags().StringVar(&until, "until", "", "only memories created at or before this time (YYYY-MM-DD includes the whole day, RFC3339, or a duration ago)")
 	return cmd
 }
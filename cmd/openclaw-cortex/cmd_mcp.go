@@ -10,7 +10,6 @@ import (
 
 	cortexmcp "github.com/ajitpratap0/openclaw-cortex/internal/mcp"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
-	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 )
 
 func mcpCmd() *cobra.Command {
@@ -34,6 +33,7 @@ individual tool calls will return MCP error responses on failure.`,
 			ctx := cmd.Context()
 
 			emb := newEmbedder(logger)
+			warmupEmbedder(ctx, emb, logger)
 
 			st, storeErr := newMemgraphStore(ctx, logger)
 			if storeErr != nil {
@@ -43,7 +43,7 @@ individual tool calls will return MCP error responses on failure.`,
 					"error", storeErr)
 			}
 
-			recaller := recall.NewRecaller(recallWeightsFromConfig(cfg.Recall.Weights), logger)
+			recaller := newRecaller(logger)
 
 			if st != nil {
 				// Wire graph client — MemgraphStore implements graph.Client.
@@ -52,6 +52,28 @@ individual tool calls will return MCP error responses on failure.`,
 			}
 
 			srv := cortexmcp.NewServer(st, emb, recaller, logger)
+			srv.SetMinMemories(cfg.Recall.MinMemories)
+			srv.SetTrackAccess(cfg.Recall.TrackAccess)
+			srv.SetTrashEnabled(cfg.Memory.TrashRetentionHours > 0)
+			srv.SetTypeTTL(typeTTLFromConfig(logger))
+			srv.SetTagNormalizer(tagNormalizerFromConfig())
+			srv.SetFreshness(freshnessFromConfig())
+			srv.SetRelevanceCutoff(cfg.Recall.RelevanceCutoff)
+			srv.SetPerMemoryMaxTokens(cfg.Recall.PerMemoryMaxTokens)
+			srv.SetIncludeAttachments(cfg.Recall.IncludeAttachments)
+			srv.SetOnConflict(cfg.Recall.OnConflict)
+			srv.SetRecallConflictCheck(recallConflictCheckerFromConfig(logger))
+			srv.SetDedup(cfg.Memory.DedupThreshold, dedupScopeFromConfig(cfg.Memory.DedupScope))
+			srv.SetBroadenWhenEmpty(cfg.Recall.BroadenWhenEmpty)
+			srv.SetConflictDetector(conflictDetectorFromConfig(logger))
+			srv.SetRelevanceFilter(newRelevanceFilter(logger))
+			if cfg.Recall.Synthesize {
+				if syn := newSynthesizer(logger); syn != nil {
+					srv.SetSynthesizer(syn)
+				} else {
+					logger.Warn("recall.synthesize is enabled but no Claude credentials are configured; synthesize mode disabled")
+				}
+			}
 
 			// Use a standard log.Logger pointing at stderr for the mcp-go error logger.
 			errLogger := log.New(os.Stderr, "mcp: ", log.LstdFlags)
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func moveProjectCmd() *cobra.Command {
+	var (
+		from   string
+		to     string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "move-project",
+		Short: "Reassign all memories and entities of a project to a new project name",
+		Long: `Find every memory whose project is --from and set it to --to, without
+re-embedding. Project-scoped memories, global-scope memories that carry the
+project name, trashed memories and extracted entities are all moved, so
+recall, lifecycle and "forget --restore" see the project under its new name.
+
+If --to already has memories the two projects are merged.
+Use --dry-run to report the counts without changing anything.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+			if from == "" || to == "" {
+				return fmt.Errorf("move-project: --from and --to are required")
+			}
+			if from == to {
+				return fmt.Errorf("move-project: --from and --to are both %q", from)
+			}
+
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("move-project: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			if dryRun {
+				memories, entities, countErr := st.CountProject(ctx, from)
+				if countErr != nil {
+					return cmdErr("move-project: counting project", countErr)
+				}
+				fmt.Printf("[dry-run] Would move %d memories and %d entities from project %q to %q.\n", memories, entities, from, to)
+				return nil
+			}
+
+			memories, entities, err := st.MoveProject(ctx, from, to)
+			if err != nil {
+				return cmdErr("move-project", err)
+			}
+			if memories == 0 && entities == 0 {
+				fmt.Printf("No memories or entities in project %q — nothing to do.\n", from)
+				return nil
+			}
+			fmt.Printf("Moved %d memories and %d entities from project %q to %q.\n", memories, entities, from, to)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "current project name (required)")
+	cmd.Flags().StringVar(&to, "to", "", "new project name (required)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report how many memories and entities would move without changing them")
+	return cmd
+}
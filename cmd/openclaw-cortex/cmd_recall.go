@@ -8,12 +8,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 	"github.com/ajitpratap0/openclaw-cortex/internal/timeutil"
-	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
 func recallCmd() *cobra.Command {
@@ -23,16 +24,26 @@ func recallCmd() *cobra.Command {
 		format           string
 		limit            int
 		project          string
+		projectsFlag     string
 		memType          string
 		memScope         string
 		tagsFlag         string
 		reason           bool
 		reasonCandidates int
 		graphDepth       int
+		expandEntities   int
 		includeHistory   bool
 		noAccessUpdate   bool
 		validBeforeStr   string
 		validAfterStr    string
+		inContent        string
+		maxMemories      int
+		relevanceCutoff  float64
+		perMemoryMax     int
+		attachments      bool
+		onConflict       string
+		synthesize       bool
+		freshness        string
 	)
 
 	cmd := &cobra.Command{
@@ -40,8 +51,8 @@ func recallCmd() *cobra.Command {
 		Short: "Recall relevant memories with multi-factor ranking",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if format != "text" && format != "json" {
-				return fmt.Errorf("recall: unknown --format %q; expected \"text\" or \"json\"", format)
+			if format != "text" && format != "json" && format != recall.FormatGrouped {
+				return fmt.Errorf("recall: unknown --format %q; expected \"text\", \"grouped\" or \"json\"", format)
 			}
 			// Note: "--limit -1" (space-separated) is rejected by pflag before RunE fires;
 			// "--limit=-1" (equals form) reaches this check and returns the custom error.
@@ -51,9 +62,43 @@ func recallCmd() *cobra.Command {
 			if limit > 10000 {
 				return fmt.Errorf("recall: --limit %d exceeds maximum of 10000", limit)
 			}
+			if maxMemories < 0 {
+				return fmt.Errorf("recall: --max-memories must be non-negative, got %d", maxMemories)
+			}
+			if !cmd.Flags().Changed("relevance-cutoff") {
+				relevanceCutoff = cfg.Recall.RelevanceCutoff
+			}
+			if relevanceCutoff < 0 || relevanceCutoff > 1 {
+				return fmt.Errorf("recall: --relevance-cutoff must be between 0 and 1, got %g", relevanceCutoff)
+			}
+			if !cmd.Flags().Changed("per-memory-max-tokens") {
+				perMemoryMax = cfg.Recall.PerMemoryMaxTokens
+			}
+			if perMemoryMax < 0 {
+				return fmt.Errorf("recall: --per-memory-max-tokens must be non-negative, got %d", perMemoryMax)
+			}
+			if !cmd.Flags().Changed("include-attachments") {
+				attachments = cfg.Recall.IncludeAttachments
+			}
+			if !cmd.Flags().Changed("on-conflict") {
+				onConflict = cfg.Recall.OnConflict
+			}
+			if !recall.ValidConflictMode(onConflict) {
+				return fmt.Errorf("recall: --on-conflict must be both, newest or flag, got %q", onConflict)
+			}
+			if synthesize && format == "json" {
+				return fmt.Errorf("recall: --synthesize cannot be combined with --format json")
+			}
+			if expandEntities < 0 {
+				return fmt.Errorf("recall: --expand-entities must be non-negative, got %d", expandEntities)
+			}
+			if !recall.ValidFreshness(freshness) {
+				return fmt.Errorf("recall: unknown --freshness %q; expected \"any\", \"recent\" or \"established\"", freshness)
+			}
 
 			logger := newLogger()
-			ctx := cmd.Context()
+			ctx, span := tracing.Start(cmd.Context(), "cli.recall", "recall.budget", budget, "recall.project", project)
+			defer span.End()
 			query := args[0]
 
 			emb := newEmbedder(logger)
@@ -63,12 +108,13 @@ func recallCmd() *cobra.Command {
 			}
 			defer func() { _ = st.Close() }()
 
-			vec, err := emb.Embed(ctx, query)
+			recaller := newRecaller(logger)
+			vec, err := emb.Embed(ctx, embedder.QueryText(recaller.ExpandQuery(query)))
 			if err != nil {
 				return cmdErr("recall: embedding query", err)
 			}
 
-			filters, filterErr := buildSearchFilters("recall", memType, memScope, project, tagsFlag)
+			filters, filterErr := buildSearchFilters("recall", memType, memScope, project, projectsFlag, tagsFlag)
 			if filterErr != nil {
 				return filterErr
 			}
@@ -94,6 +140,9 @@ func recallCmd() *cobra.Command {
 				}
 				filters.ValidAfter = &t
 			}
+			fresh := freshnessFromConfig()
+			now := time.Now().UTC()
+			filters = fresh.ApplyFilters(freshness, filters, now)
 
 			// Fetch more results than needed for re-ranking. When --limit is
 			// set, use it as a floor so we always retrieve at least that many
@@ -102,20 +151,37 @@ func recallCmd() *cobra.Command {
 			if limit > 0 && uint64(limit)*2 > searchLimit {
 				searchLimit = uint64(limit) * 2
 			}
-			results, err := st.Search(ctx, vec, searchLimit, filters)
+			if inContent != "" {
+				searchLimit *= inContentOverfetch
+			}
+			results, broadened, err := store.SearchBroadening(ctx, st, vec, searchLimit, filters, cfg.Recall.BroadenWhenEmpty, cfg.Recall.MinMemories)
 			if err != nil {
 				return cmdErr("recall: searching store", err)
 			}
+			if broadened {
+				logger.Debug("recall: broadened project recall to global memories", "project", project)
+			}
+			results = store.FilterByContent(results, inContent)
+			results = fresh.FilterResults(freshness, results, now)
 
 			// Re-rank with multi-factor scoring using config-loaded weights.
-			recaller := recall.NewRecaller(recallWeightsFromConfig(cfg.Recall.Weights), logger)
-
 			// Wire graph client for graph-augmented recall — MemgraphStore implements graph.Client.
 			gc := memgraph.NewGraphAdapter(st)
 			recaller.SetGraphClient(gc, st, cfg.Recall.GraphBudgetCLIMs)
 			recaller.SetGraphDepth(graphDepth)
 
-			ranked := recaller.RecallWithGraph(ctx, query, vec, results, project)
+			ranked := recaller.RecallWithGraphDepth(ctx, query, vec, results, project, expandEntities)
+			if inContent != "" {
+				// Graph expansion can inject memories that were never in the
+				// vector results; hold them to the same phrase constraint.
+				kept := ranked[:0]
+				for i := range ranked {
+					if store.ContainsPhrase(ranked[i].Memory.Content, inContent) {
+						kept = append(kept, ranked[i])
+					}
+				}
+				ranked = kept
+			}
 
 			// Optionally re-rank with Claude for genuine relevance.
 			// Threshold-gated: also triggers automatically when top-4 scores are clustered.
@@ -139,6 +205,15 @@ func recallCmd() *cobra.Command {
 				logger.Warn("--reason requires ANTHROPIC_API_KEY; skipping re-rank")
 			}
 
+			if filter := newRelevanceFilter(logger); filter != nil {
+				ranked = filter.Filter(ctx, query, ranked, 0)
+			}
+			if checker := recallConflictCheckerFromConfig(logger); checker != nil {
+				ranked = recall.DetectConflicts(ctx, checker, st, ranked, logger)
+			}
+
+			ranked, conflicts := recall.ResolveConflicts(ranked, onConflict)
+
 			// Apply --limit cap before token-budget trimming so the result
 			// count is deterministic when --limit is set. Note: --budget
 			// applies after this cap for both modes — it formats text output
@@ -153,7 +228,20 @@ func recallCmd() *cobra.Command {
 				contents = append(contents, ranked[i].Memory.Content)
 			}
 
-			output, count := tokenizer.FormatMemoriesWithBudget(contents, budget)
+			_, formatSpan := tracing.Start(ctx, "recall.format", "recall.budget", budget, "recall.candidates", len(contents))
+			contextFormat := recall.FormatPlain
+			if format == recall.FormatGrouped {
+				contextFormat = recall.FormatGrouped
+			}
+			maxCount := recall.RelevanceCap(ranked, relevanceCutoff, maxMemories)
+			shown := recall.MarkConflicts(recall.TruncateEach(ranked, perMemoryMax), conflicts, onConflict)
+			if attachments {
+				shown = recall.WithAttachmentRefs(shown)
+			}
+			output, count := recall.FormatContext(contextFormat, shown, budget, cfg.Recall.MinMemories, maxCount)
+			formatSpan.SetAttributes("memory.count", count)
+			formatSpan.End()
+			span.SetAttributes("memory.count", count)
 
 			// JSON output mode is activated by either:
 			//   --format json  (preferred; explicit, no sentinel hack)
@@ -180,18 +268,28 @@ func recallCmd() *cobra.Command {
 				}
 				fmt.Println(string(out))
 			} else {
-				fmt.Printf("Recalled %d memories (budget: %d tokens):\n\n", count, budget)
+				header := fmt.Sprintf("Recalled %d memories (budget: %d tokens):", count, budget)
+				if synthesize && count > 0 {
+					if syn := newSynthesizer(logger); syn == nil {
+						logger.Warn("--synthesize requires Claude credentials; printing memories instead")
+					} else if summary, synErr := syn.Synthesize(ctx, query, contents[:count]); synErr != nil {
+						logger.Warn("recall: synthesis failed, printing memories instead", "error", synErr)
+					} else {
+						header = fmt.Sprintf("Synthesized from %d memories:", count)
+						output = summary
+					}
+				}
+				fmt.Printf("%s\n\n", header)
 				fmt.Println(output)
 			}
 
 			// Update access metadata for returned memories.
 			// Skipped when --no-access-update is set to prevent automated
-			// injection pipelines from inflating access counts.
-			if !noAccessUpdate {
-				for i := 0; i < count && i < len(ranked); i++ {
-					if updateErr := st.UpdateAccessMetadata(ctx, ranked[i].Memory.ID); updateErr != nil {
-						logger.Warn("recall: UpdateAccessMetadata", "id", ranked[i].Memory.ID, "error", updateErr)
-					}
+			// injection pipelines from inflating access counts, or when
+			// access tracking is disabled via recall.track_access.
+			if !noAccessUpdate && cfg.Recall.TrackAccess {
+				if updateErr := st.UpdateAccessMetadataBatch(ctx, recall.TopIDs(ranked, count)); updateErr != nil {
+					logger.Warn("recall: UpdateAccessMetadataBatch", "count", count, "error", updateErr)
 				}
 			}
 
@@ -201,18 +299,28 @@ func recallCmd() *cobra.Command {
 
 	cmd.Flags().IntVar(&budget, "budget", 2000, "token budget")
 	cmd.Flags().StringVar(&ctxJSON, "context", "", "output as JSON context; WARNING: activates JSON output mode unless --format text is explicitly set (backward-compat; prefer --format json)")
-	cmd.Flags().StringVar(&format, "format", "text", "output format: text or json (json is preferred over --context sentinel)")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, grouped (text under type headings) or json (json is preferred over --context sentinel)")
 	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of results (0 = no cap, max 10000)")
+	cmd.Flags().IntVar(&maxMemories, "max-memories", 0, "maximum number of memories in the recalled context regardless of remaining budget (0 = no cap)")
+	cmd.Flags().Float64Var(&relevanceCutoff, "relevance-cutoff", 0, "stop adding memories once one scores below this fraction (0-1) of the top hit (default recall.relevance_cutoff; 0 = off)")
+	cmd.Flags().IntVar(&perMemoryMax, "per-memory-max-tokens", 0, "truncate each memory to this many tokens so more fit in the budget (default recall.per_memory_max_tokens; 0 = off)")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", recall.ConflictBoth, "competing memories: both (marked), newest, or flag (newest, marked disputed) (default recall.on_conflict)")
+	cmd.Flags().BoolVar(&attachments, "include-attachments", false, "append each memory's attachment references to the context (default recall.include_attachments)")
 	cmd.Flags().StringVar(&project, "project", "", "project context for scope boosting")
+	cmd.Flags().StringVar(&projectsFlag, "projects", "", "recall across these projects plus global memories (comma-separated; merged with --project)")
 	cmd.Flags().StringVar(&memType, "type", "", "filter by memory type (rule|fact|episode|procedure|preference)")
 	cmd.Flags().StringVar(&memScope, "scope", "", "filter by scope (permanent|project|session|ttl)")
 	cmd.Flags().StringVar(&tagsFlag, "tags", "", "filter by tags (comma-separated)")
 	cmd.Flags().BoolVar(&reason, "reason", false, "use Claude to re-rank results by genuine relevance (requires ANTHROPIC_API_KEY)")
 	cmd.Flags().IntVar(&reasonCandidates, "reason-candidates", 10, "number of top candidates to pass to Claude for re-ranking")
 	cmd.Flags().IntVar(&graphDepth, "graph-depth", 2, "graph traversal depth for graph-aware recall (1=direct entity facts only, 2=also traverse neighbor entities)")
+	cmd.Flags().IntVar(&expandEntities, "expand-entities", 0, "traverse up to N relationship hops from query entities and include linked memories with a boost that halves per hop (0 = use --graph-depth)")
 	cmd.Flags().BoolVar(&includeHistory, "include-history", false, "include invalidated/superseded memories in results")
 	cmd.Flags().BoolVar(&noAccessUpdate, "no-access-update", false, "skip updating access metadata (prevents automated pipelines from inflating access counts)")
 	cmd.Flags().StringVar(&validBeforeStr, "valid-before", "", "return memories whose valid_from is at or before this time (ISO 8601 or relative: 7d, 24h, 30m); date-only values (2026-03-01) include the full day (cutoff 23:59:59 UTC); relative durations are subtracted from now as-is (no end-of-day rounding); memories with no valid_from pass this filter")
 	cmd.Flags().StringVar(&validAfterStr, "valid-after", "", "return memories whose valid_from is at or after this time (ISO 8601 or relative: 7d, 24h, 30m); memories with no valid_from are excluded")
+	cmd.Flags().BoolVar(&synthesize, "synthesize", false, "print a single Claude-written summary of the recalled memories instead of the list (adds LLM latency)")
+	cmd.Flags().StringVar(&freshness, "freshness", "", "limit recall to an age bucket: any, recent (created in the last recall.freshness.recent_days) or established (older than recall.freshness.established_days and accessed within recall.freshness.established_access_days)")
+	cmd.Flags().StringVar(&inContent, "in-content", "", "only recall memories whose content contains this exact phrase (case-insensitive)")
 	return cmd
 }
@@ -3,140 +3,435 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
+// needsReembed reports whether mem should be re-embedded. Memories without a
+// vector always qualify; with stale set, so do memories whose recorded
+// embedding model differs from currentModel (including legacy memories that
+// have no recorded model).
+func needsReembed(mem *models.Memory, currentModel string, stale bool) bool {
+	if !mem.HasEmbedding {
+		return true
+	}
+	return stale && mem.EmbeddingModel != currentModel
+}
+
 func reembedCmd() *cobra.Command {
 	var (
-		dryRun    bool
-		batchSize int
+		dryRun       bool
+		batchSize    int
+		reembedStale bool
+		broken       bool
+		recreate     bool
+		yes          bool
 	)
 
 	cmd := &cobra.Command{
-		Use:   "reembed",
-		Short: "Re-embed memories that have no embedding vector",
+		Use:     "reembed",
+		Aliases: []string{"reindex"},
+		Short:   "Re-embed memories with missing or stale embedding vectors",
 		Long: `Scan all memories and re-embed those whose embedding field is NULL or empty.
 Memories without embeddings are silently invisible to recall, search, and forget --query.
 
+Use --reembed-stale to also re-embed memories whose stored embedding model
+differs from the currently configured one. Each re-embedded memory is stamped
+with the current model, so an interrupted migration can simply be re-run and
+already-current vectors are left untouched.
+
+Use --broken to also re-embed memories whose stored vector is all zeros,
+contains NaN/Inf values or has the wrong dimension (see "embeddings stats").
+
+When memory.vector_dimension no longer matches the dimension a vector index
+was built for (for example after switching to a 1024-dimensional model), pass
+--recreate-index: after confirmation the memory and entity-name indexes are
+dropped and recreated with the new dimension, vectors of the old size are
+cleared and every affected memory and entity name is re-embedded. Commands that
+set up the schema fail until this is done. Entity names left without a vector
+by an earlier run are re-embedded too.
+
 Use --dry-run to preview which memories would be re-embedded without making changes.
-Use --batch to control how many memories are fetched per page (default 50).`,
+Use --batch to control how many memories are fetched per page (default 50).
+Progress is reported on stderr after each page.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if batchSize <= 0 {
-				return fmt.Errorf("--batch must be a positive integer, got %d", batchSize)
+			return runReembed(cmd.Context(), reembedOptions{
+				name:                "reembed",
+				dryRun:              dryRun,
+				batchSize:           batchSize,
+				stale:               reembedStale,
+				broken:              broken,
+				checkIndexDimension: true,
+				recreate:            recreate,
+				yes:                 yes,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview which memories would be re-embedded without applying changes")
+	cmd.Flags().IntVar(&batchSize, "batch", 50, "number of memories to process per page")
+	cmd.Flags().BoolVar(&reembedStale, "reembed-stale", false, "also re-embed memories whose stored embedding model differs from the configured model")
+	cmd.Flags().BoolVar(&broken, "broken", false, "also re-embed memories whose stored vector is zero, non-finite or the wrong dimension")
+	cmd.Flags().BoolVar(&recreate, "recreate-index", false, "drop and recreate the vector index when its dimension differs from memory.vector_dimension, then re-embed every memory")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip the --recreate-index confirmation prompt")
+	return cmd
+}
+
+func repairEmbeddingsCmd() *cobra.Command {
+	var (
+		dryRun    bool
+		batchSize int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "repair-embeddings",
+		Short: "Re-embed memories whose stored vector is missing, zero or NaN",
+		Long: `Find memories whose stored vector is missing, all zeros, contains NaN/Inf
+values or has the wrong dimension, and re-embed them. Cosine similarity against
+such a vector is zero or undefined, so these memories never show up in recall,
+search or forget --query even though they are stored.
+
+This is "reembed --broken" without the stale-model migration; run
+"embeddings stats" first to see how many memories are affected. A memory is
+only rewritten when the embedder returns a healthy vector for it.
+
+Use --dry-run to list the broken memories without re-embedding them.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runReembed(cmd.Context(), reembedOptions{
+				name:      "repair-embeddings",
+				dryRun:    dryRun,
+				batchSize: batchSize,
+				broken:    true,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list broken memories without re-embedding them")
+	cmd.Flags().IntVar(&batchSize, "batch", 50, "number of memories to process per page")
+	return cmd
+}
+
+// reembedOptions selects which memories runReembed re-embeds. Memories with
+// no vector are always included.
+type reembedOptions struct {
+	name                string // command name used in messages
+	dryRun              bool
+	batchSize           int
+	stale               bool // also memories embedded by a different model
+	broken              bool // also memories whose vector is zero, non-finite or mis-sized
+	checkIndexDimension bool // compare vector index dimensions with memory.vector_dimension first
+	recreate            bool // rebuild a vector index whose dimension changed
+	yes                 bool // skip the recreate confirmation
+}
+
+// runReembed pages through every memory and re-embeds those selected by opts.
+func runReembed(ctx context.Context, opts reembedOptions) error {
+	if opts.batchSize <= 0 {
+		return fmt.Errorf("--batch must be a positive integer, got %d", opts.batchSize)
+	}
+
+	logger := newLogger()
+
+	st, err := newMemgraphStore(ctx, logger)
+	if err != nil {
+		return cmdErr(opts.name+": connecting to store", err)
+	}
+	defer func() { _ = st.Close() }()
+
+	currentModel := embedder.ModelID(cfg.Ollama, cfg.Embedder)
+
+	// A vector index built for another dimension makes every vector it covers
+	// stale. Rebuilding clears those vectors, after which the missing-vector
+	// passes below re-embed the entity names and memories.
+	dimensionChanged, entityErrored := false, 0
+	if opts.checkIndexDimension {
+		indexDims, dimErr := st.VectorIndexDimensions(ctx)
+		if dimErr != nil {
+			return cmdErr(opts.name+": inspecting vector indexes", dimErr)
+		}
+		wantDim := int(cfg.Memory.VectorDimension)
+		var mismatched []string
+		for _, name := range []string{"memory_embedding", "entity_name_embedding"} {
+			if d := indexDims[name]; d != 0 && d != wantDim {
+				mismatched = append(mismatched, name)
+				fmt.Printf("Vector index %s was built for %d-dimensional vectors; memory.vector_dimension is %d.\n", name, d, wantDim)
 			}
+		}
+		dimensionChanged = slices.Contains(mismatched, "memory_embedding")
+		if len(mismatched) > 0 {
+			switch {
+			case opts.dryRun:
+				fmt.Println("[dry-run] --recreate-index would rebuild the index and re-embed every vector it covers.")
+			case !opts.recreate:
+				return fmt.Errorf("%s: vector index %s does not match memory.vector_dimension %d; pass --recreate-index to rebuild it and re-embed every vector it covers",
+					opts.name, strings.Join(mismatched, ", "), wantDim)
+			default:
+				if !opts.yes {
+					fmt.Print("Drop and recreate the vector index? Every vector it covers will be re-embedded. [y/N] ")
+					var response string
+					if _, scanErr := fmt.Scanln(&response); scanErr != nil || strings.ToLower(strings.TrimSpace(response)) != "y" {
+						fmt.Println("Aborted.")
+						return nil
+					}
+				}
+				st.SetRebuildOnDimensionChange(true)
+				if ensureErr := st.EnsureCollection(ctx); ensureErr != nil {
+					return cmdErr(opts.name+": recreating vector index", ensureErr)
+				}
+				fmt.Printf("Recreated %s with dimension %d.\n", strings.Join(mismatched, ", "), wantDim)
+			}
+		}
 
-			logger := newLogger()
-			ctx := cmd.Context()
+		var entityErr error
+		if entityErrored, entityErr = reembedEntityNames(ctx, st, opts, logger); entityErr != nil {
+			return entityErr
+		}
+	}
 
-			st, err := newMemgraphStore(ctx, logger)
-			if err != nil {
-				return cmdErr("reembed: connecting to store", err)
+	// Count how many memories need re-embedding before we start. Stale
+	// vectors are only discovered while paging, so the shortcut applies
+	// to the missing-vector mode alone.
+	if !opts.stale && !opts.broken && !dimensionChanged {
+		zeroCount, countErr := st.CountZeroEmbeddingMemories(ctx)
+		if countErr != nil {
+			return cmdErr(opts.name+": counting zero-embedding memories", countErr)
+		}
+
+		if zeroCount == 0 {
+			fmt.Println("All memories have embeddings — nothing to do.")
+			return entityReembedErr(opts.name, entityErrored)
+		}
+	}
+
+	// Only dial Ollama when we will actually write embeddings.
+	// During --dry-run we never call emb.Embed, so connecting to
+	// Ollama would be unnecessary and would fail if it is down.
+	var emb interface {
+		Embed(ctx context.Context, text string) ([]float32, error)
+	}
+	if !opts.dryRun {
+		emb = newEmbedder(logger)
+	}
+
+	// Paginate unconditionally through all memories and re-embed only those
+	// whose embedding is missing (zero-length). We cannot rely on the initial
+	// zeroCount as a loop bound because the List API returns all memories
+	// regardless of embedding state — stopping after `fixed >= zeroCount`
+	// iterations would skip memories that happen to appear later in the page
+	// order and may miss actual zero-embedding nodes while re-embedding ones
+	// that already had valid vectors.
+	//
+	// We track three counters:
+	//   fixed   — memories whose embedding was missing (or stale) and was written
+	//   skipped — memories that already had a current embedding (left untouched)
+	//   errored — memories that needed fixing but embed/upsert failed
+
+	var (
+		cursor  string
+		fixed   int64
+		skipped int64
+		errored int64
+	)
+
+	for {
+		memories, nextCursor, listErr := st.List(ctx, &store.SearchFilters{IncludeInvalidated: true}, uint64(opts.batchSize), cursor) //nolint:gosec
+		if listErr != nil {
+			return cmdErr(opts.name+": listing memories", listErr)
+		}
+
+		var stored map[string][]float32
+		if opts.broken {
+			ids := make([]string, len(memories))
+			for i := range memories {
+				ids[i] = memories[i].ID
 			}
-			defer func() { _ = st.Close() }()
+			var vecErr error
+			if stored, vecErr = st.GetVectors(ctx, ids); vecErr != nil {
+				return cmdErr(opts.name+": fetching vectors", vecErr)
+			}
+		}
 
-			// Count how many memories need re-embedding before we start.
-			zeroCount, countErr := st.CountZeroEmbeddingMemories(ctx)
-			if countErr != nil {
-				return cmdErr("reembed: counting zero-embedding memories", countErr)
+		for i := range memories {
+			mem := memories[i]
+			brokenReason := ""
+			if opts.broken && mem.HasEmbedding {
+				brokenReason = vectorReason(stored[mem.ID], int(cfg.Memory.VectorDimension))
+			}
+			// In a dry run the old-size vectors are still stored, so a
+			// dimension change counts every memory as affected.
+			mismatched := dimensionChanged && opts.dryRun
+			if !mismatched && brokenReason == "" && !needsReembed(&mem, currentModel, opts.stale) {
+				skipped++
+				continue // already has a current embedding, skip
 			}
 
-			if zeroCount == 0 {
-				fmt.Println("All memories have embeddings — nothing to do.")
-				return nil
+			if opts.dryRun {
+				preview := mem.Content
+				if len([]rune(preview)) > 80 {
+					preview = string([]rune(preview)[:80])
+				}
+				reason := "missing-vector"
+				switch {
+				case mismatched:
+					reason = "wrong-dimension"
+				case brokenReason != "":
+					reason = brokenReason + "-vector"
+				case mem.HasEmbedding:
+					reason = fmt.Sprintf("stale (%q)", mem.EmbeddingModel)
+				}
+				fmt.Printf("[dry-run] would re-embed %s memory %s: %q\n", reason, mem.ID, preview)
+				fixed++
+				continue
 			}
 
-			// Only dial Ollama when we will actually write embeddings.
-			// During --dry-run we never call emb.Embed, so connecting to
-			// Ollama would be unnecessary and would fail if it is down.
-			var emb interface {
-				Embed(ctx context.Context, text string) ([]float32, error)
+			vec, embedErr := emb.Embed(ctx, embedder.DocumentText(mem.Type, mem.Content))
+			if embedErr != nil {
+				logger.Warn(opts.name+": failed to embed memory", "id", mem.ID, "error", embedErr)
+				errored++
+				continue
 			}
-			if !dryRun {
-				emb = newEmbedder(logger)
+			if reason := vectorReason(vec, int(cfg.Memory.VectorDimension)); reason != "" {
+				// Writing a degenerate vector would leave the memory just as
+				// invisible as before, so count it as a failure instead.
+				logger.Warn(opts.name+": embedder returned an unusable vector", "id", mem.ID, "reason", reason)
+				errored++
+				continue
 			}
 
-			// Paginate unconditionally through all memories and re-embed only those
-			// whose embedding is missing (zero-length). We cannot rely on the initial
-			// zeroCount as a loop bound because the List API returns all memories
-			// regardless of embedding state — stopping after `fixed >= zeroCount`
-			// iterations would skip memories that happen to appear later in the page
-			// order and may miss actual zero-embedding nodes while re-embedding ones
-			// that already had valid vectors.
-			//
-			// We track three counters:
-			//   fixed   — memories whose embedding was missing and was written
-			//   skipped — memories that already had an embedding (left untouched)
-			//   errored — memories that needed fixing but embed/upsert failed
-
-			var (
-				cursor  string
-				fixed   int64
-				skipped int64
-				errored int64
-			)
-
-			for {
-				memories, nextCursor, listErr := st.List(ctx, &store.SearchFilters{IncludeInvalidated: true}, uint64(batchSize), cursor) //nolint:gosec
-				if listErr != nil {
-					return cmdErr("reembed: listing memories", listErr)
-				}
+			if upsertErr := st.Upsert(ctx, mem, vec); upsertErr != nil {
+				logger.Warn(opts.name+": failed to upsert re-embedded memory", "id", mem.ID, "error", upsertErr)
+				errored++
+				continue
+			}
+			fixed++
+		}
 
-				for i := range memories {
-					mem := memories[i]
-					if mem.HasEmbedding {
-						skipped++
-						continue // already has embedding, skip
-					}
+		if !opts.dryRun {
+			fmt.Fprintf(os.Stderr, "%s: scanned %d memories, %d re-embedded, %d errored\n",
+				opts.name, fixed+skipped+errored, fixed, errored)
+		}
 
-					if dryRun {
-						preview := mem.Content
-						if len([]rune(preview)) > 80 {
-							preview = string([]rune(preview)[:80])
-						}
-						fmt.Printf("[dry-run] would re-embed missing-vector memory %s: %q\n", mem.ID, preview)
-						fixed++
-						continue
-					}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
 
-					vec, embedErr := emb.Embed(ctx, mem.Content)
-					if embedErr != nil {
-						logger.Warn("reembed: failed to embed memory", "id", mem.ID, "error", embedErr)
-						errored++
-						continue
-					}
+	if opts.dryRun {
+		fmt.Printf("Found %d memor%s to re-embed (dry run — no changes applied)\n",
+			fixed, map[bool]string{true: "y", false: "ies"}[fixed == 1])
+	} else {
+		fmt.Printf("Re-embedded %d memories (%d skipped as already embedded, %d errored)\n", fixed, skipped, errored)
+	}
+	if !opts.dryRun && errored > 0 {
+		return fmt.Errorf("%s: %d memor%s failed to re-embed (see warnings above)",
+			opts.name, errored, map[bool]string{true: "y", false: "ies"}[errored == 1])
+	}
+	return entityReembedErr(opts.name, entityErrored)
+}
 
-					if upsertErr := st.Upsert(ctx, mem, vec); upsertErr != nil {
-						logger.Warn("reembed: failed to upsert re-embedded memory", "id", mem.ID, "error", upsertErr)
-						errored++
-						continue
-					}
-					fixed++
-				}
+// reembedEntityNames embeds the names of entities that have no name vector,
+// such as those cleared when the entity index is rebuilt for a new dimension,
+// and returns how many failed. Without a vector an entity is invisible to
+// semantic entity search.
+// entityMentions returns the content of the most recently linked memories of
+// ent, newest first, for its embedding text. Lookup failures are logged and
+// yield no mentions so the entity is still re-embedded from its name.
+func entityMentions(ctx context.Context, st *memgraph.MemgraphStore, ent *models.Entity, logger *slog.Logger) []string {
+	ids := ent.MemoryIDs
+	if len(ids) > models.EntityMentionLimit {
+		ids = ids[len(ids)-models.EntityMentionLimit:]
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	found, err := st.GetMany(ctx, ids)
+	if err != nil {
+		logger.Warn("reembed: loading linked memories failed", "id", ent.ID, "error", err)
+		return nil
+	}
+	mentions := make([]string, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		if m, ok := found[ids[i]]; ok {
+			mentions = append(mentions, m.Content)
+		}
+	}
+	return mentions
+}
 
-				if nextCursor == "" {
-					break
-				}
-				cursor = nextCursor
-			}
+func reembedEntityNames(ctx context.Context, st *memgraph.MemgraphStore, opts reembedOptions, logger *slog.Logger) (int, error) {
+	var (
+		emb     embedder.Embedder
+		after   string
+		fixed   int
+		errored int
+	)
+	for {
+		entities, err := st.ListEntitiesWithoutEmbedding(ctx, after, opts.batchSize)
+		if err != nil {
+			return errored, cmdErr(opts.name+": listing entities without embeddings", err)
+		}
+		if len(entities) == 0 {
+			break
+		}
+		after = entities[len(entities)-1].ID
 
-			if dryRun {
-				fmt.Printf("Found %d memor%s to re-embed (dry run — no changes applied)\n",
-					fixed, map[bool]string{true: "y", false: "ies"}[fixed == 1])
-			} else {
-				fmt.Printf("Re-embedded %d memories (%d skipped as already embedded, %d errored)\n", fixed, skipped, errored)
+		for i := range entities {
+			ent := entities[i]
+			if opts.dryRun {
+				fmt.Printf("[dry-run] would re-embed entity name %s: %q\n", ent.ID, ent.Name)
+				fixed++
+				continue
 			}
-			if !dryRun && errored > 0 {
-				return fmt.Errorf("reembed: %d memor%s failed to re-embed (see warnings above)",
-					errored, map[bool]string{true: "y", false: "ies"}[errored == 1])
+			if emb == nil {
+				emb = newEmbedder(logger)
 			}
-			return nil
-		},
+			vec, embedErr := emb.Embed(ctx, ent.EmbeddingTextWith(entityMentions(ctx, st, &ent, logger)))
+			if embedErr != nil {
+				logger.Warn(opts.name+": failed to embed entity name", "id", ent.ID, "error", embedErr)
+				errored++
+				continue
+			}
+			if reason := vectorReason(vec, int(cfg.Memory.VectorDimension)); reason != "" {
+				logger.Warn(opts.name+": embedder returned an unusable vector", "id", ent.ID, "reason", reason)
+				errored++
+				continue
+			}
+			ent.NameEmbedding = vec
+			if upsertErr := st.UpsertEntity(ctx, ent); upsertErr != nil {
+				logger.Warn(opts.name+": failed to upsert re-embedded entity", "id", ent.ID, "error", upsertErr)
+				errored++
+				continue
+			}
+			fixed++
+		}
 	}
 
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview which memories would be re-embedded without applying changes")
-	cmd.Flags().IntVar(&batchSize, "batch", 50, "number of memories to process per page")
-	return cmd
+	switch {
+	case opts.dryRun && fixed > 0:
+		fmt.Printf("Found %d entity name%s to re-embed (dry run — no changes applied)\n",
+			fixed, map[bool]string{true: "", false: "s"}[fixed == 1])
+	case fixed > 0 || errored > 0:
+		fmt.Printf("Re-embedded %d entity names (%d errored)\n", fixed, errored)
+	}
+	return errored, nil
+}
+
+// entityReembedErr reports entity names reembedEntityNames could not embed.
+// They keep no vector, so re-running the command retries them.
+func entityReembedErr(name string, errored int) error {
+	if errored == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %d entity name%s failed to re-embed (see warnings above); re-run %s to retry",
+		name, errored, map[bool]string{true: "", false: "s"}[errored == 1], name)
 }
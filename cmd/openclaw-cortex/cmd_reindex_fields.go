@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func reindexFieldsCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "reindex-fields",
+		Short: "Create any missing property indexes on filterable fields",
+		Long: `Compare the property indexes in Memgraph with the ones the current schema
+expects (type, scope, project, source, validity window, ...) and create the
+missing ones. Databases created by an older release keep working without them,
+but filters on a new field fall back to a full label scan until it is indexed.
+
+Existing indexes are left untouched, so the command is safe to re-run.
+Use --dry-run to list the missing indexes without creating them.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			logger := newLogger()
+			ctx := cmd.Context()
+
+			st, err := newMemgraphStore(ctx, logger)
+			if err != nil {
+				return cmdErr("reindex-fields: connecting to store", err)
+			}
+			defer func() { _ = st.Close() }()
+
+			missing, err := st.MissingPropertyIndexes(ctx)
+			if err != nil {
+				return cmdErr("reindex-fields: listing indexes", err)
+			}
+			if len(missing) == 0 {
+				fmt.Println("All property indexes are present — nothing to do.")
+				return nil
+			}
+
+			for _, idx := range missing {
+				if dryRun {
+					fmt.Printf("Would create index %s\n", idx)
+					continue
+				}
+				if err := st.CreatePropertyIndex(ctx, idx); err != nil {
+					return cmdErr("reindex-fields", err)
+				}
+				fmt.Printf("Created index %s\n", idx)
+			}
+			if dryRun {
+				fmt.Printf("[dry-run] %d missing index(es) — no changes made.\n", len(missing))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list missing indexes without creating them")
+	return cmd
+}
@@ -6,9 +6,17 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
 )
 
+// inContentOverfetch multiplies the vector search limit when --in-content is
+// set, since the phrase filter runs after retrieval and discards candidates.
+const inContentOverfetch = 5
+
 func searchCmd() *cobra.Command {
 	var (
 		memType        string
@@ -16,8 +24,15 @@ func searchCmd() *cobra.Command {
 		tagsFlag       string
 		limit          uint64
 		project        string
+		projectsFlag   string
 		jsonFlag       bool
 		includeHistory bool
+		inContent      string
+		keyword        string
+		since          string
+		until          string
+		near           string
+		nearWeight     float64
 	)
 
 	cmd := &cobra.Command{
@@ -25,8 +40,13 @@ func searchCmd() *cobra.Command {
 		Short: "Search memories by semantic similarity",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if nearWeight < 0 || nearWeight > 1 {
+				return fmt.Errorf("search: --near-weight must be between 0 and 1, got %g", nearWeight)
+			}
+
 			logger := newLogger()
-			ctx := cmd.Context()
+			ctx, span := tracing.Start(cmd.Context(), "cli.search", "search.limit", limit)
+			defer span.End()
 			query := args[0]
 
 			emb := newEmbedder(logger)
@@ -36,23 +56,64 @@ func searchCmd() *cobra.Command {
 			}
 			defer func() { _ = st.Close() }()
 
-			vec, err := emb.Embed(ctx, query)
+			vec, err := emb.Embed(ctx, embedder.QueryText(query))
 			if err != nil {
 				return cmdErr("search: embedding query", err)
 			}
 
-			filters, filterErr := buildSearchFilters("search", memType, memScope, project, tagsFlag)
+			// --near blends the anchor memory's stored vector into the query
+			// vector: "more like this one, but about the query".
+			if near != "" {
+				anchor, anchorErr := st.GetVector(ctx, near)
+				if anchorErr != nil {
+					return cmdErr("search: loading --near memory", anchorErr)
+				}
+				blended := vecmath.Blend(vec, anchor, nearWeight)
+				if blended == nil {
+					return fmt.Errorf("search: --near memory %s has no usable embedding (see \"embeddings stats\")", near)
+				}
+				vec = blended
+			}
+
+			filters, filterErr := buildSearchFilters("search", memType, memScope, project, projectsFlag, tagsFlag)
 			if filterErr != nil {
 				return filterErr
 			}
+			if (includeHistory || keyword != "" || since != "" || until != "") && filters == nil {
+				filters = &store.SearchFilters{}
+			}
+			if rangeErr := applyCreatedRange("search", filters, since, until); rangeErr != nil {
+				return rangeErr
+			}
 			if includeHistory {
 				filters.IncludeInvalidated = true
 			}
+			if keyword != "" {
+				filters.Keyword = keyword
+			}
 
-			results, err := st.Search(ctx, vec, limit, filters)
+			// --in-content post-filters the vector results, so over-fetch to
+			// leave enough candidates after the phrase constraint is applied.
+			searchLimit := limit
+			if inContent != "" {
+				searchLimit = limit * inContentOverfetch
+			}
+			if near != "" {
+				searchLimit++ // the anchor itself is dropped below
+			}
+			results, err := st.Search(ctx, vec, searchLimit, filters)
 			if err != nil {
 				return cmdErr("search: querying store", err)
 			}
+			if near != "" {
+				results = dropResult(results, near)
+			}
+			if inContent != "" {
+				results = store.FilterByContent(results, inContent)
+			}
+			if uint64(len(results)) > limit {
+				results = results[:limit]
+			}
 
 			if jsonFlag {
 				if results == nil {
@@ -85,7 +146,25 @@ func searchCmd() *cobra.Command {
 	cmd.Flags().StringVar(&tagsFlag, "tags", "", "filter by tags (comma-separated)")
 	cmd.Flags().Uint64Var(&limit, "limit", 10, "max results")
 	cmd.Flags().StringVar(&project, "project", "", "filter by project")
+	cmd.Flags().StringVar(&projectsFlag, "projects", "", "search across these projects plus global memories (comma-separated; merged with --project)")
 	cmd.Flags().BoolVar(&jsonFlag, "json", false, "output results as JSON")
 	cmd.Flags().BoolVar(&includeHistory, "include-history", false, "include invalidated/superseded memories in results")
+	cmd.Flags().StringVar(&near, "near", "", "blend this memory's stored vector into the query to find memories like it but about the query")
+	cmd.Flags().Float64Var(&nearWeight, "near-weight", 0.5, "weight of the --near memory's vector in the blend (0 = query only, 1 = memory only)")
+	cmd.Flags().StringVar(&since, "since", "", "only memories created at or after this time (YYYY-MM-DD, RFC3339, or a duration ago such as 7d)")
+	cmd.Flags().StringVar(&until, "until", "", "only memories created at or before this time (YYYY-MM-DD includes the whole day, RFC3339, or a duration ago)")
+	cmd.Flags().StringVar(&keyword, "keyword", "", "hybrid search: fuse the semantic ranking with a keyword match on content (e.g. error codes, function names)")
+	cmd.Flags().StringVar(&inContent, "in-content", "", "only return memories whose content contains this exact phrase (case-insensitive)")
 	return cmd
 }
+
+// dropResult returns results without the memory with the given ID.
+func dropResult(results []models.SearchResult, id string) []models.SearchResult {
+	out := results[:0]
+	for i := range results {
+		if results[i].Memory.ID != id {
+			out = append(out, results[i])
+		}
+	}
+	return out
+}
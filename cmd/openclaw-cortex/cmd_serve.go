@@ -37,19 +37,56 @@ func serveCmd() *cobra.Command {
 			}
 
 			emb := newEmbedder(logger)
+			warmupEmbedder(ctx, emb, logger)
 			st, err := newMemgraphStore(ctx, logger)
 			if err != nil {
 				return cmdErr("serve: connecting to store", err)
 			}
 			defer func() { _ = st.Close() }()
 
-			rec := recall.NewRecaller(recallWeightsFromConfig(cfg.Recall.Weights), logger)
+			rec := newRecaller(logger)
 
 			// Wire graph client — MemgraphStore implements graph.Client.
 			gc := memgraph.NewGraphAdapter(st)
 			rec.SetGraphClient(gc, st, cfg.Recall.GraphBudgetCLIMs)
 
 			srv := api.NewServer(st, rec, emb, logger, cfg.API.AuthToken, cfg.API.CursorSecret)
+			srv.SetMinMemories(cfg.Recall.MinMemories)
+			srv.SetTrackAccess(cfg.Recall.TrackAccess)
+			srv.SetTrashEnabled(cfg.Memory.TrashRetentionHours > 0)
+			srv.SetTypeTTL(typeTTLFromConfig(logger))
+			srv.SetTagNormalizer(tagNormalizerFromConfig())
+			srv.SetFreshness(freshnessFromConfig())
+			srv.SetRelevanceCutoff(cfg.Recall.RelevanceCutoff)
+			srv.SetPerMemoryMaxTokens(cfg.Recall.PerMemoryMaxTokens)
+			srv.SetIncludeAttachments(cfg.Recall.IncludeAttachments)
+			srv.SetOnConflict(cfg.Recall.OnConflict)
+			srv.SetRecallConflictCheck(recallConflictCheckerFromConfig(logger))
+			srv.SetVectorDimension(int(cfg.Memory.VectorDimension))
+			srv.SetBroadenWhenEmpty(cfg.Recall.BroadenWhenEmpty)
+			srv.SetConflictDetector(conflictDetectorFromConfig(logger))
+			srv.SetRelevanceFilter(newRelevanceFilter(logger))
+			srv.SetDedup(cfg.Memory.DedupThreshold, dedupScopeFromConfig(cfg.Memory.DedupScope))
+			srv.SetDedupOnRemember(cfg.API.DedupOnRemember)
+			srv.SetLifecycleManager(newLifecycleManager(st, emb, logger))
+			srv.SetMetricsToken(cfg.API.MetricsToken)
+			srv.SetEmbedConcurrency(cfg.API.MaxConcurrentEmbeds, time.Duration(cfg.API.EmbedQueueTimeoutMs)*time.Millisecond)
+			if cfg.Recall.FeedbackLog != "" {
+				fl, flErr := recall.OpenFeedbackLog(cfg.Recall.FeedbackLog)
+				if flErr != nil {
+					return cmdErr("serve: opening recall feedback log", flErr)
+				}
+				defer func() { _ = fl.Close() }()
+				srv.SetFeedbackLog(fl)
+				logger.Info("recall feedback logging enabled", "path", cfg.Recall.FeedbackLog)
+			}
+			if cfg.Recall.Synthesize {
+				if syn := newSynthesizer(logger); syn != nil {
+					srv.SetSynthesizer(syn)
+				} else {
+					logger.Warn("recall.synthesize is enabled but no Claude credentials are configured; synthesize mode disabled")
+				}
+			}
 
 			rl := api.RateLimitMiddleware(ctx, cfg.API.RateLimitRPS, cfg.API.RateLimitBurst)
 			httpSrv := &http.Server{
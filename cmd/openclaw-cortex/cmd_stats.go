@@ -12,11 +12,20 @@ import (
 
 func statsCmd() *cobra.Command {
 	var jsonOutput bool
+	var sampleSize int
 
 	cmd := &cobra.Command{
 		Use:   "stats",
 		Short: "Show memory collection statistics",
+		Long: `Show memory collection statistics.
+
+Exact statistics scan the whole collection. On very large collections pass
+--sample N to read the total from the index count and estimate the type and
+scope breakdowns from N memories; health metrics are skipped in that mode.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if sampleSize < 0 {
+				return fmt.Errorf("stats: --sample must be >= 0, got %d", sampleSize)
+			}
 			logger := newLogger()
 			ctx := cmd.Context()
 
@@ -26,7 +35,12 @@ func statsCmd() *cobra.Command {
 			}
 			defer func() { _ = st.Close() }()
 
-			stats, err := st.Stats(ctx)
+			var stats *models.CollectionStats
+			if sampleSize > 0 {
+				stats, err = st.SampleStats(ctx, sampleSize)
+			} else {
+				stats, err = st.Stats(ctx)
+			}
 			if err != nil {
 				return fmt.Errorf("stats: fetching statistics: %w", err)
 			}
@@ -54,7 +68,12 @@ func statsCmd() *cobra.Command {
 				return nil
 			}
 
-			fmt.Printf("Total memories: %d\n\n", stats.TotalMemories)
+			if stats.Approximate {
+				fmt.Printf("Approximate: estimated from a sample of %d memories\n", stats.SampleSize)
+				fmt.Printf("Total memories: ~%d\n\n", stats.TotalMemories)
+			} else {
+				fmt.Printf("Total memories: %d\n\n", stats.TotalMemories)
+			}
 			fmt.Printf("Entities:       %d\n", entityCount)
 
 			fmt.Println("By type:")
@@ -69,14 +88,19 @@ func statsCmd() *cobra.Command {
 
 			// Health metrics
 			fmt.Println("\nHealth:")
+			if stats.Approximate {
+				fmt.Println("  (skipped in sample mode; run without --sample for health metrics)")
+			}
 			if stats.OldestMemory != nil {
 				fmt.Printf("  %-24s %s\n", "oldest_memory", stats.OldestMemory.Format("2006-01-02T15:04:05Z"))
 			}
 			if stats.NewestMemory != nil {
 				fmt.Printf("  %-24s %s\n", "newest_memory", stats.NewestMemory.Format("2006-01-02T15:04:05Z"))
 			}
-			fmt.Printf("  %-24s %d\n", "active_conflicts", stats.ActiveConflicts)
-			fmt.Printf("  %-24s %d\n", "pending_ttl_expiry", stats.PendingTTLExpiry)
+			if !stats.Approximate {
+				fmt.Printf("  %-24s %d\n", "active_conflicts", stats.ActiveConflicts)
+				fmt.Printf("  %-24s %d\n", "pending_ttl_expiry", stats.PendingTTLExpiry)
+			}
 			fmt.Printf("  %-24s %d bytes\n", "storage_estimate", stats.StorageEstimate)
 
 			if len(stats.ReinforcementTiers) > 0 {
@@ -108,5 +132,6 @@ func statsCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output stats as JSON")
+	cmd.Flags().IntVar(&sampleSize, "sample", 0, "Estimate counts from this many memories instead of a full scan (0 = exact)")
 	return cmd
 }
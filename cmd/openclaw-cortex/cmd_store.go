@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -9,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/async"
+	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/extract"
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
@@ -24,24 +28,46 @@ func storeCmd() *cobra.Command {
 		tags            string
 		project         string
 		confidence      float64
+		boost           float64
 		ttlHours        int
 		supersedesID    string
 		validUntil      string
+		expiresAt       string
 		extractEntities bool
 		skipDedup       bool
 		dedupThreshold  float64
+		attach          []string
+		fromFile        string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "store [memory text]",
 		Short: "Store a new memory",
-		Args:  cobra.ExactArgs(1),
+		Long: `Store a new memory from the command-line argument, or with --from-file
+from a whole file ("-" reads stdin). Unlike index, a file is stored as one
+memory rather than chunked by heading.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := newLogger()
 			ctx := cmd.Context()
-			content := args[0]
 
-			// Validate minimum content length.
+			var content string
+			switch {
+			case fromFile != "" && len(args) > 0:
+				return fmt.Errorf("store: pass either memory text or --from-file, not both")
+			case fromFile != "":
+				data, readErr := readContentFile(fromFile, cmd.InOrStdin())
+				if readErr != nil {
+					return fmt.Errorf("store: %w", readErr)
+				}
+				content = data
+			case len(args) == 1:
+				content = args[0]
+			default:
+				return fmt.Errorf("store: memory text or --from-file is required")
+			}
+
+			// Validate content length.
 			if err := store.ValidateContentLength(content); err != nil {
 				return fmt.Errorf("store: %w", err)
 			}
@@ -53,6 +79,10 @@ func storeCmd() *cobra.Command {
 					memType, validTypesString())
 			}
 
+			if err := validateBoost("store", boost); err != nil {
+				return err
+			}
+
 			// Validate memory scope.
 			ms := models.MemoryScope(scope)
 			if !ms.IsValid() {
@@ -60,6 +90,15 @@ func storeCmd() *cobra.Command {
 					scope, validScopesString())
 			}
 
+			var attachments []models.Attachment
+			for _, raw := range attach {
+				a, parseErr := models.ParseAttachment(raw)
+				if parseErr != nil {
+					return fmt.Errorf("store: invalid --attach: %w", parseErr)
+				}
+				attachments = append(attachments, a)
+			}
+
 			emb := newEmbedder(logger)
 			st, err := newMemgraphStore(ctx, logger)
 			if err != nil {
@@ -71,7 +110,7 @@ func storeCmd() *cobra.Command {
 				return cmdErr("store: ensuring collection", err)
 			}
 
-			vec, err := emb.Embed(ctx, content)
+			vec, err := emb.Embed(ctx, embedder.DocumentText(mt, content))
 			if err != nil {
 				return cmdErr("store: embedding content", err)
 			}
@@ -80,15 +119,13 @@ func storeCmd() *cobra.Command {
 			// Bypassed when --skip-dedup is set.
 			if !skipDedup {
 				// Resolve effective dedup threshold: flag overrides config default.
-				effectiveThreshold := cfg.Memory.DedupThreshold
-				if cmd.Flags().Changed("dedup-threshold") {
-					if err := store.ValidateDedupThreshold(dedupThreshold); err != nil {
-						return fmt.Errorf("store: --dedup-threshold: %w", err)
-					}
-					effectiveThreshold = dedupThreshold
+				effectiveThreshold, err := dedupThresholdFromFlag(cmd, "store", dedupThreshold)
+				if err != nil {
+					return err
 				}
 
-				dedupRes, dedupErr := store.CheckAndHandleDuplicate(ctx, st, vec, content, effectiveThreshold)
+				dedupRes, dedupErr := store.CheckAndHandleDuplicateInScope(ctx, st, vec, content, effectiveThreshold,
+					mt, dedupScopeFromConfig(cfg.Memory.DedupScope))
 				if dedupErr != nil {
 					// Dedup is an optimisation, not a correctness gate — fail open
 					// so a transient Memgraph hiccup does not block all stores.
@@ -118,6 +155,7 @@ func storeCmd() *cobra.Command {
 				Visibility:   models.VisibilityShared,
 				Content:      content,
 				Confidence:   confidence,
+				Boost:        boost,
 				Source:       "explicit",
 				Tags:         tagList,
 				Project:      project,
@@ -125,6 +163,10 @@ func storeCmd() *cobra.Command {
 				UpdatedAt:    now,
 				LastAccessed: now,
 				SupersedesID: supersedesID,
+				Attachments:  attachments,
+			}
+			if fromFile != "" && fromFile != "-" {
+				mem.Metadata = map[string]any{"source_path": fromFile}
 			}
 
 			if ttlHours > 0 {
@@ -139,12 +181,31 @@ func storeCmd() *cobra.Command {
 				}
 				mem.ValidUntil = now.Add(dur)
 			}
+			if expiresAt != "" {
+				t, parseErr := parseExpiresAt(expiresAt)
+				if parseErr != nil {
+					return fmt.Errorf("store: invalid --expires-at %q: %w", expiresAt, parseErr)
+				}
+				mem.ExpiresAt = t
+			}
+
+			typeTTLFromConfig(logger).Apply(&mem)
+			normalizeMemoryTags(&mem)
+
+			var conflict *capture.ConflictWarning
+			if cd := conflictDetectorFromConfig(logger); cd != nil && supersedesID == "" {
+				conflict = cd.CheckStore(ctx, st, content, confidence, vec)
+			}
 
 			if err := st.Upsert(ctx, mem, vec); err != nil {
 				return cmdErr("store: upserting memory", err)
 			}
 
 			fmt.Printf("Stored memory %s [%s/%s]\n", mem.ID, mem.Type, mem.Scope)
+			if conflict != nil {
+				fmt.Printf("  warning: conflicts with memory %s (%s); pass --supersedes %s to replace it\n",
+					conflict.ConflictingID, conflict.Reason, conflict.ConflictingID)
+			}
 
 			if extractEntities {
 				if cfg.Async.Disabled || asyncQueue == nil {
@@ -161,6 +222,7 @@ func storeCmd() *cobra.Command {
 							Store:       st,
 							GraphClient: gc,
 							Logger:      logger,
+							Embedder:    emb,
 						}, []extract.StoredMemory{{ID: mem.ID, Content: content}})
 						if res.EntitiesExtracted > 0 || res.FactsExtracted > 0 {
 							fmt.Printf("  Extracted %d entities, %d facts\n", res.EntitiesExtracted, res.FactsExtracted)
@@ -191,9 +253,13 @@ func storeCmd() *cobra.Command {
 	cmd.Flags().StringVar(&tags, "tags", "", "comma-separated tags")
 	cmd.Flags().StringVar(&project, "project", "", "project name")
 	cmd.Flags().Float64Var(&confidence, "confidence", 0.9, "confidence score")
+	cmd.Flags().Float64Var(&boost, "boost", models.DefaultBoost, "recall score multiplier for promoting authoritative memories (0 < boost <= 10)")
 	cmd.Flags().IntVar(&ttlHours, "ttl", 0, "time-to-live in hours (0 = permanent)")
+	cmd.Flags().StringArrayVar(&attach, "attach", nil, "reference external material as type:ref, e.g. file:docs/auth.md, url:https://..., commit:3f2a9c1 (repeatable)")
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "store the contents of this file as one memory (- reads stdin)")
 	cmd.Flags().StringVar(&supersedesID, "supersedes", "", "ID of memory this one replaces")
 	cmd.Flags().StringVar(&validUntil, "valid-until", "", "validity duration from now (e.g. 24h, 7d)")
+	cmd.Flags().StringVar(&expiresAt, "expires-at", "", "delete the memory after this date, whatever its scope (2006-01-02 or RFC3339)")
 	cmd.Flags().BoolVar(&extractEntities, "extract-entities", false, "extract entities and facts from content (requires LLM)")
 	cmd.Flags().BoolVar(&skipDedup, "skip-dedup", false, "bypass store-time dedup check (always store as new memory)")
 	cmd.Flags().Float64Var(&dedupThreshold, "dedup-threshold", 0, "override cosine similarity dedup threshold for this call (range (0.0, 1.0]; omit to use config default)")
@@ -215,3 +281,39 @@ func validScopesString() string {
 	}
 	return strings.Join(scopes, "|")
 }
+
+// readContentFile returns the contents of path with surrounding whitespace
+// trimmed, reading stdin when path is "-". Files longer than
+// store.MaxContentLen are rejected; use index to chunk them instead.
+func readContentFile(path string, stdin io.Reader) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	content := strings.TrimSpace(string(data))
+	if err := store.ValidateMaxContentLength(content); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return content, nil
+}
+
+// parseExpiresAt parses an --expires-at value: a date (YYYY-MM-DD, meaning
+// midnight UTC at its start) or an RFC3339 timestamp.
+func parseExpiresAt(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want a date (2006-01-02) or RFC3339 timestamp")
+	}
+	return t.UTC(), nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
@@ -112,46 +114,78 @@ Output is a JSON array of results with id and status ("created", "duplicate", "u
 				return cmdErr("store-batch: ensuring collection", collErr)
 			}
 
-			// Collect all content strings for batch embedding.
-			contents := make([]string, len(inputs))
+			results := make([]batchStoreResult, len(inputs))
+
+			// Fast textual pre-check: entries that repeat an earlier entry of
+			// this batch almost verbatim are reported as duplicates without
+			// being embedded.
+			textDup := make([]bool, len(inputs))
+			if !skipDedup && cfg.Memory.FastDedup {
+				shingles := store.NewShingleIndex(cfg.Memory.FastDedupThreshold)
+				for i := range inputs {
+					if shingles.CheckAndAdd(inputs[i].Content) {
+						textDup[i] = true
+						metrics.Inc(metrics.FastDedupSkipped)
+						results[i] = batchStoreResult{
+							Status:  "duplicate",
+							Content: truncate(inputs[i].Content, 80),
+						}
+					}
+				}
+			}
+
+			// Collect all remaining content strings for batch embedding.
+			contents := make([]string, 0, len(inputs))
 			for i := range inputs {
-				contents[i] = inputs[i].Content
+				if !textDup[i] {
+					contents = append(contents, embedder.DocumentText(models.MemoryType(inputs[i].Type), inputs[i].Content))
+				}
 			}
 
-			vectors, embedErr := emb.EmbedBatch(ctx, contents)
+			embedded, embedErr := emb.EmbedBatch(ctx, contents)
 			if embedErr != nil {
 				return cmdErr("store-batch: embedding batch", embedErr)
 			}
 
-			if len(vectors) != len(inputs) {
+			if len(embedded) != len(contents) {
 				return fmt.Errorf("store-batch: embedding returned %d vectors for %d inputs",
-					len(vectors), len(inputs))
+					len(embedded), len(contents))
+			}
+
+			vectors := make([][]float32, len(inputs))
+			for i, j := 0, 0; i < len(inputs); i++ {
+				if !textDup[i] {
+					vectors[i] = embedded[j]
+					j++
+				}
 			}
 
 			// Process each memory: dedup check then upsert.
-			results := make([]batchStoreResult, len(inputs))
 			now := time.Now().UTC()
 
 			// Resolve effective dedup threshold once (only needed when dedup is active).
 			var effectiveThreshold float64
 			if !skipDedup {
-				effectiveThreshold = cfg.Memory.DedupThreshold
-				if cmd.Flags().Changed("dedup-threshold") {
-					if err := store.ValidateDedupThreshold(dedupThreshold); err != nil {
-						return fmt.Errorf("store-batch: --dedup-threshold: %w", err)
-					}
-					effectiveThreshold = dedupThreshold
+				var thresholdErr error
+				effectiveThreshold, thresholdErr = dedupThresholdFromFlag(cmd, "store-batch", dedupThreshold)
+				if thresholdErr != nil {
+					return thresholdErr
 				}
 			}
 
+			typeTTL := typeTTLFromConfig(logger)
 			for i := range inputs {
+				if textDup[i] {
+					continue
+				}
 				inp := &inputs[i]
 				vec := vectors[i]
 
 				// Store-time dedup: check for near-identical memories.
 				// Bypassed per-entry when --skip-dedup is set.
 				if !skipDedup {
-					dedupRes, dedupErr := store.CheckAndHandleDuplicate(ctx, st, vec, inp.Content, effectiveThreshold)
+					dedupRes, dedupErr := store.CheckAndHandleDuplicateInScope(ctx, st, vec, inp.Content, effectiveThreshold,
+						models.MemoryType(inp.Type), dedupScopeFromConfig(cfg.Memory.DedupScope))
 					if dedupErr != nil {
 						// Dedup is an optimisation, not a correctness gate — fail open
 						// so a transient Memgraph hiccup does not block all stores.
@@ -199,6 +233,8 @@ Output is a JSON array of results with id and status ("created", "duplicate", "u
 					UpdatedAt:    now,
 					LastAccessed: now,
 				}
+				typeTTL.Apply(&mem)
+				normalizeMemoryTags(&mem)
 
 				if upsertErr := st.Upsert(ctx, mem, vec); upsertErr != nil {
 					results[i] = batchStoreResult{
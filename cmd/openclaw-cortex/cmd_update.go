@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 )
 
@@ -16,7 +17,9 @@ func updateCmd() *cobra.Command {
 		content    string
 		memType    string
 		tags       string
+		boost      float64
 		outputJSON bool
+		appendMode bool
 	)
 
 	cmd := &cobra.Command{
@@ -26,15 +29,24 @@ func updateCmd() *cobra.Command {
 
 The old memory remains in the store for history. The new memory carries forward
 access_count and reinforced_count from the original, and sets supersedes_id to
-link back to it. Superseded memories are automatically demoted during recall.`,
+link back to it. Superseded memories are automatically demoted during recall.
+
+With --append, --content is added to the end of the existing content (on a new
+line) instead of replacing it. --boost alone re-versions the memory with a new
+recall score multiplier and the same content.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			logger := newLogger()
 			ctx := cmd.Context()
 			oldID := args[0]
 
-			if !cmd.Flags().Changed("content") {
-				return fmt.Errorf("update: --content is required")
+			if !cmd.Flags().Changed("content") && !cmd.Flags().Changed("boost") {
+				return fmt.Errorf("update: --content or --boost is required")
+			}
+			if cmd.Flags().Changed("boost") {
+				if err := validateBoost("update", boost); err != nil {
+					return err
+				}
 			}
 
 			emb := newEmbedder(logger)
@@ -50,29 +62,16 @@ link back to it. Superseded memories are automatically demoted during recall.`,
 				return cmdErr("update: fetching memory", getErr)
 			}
 
-			// Build new memory, carrying forward fields from old.
-			now := time.Now().UTC()
-			newMem := models.Memory{
-				ID:              uuid.New().String(),
-				Type:            old.Type,
-				Scope:           old.Scope,
-				Visibility:      old.Visibility,
-				Content:         content,
-				Confidence:      old.Confidence,
-				Source:          old.Source,
-				Tags:            old.Tags,
-				Project:         old.Project,
-				TTLSeconds:      old.TTLSeconds,
-				CreatedAt:       now,
-				UpdatedAt:       now,
-				LastAccessed:    now,
-				AccessCount:     old.AccessCount,
-				ReinforcedCount: old.ReinforcedCount,
-				SupersedesID:    oldID,
-				ValidUntil:      old.ValidUntil,
-				Metadata:        old.Metadata,
+			switch {
+			case !cmd.Flags().Changed("content"):
+				content = old.Content
+			case appendMode:
+				content = models.AppendContent(old.Content, content)
 			}
 
+			// Build new memory, carrying forward fields from old.
+			newMem := nextVersion(old, uuid.New().String(), content, time.Now().UTC())
+
 			// Apply optional overrides.
 			if cmd.Flags().Changed("type") {
 				mt := models.MemoryType(memType)
@@ -83,6 +82,10 @@ link back to it. Superseded memories are automatically demoted during recall.`,
 				newMem.Type = mt
 			}
 
+			if cmd.Flags().Changed("boost") {
+				newMem.Boost = boost
+			}
+
 			if cmd.Flags().Changed("tags") {
 				if tags != "" {
 					newMem.Tags = parseTags(tags)
@@ -92,7 +95,7 @@ link back to it. Superseded memories are automatically demoted during recall.`,
 			}
 
 			// Embed new content.
-			vec, embedErr := emb.Embed(ctx, content)
+			vec, embedErr := emb.Embed(ctx, embedder.DocumentText(newMem.Type, content))
 			if embedErr != nil {
 				return cmdErr("update: embedding new content", embedErr)
 			}
@@ -120,9 +123,19 @@ link back to it. Superseded memories are automatically demoted during recall.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&content, "content", "", "new content for the memory (required)")
+	cmd.Flags().StringVar(&content, "content", "", "new content for the memory (required unless --boost is set)")
 	cmd.Flags().StringVar(&memType, "type", "", "memory type (rule|fact|episode|procedure|preference)")
 	cmd.Flags().StringVar(&tags, "tags", "", "comma-separated tags (replaces existing tags)")
+	cmd.Flags().Float64Var(&boost, "boost", models.DefaultBoost, "recall score multiplier for promoting authoritative memories (0 < boost <= 10)")
 	cmd.Flags().BoolVar(&outputJSON, "json", false, "output the new memory as JSON")
+	cmd.Flags().BoolVar(&appendMode, "append", false, "append --content to the existing content instead of replacing it")
 	return cmd
 }
+
+// nextVersion returns the memory that replaces old with the given ID and
+// content (see models.Memory.Successor).
+func nextVersion(old *models.Memory, id, content string, now time.Time) models.Memory {
+	next := old.Successor(id, now)
+	next.Content = content
+	return next
+}
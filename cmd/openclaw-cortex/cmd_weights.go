@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+)
+
+func weightsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "weights",
+		Short: "Inspect and tune recall ranking weights",
+	}
+
+	cmd.AddCommand(weightsTuneCmd())
+
+	return cmd
+}
+
+func weightsTuneCmd() *cobra.Command {
+	var (
+		from       string
+		topK       int
+		outputJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tune",
+		Short: "Optimize recall weights against a recall feedback log",
+		Long: `Search the recall weight space for the weights that best rank the memories
+marked useful in a recall feedback log (see recall.feedback_log).
+
+Starting from the configured recall.weights, each weight is nudged up and down
+in turn (keeping the sum at 1.0) and kept when it puts a useful memory in the
+top K for more recalls. The best weights are printed as a config snippet.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				return fmt.Errorf("weights tune: --from is required")
+			}
+			if topK <= 0 {
+				return fmt.Errorf("weights tune: --top-k must be > 0")
+			}
+
+			f, err := os.Open(from)
+			if err != nil {
+				return cmdErr("weights tune: opening feedback log", err)
+			}
+			defer func() { _ = f.Close() }()
+
+			examples, err := recall.LoadTuneExamples(f)
+			if err != nil {
+				return cmdErr("weights tune", err)
+			}
+			if len(examples) == 0 {
+				return fmt.Errorf("weights tune: %s has no recalls with useful feedback", from)
+			}
+
+			res := recall.Tune(recallWeightsFromConfig(cfg.Recall.Weights), examples, topK)
+
+			if outputJSON {
+				out, marshalErr := json.MarshalIndent(res, "", "  ")
+				if marshalErr != nil {
+					return cmdErr("weights tune: marshaling JSON", marshalErr)
+				}
+				fmt.Println(string(out))
+				return nil
+			}
+
+			fmt.Printf("Examples: %d\n", res.Examples)
+			fmt.Printf("Useful in top %d: %.1f%% -> %.1f%%\n", topK, res.Baseline.HitRate*100, res.Best.HitRate*100)
+			fmt.Printf("MRR:               %.3f -> %.3f\n\n", res.Baseline.MRR, res.Best.MRR)
+			fmt.Println("recall:")
+			fmt.Println("  weights:")
+			names, values := recall.WeightNames(), res.Weights.Values()
+			for i := range names {
+				fmt.Printf("    %-16s %.3f\n", names[i]+":", values[i])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "recall feedback log (JSONL) to tune against")
+	cmd.Flags().IntVar(&topK, "top-k", 5, "rank cutoff a useful memory must reach to count as a hit")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "output as JSON")
+	return cmd
+}
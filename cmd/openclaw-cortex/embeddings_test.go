@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVectorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		vec  []float32
+		want string
+	}{
+		{"healthy", []float32{0.6, 0.8, 0}, ""},
+		{"missing", nil, vectorMissing},
+		{"all zeros", []float32{0, 0, 0}, vectorZero},
+		{"NaN", []float32{0.1, float32(math.NaN()), 0.2}, vectorNonFinite},
+		{"Inf", []float32{float32(math.Inf(1)), 0, 0}, vectorNonFinite},
+		{"wrong dimension", []float32{0.6, 0.8}, vectorWrongDim},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vectorReason(tt.vec, 3); got != tt.want {
+				t.Errorf("vectorReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVectorStats(t *testing.T) {
+	s := newVectorStats(2)
+	s.add("a", []float32{3, 4}) // norm 5
+	s.add("b", []float32{0, 1}) // norm 1
+	s.add("c", []float32{0, 3}) // norm 3
+	s.add("zero", []float32{0, 0})
+	s.add("missing", nil)
+	s.finish()
+
+	if s.Scanned != 5 || s.Healthy != 3 {
+		t.Fatalf("scanned %d healthy %d, want 5 and 3", s.Scanned, s.Healthy)
+	}
+	if s.Broken[vectorZero] != 1 || s.Broken[vectorMissing] != 1 {
+		t.Errorf("broken = %v, want one zero and one missing", s.Broken)
+	}
+	if len(s.Flagged) != 2 || s.Flagged[0].ID != "zero" || s.Flagged[1].ID != "missing" {
+		t.Errorf("flagged = %+v", s.Flagged)
+	}
+	if s.NormMin != 1 || s.NormMax != 5 || s.NormMedian != 3 || s.NormMean != 3 {
+		t.Errorf("norms min %v median %v mean %v max %v, want 1 3 3 5", s.NormMin, s.NormMedian, s.NormMean, s.NormMax)
+	}
+	if s.Dimensions[2] != 4 {
+		t.Errorf("dimensions = %v, want 4 vectors of dimension 2", s.Dimensions)
+	}
+}
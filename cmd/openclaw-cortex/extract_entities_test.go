@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestForEachMemory(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMockStore()
+	vec := make([]float32, 8)
+	for i := range 5 {
+		mem := models.Memory{
+			ID:      fmt.Sprintf("m%d", i),
+			Type:    models.MemoryTypeFact,
+			Scope:   models.ScopePermanent,
+			Content: "memory",
+		}
+		if i%2 == 0 {
+			mem.Type = models.MemoryTypeRule
+		}
+		if err := st.Upsert(ctx, mem, vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect := func(ids []string, filters *store.SearchFilters, limit int) []string {
+		t.Helper()
+		var got []string
+		err := forEachMemory(ctx, st, ids, filters, 2, limit, func(m *models.Memory) error {
+			got = append(got, m.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("forEachMemory: %v", err)
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	if got := collect(nil, nil, 0); len(got) != 5 {
+		t.Errorf("all memories: got %v, want 5 across pages", got)
+	}
+	if got := collect(nil, nil, 3); len(got) != 3 {
+		t.Errorf("limit 3: got %v", got)
+	}
+	rule := models.MemoryTypeRule
+	if got := collect(nil, &store.SearchFilters{Type: &rule}, 0); !slices.Equal(got, []string{"m0", "m2", "m4"}) {
+		t.Errorf("type filter: got %v", got)
+	}
+	if got := collect([]string{"m3", "m1"}, nil, 0); !slices.Equal(got, []string{"m1", "m3"}) {
+		t.Errorf("explicit IDs: got %v", got)
+	}
+
+	err := forEachMemory(ctx, st, []string{"missing"}, nil, 2, 0, func(*models.Memory) error { return nil })
+	if err == nil {
+		t.Error("expected an error for an unknown memory ID")
+	}
+}
@@ -4,17 +4,26 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/spf13/cobra"
+
 	"github.com/ajitpratap0/openclaw-cortex/internal/async"
+	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/config"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 	"github.com/ajitpratap0/openclaw-cortex/internal/sentry"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/internal/timeutil"
 )
 
 // asyncQueue is the global async work queue. Initialized in initAsyncQueue,
@@ -49,13 +58,179 @@ func recallWeightsFromConfig(c config.RecallWeightsConfig) recall.Weights {
 	}
 }
 
+// newRecaller creates a Recaller with the configured weights, write recency
+// and query type boosts, query synonyms and supersession collapsing.
+func newRecaller(logger *slog.Logger) *recall.Recaller {
+	rec := recall.NewRecaller(recallWeightsFromConfig(cfg.Recall.Weights), logger)
+	rec.SetWriteRecencyBoost(cfg.Recall.WriteRecencyBoost)
+	rec.SetQueryTypeBoost(cfg.Recall.QueryTypeBoost)
+	rec.SetSynonyms(cfg.Recall.Synonyms)
+	rec.SetCollapseSupersessions(cfg.Recall.CollapseSupersessions)
+	return rec
+}
+
+// dedupScopeFromConfig converts the configured dedup comparison window into
+// the store package's DedupScope.
+func dedupScopeFromConfig(c config.DedupScopeConfig) store.DedupScope {
+	return store.DedupScope{
+		SameType: c.SameType,
+		Window:   time.Duration(c.WindowDays) * 24 * time.Hour,
+	}
+}
+
+// dedupThresholdFromFlag returns the cosine dedup threshold for a command:
+// the --dedup-threshold flag when it was set, memory.dedup_threshold
+// otherwise. cmdName prefixes the validation error.
+func dedupThresholdFromFlag(cmd *cobra.Command, cmdName string, flagVal float64) (float64, error) {
+	if !cmd.Flags().Changed("dedup-threshold") {
+		return cfg.Memory.DedupThreshold, nil
+	}
+	if err := store.ValidateDedupThreshold(flagVal); err != nil {
+		return 0, fmt.Errorf("%s: --dedup-threshold: %w", cmdName, err)
+	}
+	return flagVal, nil
+}
+
+// captureTemplateFromConfig returns the custom capture extraction prompt from
+// capture_quality.prompt_template or prompt_template_file, or nil to use the
+// built-in prompt.
+func captureTemplateFromConfig() (*capture.ExtractionTemplate, error) {
+	text := cfg.CaptureQuality.PromptTemplate
+	if path := cfg.CaptureQuality.PromptTemplateFile; path != "" {
+		data, err := os.ReadFile(path) //nolint:gosec // path is supplied by the operator
+		if err != nil {
+			return nil, fmt.Errorf("reading capture_quality.prompt_template_file: %w", err)
+		}
+		text = string(data)
+	}
+	return capture.NewExtractionTemplate(text)
+}
+
+// conflictDetectorFromConfig returns the store-time conflict detector when
+// memory.conflict_check is enabled, or nil when it is disabled or no LLM is
+// configured.
+func conflictDetectorFromConfig(logger *slog.Logger) *capture.ConflictDetector {
+	if !cfg.Memory.ConflictCheck {
+		return nil
+	}
+	llmClient := llm.NewClient(cfg.Claude)
+	if llmClient == nil {
+		logger.Warn("memory.conflict_check is enabled but no LLM is configured; conflict check disabled")
+		return nil
+	}
+	return capture.NewConflictDetector(llmClient, cfg.Claude.Model, logger)
+}
+
+// recallConflictCheckerFromConfig returns the recall-time contradiction
+// checker when recall.conflict_check is enabled, or nil when it is disabled
+// or no LLM is configured.
+func recallConflictCheckerFromConfig(logger *slog.Logger) *capture.ConflictDetector {
+	if !cfg.Recall.ConflictCheck {
+		return nil
+	}
+	llmClient := llm.NewClient(cfg.Claude)
+	if llmClient == nil {
+		logger.Warn("recall.conflict_check is enabled but no LLM is configured; recall conflict check disabled")
+		return nil
+	}
+	return capture.NewConflictDetector(llmClient, cfg.Claude.Model, logger)
+}
+
+// freshnessFromConfig returns the recall freshness buckets from
+// recall.freshness.
+func freshnessFromConfig() recall.Freshness {
+	fc := cfg.Recall.Freshness
+	return recall.NewFreshness(fc.RecentDays, fc.EstablishedDays, fc.EstablishedAccessDays)
+}
+
+// typeTTLFromConfig returns the per-type default TTLs from memory.type_ttl,
+// or nil when none are configured. The values were checked by
+// config.Validate, so a parse failure here is only logged.
+func typeTTLFromConfig(logger *slog.Logger) *lifecycle.TypeTTL {
+	ttl, err := lifecycle.ParseTypeTTL(cfg.Memory.TypeTTL, cfg.Memory.TypeRetention)
+	if err != nil {
+		logger.Warn("ignoring invalid memory.type_ttl", "error", err)
+		return nil
+	}
+	return ttl
+}
+
+// maxPerScopeFromConfig converts memory.max_per_scope to the per-scope caps
+// taken by lifecycle.Manager. Scope names were checked by config.Validate.
+func maxPerScopeFromConfig() map[models.MemoryScope]int {
+	if len(cfg.Memory.MaxPerScope) == 0 {
+		return nil
+	}
+	caps := make(map[models.MemoryScope]int, len(cfg.Memory.MaxPerScope))
+	for name, n := range cfg.Memory.MaxPerScope {
+		caps[models.MemoryScope(name)] = n
+	}
+	return caps
+}
+
+// newLifecycleManager returns a lifecycle.Manager configured from the
+// memory and lifecycle config sections.
+func newLifecycleManager(st store.Store, emb embedder.Embedder, logger *slog.Logger) *lifecycle.Manager {
+	lm := lifecycle.NewManager(st, emb, logger)
+	lm.SetTrashRetention(time.Duration(cfg.Memory.TrashRetentionHours) * time.Hour)
+	lm.SetScanLimits(cfg.Lifecycle.MaxScan, cfg.Lifecycle.PageSize)
+	lm.SetTypeRetention(cfg.Memory.TypeRetention)
+	lm.SetConsolidationPartition(lifecycle.ConsolidationPartition(cfg.Lifecycle.ConsolidationPartition))
+	lm.SetConsolidationExcludeTags(cfg.Lifecycle.ConsolidationExcludeTags)
+	lm.SetSessionDecay(time.Duration(cfg.Lifecycle.SessionDecayHours * float64(time.Hour)))
+	lm.SetConsolidationThreshold(cfg.Lifecycle.ConsolidationThreshold)
+	if cfg.Lifecycle.MergeMode == lifecycle.MergeModeLLM {
+		if client := llm.NewClient(cfg.Claude); client != nil {
+			lm.SetMerger(lifecycle.NewMerger(client, cfg.Claude.Model, logger))
+		} else {
+			logger.Warn("lifecycle.merge_mode is llm but no Claude credentials are configured; consolidation will delete duplicates")
+		}
+	}
+	lm.SetMaxPerScope(maxPerScopeFromConfig())
+	return lm
+}
+
+// newSynthesizer returns a recall Synthesizer backed by the configured Claude
+// client, or nil when no Claude credentials are configured.
+func newSynthesizer(logger *slog.Logger) *recall.Synthesizer {
+	client := llm.NewClient(cfg.Claude)
+	if client == nil {
+		return nil
+	}
+	return recall.NewSynthesizer(client, cfg.Claude.Model, logger)
+}
+
+// newRelevanceFilter returns the recall RelevanceFilter when recall.llm_filter
+// is enabled and Claude credentials are configured, or nil otherwise.
+func newRelevanceFilter(logger *slog.Logger) *recall.RelevanceFilter {
+	if !cfg.Recall.LLMFilter {
+		return nil
+	}
+	client := llm.NewClient(cfg.Claude)
+	if client == nil {
+		logger.Warn("recall.llm_filter is enabled but no Claude credentials are configured; relevance filter disabled")
+		return nil
+	}
+	f := recall.NewRelevanceFilter(client, cfg.Claude.Model, logger)
+	f.SetJudged(cfg.Recall.LLMFilterCandidates)
+	return f
+}
+
 // buildSearchFilters constructs a SearchFilters from optional CLI flag values.
-// Returns nil if all inputs are empty.
-func buildSearchFilters(cmdName, memType, memScope, project, tagsFlag string) (*store.SearchFilters, error) {
-	if memType == "" && memScope == "" && project == "" && tagsFlag == "" {
-		return nil, nil
+// projectsFlag is a comma-separated project list merged with project (see
+// store.ProjectFilter). Returns nil if all inputs are empty.
+func buildSearchFilters(cmdName, memType, memScope, project, projectsFlag, tagsFlag string) (*store.SearchFilters, error) {
+	var projects []string
+	if projectsFlag != "" {
+		projects = parseTags(projectsFlag)
+	}
+	filters := store.ProjectFilter(project, projects)
+	if memType == "" && memScope == "" && tagsFlag == "" {
+		return filters, nil
+	}
+	if filters == nil {
+		filters = &store.SearchFilters{}
 	}
-	filters := &store.SearchFilters{}
 	if memType != "" {
 		mt := models.MemoryType(memType)
 		if !mt.IsValid() {
@@ -70,15 +245,32 @@ func buildSearchFilters(cmdName, memType, memScope, project, tagsFlag string) (*
 		}
 		filters.Scope = &ms
 	}
-	if project != "" {
-		filters.Project = &project
-	}
 	if tagsFlag != "" {
 		filters.Tags = parseTags(tagsFlag)
 	}
 	return filters, nil
 }
 
+// applyCreatedRange parses the --since and --until flags of cmdName into
+// filters.CreatedAfter and filters.CreatedBefore. Empty flags are skipped.
+func applyCreatedRange(cmdName string, filters *store.SearchFilters, since, until string) error {
+	if since != "" {
+		t, err := timeutil.ParseTimeFlag(cmdName, "--since", since, false)
+		if err != nil {
+			return err
+		}
+		filters.CreatedAfter = &t
+	}
+	if until != "" {
+		t, err := timeutil.ParseTimeFlag(cmdName, "--until", until, true)
+		if err != nil {
+			return err
+		}
+		filters.CreatedBefore = &t
+	}
+	return nil
+}
+
 // parseTags splits a comma-separated tags string into trimmed individual tags.
 func parseTags(tagsStr string) []string {
 	parts := strings.Split(tagsStr, ",")
@@ -88,6 +280,38 @@ func parseTags(tagsStr string) []string {
 	return parts
 }
 
+// tagNormalizerFromConfig returns the tag normalizer configured by
+// memory.normalize_tags and memory.tag_synonyms, or nil when disabled.
+func tagNormalizerFromConfig() *store.TagNormalizer {
+	if !cfg.Memory.NormalizeTags {
+		return nil
+	}
+	return store.NewTagNormalizer(cfg.Memory.TagSynonyms)
+}
+
+// normalizeMemoryTags applies cfg.Memory tag normalization to m in place when
+// enabled (see store.TagNormalizer).
+func normalizeMemoryTags(m *models.Memory) {
+	tagNormalizerFromConfig().Apply(m)
+}
+
+// warmupEmbedder preloads the embedding model in the background when
+// cfg.Embedder.Warmup is enabled, so the first real query does not pay the
+// provider's lazy model-load cost. Failures are logged and otherwise ignored.
+func warmupEmbedder(ctx context.Context, emb embedder.Embedder, logger *slog.Logger) {
+	if !cfg.Embedder.Warmup || emb == nil {
+		return
+	}
+	go func() {
+		took, err := embedder.Warmup(ctx, emb)
+		if err != nil {
+			logger.Warn("embedder warmup failed; first query may be slow", "error", err)
+			return
+		}
+		logger.Info("embedder warmed up", "duration_ms", took.Milliseconds())
+	}()
+}
+
 // initAsyncQueue creates and starts the async graph pipeline pool.
 // Returns (nil, nil, nil) when cfg.Async.Disabled is true.
 // The caller is responsible for calling pool.Shutdown(ctx) when done, and then
@@ -132,3 +356,27 @@ func initAsyncQueue(ctx context.Context, c *config.Config, logger *slog.Logger)
 	asyncQueue = q
 	return pool, st.Close, nil
 }
+
+// isLoopbackURI reports whether rawURI points at localhost or a loopback
+// address. Unencrypted connections to such hosts are normal in development and
+// do not warrant a TLS warning.
+func isLoopbackURI(rawURI string) bool {
+	u, err := url.Parse(rawURI)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validateBoost checks a --boost flag value against (0, models.MaxBoost].
+func validateBoost(cmdName string, boost float64) error {
+	if boost <= 0 || boost > models.MaxBoost {
+		return fmt.Errorf("%s: --boost must be in (0, %g], got %g", cmdName, models.MaxBoost, boost)
+	}
+	return nil
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/config"
 )
 
 func TestLastHumanMessageFromTranscript(t *testing.T) {
@@ -49,3 +55,32 @@ func TestLastNTurnsFromTranscript(t *testing.T) {
 	assert.Nil(t, lastNTurnsFromTranscript("", 3))
 	assert.Nil(t, lastNTurnsFromTranscript(path, 0))
 }
+
+func TestHookPreDiagnostic(t *testing.T) {
+	ctx := context.Background()
+	assert.Contains(t, hookPreDiagnostic(ctx, nil, time.Second), "no relevant memories")
+
+	embedErr := fmt.Errorf("embedding message: %w", errors.New("connection refused"))
+	msg := hookPreDiagnostic(ctx, embedErr, time.Second)
+	assert.Contains(t, msg, "recall failed")
+	assert.Contains(t, msg, "connection refused")
+
+	timeoutErr := fmt.Errorf("embedding message: %w", context.DeadlineExceeded)
+	assert.Contains(t, hookPreDiagnostic(ctx, timeoutErr, 5*time.Second), "timed out after 5s")
+
+	expired, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	<-expired.Done()
+	assert.Contains(t, hookPreDiagnostic(expired, embedErr, time.Second), "timed out",
+		"an expired hook context counts as a timeout even when the error hides it")
+}
+
+func TestHookTimeout(t *testing.T) {
+	orig := cfg
+	t.Cleanup(func() { cfg = orig })
+
+	cfg = &config.Config{}
+	assert.Equal(t, defaultHookTimeout, hookTimeout())
+	cfg.Hooks.TimeoutSeconds = 90
+	assert.Equal(t, 90*time.Second, hookTimeout())
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// importedConfidence is assigned to memories imported from systems that do
+// not record a confidence.
+const importedConfidence = 0.8
+
+// importAdapters maps the foreign --format values of the import command to
+// their record mappers. See docs/import-formats.md for the field mappings.
+var importAdapters = map[string]struct {
+	wrappers []string // object keys whose array values hold records
+	mapFn    func(*importMapper, string, json.RawMessage) (models.Memory, bool)
+}{
+	"mem0":      {wrappers: []string{"results", "memories"}, mapFn: (*importMapper).mem0},
+	"zep":       {wrappers: []string{"messages", "facts", "relevant_facts"}, mapFn: (*importMapper).zep},
+	"langchain": {wrappers: []string{"messages", "documents"}, mapFn: (*importMapper).langchain},
+}
+
+// importMapper converts one foreign export into memories, counting the
+// records it had to skip or repair.
+type importMapper struct {
+	source   string
+	cls      classifier.Classifier
+	logger   *slog.Logger
+	record   int // 1-based index of the record being mapped, for log lines
+	skipped  int
+	repaired int
+}
+
+// mapForeignExport decodes r as a format export and maps every record onto a
+// models.Memory. Records that cannot be mapped are skipped; fields that are
+// malformed are repaired with defaults. Both are logged.
+func mapForeignExport(r io.Reader, format string, cls classifier.Classifier, logger *slog.Logger) ([]models.Memory, *importMapper, error) {
+	adapter, ok := importAdapters[format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported format %q", format)
+	}
+	records, err := decodeImportRecords(r, adapter.wrappers)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := &importMapper{source: format, cls: cls, logger: logger}
+	memories := make([]models.Memory, 0, len(records))
+	for i := range records {
+		m.record = i + 1
+		mem, keep := adapter.mapFn(m, records[i].wrapper, records[i].raw)
+		if !keep {
+			m.skipped++
+			continue
+		}
+		// Sources rarely track updates or access; start both at creation.
+		if mem.UpdatedAt.IsZero() {
+			mem.UpdatedAt = mem.CreatedAt
+		}
+		if mem.LastAccessed.IsZero() {
+			mem.LastAccessed = mem.UpdatedAt
+		}
+		memories = append(memories, mem)
+	}
+	return memories, m, nil
+}
+
+// importRecord is one raw export record and the wrapper key it was found
+// under ("" for top-level records).
+type importRecord struct {
+	wrapper string
+	raw     json.RawMessage
+}
+
+// decodeImportRecords accepts a JSON array, a JSON object, or a stream of
+// JSON values (JSONL). Arrays are flattened; objects with any of the wrapper
+// keys contribute the arrays under those keys; other objects are records.
+func decodeImportRecords(r io.Reader, wrappers []string) ([]importRecord, error) {
+	dec := json.NewDecoder(r)
+	var out []importRecord
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("decoding export: %w", err)
+		}
+		recs, err := expandImportValue(raw, wrappers, "")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, recs...)
+	}
+}
+
+func expandImportValue(raw json.RawMessage, wrappers []string, wrapper string) ([]importRecord, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fmt.Errorf("decoding export array: %w", err)
+		}
+		out := make([]importRecord, 0, len(items))
+		for i := range items {
+			out = append(out, importRecord{wrapper: wrapper, raw: items[i]})
+		}
+		return out, nil
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &obj); err != nil {
+			return nil, fmt.Errorf("decoding export object: %w", err)
+		}
+		var out []importRecord
+		wrapped := false
+		for _, key := range wrappers {
+			inner, ok := obj[key]
+			if !ok || !bytes.HasPrefix(bytes.TrimSpace(inner), []byte("[")) {
+				continue
+			}
+			wrapped = true
+			recs, err := expandImportValue(inner, nil, key)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, recs...)
+		}
+		if !wrapped {
+			out = append(out, importRecord{wrapper: wrapper, raw: trimmed})
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("export must contain JSON objects or arrays")
+	}
+}
+
+// newMemory returns a memory with the defaults shared by all adapters.
+func (m *importMapper) newMemory(content string) models.Memory {
+	return models.Memory{
+		ID:         uuid.New().String(),
+		Type:       m.cls.Classify(content),
+		Scope:      models.ScopePermanent,
+		Visibility: models.VisibilityShared,
+		Content:    content,
+		Confidence: importedConfidence,
+		Source:     "import:" + m.source,
+		Metadata:   map[string]any{},
+	}
+}
+
+// skip logs why the current record was dropped.
+func (m *importMapper) skip(reason string) (models.Memory, bool) {
+	m.logger.Warn("import: skipping record", "format", m.source, "record", m.record, "reason", reason)
+	return models.Memory{}, false
+}
+
+// repair logs that a field of the current record was replaced by a default.
+func (m *importMapper) repair(field, reason string) {
+	m.repaired++
+	m.logger.Warn("import: repaired record", "format", m.source, "record", m.record, "field", field, "reason", reason)
+}
+
+// setID keeps the source ID when it is a UUID, so re-imports upsert the same
+// memory, and records it in metadata either way.
+func (m *importMapper) setID(mem *models.Memory, id string) {
+	if id == "" {
+		return
+	}
+	mem.Metadata["source_id"] = id
+	if _, err := uuid.Parse(id); err == nil {
+		mem.ID = id
+	}
+}
+
+// importTimeLayouts are the timestamp layouts seen in foreign exports,
+// including Python's isoformat() with and without a zone.
+var importTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTime parses a foreign timestamp. An empty value returns the zero time;
+// an unparsable one is repaired to the zero time so the import back-fills it.
+func (m *importMapper) parseTime(field, v string) time.Time {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}
+	}
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC()
+		}
+	}
+	m.repair(field, fmt.Sprintf("unparsable timestamp %q", v))
+	return time.Time{}
+}
+
+// setConfidence applies c when it is a valid confidence.
+func (m *importMapper) setConfidence(mem *models.Memory, field string, c *float64) {
+	if c == nil {
+		return
+	}
+	if *c < 0 || *c > 1 {
+		m.repair(field, fmt.Sprintf("confidence %v outside [0, 1]", *c))
+		return
+	}
+	mem.Confidence = *c
+}
+
+// mergeMetadata copies src into mem.Metadata without overwriting keys the
+// adapter already set.
+func mergeMetadata(mem *models.Memory, src map[string]any) {
+	for k, v := range src {
+		if _, exists := mem.Metadata[k]; !exists {
+			mem.Metadata[k] = v
+		}
+	}
+}
+
+// mem0Record is one memory from Mem0's get_all / export API.
+type mem0Record struct {
+	ID         string         `json:"id"`
+	Memory     string         `json:"memory"`
+	Text       string         `json:"text"` // older exports
+	Metadata   map[string]any `json:"metadata"`
+	Categories []string       `json:"categories"`
+	CreatedAt  string         `json:"created_at"`
+	UpdatedAt  string         `json:"updated_at"`
+	UserID     string         `json:"user_id"`
+	AgentID    string         `json:"agent_id"`
+	AppID      string         `json:"app_id"`
+	RunID      string         `json:"run_id"`
+}
+
+func (m *importMapper) mem0(_ string, raw json.RawMessage) (models.Memory, bool) {
+	var rec mem0Record
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return m.skip("not a Mem0 memory object: " + err.Error())
+	}
+	content := strings.TrimSpace(rec.Memory)
+	if content == "" {
+		content = strings.TrimSpace(rec.Text)
+	}
+	if content == "" {
+		return m.skip("empty memory text")
+	}
+	mem := m.newMemory(content)
+	m.setID(&mem, rec.ID)
+	mem.Tags = rec.Categories
+	mem.UserID = rec.UserID
+	mem.Project = rec.AppID
+	mem.CreatedAt = m.parseTime("created_at", rec.CreatedAt)
+	mem.UpdatedAt = m.parseTime("updated_at", rec.UpdatedAt)
+	if rec.AgentID != "" {
+		mem.Metadata["agent_id"] = rec.AgentID
+	}
+	if rec.RunID != "" {
+		mem.Metadata["run_id"] = rec.RunID
+	}
+	mergeMetadata(&mem, rec.Metadata)
+	return mem, true
+}
+
+// zepRecord is a Zep message or fact. Facts carry Fact and the validity
+// window; messages carry Content and a role.
+type zepRecord struct {
+	UUID      string         `json:"uuid"`
+	Role      string         `json:"role"`
+	RoleType  string         `json:"role_type"`
+	Content   string         `json:"content"`
+	Fact      string         `json:"fact"`
+	Metadata  map[string]any `json:"metadata"`
+	CreatedAt string         `json:"created_at"`
+	ValidAt   string         `json:"valid_at"`
+	InvalidAt string         `json:"invalid_at"`
+	Rating    *float64       `json:"rating"`
+}
+
+func (m *importMapper) zep(wrapper string, raw json.RawMessage) (models.Memory, bool) {
+	var rec zepRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return m.skip("not a Zep message or fact: " + err.Error())
+	}
+	isFact := rec.Fact != "" || wrapper == "facts" || wrapper == "relevant_facts"
+	content := strings.TrimSpace(rec.Fact)
+	if content == "" {
+		content = strings.TrimSpace(rec.Content)
+	}
+	if content == "" {
+		return m.skip("empty content")
+	}
+	mem := m.newMemory(content)
+	m.setID(&mem, rec.UUID)
+	mem.CreatedAt = m.parseTime("created_at", rec.CreatedAt)
+	if isFact {
+		mem.Type = models.MemoryTypeFact
+		mem.ValidFrom = m.parseTime("valid_at", rec.ValidAt)
+		mem.ValidUntil = m.parseTime("invalid_at", rec.InvalidAt)
+		m.setConfidence(&mem, "rating", rec.Rating)
+	} else {
+		// Messages keep the default permanent scope: with their historical
+		// timestamps, session decay would delete them on the next lifecycle
+		// run.
+		if rec.RoleType != "" {
+			mem.Metadata["role"] = rec.RoleType
+		}
+		if rec.Role != "" {
+			mem.Metadata["role_name"] = rec.Role
+		}
+	}
+	mergeMetadata(&mem, rec.Metadata)
+	return mem, true
+}
+
+// langchainRecord covers the LangChain shapes that carry memory text:
+// Document ({"page_content", "metadata"}), messages_to_dict output
+// ({"type", "data": {"content"}}) and lc-serialized objects
+// ({"lc": 1, "id": [...], "kwargs": {...}}).
+type langchainRecord struct {
+	Type        string          `json:"type"`
+	PageContent *string         `json:"page_content"`
+	Metadata    map[string]any  `json:"metadata"`
+	Data        *langchainData  `json:"data"`
+	LC          int             `json:"lc"`
+	ID          []string        `json:"id"`
+	Kwargs      json.RawMessage `json:"kwargs"`
+}
+
+type langchainData struct {
+	Content json.RawMessage `json:"content"`
+	Type    string          `json:"type"`
+	ID      string          `json:"id"`
+}
+
+type langchainKwargs struct {
+	PageContent *string         `json:"page_content"`
+	Content     json.RawMessage `json:"content"`
+	Metadata    map[string]any  `json:"metadata"`
+	ID          string          `json:"id"`
+}
+
+func (m *importMapper) langchain(_ string, raw json.RawMessage) (models.Memory, bool) {
+	var rec langchainRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return m.skip("not a LangChain document or message: " + err.Error())
+	}
+
+	var (
+		content  string
+		role     string
+		id       string
+		metadata = rec.Metadata
+		ok       = true
+	)
+	switch {
+	case rec.PageContent != nil:
+		content = *rec.PageContent
+	case rec.Data != nil:
+		content, ok = langchainText(rec.Data.Content)
+		role, id = rec.Type, rec.Data.ID
+	case rec.LC > 0 && len(rec.Kwargs) > 0:
+		var kw langchainKwargs
+		if err := json.Unmarshal(rec.Kwargs, &kw); err != nil {
+			return m.skip("malformed lc kwargs: " + err.Error())
+		}
+		if kw.PageContent != nil {
+			content = *kw.PageContent
+		} else {
+			content, ok = langchainText(kw.Content)
+			if n := len(rec.ID); n > 0 {
+				role = strings.TrimSuffix(strings.ToLower(rec.ID[n-1]), "message")
+			}
+		}
+		metadata, id = kw.Metadata, kw.ID
+	default:
+		return m.skip("no page_content, data.content or kwargs")
+	}
+	if !ok {
+		return m.skip("content has no text parts")
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return m.skip("empty content")
+	}
+
+	mem := m.newMemory(content)
+	m.setID(&mem, id)
+	if role != "" {
+		// Permanent scope, as for Zep messages.
+		mem.Metadata["role"] = role
+	}
+	if ts, isString := metadata["created_at"].(string); isString {
+		mem.CreatedAt = m.parseTime("metadata.created_at", ts)
+	} else if ts, isString := metadata["timestamp"].(string); isString {
+		mem.CreatedAt = m.parseTime("metadata.timestamp", ts)
+	}
+	if tags, isList := metadata["tags"].([]any); isList {
+		for _, t := range tags {
+			if s, isString := t.(string); isString {
+				mem.Tags = append(mem.Tags, s)
+			}
+		}
+	}
+	mergeMetadata(&mem, metadata)
+	return mem, true
+}
+
+// langchainText extracts the text of a message content field, which is
+// either a string or a list of parts such as {"type": "text", "text": "..."}.
+func langchainText(raw json.RawMessage) (string, bool) {
+	if len(raw) == 0 {
+		return "", true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+	var parts []map[string]any
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", false
+	}
+	var texts []string
+	for _, p := range parts {
+		if t, ok := p["text"].(string); ok {
+			texts = append(texts, t)
+		}
+	}
+	if len(texts) == 0 {
+		return "", false
+	}
+	return strings.Join(texts, "\n"), true
+}
@@ -1,8 +1,19 @@
 package main
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
+	"github.com/ajitpratap0/openclaw-cortex/internal/config"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
 func TestNewJSONLScanner_HandlesLargeLine(t *testing.T) {
@@ -17,3 +28,180 @@ func TestNewJSONLScanner_HandlesLargeLine(t *testing.T) {
 		t.Fatalf("unexpected error: %v", scanner.Err())
 	}
 }
+
+func TestIsImportDuplicate(t *testing.T) {
+	prev := cfg
+	cfg = &config.Config{}
+	t.Cleanup(func() { cfg = prev })
+
+	ctx := context.Background()
+	st := store.NewMockStore()
+	vec := make([]float32, 8)
+	vec[0] = 1
+	existing := models.Memory{ID: "existing", Type: models.MemoryTypeFact, Content: "Deploys run on Fridays"}
+	if err := st.Upsert(ctx, existing, vec); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	// Re-importing the same record is not a duplicate of itself.
+	if isImportDuplicate(ctx, st, &existing, vec, 0.9) {
+		t.Error("same ID should not count as a duplicate")
+	}
+	other := models.Memory{ID: "other", Type: models.MemoryTypeFact, Content: "Deploys happen on Fridays"}
+	if !isImportDuplicate(ctx, st, &other, vec, 0.9) {
+		t.Error("identical vector under a different ID should be a duplicate")
+	}
+}
+
+func TestDedupThresholdFromFlag(t *testing.T) {
+	prev := cfg
+	cfg = &config.Config{Memory: config.MemoryConfig{DedupThreshold: 0.92}}
+	t.Cleanup(func() { cfg = prev })
+
+	var v float64
+	cmd := &cobra.Command{Use: "import"}
+	cmd.Flags().Float64Var(&v, "dedup-threshold", 0, "")
+
+	got, err := dedupThresholdFromFlag(cmd, "import", v)
+	if err != nil || got != 0.92 {
+		t.Fatalf("unset flag: got %v, %v; want config default 0.92", got, err)
+	}
+
+	if err := cmd.Flags().Set("dedup-threshold", "0.8"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = dedupThresholdFromFlag(cmd, "import", v)
+	if err != nil || got != 0.8 {
+		t.Fatalf("set flag: got %v, %v; want 0.8", got, err)
+	}
+
+	if err := cmd.Flags().Set("dedup-threshold", "1.5"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = dedupThresholdFromFlag(cmd, "import", v); err == nil {
+		t.Fatal("expected an error for an out-of-range threshold")
+	}
+}
+
+func mapExport(t *testing.T, format, data string) ([]models.Memory, *importMapper) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mems, mapper, err := mapForeignExport(strings.NewReader(data), format, classifier.NewClassifier(logger), logger)
+	if err != nil {
+		t.Fatalf("mapForeignExport(%s): %v", format, err)
+	}
+	return mems, mapper
+}
+
+func TestMapForeignExport_Mem0(t *testing.T) {
+	data := `{"results": [
+		{"id": "5f0c2d8e-1b7a-4c1e-9a53-2f0d9b6c8e11", "memory": "Prefers dark mode in every editor",
+		 "categories": ["preferences"], "user_id": "alice", "app_id": "editor",
+		 "created_at": "2024-07-20T10:15:30.123456-07:00", "metadata": {"source": "chat"}},
+		{"id": "m-2", "memory": "   "},
+		{"id": "m-3", "memory": "Lives in Lisbon", "created_at": "yesterday"}
+	]}`
+	mems, mapper := mapExport(t, "mem0", data)
+	if len(mems) != 2 || mapper.skipped != 1 || mapper.repaired != 1 {
+		t.Fatalf("got %d memories, %d skipped, %d repaired; want 2, 1, 1", len(mems), mapper.skipped, mapper.repaired)
+	}
+	m := mems[0]
+	if m.ID != "5f0c2d8e-1b7a-4c1e-9a53-2f0d9b6c8e11" || m.UserID != "alice" || m.Project != "editor" {
+		t.Errorf("id/user/project = %q/%q/%q", m.ID, m.UserID, m.Project)
+	}
+	if len(m.Tags) != 1 || m.Tags[0] != "preferences" || m.Metadata["source"] != "chat" {
+		t.Errorf("tags = %v, metadata = %v", m.Tags, m.Metadata)
+	}
+	if want := time.Date(2024, 7, 20, 17, 15, 30, 123456000, time.UTC); !m.CreatedAt.Equal(want) || !m.UpdatedAt.Equal(want) {
+		t.Errorf("created/updated = %v/%v, want %v", m.CreatedAt, m.UpdatedAt, want)
+	}
+	if mems[1].Metadata["source_id"] != "m-3" || mems[1].ID == "m-3" {
+		t.Errorf("non-UUID source IDs should be kept in metadata only, got id %q", mems[1].ID)
+	}
+	if !mems[1].CreatedAt.IsZero() {
+		t.Errorf("unparsable created_at should be left for back-fill, got %v", mems[1].CreatedAt)
+	}
+}
+
+func TestMapForeignExport_Zep(t *testing.T) {
+	data := `{"facts": [{"uuid": "f1", "fact": "Alice manages the billing team", "valid_at": "2024-01-01T00:00:00Z", "rating": 0.95}],
+	          "messages": [{"uuid": "m1", "role": "Alice", "role_type": "user", "content": "We decided to ship on Fridays", "created_at": "2024-02-01 09:00:00"}]}`
+	mems, mapper := mapExport(t, "zep", data)
+	if len(mems) != 2 || mapper.skipped != 0 {
+		t.Fatalf("got %d memories, %d skipped; want 2, 0", len(mems), mapper.skipped)
+	}
+	var fact, msg models.Memory
+	for _, m := range mems {
+		if m.Metadata["source_id"] == "f1" {
+			fact = m
+		} else {
+			msg = m
+		}
+	}
+	if fact.Type != models.MemoryTypeFact || fact.Confidence != 0.95 || fact.ValidFrom.Year() != 2024 {
+		t.Errorf("fact mapped to %+v", fact)
+	}
+	if msg.Scope != models.ScopePermanent || msg.Metadata["role"] != "user" || msg.CreatedAt.IsZero() {
+		t.Errorf("message mapped to %+v", msg)
+	}
+}
+
+func TestMapForeignExport_LangChain(t *testing.T) {
+	data := `{"type": "human", "data": {"content": "Remember that staging uses Postgres 16", "type": "human"}}
+{"page_content": "Deploy docs live in /docs/deploy", "metadata": {"tags": ["docs"], "created_at": "2024-03-01"}}
+{"lc": 1, "type": "constructor", "id": ["langchain", "schema", "messages", "AIMessage"], "kwargs": {"content": [{"type": "text", "text": "Noted: Postgres 16 on staging"}]}}
+{"type": "ai", "data": {"content": [{"type": "image_url"}]}}`
+	mems, mapper := mapExport(t, "langchain", data)
+	if len(mems) != 3 || mapper.skipped != 1 {
+		t.Fatalf("got %d memories, %d skipped; want 3, 1", len(mems), mapper.skipped)
+	}
+	if mems[0].Metadata["role"] != "human" || mems[0].Scope != models.ScopePermanent {
+		t.Errorf("message mapped to %+v", mems[0])
+	}
+	if mems[1].Scope != models.ScopePermanent || len(mems[1].Tags) != 1 || mems[1].CreatedAt.IsZero() {
+		t.Errorf("document mapped to %+v", mems[1])
+	}
+	if mems[2].Content != "Noted: Postgres 16 on staging" || mems[2].Metadata["role"] != "ai" {
+		t.Errorf("lc message mapped to %+v", mems[2])
+	}
+}
+
+func TestValidateImportRecords(t *testing.T) {
+	now := time.Now().UTC()
+	valid := models.Memory{
+		ID: "a", Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		Content: "Deploys run on Fridays", Confidence: 0.9, CreatedAt: now,
+	}
+	if problems := validateImportRecords([]models.Memory{valid}); len(problems) != 0 {
+		t.Fatalf("valid record reported problems: %v", problems)
+	}
+
+	earlier := now.Add(-time.Hour)
+	bad := []models.Memory{
+		valid,
+		{ID: "a", Type: "note", Scope: models.ScopePermanent, Content: "x", Confidence: 1.5, CreatedAt: now},
+		{Scope: "forever", ValidFrom: now, ValidTo: &earlier},
+	}
+	problems := validateImportRecords(bad)
+	want := []string{
+		`record 2 (a): duplicate id, first seen in record 1`,
+		`record 2 (a): invalid type "note"`,
+		`record 2 (a): confidence 1.5 outside [0, 1]`,
+		`record 3: missing id`,
+		`record 3: missing content`,
+		`record 3: missing type`,
+		`record 3: invalid scope "forever"`,
+		`record 3: missing created_at`,
+	}
+	if len(problems) != len(want)+1 {
+		t.Fatalf("got %d problems, want %d: %v", len(problems), len(want)+1, problems)
+	}
+	for i, w := range want {
+		if problems[i] != w {
+			t.Errorf("problem %d = %q, want %q", i, problems[i], w)
+		}
+	}
+	if !strings.Contains(problems[len(want)], "valid_to") {
+		t.Errorf("expected a valid_to problem, got %q", problems[len(want)])
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+)
+
+func TestBuildInspectReport_SumsToFinalScore(t *testing.T) {
+	now := time.Now().UTC()
+	mem := models.Memory{
+		ID: "m1", Type: models.MemoryTypeRule, Scope: models.ScopePermanent,
+		Content: "Always run tests", Confidence: 0.9, LastAccessed: now, AccessCount: 3,
+	}
+	rec := recall.NewRecaller(recall.DefaultWeights(), slog.Default())
+	ranked := rec.Rank([]models.SearchResult{{Memory: mem, Score: 0.8}}, "", "tests")
+
+	report := buildInspectReport(mem.ID, "tests", rec.Weights(), &ranked[0])
+	if len(report.Components) != len(recall.WeightNames()) {
+		t.Fatalf("got %d components, want one per weight", len(report.Components))
+	}
+	if report.Components[0].Name != "similarity" || report.Components[0].Score != 0.8 {
+		t.Errorf("similarity component = %+v", report.Components[0])
+	}
+	want := report.WeightedSum * report.SupersessionPenalty * report.ConflictPenalty
+	if math.Abs(want-report.FinalScore) > 1e-9 {
+		t.Errorf("components give %f, final score is %f", want, report.FinalScore)
+	}
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -15,14 +17,20 @@ import (
 	"github.com/ajitpratap0/openclaw-cortex/internal/async"
 	"github.com/ajitpratap0/openclaw-cortex/internal/config"
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/logfile"
 	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
 	"github.com/ajitpratap0/openclaw-cortex/internal/sentry"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
 var version = "0.11.0"
 
 var cfg *config.Config
 
+// logOutput is where newLogger writes: stderr, or the configured log file
+// (optionally teed to stderr) once PersistentPreRunE has opened it.
+var logOutput io.Writer = os.Stderr
+
 func main() {
 	if err := run(); err != nil {
 		sentry.Flush(2 * time.Second)
@@ -36,6 +44,10 @@ func run() error {
 
 	var asyncPool *async.Pool
 	var asyncStoreCloser func() error
+	shutdownTracing := func(context.Context) error { return nil }
+	stopProfile := func() error { return nil }
+	closeLog := func() error { return nil }
+	var profileKind, profileOutput, configProfile string
 
 	rootCmd := &cobra.Command{
 		Use:     "openclaw-cortex",
@@ -43,14 +55,29 @@ func run() error {
 		Long:    "Cortex combines file-based structured memory with vector-based semantic memory for compaction-proof, searchable, classified memory.",
 		Version: version,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			var err error
-			cfg, err = config.Load()
+			stopFn, err := startProfile(profileKind, profileOutput)
+			if err != nil {
+				return err
+			}
+			stopProfile = stopFn
+			cfg, err = config.LoadProfile(cmp.Or(configProfile, os.Getenv(config.ProfileEnv)))
 			if err != nil {
 				return fmt.Errorf("loading config: %w", err)
 			}
 			sentry.Init(cfg.Sentry.DSN, cfg.Sentry.Environment, version)
+			closeLogFile, logFileErr := openLogOutput()
+			if logFileErr == nil {
+				closeLog = closeLogFile
+			}
 
+			// Make the configured handler the default too, so packages that
+			// fall back to slog.Default() log in the same format.
 			logger := newLogger()
+			slog.SetDefault(logger)
+			if logFileErr != nil {
+				logger.Warn("log file unavailable, logging to stderr", "file", cfg.Logging.File, "err", logFileErr)
+			}
+			shutdownTracing = tracing.Init(cfg.Tracing, logger)
 			asyncPool, asyncStoreCloser, err = initAsyncQueue(cmd.Context(), cfg, logger)
 			if err != nil {
 				// Non-fatal: log and continue without async queue.
@@ -60,12 +87,20 @@ func run() error {
 		},
 	}
 
+	rootCmd.PersistentFlags().StringVar(&configProfile, "config-profile", "", "apply the settings under profiles.<name> in the config file (env "+config.ProfileEnv+")")
+	rootCmd.PersistentFlags().StringVar(&profileKind, "profile", "", "write a pprof profile of this command: cpu or mem")
+	rootCmd.PersistentFlags().StringVar(&profileOutput, "profile-output", "", "profile output path (default openclaw-cortex.<kind>.pprof)")
+	_ = rootCmd.PersistentFlags().MarkHidden("profile")
+	_ = rootCmd.PersistentFlags().MarkHidden("profile-output")
+
 	rootCmd.AddCommand(
 		indexCmd(),
 		searchCmd(),
 		storeCmd(),
 		storeBatchCmd(),
 		forgetCmd(),
+		forgetAllCmd(),
+		restoreCmd(),
 		listCmd(),
 		captureCmd(),
 		recallCmd(),
@@ -73,19 +108,26 @@ func run() error {
 		consolidateCmd(),
 		lifecycleCmd(),
 		getCmd(),
+		inspectCmd(),
 		updateCmd(),
 		exportCmd(),
 		importCmd(),
 		healthCmd(),
 		entitiesCmd(),
+		extractEntitiesCmd(),
 		serveCmd(),
 		hookCmd(),
 		mcpCmd(),
 		migrateCmd(),
+		reindexFieldsCmd(),
+		moveProjectCmd(),
 		resetCmd(),
 		reembedCmd(),
+		repairEmbeddingsCmd(),
+		embeddingsCmd(),
 		workerCmd(),
 		graphAdminCmd(),
+		weightsCmd(),
 	)
 
 	rootCmd.SetContext(ctx)
@@ -93,6 +135,17 @@ func run() error {
 	err := rootCmd.Execute()
 	stop()
 
+	if profileErr := stopProfile(); profileErr != nil {
+		slog.Default().Warn("profile could not be written", "err", profileErr)
+	}
+
+	// Flush any buffered trace spans before exiting.
+	tracingCtx, tracingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer tracingCancel()
+	if tracingErr := shutdownTracing(tracingCtx); tracingErr != nil {
+		slog.Default().Warn("tracing shutdown did not complete cleanly", "err", tracingErr)
+	}
+
 	// Graceful shutdown of the async worker pool (if initialized).
 	if asyncPool != nil {
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -108,9 +161,32 @@ func run() error {
 		}
 	}
 
+	_ = closeLog()
+
 	return err
 }
 
+// openLogOutput points logOutput at cfg.Logging.File when one is configured
+// and returns a func that closes it. On error logOutput stays on stderr.
+func openLogOutput() (func() error, error) {
+	if cfg.Logging.File == "" {
+		return func() error { return nil }, nil
+	}
+	w, err := logfile.Open(cfg.Logging.File, logfile.Options{
+		MaxSizeBytes: int64(cfg.Logging.MaxSizeMB) * 1024 * 1024,
+		MaxBackups:   cfg.Logging.MaxBackups,
+		MaxAge:       time.Duration(cfg.Logging.MaxAgeDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+	logOutput = w
+	if cfg.Logging.Stderr {
+		logOutput = io.MultiWriter(w, os.Stderr)
+	}
+	return w.Close, nil
+}
+
 func newLogger() *slog.Logger {
 	level := slog.LevelInfo
 	if cfg != nil && cfg.Logging.Level == "debug" {
@@ -118,9 +194,9 @@ func newLogger() *slog.Logger {
 	}
 	opts := &slog.HandlerOptions{Level: level}
 	if cfg != nil && cfg.Logging.Format == "json" {
-		return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+		return slog.New(slog.NewJSONHandler(logOutput, opts))
 	}
-	return slog.New(slog.NewTextHandler(os.Stderr, opts))
+	return slog.New(slog.NewTextHandler(logOutput, opts))
 }
 
 func newEmbedder(logger *slog.Logger) embedder.Embedder {
@@ -129,15 +205,30 @@ func newEmbedder(logger *slog.Logger) embedder.Embedder {
 		logger.Error("failed to initialize embedder", "error", err)
 		os.Exit(1)
 	}
-	return emb
+	tmpl, err := embedder.NewInputTemplate(cfg.Embedder.InputTemplate, cfg.Embedder.QueryTemplate)
+	if err != nil {
+		logger.Error("failed to initialize embedder", "error", err)
+		os.Exit(1)
+	}
+	embedder.SetInputTemplate(tmpl)
+	return embedder.NewCachingEmbedder(emb, embedder.ModelID(cfg.Ollama, cfg.Embedder), cfg.Embedder.CacheSize)
 }
 
 func newMemgraphStore(ctx context.Context, logger *slog.Logger) (*memgraph.MemgraphStore, error) {
-	return memgraph.New(ctx,
+	if !cfg.Memgraph.UsesTLS() && !isLoopbackURI(cfg.Memgraph.URI) {
+		logger.Warn("memgraph connection to a non-local host is not encrypted; use a bolt+s:// URI and set memgraph.require_tls to enforce TLS")
+	}
+	st, err := memgraph.New(ctx,
 		cfg.Memgraph.URI, cfg.Memgraph.Username, cfg.Memgraph.Password, cfg.Memgraph.Database,
 		int(cfg.Memory.VectorDimension),
 		logger,
 	)
+	if err != nil {
+		return nil, err
+	}
+	st.SetEmbeddingModel(embedder.ModelID(cfg.Ollama, cfg.Embedder))
+	st.SetVectorMetric(memgraph.VectorMetric(cfg.Memgraph.VectorMetric))
+	return st, nil
 }
 
 func truncate(s string, maxLen int) string {
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfile begins collecting a pprof profile of the given kind ("cpu" or
+// "mem") and returns a function that finishes it and writes it to path. An
+// empty kind disables profiling and returns a no-op stop function.
+func startProfile(kind, path string) (func() error, error) {
+	if kind == "" {
+		return func() error { return nil }, nil
+	}
+	if path == "" {
+		path = "openclaw-cortex." + kind + ".pprof"
+	}
+
+	switch kind {
+	case "cpu":
+		f, err := os.Create(path) //nolint:gosec // path is supplied by the operator
+		if err != nil {
+			return nil, fmt.Errorf("creating cpu profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("starting cpu profile: %w", err)
+		}
+		return func() error {
+			pprof.StopCPUProfile()
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("closing cpu profile: %w", err)
+			}
+			return nil
+		}, nil
+	case "mem":
+		return func() error {
+			f, err := os.Create(path) //nolint:gosec // path is supplied by the operator
+			if err != nil {
+				return fmt.Errorf("creating mem profile: %w", err)
+			}
+			runtime.GC() // materialize up-to-date allocation statistics
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("writing mem profile: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("closing mem profile: %w", err)
+			}
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid --profile %q: must be cpu or mem", kind)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	stop, err := startProfile("", "")
+	if err != nil {
+		t.Fatalf("disabled profile: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("disabled stop: %v", err)
+	}
+
+	for _, kind := range []string{"cpu", "mem"} {
+		path := filepath.Join(dir, kind+".pprof")
+		stop, err := startProfile(kind, path)
+		if err != nil {
+			t.Fatalf("%s: start: %v", kind, err)
+		}
+		if err := stop(); err != nil {
+			t.Fatalf("%s: stop: %v", kind, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("%s: profile not written: %v", kind, err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("%s: profile is empty", kind)
+		}
+	}
+
+	if _, err := startProfile("block", ""); err == nil {
+		t.Error("expected error for unknown profile kind")
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+func TestNeedsReembed(t *testing.T) {
+	const current = "ollama/nomic-embed-text"
+	tests := []struct {
+		name  string
+		mem   models.Memory
+		stale bool
+		want  bool
+	}{
+		{"missing vector", models.Memory{}, false, true},
+		{"embedded, stale mode off", models.Memory{HasEmbedding: true, EmbeddingModel: "ollama/old"}, false, false},
+		{"current model", models.Memory{HasEmbedding: true, EmbeddingModel: current}, true, false},
+		{"different model", models.Memory{HasEmbedding: true, EmbeddingModel: "ollama/old"}, true, true},
+		{"legacy untracked model", models.Memory{HasEmbedding: true}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsReembed(&tt.mem, current, tt.stale); got != tt.want {
+				t.Errorf("needsReembed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+// snapshotRecord is one line of a snapshot export: a memory with every field
+// plus its stored embedding, so a restore can skip re-embedding.
+type snapshotRecord struct {
+	Memory models.Memory `json:"memory"`
+	Vector []float32     `json:"vector,omitempty"`
+}
+
+// writeSnapshot writes every memory in st matching filters (nil = all),
+// including invalidated ones, as JSONL snapshot records and returns how many
+// it wrote. Vectors are fetched a page at a time.
+func writeSnapshot(ctx context.Context, st store.Store, filters *store.SearchFilters, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	f := store.SearchFilters{}
+	if filters != nil {
+		f = *filters
+	}
+	f.IncludeInvalidated = true
+	filters = &f
+	written := 0
+	cursor := ""
+	for {
+		memories, next, err := st.List(ctx, filters, 500, cursor)
+		if err != nil {
+			return written, fmt.Errorf("listing memories: %w", err)
+		}
+		ids := make([]string, len(memories))
+		for i := range memories {
+			ids[i] = memories[i].ID
+		}
+		vecs, err := st.GetVectors(ctx, ids)
+		if err != nil {
+			return written, fmt.Errorf("fetching vectors: %w", err)
+		}
+		for i := range memories {
+			rec := snapshotRecord{Memory: memories[i], Vector: vecs[memories[i].ID]}
+			if err := enc.Encode(rec); err != nil {
+				return written, fmt.Errorf("writing snapshot record: %w", err)
+			}
+			written++
+		}
+		if next == "" {
+			return written, nil
+		}
+		cursor = next
+	}
+}
+
+// writeJSONL streams the memories in st matching filters (nil = all),
+// including invalidated ones, as one models.Memory object per line, the
+// format "import --format jsonl" reads, and returns how many it wrote. Only
+// one page is held in memory at a time.
+func writeJSONL(ctx context.Context, st store.Store, filters *store.SearchFilters, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	f := store.SearchFilters{}
+	if filters != nil {
+		f = *filters
+	}
+	f.IncludeInvalidated = true
+	filters = &f
+	written := 0
+	cursor := ""
+	for {
+		memories, next, err := st.List(ctx, filters, 500, cursor)
+		if err != nil {
+			return written, fmt.Errorf("listing memories: %w", err)
+		}
+		for i := range memories {
+			if err := enc.Encode(&memories[i]); err != nil {
+				return written, fmt.Errorf("writing JSONL record: %w", err)
+			}
+			written++
+		}
+		if next == "" {
+			return written, nil
+		}
+		cursor = next
+	}
+}
+
+// readJSONL parses one models.Memory object per line, skipping blank lines.
+func readJSONL(r io.Reader) ([]models.Memory, error) {
+	var memories []models.Memory
+	scanner := newJSONLScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var m models.Memory
+		if err := json.Unmarshal([]byte(text), &m); err != nil {
+			return nil, fmt.Errorf("JSONL line %d: %w", line, err)
+		}
+		memories = append(memories, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading JSONL: %w", err)
+	}
+	return memories, nil
+}
+
+// readSnapshot parses a snapshot export into memories and their vectors,
+// aligned by index. A memory without a stored vector gets a nil entry.
+func readSnapshot(r io.Reader) ([]models.Memory, [][]float32, error) {
+	var (
+		memories []models.Memory
+		vectors  [][]float32
+	)
+	scanner := newJSONLScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec snapshotRecord
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, nil, fmt.Errorf("snapshot line %d: %w", line, err)
+		}
+		memories = append(memories, rec.Memory)
+		vectors = append(vectors, rec.Vector)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	return memories, vectors, nil
+}
+
+// reusableVector returns vec when it can be stored as-is: it has the store's
+// dimension and was produced by the currently configured embedding model.
+// Otherwise it returns nil and the memory must be re-embedded.
+func reusableVector(m *models.Memory, vec []float32, model string, dim int) []float32 {
+	if len(vec) != dim || m.EmbeddingModel == "" || m.EmbeddingModel != model {
+		return nil
+	}
+	return vec
+}
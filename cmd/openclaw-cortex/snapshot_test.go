@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := store.NewMockStore()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	vecA := []float32{0.1, 0.2, 0.3, 0.4}
+	memA := models.Memory{
+		ID: "a", Type: models.MemoryTypeRule, Scope: models.ScopePermanent, Content: "always run tests",
+		Project: "proj", Tags: []string{"ci"}, Confidence: 0.9, EmbeddingModel: "ollama/m",
+		CreatedAt: now, UpdatedAt: now,
+	}
+	memB := models.Memory{
+		ID: "b", Type: models.MemoryTypeFact, Scope: models.ScopePermanent, Content: "no vector yet",
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := src.Upsert(ctx, memA, vecA); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Upsert(ctx, memB, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := writeSnapshot(ctx, src, nil, &buf)
+	if err != nil {
+		t.Fatalf("writeSnapshot: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("wrote %d records, want 2", n)
+	}
+
+	memories, vectors, err := readSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("readSnapshot: %v", err)
+	}
+	if len(memories) != 2 || len(vectors) != 2 {
+		t.Fatalf("read %d memories and %d vectors, want 2 each", len(memories), len(vectors))
+	}
+	for i := range memories {
+		switch memories[i].ID {
+		case "a":
+			if !slices.Equal(vectors[i], vecA) {
+				t.Errorf("vector for a = %v, want %v", vectors[i], vecA)
+			}
+			if memories[i].Project != "proj" || !slices.Equal(memories[i].Tags, []string{"ci"}) || memories[i].EmbeddingModel != "ollama/m" {
+				t.Errorf("memory a lost fields: %+v", memories[i])
+			}
+		case "b":
+			if len(vectors[i]) != 0 {
+				t.Errorf("memory b has vector %v, want none", vectors[i])
+			}
+		default:
+			t.Errorf("unexpected memory %q", memories[i].ID)
+		}
+	}
+
+	if _, _, err := readSnapshot(bytes.NewBufferString("{not json}\n")); err == nil {
+		t.Error("expected an error for a malformed snapshot line")
+	}
+}
+
+func TestJSONL_ExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := store.NewMockStore()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	seed := []models.Memory{
+		{ID: "m1", Type: models.MemoryTypeRule, Scope: models.ScopePermanent, Content: "always run tests",
+			Project: "alpha", Tags: []string{"ci"}, Confidence: 0.9, CreatedAt: now, UpdatedAt: now},
+		{ID: "m2", Type: models.MemoryTypeFact, Scope: models.ScopeProject, Content: "the API listens on 8080",
+			Project: "alpha", Confidence: 0.8, AccessCount: 4, CreatedAt: now, UpdatedAt: now},
+		{ID: "m3", Type: models.MemoryTypePreference, Scope: models.ScopePermanent, Content: "prefers tabs",
+			Project: "beta", Confidence: 0.7, CreatedAt: now, UpdatedAt: now},
+	}
+	for i := range seed {
+		if err := src.Upsert(ctx, seed[i], []float32{float32(i + 1), 0, 0, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := writeJSONL(ctx, src, nil, &buf)
+	if err != nil {
+		t.Fatalf("writeJSONL: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("wrote %d records, want 3", n)
+	}
+
+	memories, err := readJSONL(&buf)
+	if err != nil {
+		t.Fatalf("readJSONL: %v", err)
+	}
+	dst := store.NewMockStore()
+	for i := range memories {
+		if err := dst.Upsert(ctx, memories[i], []float32{1, 0, 0, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range seed {
+		got, err := dst.Get(ctx, seed[i].ID)
+		if err != nil {
+			t.Fatalf("Get(%s) after import: %v", seed[i].ID, err)
+		}
+		if got.Content != seed[i].Content || got.Type != seed[i].Type || got.Scope != seed[i].Scope ||
+			got.Project != seed[i].Project || !slices.Equal(got.Tags, seed[i].Tags) ||
+			got.AccessCount != seed[i].AccessCount || !got.CreatedAt.Equal(seed[i].CreatedAt) {
+			t.Errorf("memory %s did not round-trip: got %+v", seed[i].ID, got)
+		}
+	}
+
+	// A project filter limits the export.
+	buf.Reset()
+	project := "alpha"
+	n, err = writeJSONL(ctx, src, &store.SearchFilters{Project: &project}, &buf)
+	if err != nil {
+		t.Fatalf("writeJSONL with filter: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("filtered export wrote %d records, want 2", n)
+	}
+
+	// Invalidated memories are exported too, without touching the caller's filters.
+	if err := src.InvalidateMemory(ctx, "m1", now); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	filters := &store.SearchFilters{Project: &project}
+	n, err = writeJSONL(ctx, src, filters, &buf)
+	if err != nil {
+		t.Fatalf("writeJSONL after invalidation: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("export after invalidation wrote %d records, want 2", n)
+	}
+	if filters.IncludeInvalidated {
+		t.Error("writeJSONL modified the caller's filters")
+	}
+
+	if _, err := readJSONL(bytes.NewBufferString("{not json}\n")); err == nil {
+		t.Error("expected an error for a malformed JSONL line")
+	}
+}
+
+func TestReusableVector(t *testing.T) {
+	vec := []float32{1, 2, 3}
+	tests := []struct {
+		name  string
+		model string
+		vec   []float32
+		want  bool
+	}{
+		{"same model and dimension", "ollama/m", vec, true},
+		{"other model", "ollama/other", vec, false},
+		{"untracked model", "", vec, false},
+		{"wrong dimension", "ollama/m", vec[:2], false},
+		{"missing vector", "ollama/m", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &models.Memory{EmbeddingModel: tt.model}
+			got := reusableVector(m, tt.vec, "ollama/m", 3) != nil
+			if got != tt.want {
+				t.Errorf("reusableVector() reused = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestReadContentFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runbook.md")
+	if err := os.WriteFile(path, []byte("\n# Deploy\n\nRun make release.\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readContentFile(path, nil)
+	if err != nil {
+		t.Fatalf("readContentFile: %v", err)
+	}
+	if want := "# Deploy\n\nRun make release."; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+
+	got, err = readContentFile("-", strings.NewReader("  from stdin\n"))
+	if err != nil {
+		t.Fatalf("readContentFile stdin: %v", err)
+	}
+	if got != "from stdin" {
+		t.Errorf("stdin content = %q, want %q", got, "from stdin")
+	}
+
+	if _, err = readContentFile(filepath.Join(t.TempDir(), "missing.md"), nil); err == nil {
+		t.Error("expected error for a missing file")
+	}
+
+	_, err = readContentFile("-", strings.NewReader(strings.Repeat("a", store.MaxContentLen+1)))
+	var tooLong *store.ErrContentTooLong
+	if !errors.As(err, &tooLong) {
+		t.Errorf("oversized file: err = %v, want ErrContentTooLong", err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+func TestNextVersion_CarriesForward(t *testing.T) {
+	now := time.Now().UTC()
+	old := &models.Memory{
+		ID: "old", Type: models.MemoryTypeRule, Scope: models.ScopePermanent, Content: "old content",
+		Tags: []string{"ci"}, Project: "proj", AccessCount: 5,
+		Attachments: []models.Attachment{{Type: models.AttachmentFile, Ref: "docs/auth.md"}},
+	}
+
+	got := nextVersion(old, "new", "new content", now)
+	if got.ID != "new" || got.Content != "new content" || got.SupersedesID != "old" {
+		t.Errorf("identity fields: %+v", got)
+	}
+	if !slices.Equal(got.Attachments, old.Attachments) {
+		t.Errorf("attachments = %v, want %v", got.Attachments, old.Attachments)
+	}
+	if got.Project != "proj" || !slices.Equal(got.Tags, old.Tags) || got.AccessCount != 5 {
+		t.Errorf("carried fields lost: %+v", got)
+	}
+	if !got.CreatedAt.Equal(now) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, now)
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
+)
+
+// errEmbedBusy is returned by Server.embed when no embedding slot frees up
+// within the configured queue timeout.
+var errEmbedBusy = errors.New("embedding backend busy")
+
+// defaultEmbedQueueTimeout is how long a request waits for an embedding slot
+// when SetEmbedConcurrency is given a non-positive timeout.
+const defaultEmbedQueueTimeout = 10 * time.Second
+
+// SetEmbedConcurrency caps the number of embedding calls the server makes at
+// once. Further requests queue for up to wait and then fail with 503.
+// n <= 0 removes the cap.
+func (s *Server) SetEmbedConcurrency(n int, wait time.Duration) {
+	if n <= 0 {
+		s.embedSem = nil
+		return
+	}
+	if wait <= 0 {
+		wait = defaultEmbedQueueTimeout
+	}
+	s.embedSem = make(chan struct{}, n)
+	s.embedWait = wait
+}
+
+// embed generates an embedding for text, holding an embedding slot for the
+// duration of the call when a concurrency cap is configured.
+func (s *Server) embed(ctx context.Context, text string) ([]float32, error) {
+	if s.embedSem != nil {
+		timer := time.NewTimer(s.embedWait)
+		select {
+		case s.embedSem <- struct{}{}:
+			timer.Stop()
+		case <-timer.C:
+			return nil, errEmbedBusy
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		defer func() { <-s.embedSem }()
+	}
+	defer metrics.EmbedLatency.ObserveSince(time.Now())
+	return s.embedder.Embed(ctx, text)
+}
+
+// writeEmbedError maps an embed failure to an HTTP response: 503 when the
+// embedding queue is full, 500 otherwise.
+func (s *Server) writeEmbedError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errEmbedBusy) {
+		w.Header().Set("Retry-After", "1")
+		s.writeError(w, http.StatusServiceUnavailable, "embedding backend busy, retry later")
+		return
+	}
+	s.writeError(w, http.StatusInternalServerError, "failed to generate embedding")
+}
+
+// queryVector returns the embedding to search with: supplied when the client
+// sent a precomputed vector, otherwise the embedding of query. On failure it
+// writes the error response and returns false.
+func (s *Server) queryVector(ctx context.Context, w http.ResponseWriter, supplied []float32, query string) ([]float32, bool) {
+	if len(supplied) > 0 {
+		dim := s.vectorDim
+		if dim <= 0 {
+			dim = s.embedder.Dimension()
+		}
+		if dim > 0 && len(supplied) != dim {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("vector has dimension %d, expected %d", len(supplied), dim))
+			return nil, false
+		}
+		return supplied, true
+	}
+	vec, err := s.embed(ctx, embedder.QueryText(query))
+	if err != nil {
+		s.logger.Error("failed to embed query", "error", err)
+		s.writeEmbedError(w, err)
+		return nil, false
+	}
+	return vec, true
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"crypto/subtle"
+	"expvar"
+	"net/http"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
+)
+
+// SetMetricsToken requires GET /metrics callers to present token as a Bearer
+// token. It is separate from the API auth token so a scraper never holds
+// credentials that can read or change memories. Empty leaves /metrics open,
+// like /healthz.
+func (s *Server) SetMetricsToken(token string) {
+	s.metricsToken = token
+}
+
+// handleMetrics serves the metrics package counters and histograms in the
+// Prometheus text format.
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := metrics.WritePrometheus(w); err != nil {
+		s.logger.Warn("failed to write metrics", "error", err)
+	}
+}
+
+// metricsAuth guards the metrics handler with the metrics token, if set.
+func (s *Server) metricsAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.metricsToken != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.metricsToken)) != 1 {
+				s.writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// countRequests counts every call to next in requests, and those answered
+// with a 4xx or 5xx status in errs.
+func countRequests(requests, errs *expvar.Int, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metrics.Inc(requests)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+		if rec.status >= http.StatusBadRequest {
+			metrics.Inc(errs)
+		}
+	}
+}
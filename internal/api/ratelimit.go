@@ -13,6 +13,7 @@ import (
 // exemptPaths are never rate-limited regardless of the bucket state.
 var exemptPaths = map[string]bool{
 	"/healthz": true,
+	"/readyz":  true,
 	"/metrics": true,
 }
 
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,10 +15,14 @@ import (
 	sentryhttp "github.com/getsentry/sentry-go/http"
 	"github.com/google/uuid"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 	"github.com/ajitpratap0/openclaw-cortex/pkg/cursor"
 	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
 )
@@ -29,7 +34,35 @@ type Server struct {
 	embedder     embedder.Embedder
 	logger       *slog.Logger
 	authToken    string // empty = no auth required
+	metricsToken string // empty = GET /metrics needs no auth
 	cursorSecret string // empty = cursor signing disabled (plain numeric offset passthrough)
+	minMemories  int    // guaranteed recall memory count regardless of budget; 0 = disabled
+	skipAccess   bool   // true = recall does not update access metadata
+	trash        bool   // true = DELETE moves memories to trash unless ?permanent=true
+	broaden      bool   // true = a project recall that finds too little retries with global memories
+
+	synthesizer *recall.Synthesizer       // nil = recall mode "synthesize" is rejected
+	filter      *recall.RelevanceFilter   // nil = no LLM relevance filter on recall
+	typeTTL     *lifecycle.TypeTTL        // nil = no per-type default TTLs
+	tags        *store.TagNormalizer      // nil = tags are stored as given
+	lifecycle   *lifecycle.Manager        // nil = consolidation preview uses a default manager
+	freshness   recall.Freshness          // age buckets for the recall "freshness" option
+	cutoff      float64                   // default recall relevance cutoff ratio; 0 = disabled
+	perMemory   int                       // default recall per-memory token cap; 0 = disabled
+	attachRefs  bool                      // default recall "include_attachments"
+	onConflict  string                    // default recall "on_conflict"; "" = both
+	contradict  *capture.ConflictDetector // nil = no LLM contradiction check on recall
+	feedbackLog *recall.FeedbackLog       // nil = recall feedback logging disabled
+	conflicts   *capture.ConflictDetector // nil = no conflict check on remember
+
+	dedupThreshold  float64          // 0 = remember does not check for duplicates
+	dedupScope      store.DedupScope // comparison set for the remember dedup check
+	dedupOnRemember bool             // false = only dry runs report duplicates
+
+	vectorDim int // expected dimension of supplied vectors; 0 = the embedder's
+
+	embedSem  chan struct{} // nil = embedding calls are not capped
+	embedWait time.Duration // how long a request queues for an embedding slot
 }
 
 // NewServer creates a new Server with the given dependencies.
@@ -44,35 +77,198 @@ func NewServer(st store.Store, rec *recall.Recaller, emb embedder.Embedder, logg
 	}
 }
 
+// SetMinMemories guarantees that POST /v1/recall returns at least n memories
+// even when the token budget would admit fewer. n <= 0 disables the guarantee.
+func (s *Server) SetMinMemories(n int) {
+	s.minMemories = n
+}
+
+// SetBroadenWhenEmpty makes POST /v1/recall retry a single-project recall
+// with global memories included when it finds fewer than max(minMemories, 1)
+// memories, so a fresh project still gets context.
+func (s *Server) SetBroadenWhenEmpty(enabled bool) {
+	s.broaden = enabled
+}
+
+// SetRelevanceCutoff sets the default "relevance_cutoff" of POST /v1/recall:
+// the context stops at the first memory whose score is below ratio times the
+// top score. ratio <= 0 disables it.
+func (s *Server) SetRelevanceCutoff(ratio float64) {
+	s.cutoff = ratio
+}
+
+// SetPerMemoryMaxTokens sets the default "per_memory_max_tokens" of
+// POST /v1/recall: each memory is truncated to n tokens before budgeting.
+// n <= 0 disables it.
+func (s *Server) SetPerMemoryMaxTokens(n int) {
+	s.perMemory = n
+}
+
+// SetIncludeAttachments sets the default "include_attachments" of
+// POST /v1/recall: each memory's attachment references are appended to the
+// context.
+func (s *Server) SetIncludeAttachments(enabled bool) {
+	s.attachRefs = enabled
+}
+
+// SetOnConflict sets the default "on_conflict" of POST /v1/recall: how
+// recalled memories that share an active conflict group are returned (see
+// recall.ResolveConflicts).
+func (s *Server) SetOnConflict(mode string) {
+	s.onConflict = mode
+}
+
+// SetVectorDimension sets the dimension client-supplied query vectors must
+// have, normally memory.vector_dimension. Needed for embedders that do not
+// know their dimension up front (Dimension() == 0). 0 falls back to the
+// embedder's dimension.
+func (s *Server) SetVectorDimension(dim int) {
+	s.vectorDim = dim
+}
+
+// SetRecallConflictCheck enables the LLM contradiction check among the top
+// POST /v1/recall results (see recall.DetectConflicts). nil disables it.
+func (s *Server) SetRecallConflictCheck(cd *capture.ConflictDetector) {
+	s.contradict = cd
+}
+
+// SetTrackAccess controls whether POST /v1/recall updates access metadata for
+// returned memories. Tracking is enabled by default.
+func (s *Server) SetTrackAccess(enabled bool) {
+	s.skipAccess = !enabled
+}
+
+// SetTrashEnabled controls whether DELETE /v1/memories/{id} moves memories to
+// the trash instead of removing them. Callers can still bypass the trash with
+// ?permanent=true. Disabled by default.
+func (s *Server) SetTrashEnabled(enabled bool) {
+	s.trash = enabled
+}
+
+// SetSynthesizer enables the "synthesize" mode of POST /v1/recall, which
+// returns a single Claude-written summary as the context. nil disables it.
+func (s *Server) SetSynthesizer(syn *recall.Synthesizer) {
+	s.synthesizer = syn
+}
+
+// SetRelevanceFilter enables the LLM relevance filter on recall (see
+// recall.RelevanceFilter). nil disables it.
+func (s *Server) SetRelevanceFilter(f *recall.RelevanceFilter) {
+	s.filter = f
+}
+
+// SetFreshness sets the age buckets used by the "freshness" option of
+// POST /v1/recall. The zero value uses the built-in defaults.
+func (s *Server) SetFreshness(f recall.Freshness) {
+	s.freshness = f
+}
+
+// SetFeedbackLog enables recall feedback logging: every POST /v1/recall is
+// recorded with its query embedding and ranked results, and
+// POST /v1/recall/feedback records which returned memories were useful.
+// nil disables it.
+func (s *Server) SetFeedbackLog(l *recall.FeedbackLog) {
+	s.feedbackLog = l
+}
+
+// SetConflictDetector enables the conflict check on POST /v1/remember: when
+// the new memory contradicts a near-similar existing one, the response
+// carries a warning with the conflicting ID. nil disables the check.
+func (s *Server) SetConflictDetector(cd *capture.ConflictDetector) {
+	s.conflicts = cd
+}
+
+// SetDedup configures the duplicate check on POST /v1/remember: a memory
+// whose cosine similarity to an existing one (within scope) is at least
+// threshold is reported as a duplicate by dry runs and, with
+// SetDedupOnRemember, not stored. threshold is also the default for
+// POST /v1/check-duplicate. threshold <= 0 disables the check.
+func (s *Server) SetDedup(threshold float64, scope store.DedupScope) {
+	s.dedupThreshold = threshold
+	s.dedupScope = scope
+}
+
+// SetDedupOnRemember makes a non-dry-run POST /v1/remember skip storing a
+// duplicate and name the existing memory instead. Off by default, so
+// remember always stores.
+func (s *Server) SetDedupOnRemember(enabled bool) {
+	s.dedupOnRemember = enabled
+}
+
+// SetTypeTTL sets the per-type default TTLs applied to memories stored via
+// POST /v1/remember. nil disables them.
+func (s *Server) SetTypeTTL(t *lifecycle.TypeTTL) {
+	s.typeTTL = t
+}
+
+// SetTagNormalizer sets the normalization applied to the tags of memories
+// written through the API. nil stores tags as given.
+func (s *Server) SetTagNormalizer(n *store.TagNormalizer) {
+	s.tags = n
+}
+
+// SetLifecycleManager sets the manager whose consolidation settings (such as
+// the partition) POST /v1/lifecycle/consolidate/preview uses. nil selects a
+// default manager over the server's store and embedder.
+func (s *Server) SetLifecycleManager(lm *lifecycle.Manager) {
+	s.lifecycle = lm
+}
+
 // Handler returns an http.Handler with all routes registered.
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
-	// Health check — no auth required.
+	// Health and readiness checks — no auth required.
 	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.HandleFunc("GET /metrics", s.metricsAuth(s.handleMetrics))
 
 	// Memory CRUD and search endpoints — wrapped with auth middleware.
-	mux.HandleFunc("POST /v1/remember", s.auth(s.handleRemember))
-	mux.HandleFunc("POST /v1/recall", s.auth(s.handleRecall))
+	mux.HandleFunc("POST /v1/remember", s.auth(countRequests(metrics.APIRememberRequests, metrics.APIRememberErrors, s.handleRemember)))
+	mux.HandleFunc("POST /v1/recall", s.auth(countRequests(metrics.APIRecallRequests, metrics.APIRecallErrors, s.handleRecall)))
+	mux.HandleFunc("POST /v1/recall/feedback", s.auth(s.handleRecallFeedback))
 	mux.HandleFunc("GET /v1/memories", s.auth(s.handleList))
+	mux.HandleFunc("DELETE /v1/memories", s.auth(s.handleBulkDelete))
 	mux.HandleFunc("GET /v1/memories/{id}", s.auth(s.handleGetMemory))
+	mux.HandleFunc("POST /v1/memories/batch-get", s.auth(s.handleBatchGet))
 	mux.HandleFunc("PUT /v1/memories/{id}", s.auth(s.handleUpdate))
+	mux.HandleFunc("PATCH /v1/memories/{id}", s.auth(s.handleUpdate))
+	mux.HandleFunc("PATCH /v1/memories/{id}/supersede", s.auth(s.handleSupersede))
 	mux.HandleFunc("DELETE /v1/memories/{id}", s.auth(s.handleDeleteMemory))
-	mux.HandleFunc("POST /v1/search", s.auth(s.handleSearch))
+	mux.HandleFunc("GET /v1/memories/{id}/vector", s.auth(s.handleGetVector))
+	mux.HandleFunc("POST /v1/vectors", s.auth(s.handleVectors))
+	mux.HandleFunc("POST /v1/search", s.auth(countRequests(metrics.APISearchRequests, metrics.APISearchErrors, s.handleSearch)))
+	mux.HandleFunc("POST /v1/check-duplicate", s.auth(s.handleCheckDuplicate))
 	mux.HandleFunc("GET /v1/stats", s.auth(s.handleStats))
+	mux.HandleFunc("GET /v1/schema", s.auth(s.handleSchema))
+	mux.HandleFunc("GET /v1/sessions/{session_id}/memories", s.auth(s.handleSessionMemories))
+	mux.HandleFunc("GET /v1/preferences", s.auth(s.handlePreferences))
+	mux.HandleFunc("POST /v1/lifecycle/consolidate/preview", s.auth(s.handleConsolidatePreview))
 
 	// Entity endpoints.
 	mux.HandleFunc("GET /v1/entities/{id}", s.auth(s.handleGetEntity))
 	mux.HandleFunc("GET /v1/entities", s.auth(s.handleSearchEntities))
+	mux.HandleFunc("POST /v1/entities/search", s.auth(s.handleSemanticSearchEntities))
 
 	sentryHandler := sentryhttp.New(sentryhttp.Options{
 		Repanic: true,
 	})
-	return sentryHandler.Handle(mux)
+	return sentryHandler.Handle(traceContext(mux))
 }
 
 // --- middleware ---
 
+// traceContext joins request spans to the caller's trace when the request
+// carries a W3C traceparent header.
+func traceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tracing.Enabled() {
+			r = r.WithContext(tracing.Extract(r.Context(), r.Header))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // auth wraps a handler with Bearer token authentication when authToken is set.
 func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -96,6 +292,17 @@ func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// handleReadyz reports whether the server can serve traffic, i.e. whether
+// the store is reachable. Unlike /healthz it fails while the store is down.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.Ping(r.Context()); err != nil {
+		s.logger.Warn("readiness check: store unreachable", "error", err)
+		s.writeError(w, http.StatusServiceUnavailable, "store unreachable")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
 // rememberRequest is the body accepted by POST /v1/remember.
 type rememberRequest struct {
 	Content    string             `json:"content"`
@@ -104,12 +311,23 @@ type rememberRequest struct {
 	Tags       []string           `json:"tags"`
 	Project    string             `json:"project"`
 	Confidence float64            `json:"confidence"`
+	DryRun     bool               `json:"dry_run"`    // validate, embed and check for duplicates without storing
+	ExpiresAt  time.Time          `json:"expires_at"` // zero = no expiry date
+
+	// Attachments reference external material (files, URLs, commits).
+	Attachments []models.Attachment `json:"attachments"`
 }
 
 // rememberResponse is returned by POST /v1/remember.
 type rememberResponse struct {
-	ID     string `json:"id"`
-	Stored bool   `json:"stored"`
+	ID          string                   `json:"id"`
+	Stored      bool                     `json:"stored"`
+	DuplicateOf string                   `json:"duplicate_of,omitempty"` // existing memory that made this one a duplicate
+	Conflict    *capture.ConflictWarning `json:"conflict,omitempty"`     // set when the memory contradicts an existing one
+
+	// WouldStore is set only for dry runs and reports whether the memory
+	// would have been stored.
+	WouldStore *bool `json:"would_store,omitempty"`
 }
 
 func (s *Server) handleRemember(w http.ResponseWriter, r *http.Request) {
@@ -142,11 +360,17 @@ func (s *Server) handleRemember(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusBadRequest, "invalid memory scope")
 		return
 	}
+	for i := range req.Attachments {
+		if err := req.Attachments[i].Validate(); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
-	vec, err := s.embedder.Embed(r.Context(), req.Content)
+	vec, err := s.embed(r.Context(), embedder.DocumentText(req.Type, req.Content))
 	if err != nil {
 		s.logger.Error("failed to embed memory", "error", err)
-		s.writeError(w, http.StatusInternalServerError, "failed to generate embedding")
+		s.writeEmbedError(w, err)
 		return
 	}
 
@@ -164,6 +388,40 @@ func (s *Server) handleRemember(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:    now,
 		UpdatedAt:    now,
 		LastAccessed: now,
+		Attachments:  req.Attachments,
+		ExpiresAt:    req.ExpiresAt.UTC(),
+	}
+	s.typeTTL.Apply(&mem)
+	s.tags.Apply(&mem)
+
+	var duplicateOf string
+	if s.dedupThreshold > 0 && (req.DryRun || s.dedupOnRemember) {
+		dupes, dedupErr := store.FindDuplicatesInScope(r.Context(), s.store, vec, s.dedupThreshold, mem.Type, s.dedupScope)
+		if dedupErr != nil {
+			// Dedup is an optimisation, not a correctness gate — fail open.
+			s.logger.Warn("remember: dedup check failed, proceeding without dedup", "error", dedupErr)
+		}
+		best := -1.0
+		for i := range dupes {
+			if dupes[i].Score > best {
+				best, duplicateOf = dupes[i].Score, dupes[i].Memory.ID
+			}
+		}
+	}
+
+	var conflict *capture.ConflictWarning
+	if s.conflicts != nil && duplicateOf == "" {
+		conflict = s.conflicts.CheckStore(r.Context(), s.store, mem.Content, mem.Confidence, vec)
+	}
+
+	if req.DryRun {
+		wouldStore := duplicateOf == ""
+		s.writeJSON(w, http.StatusOK, rememberResponse{DuplicateOf: duplicateOf, Conflict: conflict, WouldStore: &wouldStore})
+		return
+	}
+	if duplicateOf != "" {
+		s.writeJSON(w, http.StatusOK, rememberResponse{ID: duplicateOf, DuplicateOf: duplicateOf})
+		return
 	}
 
 	if err = s.store.Upsert(r.Context(), mem, vec); err != nil {
@@ -172,14 +430,41 @@ func (s *Server) handleRemember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeJSON(w, http.StatusOK, rememberResponse{ID: mem.ID, Stored: true})
+	s.writeJSON(w, http.StatusOK, rememberResponse{ID: mem.ID, Stored: true, Conflict: conflict})
 }
 
 // recallRequest is the body accepted by POST /v1/recall.
 type recallRequest struct {
-	Message string `json:"message"`
-	Project string `json:"project"`
-	Budget  int    `json:"budget"`
+	Message     string   `json:"message"`
+	Project     string   `json:"project"`
+	Projects    []string `json:"projects"` // span several projects plus globals; merged with project
+	Budget      int      `json:"budget"`
+	ExpandDepth int      `json:"expand_depth"` // entity graph hops to traverse; 0 = server default
+	MaxMemories int      `json:"max_memories"` // cap on returned memories regardless of budget; 0 = no cap
+	Mode        string   `json:"mode"`         // "list" (default) or "synthesize"
+	Freshness   string   `json:"freshness"`    // "any" (default), "recent" or "established"
+	Format      string   `json:"format"`       // "plain" (default) or "grouped"
+
+	// RelevanceCutoff stops the context at the first memory scoring below
+	// this fraction of the top hit; 0 = server default.
+	RelevanceCutoff float64 `json:"relevance_cutoff"`
+
+	// PerMemoryMaxTokens truncates each memory to this many tokens before
+	// budgeting; 0 = server default.
+	PerMemoryMaxTokens int `json:"per_memory_max_tokens"`
+
+	// IncludeAttachments appends each memory's attachment references to
+	// the context; nil = server default.
+	IncludeAttachments *bool `json:"include_attachments"`
+
+	// OnConflict is "both", "newest" or "flag" (see recall.ResolveConflicts);
+	// empty = server default.
+	OnConflict string `json:"on_conflict"`
+
+	// Vector is a precomputed query embedding. When set it is searched
+	// as-is and the embedder is skipped; Message then only feeds the
+	// lexical, graph and synthesis steps and may be empty.
+	Vector []float32 `json:"vector"`
 }
 
 // recallResponse is returned by POST /v1/recall.
@@ -187,6 +472,22 @@ type recallResponse struct {
 	Context     string `json:"context"`
 	MemoryCount int    `json:"memory_count"`
 	TokensUsed  int    `json:"tokens_used"`
+	Synthesized bool   `json:"synthesized,omitempty"` // context is a synthesized summary
+
+	// TotalCandidates is the number of ranked memories before the budget and
+	// max_memories were applied; Truncated reports that some were left out.
+	TotalCandidates int  `json:"total_candidates"`
+	Truncated       bool `json:"truncated"`
+
+	// RecallID identifies this recall in the feedback log; set only when
+	// feedback logging is enabled.
+	RecallID string `json:"recall_id,omitempty"`
+
+	// Conflicts lists the groups of competing memories in the context.
+	Conflicts []recall.Conflict `json:"conflicts,omitempty"`
+
+	// ContextJSON lists the memories in the context, in rank order.
+	ContextJSON []recall.ContextItem `json:"context_json"`
 }
 
 func (s *Server) handleRecall(w http.ResponseWriter, r *http.Request) {
@@ -197,69 +498,229 @@ func (s *Server) handleRecall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Message == "" {
-		s.writeError(w, http.StatusBadRequest, "message is required")
+	topMode := req.Mode == recall.ModeTop
+	if topMode && (req.Message != "" || len(req.Vector) > 0) {
+		s.writeError(w, http.StatusBadRequest, `mode "top" ranks without a query; omit message and vector`)
+		return
+	}
+	if !topMode && req.Message == "" && len(req.Vector) == 0 {
+		s.writeError(w, http.StatusBadRequest, "message or vector is required")
 		return
 	}
 	if req.Budget <= 0 {
 		req.Budget = 2000
 	}
-
-	vec, err := s.embedder.Embed(r.Context(), req.Message)
-	if err != nil {
-		s.logger.Error("failed to embed recall query", "error", err)
-		s.writeError(w, http.StatusInternalServerError, "failed to generate embedding")
+	if req.ExpandDepth < 0 {
+		s.writeError(w, http.StatusBadRequest, "expand_depth must be non-negative")
 		return
 	}
-
-	var filters *store.SearchFilters
-	if req.Project != "" {
-		proj := req.Project
-		filters = &store.SearchFilters{Project: &proj}
+	if req.MaxMemories < 0 {
+		s.writeError(w, http.StatusBadRequest, "max_memories must be non-negative")
+		return
 	}
-
-	results, err := s.store.Search(r.Context(), vec, 50, filters)
-	if err != nil {
-		s.logger.Error("failed to search store", "error", err)
-		s.writeError(w, http.StatusInternalServerError, "failed to search memories")
+	if !recall.ValidMode(req.Mode) {
+		s.writeError(w, http.StatusBadRequest, `mode must be "list", "synthesize" or "top"`)
+		return
+	}
+	if req.Mode == recall.ModeSynthesize && s.synthesizer == nil {
+		s.writeError(w, http.StatusBadRequest, "synthesize mode is not enabled on this server")
+		return
+	}
+	if req.Mode == recall.ModeSynthesize && req.Message == "" {
+		s.writeError(w, http.StatusBadRequest, "synthesize mode requires a message")
+		return
+	}
+	if !recall.ValidFreshness(req.Freshness) {
+		s.writeError(w, http.StatusBadRequest, `freshness must be "any", "recent" or "established"`)
+		return
+	}
+	if !recall.ValidFormat(req.Format) {
+		s.writeError(w, http.StatusBadRequest, `format must be "plain" or "grouped"`)
+		return
+	}
+	if req.RelevanceCutoff < 0 || req.RelevanceCutoff > 1 {
+		s.writeError(w, http.StatusBadRequest, "relevance_cutoff must be between 0 and 1")
+		return
+	}
+	if req.RelevanceCutoff == 0 {
+		req.RelevanceCutoff = s.cutoff
+	}
+	if req.PerMemoryMaxTokens < 0 {
+		s.writeError(w, http.StatusBadRequest, "per_memory_max_tokens must be non-negative")
+		return
+	}
+	if req.PerMemoryMaxTokens == 0 {
+		req.PerMemoryMaxTokens = s.perMemory
+	}
+	includeAttachments := s.attachRefs
+	if req.IncludeAttachments != nil {
+		includeAttachments = *req.IncludeAttachments
+	}
+	if !recall.ValidConflictMode(req.OnConflict) {
+		s.writeError(w, http.StatusBadRequest, `on_conflict must be "both", "newest" or "flag"`)
 		return
 	}
+	if req.OnConflict == "" {
+		req.OnConflict = s.onConflict
+	}
+
+	ctx, span := tracing.Start(r.Context(), "api.recall", "recall.budget", req.Budget, "recall.project", req.Project)
+	defer span.End()
+
+	filters := store.ProjectFilter(req.Project, req.Projects)
+	now := time.Now().UTC()
+	filters = s.freshness.ApplyFilters(req.Freshness, filters, now)
+
+	var (
+		vec    []float32
+		ranked []models.RecallResult
+	)
+	if topMode {
+		results, err := recall.TopCandidates(ctx, s.store, filters, recall.TopCandidateLimit)
+		if err != nil {
+			span.RecordError(err)
+			s.logger.Error("failed to list memories", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "failed to list memories")
+			return
+		}
+		results = s.freshness.FilterResults(req.Freshness, results, now)
+		ranked = s.recall.Rank(results, req.Project, "")
+	} else {
+		var ok bool
+		vec, ok = s.queryVector(ctx, w, req.Vector, s.recall.ExpandQuery(req.Message))
+		if !ok {
+			return
+		}
 
-	ranked := s.recall.RecallWithGraph(r.Context(), req.Message, vec, results, req.Project)
+		results, broadened, err := store.SearchBroadening(ctx, s.store, vec, 50, filters, s.broaden, s.minMemories)
+		if err != nil {
+			span.RecordError(err)
+			s.logger.Error("failed to search store", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "failed to search memories")
+			return
+		}
+		if broadened {
+			s.logger.Debug("recall: broadened project recall to global memories", "project", req.Project)
+		}
+		results = s.freshness.FilterResults(req.Freshness, results, now)
+		ranked = s.recall.RecallWithGraphDepth(ctx, req.Message, vec, results, req.Project, req.ExpandDepth)
+	}
+	if s.filter != nil {
+		ranked = s.filter.Filter(ctx, req.Message, ranked, 0)
+	}
+	if s.contradict != nil {
+		ranked = recall.DetectConflicts(ctx, s.contradict, s.store, ranked, s.logger)
+	}
+	ranked, conflicts := recall.ResolveConflicts(ranked, req.OnConflict)
 
 	var contents []string
 	for i := range ranked {
 		contents = append(contents, ranked[i].Memory.Content)
 	}
 
-	formattedCtx, count := tokenizer.FormatMemoriesWithBudget(contents, req.Budget)
+	_, formatSpan := tracing.Start(ctx, "recall.format", "recall.budget", req.Budget, "recall.candidates", len(contents))
+	maxCount := recall.RelevanceCap(ranked, req.RelevanceCutoff, req.MaxMemories)
+	shown := recall.MarkConflicts(recall.TruncateEach(ranked, req.PerMemoryMaxTokens), conflicts, req.OnConflict)
+	if includeAttachments {
+		shown = recall.WithAttachmentRefs(shown)
+	}
+	formattedCtx, count := recall.FormatContext(req.Format, shown, req.Budget, s.minMemories, maxCount)
 	tokensUsed := tokenizer.EstimateTokens(formattedCtx)
+	formatSpan.SetAttributes("memory.count", count, "tokens.used", tokensUsed)
+	formatSpan.End()
+	span.SetAttributes("memory.count", count, "tokens.used", tokensUsed)
+
+	synthesized := false
+	if req.Mode == recall.ModeSynthesize && count > 0 {
+		summary, synErr := s.synthesizer.Synthesize(ctx, req.Message, contents[:count])
+		if synErr != nil {
+			s.logger.Warn("handleRecall: synthesis failed, returning memory list", "error", synErr)
+		} else {
+			formattedCtx = summary
+			tokensUsed = tokenizer.EstimateTokens(summary)
+			synthesized = true
+		}
+	}
 
 	// Update access metadata for returned memories.
-	for i := 0; i < count && i < len(ranked); i++ {
-		if err := s.store.UpdateAccessMetadata(r.Context(), ranked[i].Memory.ID); err != nil {
-			s.logger.Warn("handleRecall: UpdateAccessMetadata", "id", ranked[i].Memory.ID, "error", err)
+	if !s.skipAccess {
+		if err := s.store.UpdateAccessMetadataBatch(ctx, recall.TopIDs(ranked, count)); err != nil {
+			s.logger.Warn("handleRecall: UpdateAccessMetadataBatch", "count", count, "error", err)
 		}
 	}
 
+	var recallID string
+	if s.feedbackLog != nil {
+		recallID = uuid.New().String()
+		if err := s.feedbackLog.LogRecall(recallID, req.Message, req.Project, vec, ranked, count); err != nil {
+			s.logger.Warn("handleRecall: feedback log", "error", err)
+			recallID = ""
+		}
+	}
+
+	metrics.RecallTokens.Observe(float64(tokensUsed))
 	s.writeJSON(w, http.StatusOK, recallResponse{
 		Context:     formattedCtx,
 		MemoryCount: count,
 		TokensUsed:  tokensUsed,
+		Synthesized: synthesized,
+
+		TotalCandidates: len(contents),
+		Truncated:       count < len(contents),
+		RecallID:        recallID,
+		Conflicts:       recall.ConflictsIn(conflicts, ranked[:count]),
+		ContextJSON:     recall.ContextItems(shown, count),
 	})
 }
 
-// updateRequest is the body accepted by PUT /v1/memories/{id}.
+// recallFeedbackRequest is the body accepted by POST /v1/recall/feedback.
+type recallFeedbackRequest struct {
+	RecallID  string   `json:"recall_id"`
+	Useful    []string `json:"useful"`
+	NotUseful []string `json:"not_useful"`
+}
+
+func (s *Server) handleRecallFeedback(w http.ResponseWriter, r *http.Request) {
+	if s.feedbackLog == nil {
+		s.writeError(w, http.StatusNotFound, "recall feedback logging is not enabled on this server")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req recallFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.RecallID == "" {
+		s.writeError(w, http.StatusBadRequest, "recall_id is required")
+		return
+	}
+	if len(req.Useful) == 0 && len(req.NotUseful) == 0 {
+		s.writeError(w, http.StatusBadRequest, "at least one of useful or not_useful is required")
+		return
+	}
+	if err := s.feedbackLog.LogFeedback(req.RecallID, req.Useful, req.NotUseful); err != nil {
+		s.logger.Error("failed to log recall feedback", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to record feedback")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, map[string]bool{"recorded": true})
+}
+
+// updateRequest is the body accepted by PUT and PATCH /v1/memories/{id}.
 // All fields are optional — only non-nil/non-zero values are applied.
+// AppendContent adds to the existing content instead of replacing it and
+// cannot be combined with Content.
 // Project and Confidence use pointer types so callers can explicitly set
 // them to zero-like values (empty string or 0.0).
 type updateRequest struct {
-	Content    string             `json:"content"`
-	Type       models.MemoryType  `json:"type"`
-	Scope      models.MemoryScope `json:"scope"`
-	Tags       []string           `json:"tags"`
-	Project    *string            `json:"project"`    // nil = not provided
-	Confidence *float64           `json:"confidence"` // nil = not provided
+	Content       string             `json:"content"`
+	AppendContent string             `json:"append_content"`
+	Type          models.MemoryType  `json:"type"`
+	Scope         models.MemoryScope `json:"scope"`
+	Tags          []string           `json:"tags"`
+	Project       *string            `json:"project"`    // nil = not provided
+	Confidence    *float64           `json:"confidence"` // nil = not provided
 }
 
 func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
@@ -275,6 +736,10 @@ func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	if req.Content != "" && req.AppendContent != "" {
+		s.writeError(w, http.StatusBadRequest, "content and append_content are mutually exclusive")
+		return
+	}
 
 	mem, err := s.store.Get(r.Context(), id)
 	if err != nil {
@@ -287,18 +752,50 @@ func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate and apply patches.
+	newContent, ok := s.applyUpdate(w, mem, req)
+	if !ok {
+		return
+	}
+	s.tags.Apply(mem)
+
+	var vec []float32
+	if newContent != "" {
+		mem.Content = newContent
+		var embedErr error
+		vec, embedErr = s.embed(r.Context(), embedder.DocumentText(mem.Type, newContent))
+		if embedErr != nil {
+			s.logger.Error("failed to embed updated content", "id", id, "error", embedErr)
+			s.writeEmbedError(w, embedErr)
+			return
+		}
+	}
+	// When content is unchanged, vec stays nil — Upsert will preserve the existing embedding.
+
+	mem.UpdatedAt = time.Now().UTC()
+	if upsertErr := s.store.Upsert(r.Context(), *mem, vec); upsertErr != nil {
+		s.logger.Error("failed to upsert updated memory", "id", id, "error", upsertErr)
+		s.writeError(w, http.StatusInternalServerError, "failed to update memory")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, mem)
+}
+
+// applyUpdate validates req and applies its field patches to mem. It returns
+// the new content, empty when req leaves content unchanged. On a validation
+// error it writes a 400 response and returns false.
+func (s *Server) applyUpdate(w http.ResponseWriter, mem *models.Memory, req updateRequest) (string, bool) {
 	if req.Type != "" {
 		if !req.Type.IsValid() {
 			s.writeError(w, http.StatusBadRequest, "invalid memory type")
-			return
+			return "", false
 		}
 		mem.Type = req.Type
 	}
 	if req.Scope != "" {
 		if !req.Scope.IsValid() {
 			s.writeError(w, http.StatusBadRequest, "invalid memory scope")
-			return
+			return "", false
 		}
 		mem.Scope = req.Scope
 	}
@@ -308,7 +805,7 @@ func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
 	if req.Confidence != nil {
 		if *req.Confidence < 0 || *req.Confidence > 1 {
 			s.writeError(w, http.StatusBadRequest, "confidence must be between 0.0 and 1.0")
-			return
+			return "", false
 		}
 		mem.Confidence = *req.Confidence
 	}
@@ -316,27 +813,69 @@ func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
 		mem.Tags = req.Tags
 	}
 
-	var vec []float32
-	if req.Content != "" {
-		mem.Content = req.Content
-		var embedErr error
-		vec, embedErr = s.embedder.Embed(r.Context(), req.Content)
-		if embedErr != nil {
-			s.logger.Error("failed to embed updated content", "id", id, "error", embedErr)
-			s.writeError(w, http.StatusInternalServerError, "failed to generate embedding")
+	newContent := req.Content
+	if strings.TrimSpace(req.AppendContent) != "" {
+		newContent = models.AppendContent(mem.Content, req.AppendContent)
+	}
+	return newContent, true
+}
+
+// handleSupersede is the history-preserving variant of handleUpdate: instead
+// of overwriting the memory it stores a new version whose supersedes_id points
+// at the old one. The old memory is invalidated, so search and recall return
+// only the new version, but it stays reachable through the chain.
+func (s *Server) handleSupersede(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		s.writeError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req updateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Content != "" && req.AppendContent != "" {
+		s.writeError(w, http.StatusBadRequest, "content and append_content are mutually exclusive")
+		return
+	}
+
+	old, err := s.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			s.writeError(w, http.StatusNotFound, "memory not found")
 			return
 		}
+		s.logger.Error("failed to get memory for supersede", "id", id, "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to get memory")
+		return
 	}
-	// When content is unchanged, vec stays nil — Upsert will preserve the existing embedding.
 
-	mem.UpdatedAt = time.Now().UTC()
-	if upsertErr := s.store.Upsert(r.Context(), *mem, vec); upsertErr != nil {
-		s.logger.Error("failed to upsert updated memory", "id", id, "error", upsertErr)
-		s.writeError(w, http.StatusInternalServerError, "failed to update memory")
+	next := old.Successor(uuid.New().String(), time.Now().UTC())
+	newContent, ok := s.applyUpdate(w, &next, req)
+	if !ok {
+		return
+	}
+	s.tags.Apply(&next)
+	if newContent != "" {
+		next.Content = newContent
+	}
+	vec, err := s.embed(r.Context(), embedder.DocumentText(next.Type, next.Content))
+	if err != nil {
+		s.logger.Error("failed to embed superseding content", "id", id, "error", err)
+		s.writeEmbedError(w, err)
 		return
 	}
 
-	s.writeJSON(w, http.StatusOK, mem)
+	if err := s.store.Upsert(r.Context(), next, vec); err != nil {
+		s.logger.Error("failed to store superseding memory", "id", id, "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to supersede memory")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, next)
 }
 
 // listResponse is returned by GET /v1/memories.
@@ -355,8 +894,29 @@ func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
 	projectStr := q.Get("project")
 	tagsStr := q.Get("tags") // comma-separated
 
-	if typeStr != "" || scopeStr != "" || projectStr != "" || tagsStr != "" {
-		filters = &store.SearchFilters{}
+	// Time windows on created_at and last_accessed, as RFC3339 timestamps.
+	var windows [4]*time.Time
+	for i, key := range []string{"created_after", "created_before", "accessed_after", "accessed_before"} {
+		v := q.Get(key)
+		if v == "" {
+			continue
+		}
+		t, parseErr := time.Parse(time.RFC3339, v)
+		if parseErr != nil {
+			s.writeError(w, http.StatusBadRequest, key+" must be an RFC3339 timestamp")
+			return
+		}
+		windows[i] = &t
+	}
+	hasWindow := windows != [4]*time.Time{}
+
+	if typeStr != "" || scopeStr != "" || projectStr != "" || tagsStr != "" || hasWindow {
+		filters = &store.SearchFilters{
+			CreatedAfter:   windows[0],
+			CreatedBefore:  windows[1],
+			AccessedAfter:  windows[2],
+			AccessedBefore: windows[3],
+		}
 		if typeStr != "" {
 			mt := models.MemoryType(typeStr)
 			if !mt.IsValid() {
@@ -460,6 +1020,74 @@ func (s *Server) handleGetMemory(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, mem)
 }
 
+// maxBatchGetIDs caps how many IDs POST /v1/memories/batch-get accepts.
+const maxBatchGetIDs = 1000
+
+// batchGetRequest is the body accepted by POST /v1/memories/batch-get.
+type batchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// batchGetResponse is returned by POST /v1/memories/batch-get.
+type batchGetResponse struct {
+	Memories []models.Memory `json:"memories"`
+	Missing  []string        `json:"missing"`
+	Count    int             `json:"count"`
+}
+
+// handleBatchGet resolves many memory IDs in one store round-trip. Found
+// memories are returned in request order; unknown or trashed IDs are listed
+// in missing. Duplicate IDs are collapsed.
+func (s *Server) handleBatchGet(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req batchGetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.IDs) == 0 {
+		s.writeError(w, http.StatusBadRequest, "ids is required")
+		return
+	}
+	if len(req.IDs) > maxBatchGetIDs {
+		s.writeError(w, http.StatusBadRequest, "too many ids (max 1000 per request)")
+		return
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+	ids := make([]string, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	byID, err := s.store.GetMany(r.Context(), ids)
+	if err != nil {
+		s.logger.Error("failed to batch-get memories", "count", len(ids), "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to get memories")
+		return
+	}
+
+	memories := make([]models.Memory, 0, len(byID))
+	missing := []string{}
+	for _, id := range ids {
+		if mem, ok := byID[id]; ok {
+			memories = append(memories, mem)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, batchGetResponse{
+		Memories: memories,
+		Missing:  missing,
+		Count:    len(memories),
+	})
+}
+
 func (s *Server) handleDeleteMemory(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
@@ -467,6 +1095,20 @@ func (s *Server) handleDeleteMemory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.trash && r.URL.Query().Get("permanent") != "true" {
+		if _, err := s.store.Trash(r.Context(), id); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				s.writeError(w, http.StatusNotFound, "memory not found")
+				return
+			}
+			s.logger.Error("failed to trash memory", "id", id, "error", err)
+			s.writeError(w, http.StatusInternalServerError, "failed to delete memory")
+			return
+		}
+		s.writeJSON(w, http.StatusOK, map[string]bool{"deleted": true, "trashed": true})
+		return
+	}
+
 	if err := s.store.Delete(r.Context(), id); err != nil {
 		if errors.Is(err, store.ErrNotFound) {
 			s.writeError(w, http.StatusNotFound, "memory not found")
@@ -480,14 +1122,370 @@ func (s *Server) handleDeleteMemory(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
 }
 
-// searchRequest is the body accepted by POST /v1/search.
-type searchRequest struct {
-	Message string             `json:"message"`
-	Limit   int                `json:"limit"`
+// defaultBulkDeleteLimit and maxBulkDeleteLimit bound how many memories one
+// DELETE /v1/memories call removes; callers repeat the call while "more" is true.
+const (
+	defaultBulkDeleteLimit = 1000
+	maxBulkDeleteLimit     = 10000
+)
+
+// bulkDeleteRequest is the body of DELETE /v1/memories.
+type bulkDeleteRequest struct {
+	Project   string             `json:"project"`
+	Type      models.MemoryType  `json:"type"`
+	Scope     models.MemoryScope `json:"scope"`
+	Tags      []string           `json:"tags"`
+	Confirm   bool               `json:"confirm"`   // must be true
+	Permanent bool               `json:"permanent"` // bypass the trash
+	Limit     int                `json:"limit"`
+}
+
+// bulkDeleteResponse is returned by DELETE /v1/memories.
+type bulkDeleteResponse struct {
+	Deleted int  `json:"deleted"`
+	Trashed bool `json:"trashed"`
+	More    bool `json:"more"` // further matches remain beyond limit
+}
+
+func (s *Server) handleBulkDelete(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req bulkDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if !req.Confirm {
+		s.writeError(w, http.StatusBadRequest, "confirm must be true to delete by filter")
+		return
+	}
+	if req.Type != "" && !req.Type.IsValid() {
+		s.writeError(w, http.StatusBadRequest, "invalid type filter")
+		return
+	}
+	if req.Scope != "" && !req.Scope.IsValid() {
+		s.writeError(w, http.StatusBadRequest, "invalid scope filter")
+		return
+	}
+	if req.Project == "" && req.Type == "" && req.Scope == "" && len(req.Tags) == 0 {
+		s.writeError(w, http.StatusBadRequest, "at least one of project, type, scope or tags is required")
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultBulkDeleteLimit
+	}
+	if req.Limit > maxBulkDeleteLimit {
+		req.Limit = maxBulkDeleteLimit
+	}
+
+	filters := &store.SearchFilters{Tags: req.Tags}
+	if req.Project != "" {
+		filters.Project = &req.Project
+	}
+	if req.Type != "" {
+		filters.Type = &req.Type
+	}
+	if req.Scope != "" {
+		filters.Scope = &req.Scope
+	}
+
+	trash := s.trash && !req.Permanent
+	deleted, more, err := store.DeleteByFilter(r.Context(), s.store, filters, req.Limit, trash)
+	if err != nil {
+		s.logger.Error("failed to bulk delete memories", "deleted", deleted, "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to delete memories")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, bulkDeleteResponse{Deleted: deleted, Trashed: trash, More: more})
+}
+
+// maxVectorsPerRequest caps how many vectors POST /v1/vectors returns in one
+// call; callers page through larger sets with the returned cursor.
+const maxVectorsPerRequest = 1000
+
+// memoryVector is a single memory's stored embedding.
+type memoryVector struct {
+	ID        string    `json:"id"`
+	Dimension int       `json:"dimension"`
+	Vector    []float32 `json:"vector"`
+}
+
+func (s *Server) handleGetVector(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		s.writeError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	vec, err := s.store.GetVector(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			s.writeError(w, http.StatusNotFound, "memory not found")
+			return
+		}
+		s.logger.Error("failed to get vector", "id", id, "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to get vector")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, memoryVector{ID: id, Dimension: len(vec), Vector: vec})
+}
+
+// vectorsRequest is the body accepted by POST /v1/vectors. Either IDs or the
+// filter fields select memories; when IDs is set the filter is ignored.
+type vectorsRequest struct {
+	IDs     []string           `json:"ids"`
 	Project string             `json:"project"`
 	Type    models.MemoryType  `json:"type"`
 	Scope   models.MemoryScope `json:"scope"`
 	Tags    []string           `json:"tags"`
+	Limit   int                `json:"limit"`
+	Cursor  string             `json:"cursor"`
+}
+
+// vectorsResponse is returned by POST /v1/vectors.
+type vectorsResponse struct {
+	Vectors    []memoryVector `json:"vectors"`
+	Count      int            `json:"count"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func (s *Server) handleVectors(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req vectorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.IDs) > maxVectorsPerRequest {
+		s.writeError(w, http.StatusBadRequest, "too many ids (max 1000 per request)")
+		return
+	}
+	if req.Type != "" && !req.Type.IsValid() {
+		s.writeError(w, http.StatusBadRequest, "invalid type filter")
+		return
+	}
+	if req.Scope != "" && !req.Scope.IsValid() {
+		s.writeError(w, http.StatusBadRequest, "invalid scope filter")
+		return
+	}
+	if req.Limit <= 0 || req.Limit > maxVectorsPerRequest {
+		req.Limit = maxVectorsPerRequest
+	}
+
+	ctx := r.Context()
+	ids := req.IDs
+	var nextCursor string
+	if len(ids) == 0 {
+		filters := &store.SearchFilters{Tags: req.Tags}
+		if req.Project != "" {
+			proj := req.Project
+			filters.Project = &proj
+		}
+		if req.Type != "" {
+			mt := req.Type
+			filters.Type = &mt
+		}
+		if req.Scope != "" {
+			ms := req.Scope
+			filters.Scope = &ms
+		}
+		memories, cursor, err := s.store.List(ctx, filters, uint64(req.Limit), req.Cursor)
+		if err != nil {
+			s.logger.Error("failed to list memories for vectors", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "failed to list memories")
+			return
+		}
+		nextCursor = cursor
+		ids = make([]string, len(memories))
+		for i := range memories {
+			ids[i] = memories[i].ID
+		}
+	}
+
+	vecs, err := s.store.GetVectors(ctx, ids)
+	if err != nil {
+		s.logger.Error("failed to get vectors", "count", len(ids), "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to get vectors")
+		return
+	}
+
+	out := make([]memoryVector, 0, len(vecs))
+	for _, id := range ids {
+		if vec, ok := vecs[id]; ok {
+			out = append(out, memoryVector{ID: id, Dimension: len(vec), Vector: vec})
+		}
+	}
+	s.writeJSON(w, http.StatusOK, vectorsResponse{Vectors: out, Count: len(out), NextCursor: nextCursor})
+}
+
+// sessionMemoriesResponse is returned by GET /v1/sessions/{session_id}/memories.
+type sessionMemoriesResponse struct {
+	SessionID string          `json:"session_id"`
+	Memories  []models.Memory `json:"memories"`
+	Count     int             `json:"count"`
+}
+
+// sessionMemoriesPageSize is the page size used when walking the store for a
+// session's memories; maxSessionMemories bounds the total returned.
+const (
+	sessionMemoriesPageSize uint64 = 500
+	maxSessionMemories             = 5000
+)
+
+func (s *Server) handleSessionMemories(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("session_id")
+	if sessionID == "" {
+		s.writeError(w, http.StatusBadRequest, "session_id is required")
+		return
+	}
+
+	filters := &store.SearchFilters{SessionID: sessionID}
+	memories := []models.Memory{}
+	var cur string
+	for len(memories) < maxSessionMemories {
+		page, next, err := s.store.List(r.Context(), filters, sessionMemoriesPageSize, cur)
+		if err != nil {
+			s.logger.Error("failed to list session memories", "session_id", sessionID, "error", err)
+			s.writeError(w, http.StatusInternalServerError, "failed to list session memories")
+			return
+		}
+		memories = append(memories, page...)
+		if next == "" {
+			break
+		}
+		cur = next
+	}
+	if len(memories) > maxSessionMemories {
+		memories = memories[:maxSessionMemories]
+	}
+
+	sort.SliceStable(memories, func(i, j int) bool {
+		return memories[i].CreatedAt.Before(memories[j].CreatedAt)
+	})
+
+	s.writeJSON(w, http.StatusOK, sessionMemoriesResponse{
+		SessionID: sessionID,
+		Memories:  memories,
+		Count:     len(memories),
+	})
+}
+
+// preferenceEntry is one structured preference returned by GET /v1/preferences.
+type preferenceEntry struct {
+	ID string `json:"id"`
+	models.Preference
+	Content    string    `json:"content"`
+	Confidence float64   `json:"confidence"`
+	Project    string    `json:"project,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// preferencesResponse is returned by GET /v1/preferences.
+type preferencesResponse struct {
+	Subject     string            `json:"subject"`
+	Preferences []preferenceEntry `json:"preferences"`
+	Count       int               `json:"count"`
+}
+
+// handlePreferences returns the structured preferences recorded for a
+// subject, newest first. Preferences captured without a triple are not listed.
+func (s *Server) handlePreferences(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	subject := strings.TrimSpace(q.Get("subject"))
+	if subject == "" {
+		s.writeError(w, http.StatusBadRequest, "subject is required")
+		return
+	}
+
+	prefType := models.MemoryTypePreference
+	filters := &store.SearchFilters{Type: &prefType, PreferenceSubject: subject}
+	if project := q.Get("project"); project != "" {
+		filters.Project = &project
+	}
+
+	entries := []preferenceEntry{}
+	var cur string
+	for len(entries) < maxSessionMemories {
+		page, next, err := s.store.List(r.Context(), filters, sessionMemoriesPageSize, cur)
+		if err != nil {
+			s.logger.Error("failed to list preferences", "subject", subject, "error", err)
+			s.writeError(w, http.StatusInternalServerError, "failed to list preferences")
+			return
+		}
+		for i := range page {
+			p, ok := page[i].PreferenceTriple()
+			if !ok {
+				continue
+			}
+			entries = append(entries, preferenceEntry{
+				ID:         page[i].ID,
+				Preference: p,
+				Content:    page[i].Content,
+				Confidence: page[i].Confidence,
+				Project:    page[i].Project,
+				CreatedAt:  page[i].CreatedAt,
+			})
+		}
+		if next == "" {
+			break
+		}
+		cur = next
+	}
+	if len(entries) > maxSessionMemories {
+		entries = entries[:maxSessionMemories]
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+
+	s.writeJSON(w, http.StatusOK, preferencesResponse{
+		Subject:     subject,
+		Preferences: entries,
+		Count:       len(entries),
+	})
+}
+
+// consolidatePreviewResponse is returned by POST /v1/lifecycle/consolidate/preview.
+type consolidatePreviewResponse struct {
+	Clusters    []lifecycle.ConsolidationCluster `json:"clusters"`
+	WouldDelete int                              `json:"would_delete"` // memories consolidation would delete
+}
+
+// handleConsolidatePreview reports the near-duplicate clusters the lifecycle
+// consolidation phase would merge, without deleting anything.
+func (s *Server) handleConsolidatePreview(w http.ResponseWriter, r *http.Request) {
+	lm := s.lifecycle
+	if lm == nil {
+		lm = lifecycle.NewManager(s.store, s.embedder, s.logger)
+	}
+	clusters, err := lm.PreviewConsolidation(r.Context())
+	if err != nil {
+		s.logger.Error("failed to preview consolidation", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to preview consolidation")
+		return
+	}
+	resp := consolidatePreviewResponse{Clusters: clusters}
+	if resp.Clusters == nil {
+		resp.Clusters = []lifecycle.ConsolidationCluster{}
+	}
+	for i := range clusters {
+		resp.WouldDelete += len(clusters[i].Members) - 1
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// searchRequest is the body accepted by POST /v1/search.
+type searchRequest struct {
+	Message  string             `json:"message"`
+	Limit    int                `json:"limit"`
+	Project  string             `json:"project"`
+	Projects []string           `json:"projects"` // span several projects plus globals; merged with project
+	Type     models.MemoryType  `json:"type"`
+	Scope    models.MemoryScope `json:"scope"`
+	Tags     []string           `json:"tags"`
+	Vector   []float32          `json:"vector"` // precomputed query embedding; skips the embedder
 }
 
 // searchResponse is returned by POST /v1/search.
@@ -496,6 +1494,7 @@ type searchResponse struct {
 }
 
 func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	defer metrics.SearchLatency.ObserveSince(time.Now())
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
 	var req searchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -503,8 +1502,8 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Message == "" {
-		s.writeError(w, http.StatusBadRequest, "message is required")
+	if req.Message == "" && len(req.Vector) == 0 {
+		s.writeError(w, http.StatusBadRequest, "message or vector is required")
 		return
 	}
 	const maxSearchLimit = 1000
@@ -515,10 +1514,11 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		req.Limit = maxSearchLimit
 	}
 
-	vec, err := s.embedder.Embed(r.Context(), req.Message)
-	if err != nil {
-		s.logger.Error("failed to embed search query", "error", err)
-		s.writeError(w, http.StatusInternalServerError, "failed to generate embedding")
+	ctx, span := tracing.Start(r.Context(), "api.search", "search.limit", req.Limit, "search.project", req.Project)
+	defer span.End()
+
+	vec, ok := s.queryVector(ctx, w, req.Vector, req.Message)
+	if !ok {
 		return
 	}
 
@@ -531,12 +1531,10 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var filters *store.SearchFilters
-	if req.Project != "" || req.Type != "" || req.Scope != "" || len(req.Tags) > 0 {
-		filters = &store.SearchFilters{}
-		if req.Project != "" {
-			proj := req.Project
-			filters.Project = &proj
+	filters := store.ProjectFilter(req.Project, req.Projects)
+	if req.Type != "" || req.Scope != "" || len(req.Tags) > 0 {
+		if filters == nil {
+			filters = &store.SearchFilters{}
 		}
 		if req.Type != "" {
 			mt := req.Type
@@ -551,8 +1549,9 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	results, err := s.store.Search(r.Context(), vec, uint64(req.Limit), filters)
+	results, err := s.store.Search(ctx, vec, uint64(req.Limit), filters)
 	if err != nil {
+		span.RecordError(err)
 		s.logger.Error("failed to search store", "error", err)
 		s.writeError(w, http.StatusInternalServerError, "failed to search memories")
 		return
@@ -561,6 +1560,71 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, searchResponse{Results: results})
 }
 
+// checkDuplicateRequest is the body accepted by POST /v1/check-duplicate.
+type checkDuplicateRequest struct {
+	Content   string            `json:"content"`
+	Type      models.MemoryType `json:"type"`
+	Threshold float64           `json:"threshold"` // 0 = the server's dedup threshold
+}
+
+// checkDuplicateResponse is returned by POST /v1/check-duplicate.
+type checkDuplicateResponse struct {
+	Duplicates []models.SearchResult `json:"duplicates"`
+	Threshold  float64               `json:"threshold"`
+}
+
+// handleCheckDuplicate runs the remember dedup check for content and returns
+// the similar memories without storing anything.
+func (s *Server) handleCheckDuplicate(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req checkDuplicateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		s.writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	if req.Type == "" {
+		req.Type = models.MemoryTypeFact
+	}
+	if !req.Type.IsValid() {
+		s.writeError(w, http.StatusBadRequest, "invalid memory type")
+		return
+	}
+	threshold := req.Threshold
+	if threshold == 0 {
+		threshold = s.dedupThreshold
+	}
+	if threshold == 0 {
+		s.writeError(w, http.StatusBadRequest, "threshold is required: dedup is disabled on this server")
+		return
+	}
+	if err := store.ValidateDedupThreshold(threshold); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vec, err := s.embed(r.Context(), embedder.DocumentText(req.Type, req.Content))
+	if err != nil {
+		s.logger.Error("failed to embed content", "error", err)
+		s.writeEmbedError(w, err)
+		return
+	}
+
+	dupes, err := store.CheckDuplicates(r.Context(), s.store, vec, threshold, req.Type, s.dedupScope)
+	if err != nil {
+		s.logger.Error("failed to check duplicates", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to check duplicates")
+		return
+	}
+	if dupes == nil {
+		dupes = []models.SearchResult{}
+	}
+	s.writeJSON(w, http.StatusOK, checkDuplicateResponse{Duplicates: dupes, Threshold: threshold})
+}
+
 func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := s.store.Stats(r.Context())
 	if err != nil {
@@ -572,6 +1636,27 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, stats)
 }
 
+// schemaResponse is returned by GET /v1/schema.
+type schemaResponse struct {
+	MemoryTypes        []models.MemoryType       `json:"memory_types"`
+	MemoryScopes       []models.MemoryScope      `json:"memory_scopes"`
+	MemoryVisibilities []models.MemoryVisibility `json:"memory_visibilities"`
+	EntityTypes        []models.EntityType       `json:"entity_types"`
+	RecallWeightFields []string                  `json:"recall_weight_fields"`
+}
+
+// handleSchema lists the enum values the API accepts so clients can discover
+// them instead of hardcoding them.
+func (s *Server) handleSchema(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, schemaResponse{
+		MemoryTypes:        models.ValidMemoryTypes,
+		MemoryScopes:       models.ValidMemoryScopes,
+		MemoryVisibilities: models.ValidMemoryVisibilities,
+		EntityTypes:        models.ValidEntityTypes,
+		RecallWeightFields: recall.WeightNames(),
+	})
+}
+
 // --- entity handlers ---
 
 func (s *Server) handleSearchEntities(w http.ResponseWriter, r *http.Request) {
@@ -599,6 +1684,49 @@ func (s *Server) handleSearchEntities(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]any{"entities": entities})
 }
 
+// entitySearchRequest is the body of POST /v1/entities/search.
+type entitySearchRequest struct {
+	Query  string    `json:"query"`
+	Limit  int       `json:"limit"`
+	Vector []float32 `json:"vector"` // precomputed query embedding; skips the embedder
+}
+
+// handleSemanticSearchEntities finds entities by embedding similarity rather
+// than name substring, e.g. "entities related to payments".
+func (s *Server) handleSemanticSearchEntities(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MB limit
+	var req entitySearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Query == "" && len(req.Vector) == 0 {
+		s.writeError(w, http.StatusBadRequest, "query or vector is required")
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	vec, ok := s.queryVector(r.Context(), w, req.Vector, req.Query)
+	if !ok {
+		return
+	}
+	results, err := s.store.SearchEntitiesByVector(r.Context(), vec, req.Limit)
+	if err != nil {
+		s.logger.Error("failed to search entities by vector", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to search entities")
+		return
+	}
+	if results == nil {
+		results = []models.EntitySearchResult{}
+	}
+	s.writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
 func (s *Server) handleGetEntity(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	entity, err := s.store.GetEntity(r.Context(), id)
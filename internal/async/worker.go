@@ -257,6 +257,7 @@ func (gp *GraphProcessor) runExtraction(ctx context.Context, item WorkItem) erro
 		Store:       gp.store,
 		GraphClient: gp.graphClient,
 		Logger:      gp.logger,
+		Embedder:    gp.embedder,
 	}
 
 	result := extract.Run(ctx, deps, memories)
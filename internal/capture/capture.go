@@ -35,6 +35,16 @@ type ClaudeCapturer struct {
 	client llm.LLMClient
 	model  string
 	logger *slog.Logger
+
+	// structuredPreferences asks the LLM for a subject/predicate/object
+	// triple on every preference memory.
+	structuredPreferences bool
+
+	// custom replaces the built-in extraction prompts when set.
+	custom *ExtractionTemplate
+
+	// minWords drops extracted memories shorter than this many words.
+	minWords int
 }
 
 // NewCapturer creates a new Claude-based memory capturer.
@@ -46,6 +56,46 @@ func NewCapturer(client llm.LLMClient, model string, logger *slog.Logger) *Claud
 	}
 }
 
+// WithStructuredPreferences enables extraction of a subject/predicate/object
+// triple for preference memories. Returns c for chaining.
+func (c *ClaudeCapturer) WithStructuredPreferences(enabled bool) *ClaudeCapturer {
+	c.structuredPreferences = enabled
+	return c
+}
+
+// WithPromptTemplate replaces the built-in extraction prompts with t. The
+// structured preference instruction is not added to custom prompts. nil
+// restores the built-in prompts. Returns c for chaining.
+func (c *ClaudeCapturer) WithPromptTemplate(t *ExtractionTemplate) *ClaudeCapturer {
+	c.custom = t
+	return c
+}
+
+// WithMinWords drops extracted memories with fewer than n words. 0 disables
+// the check. Returns c for chaining.
+func (c *ClaudeCapturer) WithMinWords(n int) *ClaudeCapturer {
+	c.minWords = n
+	return c
+}
+
+// tagsFieldInstruction is the last per-memory field in both extraction
+// prompts; the optional preference field is spliced in after it.
+const tagsFieldInstruction = "- tags: Relevant keywords for categorization\n"
+
+// preferenceFieldInstruction describes the structured preference triple.
+const preferenceFieldInstruction = `- preference: Only for type "preference": an object {"subject", "predicate", "object"}
+  e.g. "Ajit likes drone piloting" -> {"subject": "Ajit", "predicate": "likes", "object": "drone piloting"}
+`
+
+// promptTemplate returns base with the preference field instruction added
+// when structured preference extraction is enabled.
+func (c *ClaudeCapturer) promptTemplate(base string) string {
+	if !c.structuredPreferences {
+		return base
+	}
+	return strings.Replace(base, tagsFieldInstruction, tagsFieldInstruction+preferenceFieldInstruction, 1)
+}
+
 // extractionPromptTemplate is the base prompt; user/assistant content is injected via XML tags
 // to prevent prompt injection attacks.
 const extractionPromptTemplate = `You are a memory extraction system. Analyze the conversation and extract discrete, reusable memories.
@@ -96,7 +146,13 @@ type extractionResponse struct {
 // Extract analyzes a conversation turn and returns captured memories above the confidence threshold.
 func (c *ClaudeCapturer) Extract(ctx context.Context, userMsg, assistantMsg string) ([]models.CapturedMemory, error) {
 	// Escape XML-special characters to prevent prompt injection from user/assistant content.
-	prompt := fmt.Sprintf(extractionPromptTemplate, xmlutil.Escape(userMsg), xmlutil.Escape(assistantMsg))
+	if c.custom != nil {
+		return c.extractFromCustom(ctx, ExtractionTemplateData{
+			UserMessage:      xmlutil.Escape(userMsg),
+			AssistantMessage: xmlutil.Escape(assistantMsg),
+		})
+	}
+	prompt := fmt.Sprintf(c.promptTemplate(extractionPromptTemplate), xmlutil.Escape(userMsg), xmlutil.Escape(assistantMsg))
 	return c.extractFromPrompt(ctx, prompt)
 }
 
@@ -109,11 +165,27 @@ func (c *ClaudeCapturer) ExtractWithContext(ctx context.Context, userMsg, assist
 	for _, t := range priorTurns {
 		fmt.Fprintf(&sb, "[%s]: %s\n", xmlutil.Escape(t.Role), xmlutil.Escape(t.Content))
 	}
-	prompt := fmt.Sprintf(extractionPromptWithContextTemplate,
+	if c.custom != nil {
+		return c.extractFromCustom(ctx, ExtractionTemplateData{
+			UserMessage:      xmlutil.Escape(userMsg),
+			AssistantMessage: xmlutil.Escape(assistantMsg),
+			PriorTurns:       sb.String(),
+		})
+	}
+	prompt := fmt.Sprintf(c.promptTemplate(extractionPromptWithContextTemplate),
 		sb.String(), xmlutil.Escape(userMsg), xmlutil.Escape(assistantMsg))
 	return c.extractFromPrompt(ctx, prompt)
 }
 
+// extractFromCustom renders the custom prompt template and extracts from it.
+func (c *ClaudeCapturer) extractFromCustom(ctx context.Context, data ExtractionTemplateData) ([]models.CapturedMemory, error) {
+	prompt, err := c.custom.render(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.extractFromPrompt(ctx, prompt)
+}
+
 // extractFromPrompt calls Claude with the given prompt and parses the response into memories.
 func (c *ClaudeCapturer) extractFromPrompt(ctx context.Context, prompt string) ([]models.CapturedMemory, error) {
 	responseText, err := c.client.Complete(ctx, c.model,
@@ -148,7 +220,24 @@ func (c *ClaudeCapturer) extractFromPrompt(ctx context.Context, prompt string) (
 	// Filter out low-confidence extractions
 	var filtered []models.CapturedMemory
 	for _, m := range memories {
+		if strings.TrimSpace(m.Content) == "" {
+			continue
+		}
+		if words := len(strings.Fields(m.Content)); words < c.minWords {
+			c.logger.Info("dropping short captured memory", "words", words, "min_words", c.minWords, "content", m.Content)
+			continue
+		}
+		// Unknown types (e.g. invented by a custom prompt) are left for the
+		// classifier to decide.
+		if m.Type != "" && !m.Type.IsValid() {
+			m.Type = ""
+		}
 		if m.Confidence >= minCaptureConfidence {
+			// Keep a triple only when requested, complete, and on a preference.
+			if m.Preference != nil && (!c.structuredPreferences ||
+				m.Type != models.MemoryTypePreference || !m.Preference.IsComplete()) {
+				m.Preference = nil
+			}
 			filtered = append(filtered, m)
 		}
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 	"github.com/ajitpratap0/openclaw-cortex/pkg/xmlutil"
 )
 
@@ -119,3 +120,49 @@ func (d *ConflictDetector) Detect(ctx context.Context, newContent string, candid
 
 	return result.Contradicts, result.ContradictedID, result.Reason, nil
 }
+
+const (
+	// storeConflictMinSimilarity is the cosine similarity an existing memory
+	// needs before CheckStore asks Claude whether it conflicts.
+	storeConflictMinSimilarity = 0.75
+
+	// storeConflictMinConfidence is the confidence both the new and the
+	// existing memory need for a conflict to be worth flagging.
+	storeConflictMinConfidence = 0.7
+
+	// storeConflictCandidates caps how many neighbours CheckStore considers.
+	storeConflictCandidates = 5
+)
+
+// ConflictWarning reports that a memory being stored contradicts an existing
+// one. It is advisory: the caller decides whether to supersede.
+type ConflictWarning struct {
+	ConflictingID string `json:"conflicting_id"`
+	Reason        string `json:"reason"`
+}
+
+// CheckStore looks for a near-similar, high-confidence memory in st that
+// content (embedded as vec) contradicts. It returns nil when there is no
+// conflict, when confidence is too low to matter, or when the search or
+// Claude call fails — the check never blocks a store.
+func (d *ConflictDetector) CheckStore(ctx context.Context, st store.Store, content string, confidence float64, vec []float32) *ConflictWarning {
+	if confidence < storeConflictMinConfidence {
+		return nil
+	}
+	results, err := st.Search(ctx, vec, storeConflictCandidates, nil)
+	if err != nil {
+		d.logger.Warn("conflict_detector: candidate search failed, skipping conflict check", "error", err)
+		return nil
+	}
+	var candidates []models.Memory
+	for i := range results {
+		if results[i].Score >= storeConflictMinSimilarity && results[i].Memory.Confidence >= storeConflictMinConfidence {
+			candidates = append(candidates, results[i].Memory)
+		}
+	}
+	contradicts, id, reason, _ := d.Detect(ctx, content, candidates)
+	if !contradicts || id == "" {
+		return nil
+	}
+	return &ConflictWarning{ConflictingID: id, Reason: reason}
+}
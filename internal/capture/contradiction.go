@@ -236,6 +236,8 @@ func (d *MemoryContradictionDetector) enrichFromGraph(
 	}
 
 	// For each entity, find memory IDs linked via facts.
+	seen := make(map[string]bool)
+	var linkedIDs []string
 	for eid := range entityIDs {
 		facts, err := d.graphClient.GetFactsForEntity(ctx, eid)
 		if err != nil {
@@ -243,18 +245,26 @@ func (d *MemoryContradictionDetector) enrichFromGraph(
 		}
 		for fi := range facts {
 			for _, mid := range facts[fi].SourceMemoryIDs {
-				if _, already := byID[mid]; already {
+				if _, already := byID[mid]; already || seen[mid] {
 					continue
 				}
-				mem, err := d.store.Get(ctx, mid)
-				if err != nil || mem == nil {
-					continue
-				}
-				byID[mid] = *mem
-				scores[mid] = 0.75 // baseline for entity-linked candidates
+				seen[mid] = true
+				linkedIDs = append(linkedIDs, mid)
 			}
 		}
 	}
+	if len(linkedIDs) == 0 {
+		return
+	}
+
+	linked, err := d.store.GetMany(ctx, linkedIDs)
+	if err != nil {
+		return
+	}
+	for mid, mem := range linked {
+		byID[mid] = mem
+		scores[mid] = 0.75 // baseline for entity-linked candidates
+	}
 }
 
 // ── Stage 2 ──────────────────────────────────────────────────────────────────
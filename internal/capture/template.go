@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ExtractionTemplateData is passed to a custom extraction prompt template.
+// All fields are XML-escaped before rendering.
+type ExtractionTemplateData struct {
+	UserMessage      string
+	AssistantMessage string
+	// PriorTurns holds earlier turns as "[role]: content" lines; empty when
+	// extraction runs without conversation context.
+	PriorTurns string
+}
+
+// requiredTemplateFields are the CapturedMemory JSON keys a custom prompt must
+// ask for, so the response still parses into memories.
+var requiredTemplateFields = []string{"content", "type", "confidence"}
+
+// ExtractionTemplate is an operator-supplied capture prompt that replaces the
+// built-in extraction prompt, e.g. to focus capture on a team's domain.
+type ExtractionTemplate struct {
+	tmpl *template.Template
+}
+
+// NewExtractionTemplate parses text as a Go text/template over
+// ExtractionTemplateData. The template must render {{.UserMessage}} and
+// {{.AssistantMessage}} and must ask for a JSON array of objects with the
+// "content", "type" and "confidence" fields. An empty text returns nil, which
+// selects the built-in prompt.
+func NewExtractionTemplate(text string) (*ExtractionTemplate, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("capture").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("capture: parsing prompt template: %w", err)
+	}
+	t := &ExtractionTemplate{tmpl: tmpl}
+
+	// Render with sentinels to check that the conversation actually reaches
+	// the prompt and that the expected JSON shape is requested.
+	const userSentinel, assistantSentinel = "\x00user\x00", "\x00assistant\x00"
+	out, err := t.render(ExtractionTemplateData{UserMessage: userSentinel, AssistantMessage: assistantSentinel})
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(out, userSentinel) || !strings.Contains(out, assistantSentinel) {
+		return nil, fmt.Errorf("capture: prompt template must include {{.UserMessage}} and {{.AssistantMessage}}")
+	}
+	for _, field := range requiredTemplateFields {
+		if !strings.Contains(out, field) {
+			return nil, fmt.Errorf("capture: prompt template must ask for the %q field of each memory", field)
+		}
+	}
+	return t, nil
+}
+
+func (t *ExtractionTemplate) render(data ExtractionTemplateData) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("capture: rendering prompt template: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+// turnNamespace seeds the name-based UUIDs returned by MemoryID.
+var turnNamespace = uuid.MustParse("6f1c2a4e-3b7d-4c1e-9a55-0d2f8e6b7c31")
+
+// TurnID returns a deterministic identifier for one conversation turn, derived
+// from the session and both messages. Capturing the same turn twice yields the
+// same ID, which is what makes a retried capture idempotent.
+func TurnID(sessionID, userMsg, assistantMsg string) string {
+	h := sha256.New()
+	for _, part := range []string{sessionID, userMsg, assistantMsg} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// MemoryID returns the deterministic memory ID for content extracted from the
+// turn identified by turnID. A capture that dies partway and is re-run assigns
+// the same IDs to the memories it already stored, so callers can skip them
+// with a Get instead of storing them again.
+func MemoryID(turnID, content string) string {
+	return uuid.NewSHA1(turnNamespace, []byte(turnID+"\x00"+strings.TrimSpace(content))).String()
+}
+
+// RetryDedupThreshold is the cosine similarity at or above which a memory
+// extracted on a retried turn is treated as a rewording of one the earlier run
+// already stored for that turn. It sits below the regular dedup threshold
+// because a re-extraction of the same text rarely phrases a fact identically.
+const RetryDedupThreshold = 0.85
+
+// TurnCaptured reports whether st already holds memories captured from the
+// turn identified by turnID, i.e. whether the current capture is a retry.
+func TurnCaptured(ctx context.Context, st store.Store, turnID string) (bool, error) {
+	mems, _, err := st.List(ctx, &store.SearchFilters{TurnID: turnID}, 1, "")
+	if err != nil {
+		return false, fmt.Errorf("listing memories for turn %s: %w", turnID, err)
+	}
+	return len(mems) > 0, nil
+}
+
+// CapturedInTurn returns the memory already stored for turnID that vec most
+// resembles, when the similarity reaches RetryDedupThreshold. A retried
+// extraction that words a fact differently is caught here even though its
+// MemoryID differs from the one the earlier run stored.
+func CapturedInTurn(ctx context.Context, st store.Store, turnID string, vec []float32) (*models.Memory, bool, error) {
+	results, err := st.Search(ctx, vec, 1, &store.SearchFilters{TurnID: turnID})
+	if err != nil {
+		return nil, false, fmt.Errorf("searching memories for turn %s: %w", turnID, err)
+	}
+	if len(results) == 0 || results[0].Score < RetryDedupThreshold {
+		return nil, false, nil
+	}
+	return &results[0].Memory, true, nil
+}
@@ -2,10 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/spf13/viper"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/timeutil"
 )
 
 const (
@@ -27,6 +33,20 @@ type CaptureQualityConfig struct {
 	MinUserMessageLength         int      `mapstructure:"min_user_message_length"`
 	MinAssistantMessageLength    int      `mapstructure:"min_assistant_message_length"`
 	BlocklistPatterns            []string `mapstructure:"blocklist_patterns"`
+	// StructuredPreferences extracts a subject/predicate/object triple for
+	// captured preference memories so they can be queried via /v1/preferences.
+	StructuredPreferences bool `mapstructure:"structured_preferences"`
+	// PromptTemplate replaces the built-in capture extraction prompt with a
+	// Go text/template over .UserMessage, .AssistantMessage and .PriorTurns.
+	// PromptTemplateFile loads the template from a file instead; at most one
+	// may be set.
+	PromptTemplate     string `mapstructure:"prompt_template"`
+	PromptTemplateFile string `mapstructure:"prompt_template_file"`
+
+	// MinWords drops extracted memories with fewer words than this before
+	// they are embedded or stored, filtering fragments such as "ok, done".
+	// 0 disables the check.
+	MinWords int `mapstructure:"min_words"`
 }
 
 // SentryConfig holds Sentry error tracking settings.
@@ -35,11 +55,25 @@ type SentryConfig struct {
 	Environment string `mapstructure:"environment"`
 }
 
+// TracingConfig holds OpenTelemetry trace export settings. Spans are sent to
+// an OTLP/HTTP collector (protobuf encoding) at Endpoint + "/v1/traces".
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`     // default http://localhost:4318
+	ServiceName string  `mapstructure:"service_name"` // default openclaw-cortex
+	SampleRate  float64 `mapstructure:"sample_rate"`  // fraction of root spans kept, default 1.0
+}
+
 // HooksConfig holds configuration for the PostTurn hook pipeline.
 type HooksConfig struct {
 	// PostTurnConcurrency controls the number of memories processed concurrently
 	// in PostTurnHook.Execute. Must be between 1 and 16; defaults to 4.
 	PostTurnConcurrency int `mapstructure:"post_turn_concurrency"`
+
+	// TimeoutSeconds bounds each hook invocation, including embedding and
+	// the store round-trips. A hook that runs out of time emits empty output
+	// and says so on stderr. 0 = the default of 30 seconds.
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
 }
 
 // AsyncConfig controls the asynchronous graph pipeline (Phase 1 scaffold).
@@ -67,8 +101,39 @@ type Config struct {
 	EntityResolution EntityResolutionConfig `mapstructure:"entity_resolution"`
 	FactExtraction   FactExtractionConfig   `mapstructure:"fact_extraction"`
 	Sentry           SentryConfig           `mapstructure:"sentry"`
+	Tracing          TracingConfig          `mapstructure:"tracing"`
 	Hooks            HooksConfig            `mapstructure:"hooks"`
 	Async            AsyncConfig            `mapstructure:"async"`
+	Lifecycle        LifecycleConfig        `mapstructure:"lifecycle"`
+}
+
+// LifecycleConfig bounds how much of the store a lifecycle run scans.
+type LifecycleConfig struct {
+	// MaxScan caps the memories loaded per lifecycle scan (default 50000).
+	// Memories beyond the cap are skipped for that run and a warning is logged.
+	MaxScan int `mapstructure:"max_scan"`
+	// PageSize is the number of memories fetched per List call (default 500).
+	PageSize int `mapstructure:"page_size"`
+	// ConsolidationPartition limits which memories consolidation may merge:
+	// "none" (default, any permanent memories), "type", "project" or
+	// "type_project" (only within the same type and project).
+	ConsolidationPartition string `mapstructure:"consolidation_partition"`
+	// ConsolidationExcludeTags protects memories carrying any of these tags
+	// from being merged by consolidation, e.g. per-environment variants
+	// tagged env:prod and env:dev.
+	ConsolidationExcludeTags []string `mapstructure:"consolidation_exclude_tags"`
+	// SessionDecayHours is how long a session memory may go without access
+	// before lifecycle deletes it (default 24; must be > 0).
+	SessionDecayHours float64 `mapstructure:"session_decay_hours"`
+	// ConsolidationThreshold is the cosine similarity above which
+	// consolidation merges two permanent memories (default 0.92; 0 keeps the
+	// default).
+	ConsolidationThreshold float64 `mapstructure:"consolidation_threshold"`
+	// MergeMode selects what consolidation does with near-duplicates:
+	// "delete" (default) keeps the highest-confidence memory and deletes the
+	// rest; "llm" asks Claude to merge them into one new memory that
+	// supersedes them. "llm" needs Claude credentials.
+	MergeMode string `mapstructure:"merge_mode"`
 }
 
 // MemgraphConfig holds Memgraph database connection settings.
@@ -77,6 +142,15 @@ type MemgraphConfig struct {
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
 	Database string `mapstructure:"database"`
+
+	// RequireTLS refuses to start unless URI uses an encrypted scheme
+	// (bolt+s, bolt+ssc, neo4j+s or neo4j+ssc).
+	RequireTLS bool `mapstructure:"require_tls"`
+
+	// VectorMetric is the distance function of the vector indexes: "cosine"
+	// (default), "dot" or "euclid". Changing it on an existing database
+	// requires dropping the vector indexes so they are rebuilt.
+	VectorMetric string `mapstructure:"vector_metric"`
 }
 
 // EntityResolutionConfig holds entity resolution parameters.
@@ -92,12 +166,67 @@ type FactExtractionConfig struct {
 
 // RecallConfig holds re-ranking and latency budget settings for recall.
 type RecallConfig struct {
-	RerankScoreSpreadThreshold float64             `mapstructure:"rerank_score_spread_threshold"`
-	RerankLatencyBudgetHooksMs int                 `mapstructure:"rerank_latency_budget_hooks_ms"`
-	RerankLatencyBudgetCLIMs   int                 `mapstructure:"rerank_latency_budget_cli_ms"`
-	GraphBudgetMs              int                 `mapstructure:"graph_budget_ms"`
-	GraphBudgetCLIMs           int                 `mapstructure:"graph_budget_cli_ms"`
-	Weights                    RecallWeightsConfig `mapstructure:"weights"`
+	RerankScoreSpreadThreshold float64               `mapstructure:"rerank_score_spread_threshold"`
+	RerankLatencyBudgetHooksMs int                   `mapstructure:"rerank_latency_budget_hooks_ms"`
+	RerankLatencyBudgetCLIMs   int                   `mapstructure:"rerank_latency_budget_cli_ms"`
+	GraphBudgetMs              int                   `mapstructure:"graph_budget_ms"`
+	GraphBudgetCLIMs           int                   `mapstructure:"graph_budget_cli_ms"`
+	MinMemories                int                   `mapstructure:"min_memories"`        // guaranteed memory count regardless of budget; 0 = disabled
+	TrackAccess                bool                  `mapstructure:"track_access"`        // update access_count/last_accessed on recall; default true
+	Synthesize                 bool                  `mapstructure:"synthesize"`          // allow recall mode "synthesize" on the API and MCP servers; default false
+	FeedbackLog                string                `mapstructure:"feedback_log"`        // JSONL path for recall/feedback training data on the API server; empty = disabled
+	WriteRecencyBoost          float64               `mapstructure:"write_recency_boost"` // ranking bonus for new memories, halving daily since creation; 0 = disabled
+	RelevanceCutoff            float64               `mapstructure:"relevance_cutoff"`    // stop recall at the first memory scoring below this fraction of the top hit; 0 = disabled
+	Synonyms                   map[string]string     `mapstructure:"synonyms"`            // acronym/jargon → expansion used to expand recall queries; empty = disabled
+	BroadenWhenEmpty           bool                  `mapstructure:"broaden_when_empty"`  // retry a project recall with global memories when it finds fewer than max(min_memories, 1)
+	Freshness                  RecallFreshnessConfig `mapstructure:"freshness"`
+	Weights                    RecallWeightsConfig   `mapstructure:"weights"`
+
+	// CollapseSupersessions drops recall results superseded by another result
+	// so only the newest version of a fact is shown; default true.
+	CollapseSupersessions bool `mapstructure:"collapse_supersessions"`
+
+	// PerMemoryMaxTokens truncates each recalled memory to this many tokens
+	// before the token budget is applied, trading detail for breadth;
+	// 0 = disabled.
+	PerMemoryMaxTokens int `mapstructure:"per_memory_max_tokens"`
+
+	// IncludeAttachments appends each recalled memory's attachment
+	// references to the context so the agent knows where to look.
+	IncludeAttachments bool `mapstructure:"include_attachments"`
+
+	// OnConflict governs recalled memories that share an active conflict
+	// group: "both" (default) keeps all of them with conflict markers,
+	// "newest" keeps only the newest, "flag" keeps the newest marked as
+	// disputed.
+	OnConflict string `mapstructure:"on_conflict"`
+
+	// ConflictCheck also asks Claude whether similar top-ranked results
+	// contradict each other, treating each contradicting pair like a stored
+	// conflict group under OnConflict. Adds up to four Claude calls per
+	// recall; default false.
+	ConflictCheck bool `mapstructure:"conflict_check"`
+
+	// QueryTypeBoost is a ranking bonus for memories whose type matches the
+	// type the query's phrasing asks for ("how do I..." → procedure);
+	// 0 = disabled.
+	QueryTypeBoost float64 `mapstructure:"query_type_boost"`
+
+	// LLMFilter asks Claude to judge the top-ranked recall candidates and
+	// drops the ones that do not answer the query before the token budget
+	// is applied. Adds a Claude round-trip per recall; default false.
+	// LLMFilterCandidates is how many top-ranked results are judged (in
+	// batches of 10); the rest pass through unjudged. Default 30.
+	LLMFilter           bool `mapstructure:"llm_filter"`
+	LLMFilterCandidates int  `mapstructure:"llm_filter_candidates"`
+}
+
+// RecallFreshnessConfig defines the age buckets behind the recall "freshness"
+// option. Zero values fall back to the built-in defaults.
+type RecallFreshnessConfig struct {
+	RecentDays            int `mapstructure:"recent_days"`             // "recent" = created within this many days; default 7
+	EstablishedDays       int `mapstructure:"established_days"`        // "established" = older than this many days; default 30
+	EstablishedAccessDays int `mapstructure:"established_access_days"` // ...and accessed within this many days; default 30
 }
 
 // RecallWeightsConfig holds the scoring weights for the recall ranking formula.
@@ -120,6 +249,21 @@ type APIConfig struct {
 	CursorSecret   string  `mapstructure:"cursor_secret"`
 	RateLimitRPS   float64 `mapstructure:"rate_limit_rps"`
 	RateLimitBurst int     `mapstructure:"rate_limit_burst"`
+
+	// MaxConcurrentEmbeds caps simultaneous embedding calls made by the API;
+	// 0 = unlimited. Requests that wait longer than EmbedQueueTimeoutMs for a
+	// slot get 503.
+	MaxConcurrentEmbeds int `mapstructure:"max_concurrent_embeds"`
+	EmbedQueueTimeoutMs int `mapstructure:"embed_queue_timeout_ms"` // default: 10000
+
+	// MetricsToken, when set, must be sent as a Bearer token to scrape
+	// GET /metrics. Empty leaves /metrics unauthenticated, like /healthz.
+	MetricsToken string `mapstructure:"metrics_token"`
+
+	// DedupOnRemember skips storing a POST /v1/remember memory that is a
+	// near-duplicate (memory.dedup_threshold) of an existing one. Dry runs
+	// always report duplicates. Default false.
+	DedupOnRemember bool `mapstructure:"dedup_on_remember"`
 }
 
 // OllamaConfig holds Ollama embedding service settings.
@@ -141,6 +285,26 @@ type EmbedderConfig struct {
 	// Provider selects the embedding backend: "ollama" (default) | "lmstudio".
 	Provider string         `mapstructure:"provider"`
 	LMStudio LMStudioConfig `mapstructure:"lmstudio"`
+	// Warmup embeds a dummy string when long-running commands (serve, mcp)
+	// start, forcing the provider to load its model ahead of the first query.
+	Warmup bool `mapstructure:"warmup"`
+	// InputTemplate is a Go text/template applied to memory content before it
+	// is embedded for storage, e.g. "{{.Type}}: {{.Content}}". QueryTemplate
+	// is applied to search/recall queries ({{.Content}} only). Stored content
+	// stays raw. Empty = embed text unchanged. Changing either requires
+	// re-embedding existing memories.
+	InputTemplate string `mapstructure:"input_template"`
+	QueryTemplate string `mapstructure:"query_template"`
+
+	// MaxRetries is how many times an embedding request failing with a
+	// connection error, 429 or 5xx is retried; other errors fail fast.
+	// RetryBaseDelayMs is the first backoff, doubled per retry (0 = 500ms).
+	MaxRetries       int `mapstructure:"max_retries"`         // default: 2
+	RetryBaseDelayMs int `mapstructure:"retry_base_delay_ms"` // default: 500
+
+	// CacheSize is the number of vectors kept in an in-process LRU cache so
+	// identical text is embedded once. 0 = no cache.
+	CacheSize int `mapstructure:"cache_size"` // default: 1000
 }
 
 // ClaudeConfig holds Anthropic Claude API settings.
@@ -166,6 +330,15 @@ func (c ClaudeConfig) String() string {
 	return fmt.Sprintf("ClaudeConfig{APIKey:%s, Model:%s, GatewayURL:%s}", masked, c.Model, c.GatewayURL)
 }
 
+// UsesTLS reports whether URI selects an encrypted Bolt connection.
+func (c MemgraphConfig) UsesTLS() bool {
+	u, err := url.Parse(c.URI)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(u.Scheme, "+s") || strings.HasSuffix(u.Scheme, "+ssc")
+}
+
 // String returns a human-readable representation of EmbedderConfig.
 func (c EmbedderConfig) String() string {
 	if c.Provider == "lmstudio" {
@@ -192,16 +365,97 @@ type MemoryConfig struct {
 	DedupThresholdHook float64 `mapstructure:"dedup_threshold_hook"` // default 0.95
 	DefaultTTLHours    int     `mapstructure:"default_ttl_hours"`
 	VectorDimension    uint64  `mapstructure:"vector_dimension"`
+
+	// NormalizeTags lowercases, trims and de-duplicates tags on every write (CLI, API, MCP, capture),
+	// mapping them through TagSynonyms (e.g. golang → go). Default false.
+	NormalizeTags bool              `mapstructure:"normalize_tags"`
+	TagSynonyms   map[string]string `mapstructure:"tag_synonyms"`
+
+	// FastDedup enables a MinHash shingle pre-check that rejects textually
+	// near-identical content before it is embedded during bulk ingestion
+	// (store-batch, index, import with --dedup-threshold, post-turn capture).
+	// FastDedupThreshold is the estimated Jaccard similarity treated as a
+	// duplicate; 0 selects 0.9.
+	FastDedup          bool    `mapstructure:"fast_dedup"`
+	FastDedupThreshold float64 `mapstructure:"fast_dedup_threshold"`
+
+	// TrashRetentionHours is how long forgotten memories stay restorable in
+	// the trash before lifecycle purges them. 0 disables the trash: forget
+	// deletes permanently. Default 72.
+	TrashRetentionHours int `mapstructure:"trash_retention_hours"`
+
+	// DedupScope narrows the store-time dedup check to a recent, same-type
+	// subset of memories. The zero value compares against the whole collection.
+	DedupScope DedupScopeConfig `mapstructure:"dedup_scope"`
+
+	// TypeTTL maps memory types to a default time-to-live ("7d", "36h")
+	// assigned at store time to ttl-scoped memories without an explicit TTL.
+	// With TypeRetention set it applies to memories of every scope, and the
+	// lifecycle expire phase scans all scopes. Empty by default.
+	TypeTTL       map[string]string `mapstructure:"type_ttl"`
+	TypeRetention bool              `mapstructure:"type_retention"`
+
+	// MaxPerScope caps the number of memories per scope ("session": 10000).
+	// The lifecycle eviction phase deletes the least recently accessed
+	// memories of a scope over its cap; a scope larger than
+	// lifecycle.max_scan is skipped with a warning. Empty by default (no caps).
+	MaxPerScope map[string]int `mapstructure:"max_per_scope"`
+
+	// ConflictCheck asks Claude, on explicit store and remember, whether the
+	// new memory contradicts a near-similar high-confidence one, and returns
+	// a warning with the conflicting ID. The memory is still stored. Adds an
+	// LLM call per store; default false.
+	ConflictCheck bool `mapstructure:"conflict_check"`
+
+	// IndexMaxChunkTokens is the largest chunk, in estimated tokens, the
+	// indexer sends to the embedder; bigger chunks (e.g. one giant
+	// unbreakable line) are handled per IndexOversize: "split" (default)
+	// cuts them into pieces that fit, "truncate" keeps only the start.
+	// 0 = no limit.
+	IndexMaxChunkTokens int    `mapstructure:"index_max_chunk_tokens"`
+	IndexOversize       string `mapstructure:"index_oversize"`
+}
+
+// DedupScopeConfig limits which existing memories a dedup check compares against.
+type DedupScopeConfig struct {
+	// SameType only compares against memories of the same type.
+	SameType bool `mapstructure:"same_type"`
+	// WindowDays only compares against memories created in the last N days.
+	// 0 means no time limit.
+	WindowDays int `mapstructure:"window_days"`
 }
 
 // LoggingConfig holds structured logging settings.
 type LoggingConfig struct {
 	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Format string `mapstructure:"format"` // "text" (default) or "json" for log aggregation pipelines
+
+	// File, when set, writes logs to this file instead of stderr. Hooks run
+	// under Claude Code, which may swallow their stderr, so this is the
+	// reliable way to see what they did.
+	File       string `mapstructure:"file"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`  // rotate the file past this size (0 = never)
+	MaxBackups int    `mapstructure:"max_backups"`  // rotated files to keep
+	MaxAgeDays int    `mapstructure:"max_age_days"` // delete rotated files older than this (0 = keep)
+	Stderr     bool   `mapstructure:"stderr"`       // also log to stderr when File is set
 }
 
-// Load reads configuration from file and environment variables.
+// ProfileEnv names the environment variable that selects a config profile
+// when none is passed explicitly.
+const ProfileEnv = "OPENCLAW_CORTEX_PROFILE"
+
+// Load reads configuration from file and environment variables, applying
+// the profile named by ProfileEnv, if any.
 func Load() (*Config, error) {
+	return LoadProfile(os.Getenv(ProfileEnv))
+}
+
+// LoadProfile is like Load but applies the named profile: the settings under
+// profiles.<name> in the config file are merged over the top-level ones, so
+// one file can hold e.g. dev and prod Memgraph and Ollama endpoints.
+// Environment variables still take precedence over both. An empty name
+// applies no profile; an unknown name is an error.
+func LoadProfile(profile string) (*Config, error) {
 	v := viper.New()
 
 	// Defaults
@@ -209,12 +463,18 @@ func Load() (*Config, error) {
 	v.SetDefault("memgraph.username", "")
 	v.SetDefault("memgraph.password", "")
 	v.SetDefault("memgraph.database", "")
+	v.SetDefault("memgraph.require_tls", false)
+	v.SetDefault("memgraph.vector_metric", "cosine")
 
 	v.SetDefault("ollama.base_url", "http://localhost:11434")
 	v.SetDefault("ollama.model", "nomic-embed-text")
 
 	v.SetDefault("embedder.provider", "ollama")
 	v.SetDefault("embedder.lmstudio.url", "http://localhost:1234")
+	v.SetDefault("embedder.warmup", false)
+	v.SetDefault("embedder.max_retries", 2)
+	v.SetDefault("embedder.retry_base_delay_ms", 500)
+	v.SetDefault("embedder.cache_size", 1000)
 
 	v.SetDefault("claude.model", "claude-haiku-4-5-20251001")
 	v.SetDefault("claude.health_check_timeout_seconds", 15)
@@ -230,21 +490,86 @@ func Load() (*Config, error) {
 	v.SetDefault("memory.dedup_threshold_hook", 0.95)
 	v.SetDefault("memory.default_ttl_hours", 720) // 30 days
 	v.SetDefault("memory.vector_dimension", 768)
+	v.SetDefault("memory.normalize_tags", false)
+	_ = v.BindEnv("memory.normalize_tags", "OPENCLAW_CORTEX_MEMORY_NORMALIZE_TAGS")
+	v.SetDefault("memory.fast_dedup", false)
+	_ = v.BindEnv("memory.fast_dedup", "OPENCLAW_CORTEX_MEMORY_FAST_DEDUP")
+	v.SetDefault("memory.fast_dedup_threshold", 0.9)
+	_ = v.BindEnv("memory.fast_dedup_threshold", "OPENCLAW_CORTEX_MEMORY_FAST_DEDUP_THRESHOLD")
+	v.SetDefault("memory.trash_retention_hours", 72)
+	_ = v.BindEnv("memory.trash_retention_hours", "OPENCLAW_CORTEX_MEMORY_TRASH_RETENTION_HOURS")
+	v.SetDefault("memory.dedup_scope.same_type", false)
+	_ = v.BindEnv("memory.dedup_scope.same_type", "OPENCLAW_CORTEX_MEMORY_DEDUP_SCOPE_SAME_TYPE")
+	v.SetDefault("memory.dedup_scope.window_days", 0)
+	_ = v.BindEnv("memory.dedup_scope.window_days", "OPENCLAW_CORTEX_MEMORY_DEDUP_SCOPE_WINDOW_DAYS")
+	v.SetDefault("memory.type_retention", false)
+	_ = v.BindEnv("memory.type_retention", "OPENCLAW_CORTEX_MEMORY_TYPE_RETENTION")
+	v.SetDefault("memory.conflict_check", false)
+	_ = v.BindEnv("memory.conflict_check", "OPENCLAW_CORTEX_MEMORY_CONFLICT_CHECK")
+	v.SetDefault("memory.index_max_chunk_tokens", 2000)
+	_ = v.BindEnv("memory.index_max_chunk_tokens", "OPENCLAW_CORTEX_MEMORY_INDEX_MAX_CHUNK_TOKENS")
+	v.SetDefault("memory.index_oversize", "split")
+	_ = v.BindEnv("memory.index_oversize", "OPENCLAW_CORTEX_MEMORY_INDEX_OVERSIZE")
 
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "text")
+	_ = v.BindEnv("logging.level", "OPENCLAW_CORTEX_LOGGING_LEVEL")
+	_ = v.BindEnv("logging.format", "OPENCLAW_CORTEX_LOGGING_FORMAT")
+	v.SetDefault("logging.file", "")
+	v.SetDefault("logging.max_size_mb", 10)
+	v.SetDefault("logging.max_backups", 3)
+	v.SetDefault("logging.max_age_days", 0)
+	v.SetDefault("logging.stderr", false)
+	_ = v.BindEnv("logging.file", "OPENCLAW_CORTEX_LOGGING_FILE")
 
 	v.SetDefault("api.listen_addr", ":8080")
 	v.SetDefault("api.auth_token", "")
 	v.SetDefault("api.cursor_secret", "")
+	v.SetDefault("api.metrics_token", "")
 	v.SetDefault("api.rate_limit_rps", 100.0)
 	v.SetDefault("api.rate_limit_burst", 20)
+	v.SetDefault("api.max_concurrent_embeds", 0)
+	v.SetDefault("api.embed_queue_timeout_ms", 10000)
+	v.SetDefault("api.dedup_on_remember", false)
 
 	v.SetDefault("recall.rerank_score_spread_threshold", 0.15)
 	v.SetDefault("recall.rerank_latency_budget_hooks_ms", 100)
 	v.SetDefault("recall.rerank_latency_budget_cli_ms", 3000)
 	v.SetDefault("recall.graph_budget_ms", 50)
 	v.SetDefault("recall.graph_budget_cli_ms", 500)
+	v.SetDefault("recall.min_memories", 0)
+	_ = v.BindEnv("recall.min_memories", "OPENCLAW_CORTEX_RECALL_MIN_MEMORIES")
+	v.SetDefault("recall.track_access", true)
+	_ = v.BindEnv("recall.track_access", "OPENCLAW_CORTEX_RECALL_TRACK_ACCESS")
+	v.SetDefault("recall.synthesize", false)
+	_ = v.BindEnv("recall.synthesize", "OPENCLAW_CORTEX_RECALL_SYNTHESIZE")
+	v.SetDefault("recall.feedback_log", "")
+	_ = v.BindEnv("recall.feedback_log", "OPENCLAW_CORTEX_RECALL_FEEDBACK_LOG")
+	v.SetDefault("recall.write_recency_boost", 0.0)
+	_ = v.BindEnv("recall.write_recency_boost", "OPENCLAW_CORTEX_RECALL_WRITE_RECENCY_BOOST")
+	v.SetDefault("recall.relevance_cutoff", 0.0)
+	_ = v.BindEnv("recall.relevance_cutoff", "OPENCLAW_CORTEX_RECALL_RELEVANCE_CUTOFF")
+	v.SetDefault("recall.per_memory_max_tokens", 0)
+	_ = v.BindEnv("recall.per_memory_max_tokens", "OPENCLAW_CORTEX_RECALL_PER_MEMORY_MAX_TOKENS")
+	v.SetDefault("recall.include_attachments", false)
+	_ = v.BindEnv("recall.include_attachments", "OPENCLAW_CORTEX_RECALL_INCLUDE_ATTACHMENTS")
+	v.SetDefault("recall.on_conflict", "both")
+	v.SetDefault("recall.conflict_check", false)
+	_ = v.BindEnv("recall.on_conflict", "OPENCLAW_CORTEX_RECALL_ON_CONFLICT")
+	_ = v.BindEnv("recall.conflict_check", "OPENCLAW_CORTEX_RECALL_CONFLICT_CHECK")
+	v.SetDefault("recall.query_type_boost", 0.0)
+	_ = v.BindEnv("recall.query_type_boost", "OPENCLAW_CORTEX_RECALL_QUERY_TYPE_BOOST")
+	v.SetDefault("recall.llm_filter", false)
+	v.SetDefault("recall.llm_filter_candidates", 30)
+	_ = v.BindEnv("recall.llm_filter", "OPENCLAW_CORTEX_RECALL_LLM_FILTER")
+	_ = v.BindEnv("recall.llm_filter_candidates", "OPENCLAW_CORTEX_RECALL_LLM_FILTER_CANDIDATES")
+	v.SetDefault("recall.broaden_when_empty", false)
+	_ = v.BindEnv("recall.broaden_when_empty", "OPENCLAW_CORTEX_RECALL_BROADEN_WHEN_EMPTY")
+	v.SetDefault("recall.collapse_supersessions", true)
+	_ = v.BindEnv("recall.collapse_supersessions", "OPENCLAW_CORTEX_RECALL_COLLAPSE_SUPERSESSIONS")
+	v.SetDefault("recall.freshness.recent_days", 7)
+	v.SetDefault("recall.freshness.established_days", 30)
+	v.SetDefault("recall.freshness.established_access_days", 30)
 
 	v.SetDefault("recall.weights.similarity", 0.50)
 	v.SetDefault("recall.weights.recency", 0.08)
@@ -265,14 +590,27 @@ func Load() (*Config, error) {
 	v.SetDefault("capture_quality.min_user_message_length", 20)
 	v.SetDefault("capture_quality.min_assistant_message_length", 20)
 	v.SetDefault("capture_quality.blocklist_patterns", []string{"HEARTBEAT_OK", "NO_REPLY"})
+	v.SetDefault("capture_quality.structured_preferences", false)
+	v.SetDefault("capture_quality.min_words", 0)
 
 	v.SetDefault("sentry.dsn", "")
 	v.SetDefault("sentry.environment", "production")
 	_ = v.BindEnv("sentry.dsn", "SENTRY_DSN")
 	_ = v.BindEnv("sentry.environment", "SENTRY_ENVIRONMENT")
 
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.endpoint", "http://localhost:4318")
+	v.SetDefault("tracing.service_name", "openclaw-cortex")
+	v.SetDefault("tracing.sample_rate", 1.0)
+	_ = v.BindEnv("tracing.enabled", "OPENCLAW_CORTEX_TRACING_ENABLED")
+	_ = v.BindEnv("tracing.endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT")
+	_ = v.BindEnv("tracing.service_name", "OTEL_SERVICE_NAME")
+	_ = v.BindEnv("tracing.sample_rate", "OPENCLAW_CORTEX_TRACING_SAMPLE_RATE")
+
 	v.SetDefault("hooks.post_turn_concurrency", 4)
 	_ = v.BindEnv("hooks.post_turn_concurrency", "OPENCLAW_CORTEX_HOOKS_POST_TURN_CONCURRENCY")
+	v.SetDefault("hooks.timeout_seconds", 30)
+	_ = v.BindEnv("hooks.timeout_seconds", "OPENCLAW_CORTEX_HOOKS_TIMEOUT_SECONDS")
 
 	v.SetDefault("async.worker_count", 2)
 	v.SetDefault("async.queue_capacity", 512)
@@ -281,6 +619,12 @@ func Load() (*Config, error) {
 	v.SetDefault("async.wal_path", "")
 	v.SetDefault("async.wal_compact_every", 1000)
 	v.SetDefault("async.disabled", false)
+	v.SetDefault("lifecycle.max_scan", 50000)
+	v.SetDefault("lifecycle.page_size", 500)
+	v.SetDefault("lifecycle.consolidation_partition", "none")
+	v.SetDefault("lifecycle.session_decay_hours", 24)
+	v.SetDefault("lifecycle.consolidation_threshold", 0.92)
+	v.SetDefault("lifecycle.merge_mode", "delete")
 	_ = v.BindEnv("async.worker_count", "OPENCLAW_CORTEX_ASYNC_WORKER_COUNT")
 	_ = v.BindEnv("async.queue_capacity", "OPENCLAW_CORTEX_ASYNC_QUEUE_CAPACITY")
 	_ = v.BindEnv("async.max_retries", "OPENCLAW_CORTEX_ASYNC_MAX_RETRIES")
@@ -288,6 +632,12 @@ func Load() (*Config, error) {
 	_ = v.BindEnv("async.wal_path", "OPENCLAW_CORTEX_ASYNC_WAL_PATH")
 	_ = v.BindEnv("async.wal_compact_every", "OPENCLAW_CORTEX_ASYNC_WAL_COMPACT_EVERY")
 	_ = v.BindEnv("async.disabled", "OPENCLAW_CORTEX_ASYNC_DISABLED")
+	_ = v.BindEnv("lifecycle.max_scan", "OPENCLAW_CORTEX_LIFECYCLE_MAX_SCAN")
+	_ = v.BindEnv("lifecycle.page_size", "OPENCLAW_CORTEX_LIFECYCLE_PAGE_SIZE")
+	_ = v.BindEnv("lifecycle.consolidation_partition", "OPENCLAW_CORTEX_LIFECYCLE_CONSOLIDATION_PARTITION")
+	_ = v.BindEnv("lifecycle.session_decay_hours", "OPENCLAW_CORTEX_LIFECYCLE_SESSION_DECAY_HOURS")
+	_ = v.BindEnv("lifecycle.consolidation_threshold", "OPENCLAW_CORTEX_LIFECYCLE_CONSOLIDATION_THRESHOLD")
+	_ = v.BindEnv("lifecycle.merge_mode", "OPENCLAW_CORTEX_LIFECYCLE_MERGE_MODE")
 
 	// Config file
 	v.SetConfigName("config")
@@ -305,15 +655,30 @@ func Load() (*Config, error) {
 	_ = v.BindEnv("memgraph.username", "OPENCLAW_CORTEX_MEMGRAPH_USERNAME")
 	_ = v.BindEnv("memgraph.password", "OPENCLAW_CORTEX_MEMGRAPH_PASSWORD")
 	_ = v.BindEnv("memgraph.database", "OPENCLAW_CORTEX_MEMGRAPH_DATABASE")
+	_ = v.BindEnv("memgraph.require_tls", "OPENCLAW_CORTEX_MEMGRAPH_REQUIRE_TLS")
+	_ = v.BindEnv("memgraph.vector_metric", "OPENCLAW_CORTEX_MEMGRAPH_VECTOR_METRIC")
 	_ = v.BindEnv("ollama.base_url", "OPENCLAW_CORTEX_OLLAMA_BASE_URL")
 	_ = v.BindEnv("api.listen_addr", "OPENCLAW_CORTEX_API_LISTEN_ADDR")
 	_ = v.BindEnv("api.auth_token", "OPENCLAW_CORTEX_API_AUTH_TOKEN")
 	_ = v.BindEnv("api.cursor_secret", "OPENCLAW_CORTEX_API_CURSOR_SECRET")
+	_ = v.BindEnv("api.metrics_token", "OPENCLAW_CORTEX_API_METRICS_TOKEN")
 	_ = v.BindEnv("api.rate_limit_rps", "OPENCLAW_CORTEX_API_RATE_LIMIT_RPS")
 	_ = v.BindEnv("api.rate_limit_burst", "OPENCLAW_CORTEX_API_RATE_LIMIT_BURST")
+	_ = v.BindEnv("api.max_concurrent_embeds", "OPENCLAW_CORTEX_API_MAX_CONCURRENT_EMBEDS")
+	_ = v.BindEnv("api.embed_queue_timeout_ms", "OPENCLAW_CORTEX_API_EMBED_QUEUE_TIMEOUT_MS")
+	_ = v.BindEnv("api.dedup_on_remember", "OPENCLAW_CORTEX_API_DEDUP_ON_REMEMBER")
 	_ = v.BindEnv("embedder.provider", "OPENCLAW_CORTEX_EMBEDDER_PROVIDER")
 	_ = v.BindEnv("embedder.lmstudio.url", "OPENCLAW_CORTEX_LMSTUDIO_URL")
 	_ = v.BindEnv("embedder.lmstudio.model", "OPENCLAW_CORTEX_LMSTUDIO_MODEL")
+	_ = v.BindEnv("embedder.warmup", "OPENCLAW_CORTEX_EMBEDDER_WARMUP")
+	_ = v.BindEnv("embedder.max_retries", "OPENCLAW_CORTEX_EMBEDDER_MAX_RETRIES")
+	_ = v.BindEnv("embedder.retry_base_delay_ms", "OPENCLAW_CORTEX_EMBEDDER_RETRY_BASE_DELAY_MS")
+	_ = v.BindEnv("embedder.cache_size", "OPENCLAW_CORTEX_EMBEDDER_CACHE_SIZE")
+	_ = v.BindEnv("embedder.input_template", "OPENCLAW_CORTEX_EMBEDDER_INPUT_TEMPLATE")
+	_ = v.BindEnv("embedder.query_template", "OPENCLAW_CORTEX_EMBEDDER_QUERY_TEMPLATE")
+	_ = v.BindEnv("capture_quality.structured_preferences", "OPENCLAW_CORTEX_CAPTURE_STRUCTURED_PREFERENCES")
+	_ = v.BindEnv("capture_quality.prompt_template_file", "OPENCLAW_CORTEX_CAPTURE_PROMPT_TEMPLATE_FILE")
+	_ = v.BindEnv("capture_quality.min_words", "OPENCLAW_CORTEX_CAPTURE_MIN_WORDS")
 	_ = v.BindEnv("claude.gateway_url", "OPENCLAW_GATEWAY_URL")
 	_ = v.BindEnv("claude.gateway_token", "OPENCLAW_GATEWAY_TOKEN")
 
@@ -323,6 +688,11 @@ func Load() (*Config, error) {
 		}
 		// Config file not found is OK — use defaults + env vars
 	}
+	if profile != "" {
+		if err := applyProfile(v, profile); err != nil {
+			return nil, err
+		}
+	}
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
@@ -336,11 +706,32 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
+// applyProfile merges the settings under profiles.<name> over the config
+// file's top-level settings.
+func applyProfile(v *viper.Viper, name string) error {
+	settings, ok := v.Get("profiles." + name).(map[string]any)
+	if !ok {
+		return fmt.Errorf("config profile %q not found: define it under profiles.%s in the config file", name, name)
+	}
+	if err := v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("applying config profile %q: %w", name, err)
+	}
+	return nil
+}
+
 // Validate checks that required configuration fields are set and consistent.
 func (c *Config) Validate() error {
 	if c.Memgraph.URI == "" {
 		return fmt.Errorf("memgraph.uri must not be empty")
 	}
+	if c.Memgraph.RequireTLS && !c.Memgraph.UsesTLS() {
+		return fmt.Errorf("memgraph.require_tls is set but memgraph.uri does not use a TLS scheme (bolt+s, bolt+ssc, neo4j+s or neo4j+ssc)")
+	}
+	switch c.Memgraph.VectorMetric {
+	case "", "cosine", "dot", "euclid":
+	default:
+		return fmt.Errorf("memgraph.vector_metric must be one of cosine, dot, euclid; got %q", c.Memgraph.VectorMetric)
+	}
 	if c.Ollama.BaseURL == "" {
 		return fmt.Errorf("ollama.base_url must not be empty")
 	}
@@ -359,12 +750,116 @@ func (c *Config) Validate() error {
 	if c.Memory.DedupThresholdHook <= 0 || c.Memory.DedupThresholdHook > 1 {
 		return fmt.Errorf("memory.dedup_threshold_hook must be in range (0, 1]")
 	}
+	if c.Tracing.SampleRate < 0 || c.Tracing.SampleRate > 1 {
+		return fmt.Errorf("tracing.sample_rate must be in range [0, 1]")
+	}
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		return fmt.Errorf("tracing.endpoint must not be empty when tracing is enabled")
+	}
+	if c.Memory.TrashRetentionHours < 0 {
+		return fmt.Errorf("memory.trash_retention_hours must be >= 0")
+	}
+	if c.Memory.FastDedupThreshold < 0 || c.Memory.FastDedupThreshold > 1 {
+		return fmt.Errorf("memory.fast_dedup_threshold must be in range [0, 1]")
+	}
+	if c.Memory.DedupScope.WindowDays < 0 {
+		return fmt.Errorf("memory.dedup_scope.window_days must be >= 0")
+	}
+	for name, raw := range c.Memory.TypeTTL {
+		if !models.MemoryType(name).IsValid() {
+			return fmt.Errorf("memory.type_ttl: unknown memory type %q", name)
+		}
+		d, err := timeutil.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("memory.type_ttl.%s: %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("memory.type_ttl.%s must be positive", name)
+		}
+	}
+	for name, n := range c.Memory.MaxPerScope {
+		if !models.MemoryScope(name).IsValid() {
+			return fmt.Errorf("memory.max_per_scope: unknown memory scope %q", name)
+		}
+		if n < 0 {
+			return fmt.Errorf("memory.max_per_scope.%s must be >= 0", name)
+		}
+	}
+	if c.Memory.IndexMaxChunkTokens < 0 {
+		return fmt.Errorf("memory.index_max_chunk_tokens must be >= 0, got %d", c.Memory.IndexMaxChunkTokens)
+	}
+	if c.Memory.IndexOversize != "" && c.Memory.IndexOversize != "split" && c.Memory.IndexOversize != "truncate" {
+		return fmt.Errorf("memory.index_oversize must be \"split\" or \"truncate\", got %q", c.Memory.IndexOversize)
+	}
 	if c.Memory.VectorDimension <= 0 {
 		return fmt.Errorf("memory.vector_dimension must be greater than 0")
 	}
 	if c.Memory.DefaultTTLHours < 0 {
 		return fmt.Errorf("memory.default_ttl_hours must be >= 0")
 	}
+	if c.Lifecycle.MaxScan < 0 {
+		return fmt.Errorf("lifecycle.max_scan must be >= 0")
+	}
+	if c.Lifecycle.PageSize < 0 {
+		return fmt.Errorf("lifecycle.page_size must be >= 0")
+	}
+	switch c.Lifecycle.ConsolidationPartition {
+	case "", "none", "type", "project", "type_project":
+	default:
+		return fmt.Errorf("lifecycle.consolidation_partition must be \"none\", \"type\", \"project\" or \"type_project\", got %q", c.Lifecycle.ConsolidationPartition)
+	}
+	if c.Lifecycle.SessionDecayHours <= 0 {
+		return fmt.Errorf("lifecycle.session_decay_hours must be > 0, got %f", c.Lifecycle.SessionDecayHours)
+	}
+	if c.Lifecycle.ConsolidationThreshold < 0 || c.Lifecycle.ConsolidationThreshold > 1 {
+		return fmt.Errorf("lifecycle.consolidation_threshold must be in [0, 1], got %f", c.Lifecycle.ConsolidationThreshold)
+	}
+	switch c.Lifecycle.MergeMode {
+	case "", "delete", "llm":
+	default:
+		return fmt.Errorf("lifecycle.merge_mode must be \"delete\" or \"llm\", got %q", c.Lifecycle.MergeMode)
+	}
+	if c.API.MaxConcurrentEmbeds < 0 {
+		return fmt.Errorf("api.max_concurrent_embeds must be >= 0")
+	}
+	if c.API.EmbedQueueTimeoutMs < 0 {
+		return fmt.Errorf("api.embed_queue_timeout_ms must be >= 0")
+	}
+	if c.Recall.MinMemories < 0 {
+		return fmt.Errorf("recall.min_memories must be >= 0")
+	}
+	if c.Recall.WriteRecencyBoost < 0 || c.Recall.WriteRecencyBoost > 1 {
+		return fmt.Errorf("recall.write_recency_boost must be in [0, 1], got %f", c.Recall.WriteRecencyBoost)
+	}
+	if c.Recall.QueryTypeBoost < 0 || c.Recall.QueryTypeBoost > 1 {
+		return fmt.Errorf("recall.query_type_boost must be in [0, 1], got %f", c.Recall.QueryTypeBoost)
+	}
+	if c.Recall.RelevanceCutoff < 0 || c.Recall.RelevanceCutoff > 1 {
+		return fmt.Errorf("recall.relevance_cutoff must be in [0, 1], got %f", c.Recall.RelevanceCutoff)
+	}
+	if c.Recall.PerMemoryMaxTokens < 0 {
+		return fmt.Errorf("recall.per_memory_max_tokens must be >= 0, got %d", c.Recall.PerMemoryMaxTokens)
+	}
+	if c.Recall.LLMFilterCandidates < 0 {
+		return fmt.Errorf("recall.llm_filter_candidates must be >= 0, got %d", c.Recall.LLMFilterCandidates)
+	}
+	switch c.Recall.OnConflict {
+	case "", "both", "newest", "flag":
+	default:
+		return fmt.Errorf("recall.on_conflict must be \"both\", \"newest\" or \"flag\", got %q", c.Recall.OnConflict)
+	}
+	if c.Recall.Freshness.RecentDays < 0 {
+		return fmt.Errorf("recall.freshness.recent_days must be >= 0")
+	}
+	if c.Recall.Freshness.EstablishedDays < 0 {
+		return fmt.Errorf("recall.freshness.established_days must be >= 0")
+	}
+	if c.Recall.Freshness.EstablishedAccessDays < 0 {
+		return fmt.Errorf("recall.freshness.established_access_days must be >= 0")
+	}
+	if c.Hooks.TimeoutSeconds < 0 {
+		return fmt.Errorf("hooks.timeout_seconds must be >= 0, got %d", c.Hooks.TimeoutSeconds)
+	}
 	// Only validate async pipeline fields when async is enabled.
 	if !c.Async.Disabled {
 		if c.Async.WorkerCount < 1 {
@@ -381,6 +876,31 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Embedder.MaxRetries < 0 {
+		return fmt.Errorf("embedder.max_retries must be >= 0, got %d", c.Embedder.MaxRetries)
+	}
+	if c.Embedder.RetryBaseDelayMs < 0 {
+		return fmt.Errorf("embedder.retry_base_delay_ms must be >= 0, got %d", c.Embedder.RetryBaseDelayMs)
+	}
+	if c.Embedder.CacheSize < 0 {
+		return fmt.Errorf("embedder.cache_size must be >= 0, got %d", c.Embedder.CacheSize)
+	}
+	if _, err := template.New("input").Parse(c.Embedder.InputTemplate); err != nil {
+		return fmt.Errorf("embedder.input_template is not a valid template: %w", err)
+	}
+	if _, err := template.New("query").Parse(c.Embedder.QueryTemplate); err != nil {
+		return fmt.Errorf("embedder.query_template is not a valid template: %w", err)
+	}
+	if c.CaptureQuality.PromptTemplate != "" && c.CaptureQuality.PromptTemplateFile != "" {
+		return fmt.Errorf("capture_quality.prompt_template and capture_quality.prompt_template_file are mutually exclusive")
+	}
+	if _, err := template.New("capture").Parse(c.CaptureQuality.PromptTemplate); err != nil {
+		return fmt.Errorf("capture_quality.prompt_template is not a valid template: %w", err)
+	}
+	if c.CaptureQuality.MinWords < 0 {
+		return fmt.Errorf("capture_quality.min_words must be >= 0, got %d", c.CaptureQuality.MinWords)
+	}
+
 	// Validate provider name and provider-specific fields.
 	switch c.Embedder.Provider {
 	case "ollama", "":
@@ -393,6 +913,21 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("embedder.provider must be \"ollama\" or \"lmstudio\", got %q", c.Embedder.Provider)
 	}
 
+	switch c.Logging.Format {
+	case "text", "json", "":
+	default:
+		return fmt.Errorf("logging.format must be \"text\" or \"json\", got %q", c.Logging.Format)
+	}
+	if c.Logging.MaxSizeMB < 0 {
+		return fmt.Errorf("logging.max_size_mb must be >= 0")
+	}
+	if c.Logging.MaxBackups < 0 {
+		return fmt.Errorf("logging.max_backups must be >= 0")
+	}
+	if c.Logging.MaxAgeDays < 0 {
+		return fmt.Errorf("logging.max_age_days must be >= 0")
+	}
+
 	return nil
 }
 
@@ -31,6 +31,7 @@ func validCfg() *Config {
 			RetryDelaySeconds: 5,
 			WALCompactEvery:   1000,
 		},
+		Lifecycle: LifecycleConfig{SessionDecayHours: 24},
 	}
 }
 
@@ -132,3 +133,23 @@ func TestUAT_Validate_ValidConfigPasses(t *testing.T) {
 		t.Fatalf("valid config should pass, got: %v", err)
 	}
 }
+
+func TestUAT_Validate_LoggingFormat(t *testing.T) {
+	for _, format := range []string{"", "text", "json"} {
+		cfg := validCfg()
+		cfg.Logging.Format = format
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("format %q: unexpected error: %v", format, err)
+		}
+	}
+
+	cfg := validCfg()
+	cfg.Logging.Format = "logfmt"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error for logging.format = logfmt")
+	}
+	if !strings.Contains(err.Error(), "logging.format") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package embedder
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
+)
+
+// CachingEmbedder wraps an Embedder with an in-memory LRU cache of vectors
+// keyed by a hash of the model, dimension and exact input text, so repeated
+// queries and unchanged content are not re-embedded. It is safe for
+// concurrent use.
+type CachingEmbedder struct {
+	inner Embedder
+	model string
+	size  int
+
+	mu      sync.Mutex
+	order   *list.List // front = most recently used; values are *cacheEntry
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+type cacheEntry struct {
+	key [sha256.Size]byte
+	vec []float32
+}
+
+// NewCachingEmbedder returns inner wrapped in an LRU cache holding up to size
+// vectors. model identifies the embedding model (see ModelID) and is part of
+// the cache key. size <= 0 returns inner unchanged.
+func NewCachingEmbedder(inner Embedder, model string, size int) Embedder {
+	if size <= 0 {
+		return inner
+	}
+	return &CachingEmbedder{
+		inner:   inner,
+		model:   model,
+		size:    size,
+		order:   list.New(),
+		entries: make(map[[sha256.Size]byte]*list.Element, size),
+	}
+}
+
+// Embed returns the cached vector for text, embedding it on a miss.
+func (c *CachingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := c.key(text)
+	if vec, ok := c.get(key); ok {
+		metrics.Inc(metrics.EmbedCacheHits)
+		return vec, nil
+	}
+	metrics.Inc(metrics.EmbedCacheMisses)
+	vec, err := c.inner.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, vec)
+	return vec, nil
+}
+
+// EmbedBatch serves cached texts from the cache and embeds the rest, each
+// distinct text once, in a single inner EmbedBatch call. Results are returned
+// in input order.
+func (c *CachingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	keys := make([][sha256.Size]byte, len(texts))
+	var (
+		missTexts []string
+		missKeys  [][sha256.Size]byte
+		missSlot  = make(map[[sha256.Size]byte]int)
+	)
+	for i, t := range texts {
+		keys[i] = c.key(t)
+		if vec, ok := c.get(keys[i]); ok {
+			metrics.Inc(metrics.EmbedCacheHits)
+			out[i] = vec
+			continue
+		}
+		metrics.Inc(metrics.EmbedCacheMisses)
+		if _, seen := missSlot[keys[i]]; !seen {
+			missSlot[keys[i]] = len(missTexts)
+			missTexts = append(missTexts, t)
+			missKeys = append(missKeys, keys[i])
+		}
+	}
+	if len(missTexts) == 0 {
+		return out, nil
+	}
+
+	vecs, err := c.inner.EmbedBatch(ctx, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != len(missTexts) {
+		return nil, fmt.Errorf("embed batch: expected %d embeddings, got %d", len(missTexts), len(vecs))
+	}
+	for j, vec := range vecs {
+		c.put(missKeys[j], vec)
+	}
+	for i := range out {
+		if out[i] == nil {
+			out[i] = clone(vecs[missSlot[keys[i]]])
+		}
+	}
+	return out, nil
+}
+
+// Dimension returns the wrapped embedder's dimension.
+func (c *CachingEmbedder) Dimension() int {
+	return c.inner.Dimension()
+}
+
+// Len returns the number of cached vectors.
+func (c *CachingEmbedder) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *CachingEmbedder) key(text string) [sha256.Size]byte {
+	return sha256.Sum256(fmt.Appendf(nil, "%s\x00%d\x00%s", c.model, c.inner.Dimension(), text))
+}
+
+// get returns a copy of the cached vector so callers cannot mutate the cache.
+func (c *CachingEmbedder) get(key [sha256.Size]byte) ([]float32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return clone(el.Value.(*cacheEntry).vec), true
+}
+
+func (c *CachingEmbedder) put(key [sha256.Size]byte, vec []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).vec = clone(vec)
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, vec: clone(vec)})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func clone(vec []float32) []float32 {
+	return append([]float32(nil), vec...)
+}
@@ -3,6 +3,7 @@ package embedder
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/config"
 )
@@ -13,11 +14,14 @@ import (
 //   - "" or "ollama" → OllamaEmbedder using ollaCfg
 //   - "lmstudio"    → LMStudioEmbedder using embCfg.LMStudio
 //
-// Any other provider string is an error.
+// Any other provider string is an error. Both providers retry transient
+// failures per embCfg.MaxRetries and embCfg.RetryBaseDelayMs.
 func New(ollaCfg config.OllamaConfig, embCfg config.EmbedderConfig, dimension int, logger *slog.Logger) (Embedder, error) {
+	retryDelay := time.Duration(embCfg.RetryBaseDelayMs) * time.Millisecond
 	switch embCfg.Provider {
 	case "", "ollama":
-		return NewOllamaEmbedder(ollaCfg.BaseURL, ollaCfg.Model, dimension, logger), nil
+		return NewOllamaEmbedder(ollaCfg.BaseURL, ollaCfg.Model, dimension, logger).
+			WithRetry(embCfg.MaxRetries, retryDelay), nil
 
 	case "lmstudio":
 		if embCfg.LMStudio.Model == "" {
@@ -27,9 +31,23 @@ func New(ollaCfg config.OllamaConfig, embCfg config.EmbedderConfig, dimension in
 		if url == "" {
 			url = "http://localhost:1234"
 		}
-		return NewLMStudioEmbedder(url, embCfg.LMStudio.Model), nil
+		return NewLMStudioEmbedder(url, embCfg.LMStudio.Model).
+			WithRetry(embCfg.MaxRetries, retryDelay), nil
 
 	default:
 		return nil, fmt.Errorf("embedder: unknown provider %q (supported: ollama, lmstudio)", embCfg.Provider)
 	}
 }
+
+// ModelID returns a stable identifier for the embedding model New would
+// select, in the form "<provider>/<model>". It is recorded alongside stored
+// vectors so memories embedded by a different model can be found and
+// re-embedded incrementally.
+func ModelID(ollaCfg config.OllamaConfig, embCfg config.EmbedderConfig) string {
+	switch embCfg.Provider {
+	case "", "ollama":
+		return "ollama/" + ollaCfg.Model
+	default:
+		return embCfg.Provider + "/" + embCfg.LMStudio.Model
+	}
+}
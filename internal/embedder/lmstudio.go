@@ -1,13 +1,15 @@
 package embedder
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
 // LMStudioEmbedder implements Embedder using the LM Studio local server's
@@ -16,6 +18,7 @@ type LMStudioEmbedder struct {
 	baseURL string
 	model   string
 	client  *http.Client
+	retry   retryPolicy
 }
 
 // NewLMStudioEmbedder creates a new LM Studio embedder pointed at baseURL
@@ -25,9 +28,21 @@ func NewLMStudioEmbedder(baseURL, model string) *LMStudioEmbedder {
 		baseURL: baseURL,
 		model:   model,
 		client:  &http.Client{Timeout: 30 * time.Second},
+		retry:   defaultRetryPolicy(),
 	}
 }
 
+// WithRetry sets how many times a request failing with a connection error,
+// 429 or 5xx is retried, and the initial backoff, which doubles per retry.
+// maxRetries 0 disables retries; baseDelay <= 0 keeps DefaultRetryBaseDelay.
+func (e *LMStudioEmbedder) WithRetry(maxRetries int, baseDelay time.Duration) *LMStudioEmbedder {
+	e.retry.maxRetries = max(maxRetries, 0)
+	if baseDelay > 0 {
+		e.retry.baseDelay = baseDelay
+	}
+	return e
+}
+
 // lmStudioRequest is the JSON body sent to /v1/embeddings.
 type lmStudioRequest struct {
 	Model string `json:"model"`
@@ -44,20 +59,17 @@ type lmStudioResponse struct {
 // Embed returns a vector embedding for the given text by calling the LM Studio
 // /v1/embeddings endpoint.
 func (e *LMStudioEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	ctx, span := tracing.Start(ctx, "embedder.embed", "embedder.provider", "lmstudio", "embedder.model", e.model)
+	defer span.End()
+
 	reqBody, err := json.Marshal(lmStudioRequest{Model: e.model, Input: text})
 	if err != nil {
 		return nil, fmt.Errorf("lmstudio embed: marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/v1/embeddings", bytes.NewReader(reqBody))
+	resp, err := postJSON(ctx, e.client, e.retry, slog.Default(), "lmstudio", e.baseURL+"/v1/embeddings", reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("lmstudio embed: create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := e.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("lmstudio embed: do request: %w", err)
+		return nil, fmt.Errorf("lmstudio embed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -89,6 +101,10 @@ func (e *LMStudioEmbedder) Embed(ctx context.Context, text string) ([]float32, e
 
 // EmbedBatch returns embeddings for multiple texts by calling Embed in a loop.
 func (e *LMStudioEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	ctx, span := tracing.Start(ctx, "embedder.embed_batch",
+		"embedder.provider", "lmstudio", "embedder.model", e.model, "batch.size", len(texts))
+	defer span.End()
+
 	results := make([][]float32, len(texts))
 	for i, text := range texts {
 		vec, err := e.Embed(ctx, text)
@@ -1,7 +1,6 @@
 package embedder
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,13 +10,10 @@ import (
 	"time"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/sentry"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
-const (
-	ollamaHTTPTimeout   = 30 * time.Second
-	ollamaMaxRetries    = 3
-	ollamaRetryBaseWait = 500 * time.Millisecond
-)
+const ollamaHTTPTimeout = 30 * time.Second
 
 // OllamaEmbedder implements Embedder using the Ollama HTTP API.
 type OllamaEmbedder struct {
@@ -26,6 +22,7 @@ type OllamaEmbedder struct {
 	dimension int
 	client    *http.Client
 	logger    *slog.Logger
+	retry     retryPolicy
 }
 
 type ollamaEmbedRequest struct {
@@ -56,13 +53,27 @@ func NewOllamaEmbedder(baseURL, model string, dimension int, logger *slog.Logger
 		dimension: dimension,
 		client:    &http.Client{Timeout: ollamaHTTPTimeout},
 		logger:    logger,
+		retry:     defaultRetryPolicy(),
+	}
+}
+
+// WithRetry sets how many times a request failing with a connection error,
+// 429 or 5xx is retried, and the initial backoff, which doubles per retry.
+// maxRetries 0 disables retries; baseDelay <= 0 keeps DefaultRetryBaseDelay.
+func (o *OllamaEmbedder) WithRetry(maxRetries int, baseDelay time.Duration) *OllamaEmbedder {
+	o.retry.maxRetries = max(maxRetries, 0)
+	if baseDelay > 0 {
+		o.retry.baseDelay = baseDelay
 	}
+	return o
 }
 
 // Embed returns a vector embedding for the given text using the Ollama API.
 func (o *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	finish := sentry.StartSpan(ctx, "embed.ollama", "OllamaEmbedder.Embed")
 	defer finish()
+	ctx, span := tracing.Start(ctx, "embedder.embed", "embedder.provider", "ollama", "embedder.model", o.model)
+	defer span.End()
 	reqBody := ollamaEmbedRequest{
 		Model:  o.model,
 		Prompt: text,
@@ -73,46 +84,9 @@ func (o *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, err
 		return nil, fmt.Errorf("marshaling request: %w", err)
 	}
 
-	endpoint := o.baseURL + "/api/embeddings"
-
-	var resp *http.Response
-	for attempt := 0; attempt < ollamaMaxRetries; attempt++ {
-		if attempt > 0 {
-			wait := ollamaRetryBaseWait * time.Duration(1<<(attempt-1))
-			o.logger.Warn("retrying Ollama request", "attempt", attempt+1, "wait", wait)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(wait):
-			}
-		}
-
-		req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
-		if reqErr != nil {
-			return nil, fmt.Errorf("creating request: %w", reqErr)
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err = o.client.Do(req)
-		if err != nil {
-			if attempt < ollamaMaxRetries-1 {
-				continue
-			}
-			return nil, fmt.Errorf("calling Ollama API: %w", err)
-		}
-
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			_ = resp.Body.Close()
-			if attempt < ollamaMaxRetries-1 {
-				continue
-			}
-			return nil, fmt.Errorf("ollama API returned %d after %d attempts", resp.StatusCode, ollamaMaxRetries)
-		}
-		break
-	}
-
-	if resp == nil {
-		return nil, fmt.Errorf("ollama API: no response after %d attempts", ollamaMaxRetries)
+	resp, err := postJSON(ctx, o.client, o.retry, o.logger, "ollama", o.baseURL+"/api/embeddings", bodyBytes)
+	if err != nil {
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -148,6 +122,10 @@ func (o *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]fl
 		return nil, nil
 	}
 
+	ctx, span := tracing.Start(ctx, "embedder.embed_batch",
+		"embedder.provider", "ollama", "embedder.model", o.model, "batch.size", len(texts))
+	defer span.End()
+
 	// Single text — use the standard Embed path.
 	if len(texts) == 1 {
 		vec, err := o.Embed(ctx, texts[0])
@@ -167,46 +145,9 @@ func (o *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]fl
 		return nil, fmt.Errorf("embed batch: marshaling request: %w", err)
 	}
 
-	endpoint := o.baseURL + "/api/embed"
-
-	var resp *http.Response
-	for attempt := 0; attempt < ollamaMaxRetries; attempt++ {
-		if attempt > 0 {
-			wait := ollamaRetryBaseWait * time.Duration(1<<(attempt-1))
-			o.logger.Warn("retrying Ollama batch request", "attempt", attempt+1, "wait", wait)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(wait):
-			}
-		}
-
-		req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
-		if reqErr != nil {
-			return nil, fmt.Errorf("embed batch: creating request: %w", reqErr)
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err = o.client.Do(req)
-		if err != nil {
-			if attempt < ollamaMaxRetries-1 {
-				continue
-			}
-			return nil, fmt.Errorf("embed batch: calling Ollama API: %w", err)
-		}
-
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			_ = resp.Body.Close()
-			if attempt < ollamaMaxRetries-1 {
-				continue
-			}
-			return nil, fmt.Errorf("embed batch: ollama API returned %d after %d attempts", resp.StatusCode, ollamaMaxRetries)
-		}
-		break
-	}
-
-	if resp == nil {
-		return nil, fmt.Errorf("embed batch: no response after %d attempts", ollamaMaxRetries)
+	resp, err := postJSON(ctx, o.client, o.retry, o.logger, "ollama", o.baseURL+"/api/embed", bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("embed batch: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -0,0 +1,79 @@
+package embedder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of extra attempts an HTTP embedder makes
+	// after a transient failure before giving up.
+	DefaultMaxRetries = 2
+
+	// DefaultRetryBaseDelay is the wait before the first retry; each further
+	// retry doubles it.
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+)
+
+// retryPolicy controls how an HTTP embedder retries transient failures.
+type retryPolicy struct {
+	maxRetries int           // extra attempts after the first; 0 = no retry
+	baseDelay  time.Duration // wait before the first retry, doubled per retry
+}
+
+func defaultRetryPolicy() retryPolicy {
+	return retryPolicy{maxRetries: DefaultMaxRetries, baseDelay: DefaultRetryBaseDelay}
+}
+
+// retryableStatus reports whether an HTTP status is worth retrying: 429 and
+// any 5xx. Other 4xx responses will not succeed on retry and fail fast.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// postJSON POSTs body to endpoint, retrying connection errors, 429 and 5xx
+// responses with exponential backoff. It returns the first response with any
+// other status, which the caller must close, and stops as soon as ctx is done.
+func postJSON(ctx context.Context, client *http.Client, policy retryPolicy, logger *slog.Logger, provider, endpoint string, body []byte) (*http.Response, error) {
+	attempts := policy.maxRetries + 1
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			wait := policy.baseDelay * time.Duration(1<<(attempt-1))
+			logger.Warn("retrying embedding request", "provider", provider, "attempt", attempt+1, "wait", wait)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if attempt+1 < attempts {
+				continue
+			}
+			return nil, fmt.Errorf("calling %s API: %w", provider, err)
+		}
+		if retryableStatus(resp.StatusCode) {
+			_ = resp.Body.Close()
+			if attempt+1 < attempts {
+				continue
+			}
+			return nil, fmt.Errorf("%s API returned %d after %d attempts", provider, resp.StatusCode, attempts)
+		}
+		return resp, nil
+	}
+}
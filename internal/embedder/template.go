@@ -0,0 +1,95 @@
+package embedder
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"text/template"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// TemplateData is the data available to embedding input templates.
+// Query templates only receive Content; Type is empty.
+type TemplateData struct {
+	Type    string
+	Content string
+}
+
+// InputTemplate rewrites text before it is embedded, e.g. prefixing stored
+// memories with their type ("rule: always wrap errors") so type-aware queries
+// retrieve them more reliably. Stored content is never changed; only the
+// embedded representation is.
+type InputTemplate struct {
+	document *template.Template
+	query    *template.Template
+}
+
+// NewInputTemplate parses the document-side and query-side templates. Either
+// may be empty, in which case that side embeds text unchanged. Returns nil
+// when both are empty.
+func NewInputTemplate(document, query string) (*InputTemplate, error) {
+	if document == "" && query == "" {
+		return nil, nil
+	}
+	t := &InputTemplate{}
+	var err error
+	if document != "" {
+		if t.document, err = template.New("input").Option("missingkey=zero").Parse(document); err != nil {
+			return nil, fmt.Errorf("embedder: parsing input_template: %w", err)
+		}
+	}
+	if query != "" {
+		if t.query, err = template.New("query").Option("missingkey=zero").Parse(query); err != nil {
+			return nil, fmt.Errorf("embedder: parsing query_template: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// Document returns the text to embed for a stored memory. A nil template or
+// a render failure yields content unchanged.
+func (t *InputTemplate) Document(memType models.MemoryType, content string) string {
+	if t == nil || t.document == nil {
+		return content
+	}
+	return render(t.document, TemplateData{Type: string(memType), Content: content}, content)
+}
+
+// Query returns the text to embed for a search or recall query.
+func (t *InputTemplate) Query(text string) string {
+	if t == nil || t.query == nil {
+		return text
+	}
+	return render(t.query, TemplateData{Content: text}, text)
+}
+
+func render(tmpl *template.Template, data TemplateData, fallback string) string {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fallback
+	}
+	return b.String()
+}
+
+// activeTemplate is the process-wide template applied by DocumentText and
+// QueryText; nil means text is embedded as-is.
+var activeTemplate atomic.Pointer[InputTemplate]
+
+// SetInputTemplate installs t as the process-wide embedding input template.
+// Passing nil restores the default of embedding text unchanged.
+func SetInputTemplate(t *InputTemplate) {
+	activeTemplate.Store(t)
+}
+
+// DocumentText applies the active input template to a memory's content.
+// Use it for every embedding that is stored or compared against stored
+// vectors (store, capture, dedup, re-embed).
+func DocumentText(memType models.MemoryType, content string) string {
+	return activeTemplate.Load().Document(memType, content)
+}
+
+// QueryText applies the active query template to a search or recall query.
+func QueryText(text string) string {
+	return activeTemplate.Load().Query(text)
+}
@@ -0,0 +1,22 @@
+package embedder
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// warmupText is embedded once at startup; its content is irrelevant.
+const warmupText = "openclaw-cortex warmup"
+
+// Warmup embeds a throwaway string so the provider loads its model before the
+// first real query arrives. Ollama and LM Studio load models lazily, which
+// otherwise makes the first recall after startup noticeably slow. Returns how
+// long the warm-up embedding took.
+func Warmup(ctx context.Context, emb Embedder) (time.Duration, error) {
+	start := time.Now()
+	if _, err := emb.Embed(ctx, warmupText); err != nil {
+		return time.Since(start), fmt.Errorf("embedder warmup: %w", err)
+	}
+	return time.Since(start), nil
+}
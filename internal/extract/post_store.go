@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	graphpkg "github.com/ajitpratap0/openclaw-cortex/internal/graph"
 	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
@@ -42,6 +43,12 @@ type Deps struct {
 	GraphClient graphpkg.Client
 	// Logger is used for warnings on non-fatal errors.
 	Logger *slog.Logger
+	// Embedder, when set, embeds each extracted entity together with an
+	// excerpt of the memory it came from (see models.Entity.EmbeddingTextWith)
+	// before it is upserted, so entities touched
+	// by new memories stay searchable with Store.SearchEntitiesByVector.
+	// If nil, entities are stored without embeddings.
+	Embedder embedder.Embedder
 }
 
 // Result summarizes what was extracted from the given memories.
@@ -101,6 +108,15 @@ func Run(ctx context.Context, deps Deps, memories []StoredMemory) Result {
 			continue
 		}
 		for j := range entities {
+			if deps.Embedder != nil {
+				vec, embedErr := deps.Embedder.Embed(ctx, entities[j].EmbeddingTextWith([]string{memories[i].Content}))
+				if embedErr != nil {
+					logger.Warn("entity embedding failed, storing without embedding",
+						"entity", entities[j].Name, "error", embedErr)
+				} else {
+					entities[j].NameEmbedding = vec
+				}
+			}
 			if upsertErr := deps.Store.UpsertEntity(ctx, entities[j]); upsertErr != nil {
 				logger.Warn("upsert entity to store failed",
 					"entity", entities[j].Name, "error", upsertErr)
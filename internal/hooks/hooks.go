@@ -9,11 +9,13 @@ import (
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
 	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 	"github.com/ajitpratap0/openclaw-cortex/internal/sentry"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
 )
 
@@ -39,9 +41,19 @@ type PreTurnHook struct {
 	embedder  embedder.Embedder
 	store     store.Store
 	recaller  *recall.Recaller
-	reasoner  *recall.Reasoner // nil = disabled
+	reasoner  *recall.Reasoner        // nil = disabled
+	filter    *recall.RelevanceFilter // nil = disabled
 	rerankCfg RerankConfig
 	logger    *slog.Logger
+
+	minMemories       int  // guaranteed memory count regardless of budget; 0 = disabled
+	skipAccessUpdates bool // true = do not update access metadata for injected memories
+	broadenWhenEmpty  bool // true = a project recall that finds too little retries with global memories
+	perMemoryMax      int  // truncate each injected memory to this many tokens; 0 = disabled
+	attachmentRefs    bool // true = append each memory's attachment references
+
+	onConflict string                    // recall.ConflictBoth, ConflictNewest or ConflictFlag; "" = both
+	contradict *capture.ConflictDetector // nil = no LLM contradiction check on recall
 }
 
 // PreTurnInput contains the context for a pre-turn hook.
@@ -78,6 +90,65 @@ func (h *PreTurnHook) WithReasoner(r *recall.Reasoner, cfg RerankConfig) *PreTur
 	return h
 }
 
+// WithRelevanceFilter attaches an optional RelevanceFilter that drops ranked
+// memories Claude judges irrelevant to the message before the token budget is
+// applied. Must be called before the hook is used concurrently.
+func (h *PreTurnHook) WithRelevanceFilter(f *recall.RelevanceFilter) *PreTurnHook {
+	h.filter = f
+	return h
+}
+
+// WithMinMemories guarantees that at least n top-ranked memories are injected
+// even when the token budget would admit fewer; their content is truncated to
+// fit. n <= 0 disables the guarantee.
+func (h *PreTurnHook) WithMinMemories(n int) *PreTurnHook {
+	h.minMemories = n
+	return h
+}
+
+// WithBroadenWhenEmpty makes the hook retry its project recall with global
+// memories included when it finds fewer than max(minMemories, 1) memories,
+// so the first turns in a fresh project still get context.
+func (h *PreTurnHook) WithBroadenWhenEmpty(enabled bool) *PreTurnHook {
+	h.broadenWhenEmpty = enabled
+	return h
+}
+
+// WithPerMemoryMaxTokens truncates each injected memory to n tokens before
+// the token budget is applied, so more memories fit. n <= 0 disables it.
+func (h *PreTurnHook) WithPerMemoryMaxTokens(n int) *PreTurnHook {
+	h.perMemoryMax = n
+	return h
+}
+
+// WithAttachmentRefs appends each injected memory's attachment references
+// (file:, url:, commit:) to its content so the agent knows where to look.
+func (h *PreTurnHook) WithAttachmentRefs(enabled bool) *PreTurnHook {
+	h.attachmentRefs = enabled
+	return h
+}
+
+// WithOnConflict sets how injected memories that share an active conflict
+// group are handled (see recall.ResolveConflicts).
+func (h *PreTurnHook) WithOnConflict(mode string) *PreTurnHook {
+	h.onConflict = mode
+	return h
+}
+
+// WithConflictCheck enables the LLM contradiction check among the top
+// recalled memories (see recall.DetectConflicts). nil disables it.
+func (h *PreTurnHook) WithConflictCheck(cd *capture.ConflictDetector) *PreTurnHook {
+	h.contradict = cd
+	return h
+}
+
+// WithTrackAccess controls whether injected memories have their access
+// metadata updated. Tracking is enabled by default.
+func (h *PreTurnHook) WithTrackAccess(enabled bool) *PreTurnHook {
+	h.skipAccessUpdates = !enabled
+	return h
+}
+
 // Execute runs the pre-turn hook.
 func (h *PreTurnHook) Execute(ctx context.Context, input PreTurnInput) (*PreTurnOutput, error) {
 	finish := sentry.StartSpan(ctx, "hook.pre_turn", "PreTurnHook")
@@ -88,9 +159,13 @@ func (h *PreTurnHook) Execute(ctx context.Context, input PreTurnInput) (*PreTurn
 		input.TokenBudget = 2000
 	}
 
+	ctx, span := tracing.Start(ctx, "hook.pre_turn", "recall.budget", input.TokenBudget, "recall.project", input.Project)
+	defer span.End()
+
 	// Embed the current message
-	vec, err := h.embedder.Embed(ctx, input.Message)
+	vec, err := h.embedder.Embed(ctx, embedder.QueryText(h.recaller.ExpandQuery(input.Message)))
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("embedding message: %w", err)
 	}
 
@@ -99,13 +174,20 @@ func (h *PreTurnHook) Execute(ctx context.Context, input PreTurnInput) (*PreTurn
 	if input.Project != "" {
 		filter = &store.SearchFilters{Project: &input.Project}
 	}
-	results, err := h.store.Search(ctx, vec, preTurnSearchLimit, filter)
+	results, broadened, err := store.SearchBroadening(ctx, h.store, vec, preTurnSearchLimit, filter, h.broadenWhenEmpty, h.minMemories)
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("searching memories: %w", err)
 	}
+	if broadened {
+		h.logger.Debug("pre-turn hook: broadened project recall to global memories", "project", input.Project)
+	}
 
 	// Rank with multi-factor scoring
+	_, rankSpan := tracing.Start(ctx, "recall.rank", "recall.candidates", len(results))
 	ranked := h.recaller.Rank(results, input.Project, input.Message)
+	rankSpan.SetAttributes("result.count", len(ranked))
+	rankSpan.End()
 
 	// Optionally re-rank with Claude when scores are clustered.
 	if h.reasoner != nil && h.recaller.ShouldRerank(ranked, h.rerankCfg.ScoreSpreadThreshold) {
@@ -120,19 +202,36 @@ func (h *PreTurnHook) Execute(ctx context.Context, input PreTurnInput) (*PreTurn
 		}
 	}
 
+	if h.filter != nil {
+		ranked = h.filter.Filter(ctx, input.Message, ranked, 0)
+	}
+
+	if h.contradict != nil {
+		ranked = recall.DetectConflicts(ctx, h.contradict, h.store, ranked, h.logger)
+	}
+
 	// Format within token budget
+	ranked, conflicts := recall.ResolveConflicts(ranked, h.onConflict)
+	shown := recall.MarkConflicts(recall.TruncateEach(ranked, h.perMemoryMax), conflicts, h.onConflict)
+	if h.attachmentRefs {
+		shown = recall.WithAttachmentRefs(shown)
+	}
 	var contents []string
-	for i := range ranked {
-		contents = append(contents, ranked[i].Memory.Content)
+	for i := range shown {
+		contents = append(contents, shown[i].Memory.Content)
 	}
 
-	formatted, count := tokenizer.FormatMemoriesWithBudget(contents, input.TokenBudget)
+	_, formatSpan := tracing.Start(ctx, "recall.format", "recall.budget", input.TokenBudget, "recall.candidates", len(contents))
+	formatted, count := tokenizer.FormatMemoriesWithMinimum(contents, input.TokenBudget, h.minMemories)
+	formatSpan.SetAttributes("memory.count", count)
+	formatSpan.End()
+	span.SetAttributes("memory.count", count)
 
 	// Update access metadata
-	for i := 0; i < count && i < len(ranked); i++ {
-		if updateErr := h.store.UpdateAccessMetadata(ctx, ranked[i].Memory.ID); updateErr != nil {
-			h.logger.Warn("PreTurnHook: UpdateAccessMetadata failed",
-				"id", ranked[i].Memory.ID, "error", updateErr)
+	if !h.skipAccessUpdates {
+		if updateErr := h.store.UpdateAccessMetadataBatch(ctx, recall.TopIDs(ranked, count)); updateErr != nil {
+			h.logger.Warn("PreTurnHook: UpdateAccessMetadataBatch failed",
+				"count", count, "error", updateErr)
 		}
 	}
 
@@ -166,7 +265,11 @@ type PostTurnHook struct {
 	conflictDetector       *capture.ConflictDetector // nil = disabled
 	reinforcementThreshold float64                   // 0 = disabled
 	reinforcementBoost     float64
-	concurrency            int // number of goroutines for per-memory pipeline; 0 = default (4)
+	concurrency            int                 // number of goroutines for per-memory pipeline; 0 = default (4)
+	fastDedup              *store.ShingleIndex // nil = disabled
+	dedupScope             store.DedupScope
+	typeTTL                *lifecycle.TypeTTL   // nil = no per-type default TTLs
+	tags                   *store.TagNormalizer // nil = tags are stored as given
 }
 
 // PostTurnInput contains the conversation turn data.
@@ -215,6 +318,37 @@ func (h *PostTurnHook) WithReinforcement(threshold, boost float64) *PostTurnHook
 	return h
 }
 
+// WithFastDedup enables a MinHash shingle pre-check that drops extracted
+// memories textually near-identical to ones already seen in this process
+// before they are embedded. The index is the bounded process-wide one from
+// store.SharedShingleIndex, so repeats across turns are caught too.
+// threshold <= 0 selects store.DefaultFastDedupThreshold.
+// Must be called before the hook is used concurrently.
+func (h *PostTurnHook) WithFastDedup(threshold float64) *PostTurnHook {
+	h.fastDedup = store.SharedShingleIndex(threshold)
+	return h
+}
+
+// WithDedupScope narrows the dedup check to the given comparison set
+// (see store.FindDuplicatesInScope). The zero scope compares against the
+// whole collection.
+func (h *PostTurnHook) WithDedupScope(scope store.DedupScope) *PostTurnHook {
+	h.dedupScope = scope
+	return h
+}
+
+// WithTypeTTL applies per-type default TTLs to stored memories. nil disables them.
+func (h *PostTurnHook) WithTypeTTL(t *lifecycle.TypeTTL) *PostTurnHook {
+	h.typeTTL = t
+	return h
+}
+
+// WithTagNormalizer normalizes the tags of stored memories. nil stores tags as given.
+func (h *PostTurnHook) WithTagNormalizer(n *store.TagNormalizer) *PostTurnHook {
+	h.tags = n
+	return h
+}
+
 // Execute runs the post-turn hook: extract → classify → embed → reinforce/dedup → store.
 func (h *PostTurnHook) Execute(ctx context.Context, input PostTurnInput) error {
 	finish := sentry.StartSpan(ctx, "hook.post_turn", "PostTurnHook")
@@ -236,6 +370,12 @@ func (h *PostTurnHook) Execute(ctx context.Context, input PostTurnInput) error {
 		return nil
 	}
 
+	turnID := capture.TurnID(input.SessionID, input.UserMessage, input.AssistantMessage)
+	retry, retryErr := capture.TurnCaptured(ctx, h.store, turnID)
+	if retryErr != nil {
+		h.logger.Warn("post-turn retry check failed, proceeding", "error", retryErr)
+	}
+
 	deps := pipelineDeps{
 		classifier:             h.classifier,
 		embedder:               h.embedder,
@@ -245,6 +385,13 @@ func (h *PostTurnHook) Execute(ctx context.Context, input PostTurnInput) error {
 		reinforcementThreshold: h.reinforcementThreshold,
 		reinforcementBoost:     h.reinforcementBoost,
 		project:                input.Project,
+		sessionID:              input.SessionID,
+		turnID:                 turnID,
+		retry:                  retry,
+		fastDedup:              h.fastDedup,
+		dedupScope:             h.dedupScope,
+		typeTTL:                h.typeTTL,
+		tags:                   h.tags,
 	}
 	stored, pipelineErr := runMemoryPipeline(ctx, captured, h.concurrency, deps, h.logger)
 	h.logger.Info("post-turn hook completed", "extracted", len(captured), "stored", stored)
@@ -13,6 +13,7 @@ import (
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/classifier"
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
 	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
@@ -35,6 +36,13 @@ type pipelineDeps struct {
 	reinforcementThreshold float64
 	reinforcementBoost     float64
 	project                string
+	sessionID              string
+	turnID                 string              // capture.TurnID of the turn; memory IDs derive from it
+	retry                  bool                // an earlier run already stored memories for turnID
+	fastDedup              *store.ShingleIndex // nil = disabled
+	dedupScope             store.DedupScope
+	typeTTL                *lifecycle.TypeTTL
+	tags                   *store.TagNormalizer
 }
 
 // runMemoryPipeline processes captured memories concurrently using a
@@ -89,7 +97,10 @@ func runMemoryPipeline(ctx context.Context, memories []models.CapturedMemory, co
 }
 
 // processSingleMemory runs the classify→embed→reinforce→dedup→conflict→store
-// pipeline for one captured memory. Returns nil if the memory was stored or
+// pipeline for one captured memory. The memory ID is derived from the turn and
+// the content, so a memory already stored by an earlier, interrupted run of
+// the same turn is skipped before it is embedded; on a retry, a reworded
+// re-extraction of such a memory is skipped once embedded. Returns nil if the memory was stored or
 // intentionally skipped (dedup, reinforcement). Returns a non-nil error only
 // for hard failures (context cancellation or unrecoverable store errors).
 func processSingleMemory(ctx context.Context, cm models.CapturedMemory, deps pipelineDeps, logger *slog.Logger) error {
@@ -101,8 +112,27 @@ func processSingleMemory(ctx context.Context, cm models.CapturedMemory, deps pip
 		memType = deps.classifier.Classify(cm.Content)
 	}
 
+	// Retry check — this turn already stored this memory.
+	memID := capture.MemoryID(deps.turnID, cm.Content)
+	if _, getErr := deps.store.Get(ctx, memID); getErr == nil {
+		logger.Debug("post-turn skipping memory already captured for this turn", "id", memID)
+		return errSkipped
+	} else if !errors.Is(getErr, store.ErrNotFound) {
+		if errors.Is(getErr, context.Canceled) || errors.Is(getErr, context.DeadlineExceeded) {
+			return getErr
+		}
+		logger.Warn("post-turn retry check failed, proceeding", "id", memID, "error", getErr)
+	}
+
+	// Fast textual pre-check — skips the embedding call for near-verbatim repeats.
+	if deps.fastDedup != nil && deps.fastDedup.CheckAndAdd(cm.Content) {
+		logger.Debug("post-turn skipping textual duplicate")
+		metrics.Inc(metrics.FastDedupSkipped)
+		return errSkipped
+	}
+
 	// Embed the content.
-	vec, embedErr := deps.embedder.Embed(ctx, cm.Content)
+	vec, embedErr := deps.embedder.Embed(ctx, embedder.DocumentText(cm.Type, cm.Content))
 	if embedErr != nil {
 		if errors.Is(embedErr, context.Canceled) || errors.Is(embedErr, context.DeadlineExceeded) {
 			return embedErr
@@ -111,6 +141,20 @@ func processSingleMemory(ctx context.Context, cm models.CapturedMemory, deps pip
 		return errSkipped
 	}
 
+	// Retry check — the earlier run stored this memory under other wording.
+	if deps.retry {
+		prior, found, turnErr := capture.CapturedInTurn(ctx, deps.store, deps.turnID, vec)
+		if turnErr != nil {
+			if errors.Is(turnErr, context.Canceled) || errors.Is(turnErr, context.DeadlineExceeded) {
+				return turnErr
+			}
+			logger.Warn("post-turn retry check failed, proceeding", "error", turnErr)
+		} else if found {
+			logger.Debug("post-turn skipping reworded memory already captured for this turn", "similar_to", prior.ID)
+			return errSkipped
+		}
+	}
+
 	// Reinforcement: boost confidence of near-duplicate existing memories
 	// instead of storing a new one.
 	if deps.reinforcementThreshold > 0 {
@@ -130,7 +174,7 @@ func processSingleMemory(ctx context.Context, cm models.CapturedMemory, deps pip
 	}
 
 	// Dedup — skip if an exact duplicate already exists.
-	dupes, dedupErr := deps.store.FindDuplicates(ctx, vec, deps.dedupThreshold)
+	dupes, dedupErr := store.FindDuplicatesInScope(ctx, deps.store, vec, deps.dedupThreshold, memType, deps.dedupScope)
 	if dedupErr != nil {
 		logger.Warn("post-turn dedup check failed, proceeding with store", "error", dedupErr)
 	} else if len(dupes) > 0 {
@@ -173,7 +217,7 @@ func processSingleMemory(ctx context.Context, cm models.CapturedMemory, deps pip
 		conflictStatus = models.ConflictStatusActive
 	}
 	mem := models.Memory{
-		ID:              uuid.New().String(),
+		ID:              memID,
 		Type:            memType,
 		Scope:           models.ScopeSession,
 		Visibility:      models.VisibilityPrivate,
@@ -188,6 +232,15 @@ func processSingleMemory(ctx context.Context, cm models.CapturedMemory, deps pip
 		ConflictGroupID: conflictGroupID,
 		ConflictStatus:  conflictStatus,
 	}
+	mem.Metadata = map[string]any{"turn_id": deps.turnID}
+	if deps.sessionID != "" {
+		mem.Metadata["session_id"] = deps.sessionID
+	}
+	if cm.Preference != nil && memType == models.MemoryTypePreference {
+		mem.SetPreference(*cm.Preference)
+	}
+	deps.typeTTL.Apply(&mem)
+	deps.tags.Apply(&mem)
 
 	if upsertErr := deps.store.Upsert(ctx, mem, vec); upsertErr != nil {
 		logger.Warn("post-turn store failed", "error", upsertErr)
@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
 )
 
 const (
@@ -22,6 +27,15 @@ const (
 
 	// defaultIndexedConfidence is the confidence score assigned to file-indexed memories.
 	defaultIndexedConfidence = 0.8
+
+	// upsertBatchSize is the number of chunks written per Store.UpsertBatch call.
+	upsertBatchSize = 64
+
+	// OversizeSplit cuts a chunk over the token limit into pieces that fit.
+	OversizeSplit = "split"
+
+	// OversizeTruncate keeps only the start of a chunk over the token limit.
+	OversizeTruncate = "truncate"
 )
 
 // Indexer scans markdown files, chunks them, generates embeddings, and stores them.
@@ -31,6 +45,20 @@ type Indexer struct {
 	chunkSize    int
 	chunkOverlap int
 	logger       *slog.Logger
+	fastDedup    *store.ShingleIndex // nil = disabled
+	dedupScope   store.DedupScope
+
+	maxChunkTokens int    // 0 = chunks are embedded whatever their size
+	oversize       string // OversizeSplit or OversizeTruncate
+	stats          IndexStats
+}
+
+// IndexStats counts chunks whose content was altered or lost while indexing,
+// so the caller can report them instead of losing content silently.
+type IndexStats struct {
+	Split     int // oversized chunks cut into smaller pieces
+	Truncated int // oversized chunks cut down to the token limit
+	Dropped   int // chunks that could not be embedded or stored
 }
 
 // Chunk represents a section of text extracted from a file.
@@ -55,6 +83,37 @@ func NewIndexer(emb embedder.Embedder, st store.Store, chunkSize, chunkOverlap i
 	}
 }
 
+// WithFastDedup enables a MinHash shingle pre-check that drops chunks
+// textually near-identical to chunks already indexed in this run before they
+// are embedded. threshold <= 0 selects store.DefaultFastDedupThreshold.
+func (idx *Indexer) WithFastDedup(threshold float64) *Indexer {
+	idx.fastDedup = store.NewShingleIndex(threshold)
+	return idx
+}
+
+// WithDedupScope narrows the per-chunk dedup check to the given comparison
+// set (see store.FindDuplicatesInScope).
+func (idx *Indexer) WithDedupScope(scope store.DedupScope) *Indexer {
+	idx.dedupScope = scope
+	return idx
+}
+
+// WithMaxChunkTokens caps the estimated token count of a chunk sent to the
+// embedder. Larger chunks, e.g. a giant line splitBySize cannot break, are
+// split into pieces that fit or, with mode OversizeTruncate, truncated with a
+// logged warning. maxTokens <= 0 disables the cap.
+func (idx *Indexer) WithMaxChunkTokens(maxTokens int, mode string) *Indexer {
+	idx.maxChunkTokens = maxTokens
+	idx.oversize = mode
+	return idx
+}
+
+// Stats returns the counts of split, truncated and dropped chunks
+// accumulated by this Indexer.
+func (idx *Indexer) Stats() IndexStats {
+	return idx.stats
+}
+
 // IndexDirectory scans a directory for markdown files and indexes them.
 func (idx *Indexer) IndexDirectory(ctx context.Context, dir string) (int, error) {
 	files, err := FindMarkdownFiles(dir)
@@ -95,34 +154,77 @@ func (idx *Indexer) IndexFile(ctx context.Context, filePath string) (int, error)
 
 	idx.logger.Info("chunked file", "file", filePath, "chunks", len(chunks))
 
+	if idx.fastDedup != nil {
+		kept := chunks[:0]
+		for _, c := range chunks {
+			if idx.fastDedup.CheckAndAdd(c.Content) {
+				idx.logger.Debug("skipping textual duplicate chunk", "source", c.Source)
+				metrics.Inc(metrics.FastDedupSkipped)
+				continue
+			}
+			kept = append(kept, c)
+		}
+		chunks = kept
+		if len(chunks) == 0 {
+			return 0, nil
+		}
+	}
+
+	chunks = idx.fitChunks(chunks)
+
 	// Batch-embed all chunks in one call.
 	texts := make([]string, len(chunks))
 	for i, c := range chunks {
-		texts[i] = c.Content
+		texts[i] = embedder.DocumentText(models.MemoryTypeFact, c.Content)
 	}
 	vecs, err := idx.embedder.EmbedBatch(ctx, texts)
 	if err != nil {
-		return 0, fmt.Errorf("batch embedding chunks from %s: %w", filePath, err)
+		chunks, vecs, err = idx.embedEach(ctx, chunks, texts, err)
+		if err != nil {
+			return 0, fmt.Errorf("batch embedding chunks from %s: %w", filePath, err)
+		}
 	}
 
 	indexed := 0
+	var (
+		pendingMems []models.Memory
+		pendingVecs [][]float32
+	)
+	flush := func() {
+		if len(pendingMems) == 0 {
+			return
+		}
+		if err := idx.store.UpsertBatch(ctx, pendingMems, pendingVecs); err != nil {
+			indexed += idx.upsertEach(ctx, filePath, pendingMems, pendingVecs, err)
+		} else {
+			indexed += len(pendingMems)
+		}
+		pendingMems, pendingVecs = pendingMems[:0], pendingVecs[:0]
+	}
+
 	for i, chunk := range chunks {
 		select {
 		case <-ctx.Done():
+			flush()
 			return indexed, ctx.Err()
 		default:
 		}
 
 		vec := vecs[i]
 
-		// Check for duplicates before inserting.
-		dupes, err := idx.store.FindDuplicates(ctx, vec, dedupThreshold)
+		// Check for duplicates before inserting. Chunks still waiting in the
+		// batch are not in the store yet, so compare against them directly.
+		dupes, err := store.FindDuplicatesInScope(ctx, idx.store, vec, dedupThreshold, models.MemoryTypeFact, idx.dedupScope)
 		if err != nil {
 			idx.logger.Warn("dedup check failed, proceeding with store", "error", err)
 		} else if len(dupes) > 0 {
 			idx.logger.Debug("skipping duplicate chunk", "source", chunk.Source, "similar_to", dupes[0].Memory.ID)
 			continue
 		}
+		if j := pendingDuplicate(pendingVecs, vec); j >= 0 {
+			idx.logger.Debug("skipping duplicate chunk", "source", chunk.Source, "similar_to", pendingMems[j].ID)
+			continue
+		}
 
 		now := time.Now().UTC()
 		mem := models.Memory{
@@ -141,16 +243,121 @@ func (idx *Indexer) IndexFile(ctx context.Context, filePath string) (int, error)
 			Metadata:     chunk.Metadata,
 		}
 
-		if err := idx.store.Upsert(ctx, mem, vec); err != nil {
-			idx.logger.Error("storing chunk", "source", chunk.Source, "error", err)
-			continue
+		pendingMems = append(pendingMems, mem)
+		pendingVecs = append(pendingVecs, vec)
+		if len(pendingMems) >= upsertBatchSize {
+			flush()
 		}
-		indexed++
 	}
+	flush()
 
 	return indexed, nil
 }
 
+// pendingDuplicate returns the index of the first vector in pending at least
+// dedupThreshold similar to vec, or -1.
+func pendingDuplicate(pending [][]float32, vec []float32) int {
+	for j, p := range pending {
+		if vecmath.CosineSimilarity(p, vec) >= dedupThreshold {
+			return j
+		}
+	}
+	return -1
+}
+
+// fitChunks applies the maxChunkTokens cap to chunks, splitting or truncating
+// each oversized chunk according to idx.oversize.
+func (idx *Indexer) fitChunks(chunks []Chunk) []Chunk {
+	if idx.maxChunkTokens <= 0 {
+		return chunks
+	}
+	out := make([]Chunk, 0, len(chunks))
+	for _, c := range chunks {
+		tokens := tokenizer.EstimateTokens(c.Content)
+		if tokens <= idx.maxChunkTokens {
+			out = append(out, c)
+			continue
+		}
+		if idx.oversize == OversizeTruncate {
+			idx.logger.Warn("truncating oversized chunk", "source", c.Source, "heading", c.Heading,
+				"tokens", tokens, "max_tokens", idx.maxChunkTokens)
+			c.Content = tokenizer.TruncateToTokenBudget(c.Content, idx.maxChunkTokens)
+			c.Metadata = maps.Clone(c.Metadata)
+			if c.Metadata == nil {
+				c.Metadata = map[string]any{}
+			}
+			c.Metadata["truncated"] = true
+			idx.stats.Truncated++
+			out = append(out, c)
+			continue
+		}
+		pieces := splitByTokens(c.Content, idx.maxChunkTokens)
+		idx.logger.Info("splitting oversized chunk", "source", c.Source, "heading", c.Heading,
+			"tokens", tokens, "max_tokens", idx.maxChunkTokens, "pieces", len(pieces))
+		idx.stats.Split++
+		for _, p := range pieces {
+			piece := c
+			piece.Content = p
+			piece.Metadata = maps.Clone(c.Metadata)
+			out = append(out, piece)
+		}
+	}
+	return out
+}
+
+// embedEach is the fallback when a batch embed fails: it embeds the chunks one
+// at a time so a single chunk the provider rejects does not lose the whole
+// file. Chunks that still fail are dropped with a warning. If none can be
+// embedded the provider is assumed to be down and batchErr is returned.
+func (idx *Indexer) embedEach(ctx context.Context, chunks []Chunk, texts []string, batchErr error) ([]Chunk, [][]float32, error) {
+	idx.logger.Warn("batch embedding failed, retrying chunks individually", "chunks", len(chunks), "error", batchErr)
+	var (
+		kept []Chunk
+		vecs [][]float32
+	)
+	for i := range chunks {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		vec, err := idx.embedder.Embed(ctx, texts[i])
+		if err != nil {
+			idx.logger.Warn("dropping chunk that could not be embedded", "source", chunks[i].Source,
+				"heading", chunks[i].Heading, "tokens", tokenizer.EstimateTokens(chunks[i].Content), "error", err)
+			continue
+		}
+		kept = append(kept, chunks[i])
+		vecs = append(vecs, vec)
+	}
+	if len(kept) == 0 {
+		return nil, nil, batchErr
+	}
+	idx.stats.Dropped += len(chunks) - len(kept)
+	return kept, vecs, nil
+}
+
+// upsertEach is the fallback when a batch upsert fails: it stores the chunks
+// one at a time so a single chunk the store rejects does not lose the whole
+// batch. Chunks that still fail are dropped with an error log. It returns the
+// number of chunks stored.
+func (idx *Indexer) upsertEach(ctx context.Context, filePath string, mems []models.Memory, vecs [][]float32, batchErr error) int {
+	idx.logger.Warn("storing chunk batch failed, retrying chunks individually",
+		"file", filePath, "chunks", len(mems), "error", batchErr)
+	stored := 0
+	for i := range mems {
+		if ctx.Err() != nil {
+			idx.stats.Dropped += len(mems) - i
+			break
+		}
+		if err := idx.store.Upsert(ctx, mems[i], vecs[i]); err != nil {
+			idx.logger.Error("storing chunk", "file", filePath, "source", mems[i].Source, "error", err)
+			idx.stats.Dropped++
+			continue
+		}
+		stored++
+	}
+	return stored
+}
+
 // chunkFile reads a markdown file, parses it into a section tree, and produces
 // Chunks with structural metadata (section_path, section_depth, word_count).
 func (idx *Indexer) chunkFile(filePath string) ([]Chunk, error) {
@@ -248,6 +455,32 @@ func splitBySize(text string, maxSize, overlap int) []string {
 	return chunks
 }
 
+// splitByTokens recursively halves text, preferring whitespace near the middle
+// and never cutting inside a UTF-8 sequence, until every piece fits within
+// maxTokens.
+func splitByTokens(text string, maxTokens int) []string {
+	if tokenizer.EstimateTokens(text) <= maxTokens || len(text) < 2 {
+		return []string{text}
+	}
+	mid := len(text) / 2
+	if i := strings.LastIndexAny(text[:mid], " \t\n"); i > len(text)/4 {
+		mid = i
+	}
+	for mid > 0 && !utf8.RuneStart(text[mid]) {
+		mid--
+	}
+	if mid == 0 {
+		return []string{text}
+	}
+	var pieces []string
+	for _, half := range []string{text[:mid], text[mid:]} {
+		if half = strings.TrimSpace(half); half != "" {
+			pieces = append(pieces, splitByTokens(half, maxTokens)...)
+		}
+	}
+	return pieces
+}
+
 func extractTags(heading, filePath string) []string {
 	var tags []string
 
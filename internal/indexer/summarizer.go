@@ -170,7 +170,7 @@ func (s *SectionSummarizer) summarizeFile(ctx context.Context, filePath string,
 
 	stored := 0
 	for _, mem := range memories {
-		vec, err := emb.Embed(ctx, mem.Content)
+		vec, err := emb.Embed(ctx, embedder.DocumentText(mem.Type, mem.Content))
 		if err != nil {
 			s.logger.Warn("summarizer: embed failed, skipping", "content_prefix", mem.Content[:minInt(40, len(mem.Content))], "error", err)
 			continue
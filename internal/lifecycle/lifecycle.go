@@ -3,10 +3,12 @@ package lifecycle
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
@@ -16,41 +18,205 @@ import (
 	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
 )
 
-const pageSize uint64 = 500
+// DefaultPageSize is the number of memories fetched per List call when
+// scanning the store.
+const DefaultPageSize = 500
+
+// DefaultMaxScan is the default safety cap on memories loaded by a single
+// scan, preventing unbounded memory use on very large stores.
+const DefaultMaxScan = 50000
+
+// DefaultConsolidationThreshold is the cosine similarity above which two
+// permanent memories are considered near-duplicates and eligible for merging.
+const DefaultConsolidationThreshold = 0.92
+
+// DefaultSessionDecay is how long a session memory may go without access
+// before the decay phase deletes it.
+const DefaultSessionDecay = 24 * time.Hour
+
+// ConsolidationPartition limits consolidation to memories that share the
+// partition's attributes, so near-duplicates in different partitions (a rule
+// in project A and a similar rule in project B) are never merged.
+type ConsolidationPartition string
+
+const (
+	// PartitionNone merges across all permanent memories (the default).
+	PartitionNone ConsolidationPartition = "none"
+	// PartitionType only merges memories of the same type.
+	PartitionType ConsolidationPartition = "type"
+	// PartitionProject only merges memories of the same project.
+	PartitionProject ConsolidationPartition = "project"
+	// PartitionTypeProject only merges memories of the same type and project.
+	PartitionTypeProject ConsolidationPartition = "type_project"
+)
+
+// same reports whether a and b fall in the same partition.
+func (p ConsolidationPartition) same(a, b *models.Memory) bool {
+	switch p {
+	case PartitionType:
+		return a.Type == b.Type
+	case PartitionProject:
+		return a.Project == b.Project
+	case PartitionTypeProject:
+		return a.Type == b.Type && a.Project == b.Project
+	}
+	return true
+}
 
-// maxListAllMemories is a safety cap to prevent unbounded memory loading.
-const maxListAllMemories = 50000
+// consolidationCandidates is how many nearest permanent memories in the same
+// partition are checked for each memory when consolidating across pages.
+const consolidationCandidates = 10
 
-// consolidationThreshold is the cosine similarity above which two permanent memories are
-// considered near-duplicates and eligible for merging.
-const consolidationThreshold = 0.92
+// searchFilters returns the filters that restrict a duplicate search to
+// current permanent memories in m's partition, so the nearest neighbours are
+// drawn from the partition rather than filtered out of a global top list.
+// Invalidated versions, such as the originals of an earlier merge, are left
+// out so they are never consolidated again.
+func (p ConsolidationPartition) searchFilters(m *models.Memory) *store.SearchFilters {
+	scope := models.ScopePermanent
+	f := &store.SearchFilters{Scope: &scope}
+	if p == PartitionType || p == PartitionTypeProject {
+		memType := m.Type
+		f.Type = &memType
+	}
+	if p == PartitionProject || p == PartitionTypeProject {
+		project := m.Project
+		f.Project = &project
+	}
+	return f
+}
 
 // Report summarizes the results of a lifecycle run.
 type Report struct {
 	Expired           int `json:"expired"`
+	ExpiredAt         int `json:"expired_at"` // memories past their ExpiresAt date
 	Decayed           int `json:"decayed"`
+	Evicted           int `json:"evicted"`
 	Consolidated      int `json:"consolidated"`
 	Retired           int `json:"retired"`
 	ConflictsResolved int `json:"conflicts_resolved"`
+	Purged            int `json:"purged"`
 }
 
 // Manager handles memory lifecycle operations.
 type Manager struct {
-	store  store.Store
-	emb    embedder.Embedder
-	logger *slog.Logger
+	store          store.Store
+	emb            embedder.Embedder
+	logger         *slog.Logger
+	trashRetention time.Duration // 0 = trash purge phase disabled
+	maxScan        int           // cap on memories loaded per scan
+	pageSize       int           // memories fetched per List call
+	ttlAllScopes   bool          // expire phase scans every scope, not just ttl
+	sessionDecay   time.Duration // idle time after which session memories decay
+
+	consolidationThreshold float64 // similarity above which consolidation merges
+
+	partition   ConsolidationPartition // consolidation only merges within a partition
+	excludeTags map[string]bool        // lowercased tags whose memories consolidation never merges
+
+	maxPerScope map[models.MemoryScope]int // scope → memory cap enforced by eviction
+
+	merger *Merger // nil = consolidation deletes duplicates instead of merging
 }
 
 // NewManager creates a new lifecycle manager.
 // emb may be nil; when nil, the consolidation phase is skipped.
 func NewManager(st store.Store, emb embedder.Embedder, logger *slog.Logger) *Manager {
 	return &Manager{
-		store:  st,
-		emb:    emb,
-		logger: logger,
+		store:    st,
+		emb:      emb,
+		logger:   logger,
+		maxScan:  DefaultMaxScan,
+		pageSize: DefaultPageSize,
+
+		sessionDecay:           DefaultSessionDecay,
+		consolidationThreshold: DefaultConsolidationThreshold,
+	}
+}
+
+// SetSessionDecay sets how long a session memory may go without access before
+// the decay phase deletes it. Values <= 0 keep DefaultSessionDecay.
+func (m *Manager) SetSessionDecay(d time.Duration) {
+	if d > 0 {
+		m.sessionDecay = d
+	}
+}
+
+// SetConsolidationThreshold sets the cosine similarity above which two
+// permanent memories are merged by consolidation. Values outside (0, 1] keep
+// DefaultConsolidationThreshold.
+func (m *Manager) SetConsolidationThreshold(threshold float64) {
+	if threshold > 0 && threshold <= 1 {
+		m.consolidationThreshold = threshold
+	}
+}
+
+// SetTrashRetention sets how long trashed memories are kept before the purge
+// phase deletes them permanently. Zero disables the purge phase.
+func (m *Manager) SetTrashRetention(d time.Duration) {
+	m.trashRetention = d
+}
+
+// SetScanLimits overrides how many memories a single lifecycle scan may load
+// (maxScan) and how many are fetched per page (pageSize). Values <= 0 keep
+// DefaultMaxScan and DefaultPageSize respectively.
+func (m *Manager) SetScanLimits(maxScan, pageSize int) {
+	if maxScan > 0 {
+		m.maxScan = maxScan
+	}
+	if pageSize > 0 {
+		m.pageSize = pageSize
+	}
+}
+
+// SetTypeRetention makes the expire phase consider memories of every scope,
+// not just ttl-scoped ones. Enable it when TypeTTL assigns TTLs across all
+// scopes, otherwise those memories would never expire.
+func (m *Manager) SetTypeRetention(enabled bool) {
+	m.ttlAllScopes = enabled
+}
+
+// SetConsolidationPartition restricts the consolidation phase to merging
+// memories within the same partition. The default, PartitionNone, merges
+// across all permanent memories.
+func (m *Manager) SetConsolidationPartition(p ConsolidationPartition) {
+	m.partition = p
+}
+
+// SetConsolidationExcludeTags protects memories carrying any of tags
+// (compared case-insensitively) from consolidation: they are never merged
+// into another memory nor have another merged into them. Use it for
+// deliberately similar memories such as per-environment variants.
+func (m *Manager) SetConsolidationExcludeTags(tags []string) {
+	m.excludeTags = nil
+	for _, t := range tags {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			if m.excludeTags == nil {
+				m.excludeTags = make(map[string]bool, len(tags))
+			}
+			m.excludeTags[t] = true
+		}
 	}
 }
 
+// excludedFromConsolidation reports whether mem carries a tag set with
+// SetConsolidationExcludeTags.
+func (m *Manager) excludedFromConsolidation(mem *models.Memory) bool {
+	for _, t := range mem.Tags {
+		if m.excludeTags[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMaxPerScope caps how many memories each scope may hold. When a scope
+// exceeds its cap, the eviction phase deletes its least recently accessed
+// memories until it fits. Scopes without a positive cap are unbounded.
+func (m *Manager) SetMaxPerScope(caps map[models.MemoryScope]int) {
+	m.maxPerScope = caps
+}
+
 // Run executes all lifecycle operations and collects errors from all phases.
 // Partial results are preserved even when some phases fail.
 func (m *Manager) Run(ctx context.Context, dryRun bool) (*Report, error) {
@@ -65,6 +231,14 @@ func (m *Manager) Run(ctx context.Context, dryRun bool) (*Report, error) {
 	}
 	report.Expired = expired
 
+	// 1b. Delete memories of any scope whose ExpiresAt has passed
+	expiredAt, expiresErr := m.expireAt(ctx, dryRun)
+	if expiresErr != nil {
+		m.logger.Error("lifecycle: expires_at expiry failed", "error", expiresErr)
+		errs = append(errs, fmt.Errorf("expires_at expiry: %w", expiresErr))
+	}
+	report.ExpiredAt = expiredAt
+
 	// 2. Decay old session memories
 	decayed, err := m.decaySessions(ctx, dryRun)
 	if err != nil {
@@ -73,7 +247,15 @@ func (m *Manager) Run(ctx context.Context, dryRun bool) (*Report, error) {
 	}
 	report.Decayed = decayed
 
-	// 3. Consolidate near-duplicate permanent memories
+	// 3. Evict least recently accessed memories from scopes over their cap
+	evicted, evictErr := m.evictOverCap(ctx, dryRun)
+	if evictErr != nil {
+		m.logger.Error("lifecycle: scope eviction failed", "error", evictErr)
+		errs = append(errs, fmt.Errorf("scope eviction: %w", evictErr))
+	}
+	report.Evicted = evicted
+
+	// 4. Consolidate near-duplicate permanent memories
 	consolidated, consolidateErr := m.consolidate(ctx, dryRun)
 	if consolidateErr != nil {
 		m.logger.Error("lifecycle: consolidation failed", "error", consolidateErr)
@@ -81,7 +263,7 @@ func (m *Manager) Run(ctx context.Context, dryRun bool) (*Report, error) {
 	}
 	report.Consolidated = consolidated
 
-	// 4. Retire memories whose ValidUntil has passed
+	// 5. Retire memories whose ValidUntil has passed
 	retired, retireErr := m.retireExpiredFacts(ctx, dryRun)
 	if retireErr != nil {
 		m.logger.Error("lifecycle: fact retirement failed", "error", retireErr)
@@ -89,7 +271,7 @@ func (m *Manager) Run(ctx context.Context, dryRun bool) (*Report, error) {
 	}
 	report.Retired = retired
 
-	// 5. Batch-resolve active conflict groups
+	// 6. Batch-resolve active conflict groups
 	resolved, resolveErr := m.resolveConflicts(ctx, dryRun)
 	if resolveErr != nil {
 		m.logger.Error("lifecycle: conflict resolution failed", "error", resolveErr)
@@ -97,75 +279,139 @@ func (m *Manager) Run(ctx context.Context, dryRun bool) (*Report, error) {
 	}
 	report.ConflictsResolved = resolved
 
+	// 7. Purge trashed memories past the retention window
+	purged, purgeErr := m.purgeTrash(ctx, dryRun)
+	if purgeErr != nil {
+		m.logger.Error("lifecycle: trash purge failed", "error", purgeErr)
+		errs = append(errs, fmt.Errorf("trash purge: %w", purgeErr))
+	}
+	report.Purged = purged
+
 	if len(errs) > 0 {
 		return report, fmt.Errorf("lifecycle: %w", errors.Join(errs...))
 	}
 	return report, nil
 }
 
-// listAll paginates through all memories matching filters.
-// It stops after maxListAllMemories to prevent unbounded memory usage.
-func (m *Manager) listAll(ctx context.Context, filters *store.SearchFilters) ([]models.Memory, error) {
-	var all []models.Memory
+// forEachPage streams memories matching filters one page at a time, calling fn
+// for each page. Only the current page is held in memory, so callers that keep
+// just IDs or counters run in bounded memory regardless of collection size.
+// Callers must not delete memories from inside fn: List pages by offset, so
+// deleting while scanning would shift later pages and skip memories. Collect
+// IDs instead and delete after the scan (see deleteIDs).
+//
+// The scan stops after m.maxScan memories; when that cap cuts it short a
+// warning is logged and metrics.LifecycleScanTruncated is incremented so
+// operators know part of the store was not maintained on this run.
+func (m *Manager) forEachPage(ctx context.Context, filters *store.SearchFilters, fn func(page []models.Memory) error) error {
+	_, err := m.scanPages(ctx, filters, fn)
+	return err
+}
+
+// scanPages is forEachPage that also reports whether the max_scan cap cut
+// the scan short.
+func (m *Manager) scanPages(ctx context.Context, filters *store.SearchFilters, fn func(page []models.Memory) error) (bool, error) {
 	var cursor string
+	scanned := 0
 
 	for {
-		page, nextCursor, err := m.store.List(ctx, filters, pageSize, cursor)
+		page, nextCursor, err := m.store.List(ctx, filters, uint64(m.pageSize), cursor)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
-		all = append(all, page...)
-		if uint64(len(all)) >= maxListAllMemories {
-			m.logger.Warn("listAll hit safety cap, results truncated",
-				"cap", maxListAllMemories,
-				"loaded", len(all),
+		cursor = nextCursor
+		truncated := false
+		if scanned+len(page) > m.maxScan || (scanned+len(page) == m.maxScan && cursor != "") {
+			page = page[:m.maxScan-scanned]
+			truncated = true
+		}
+		scanned += len(page)
+		if len(page) > 0 {
+			if fnErr := fn(page); fnErr != nil {
+				return false, fnErr
+			}
+		}
+		if truncated {
+			m.logger.Warn("lifecycle: scan hit max_scan cap, remaining memories not processed this run; raise lifecycle.max_scan to cover the full store",
+				"max_scan", m.maxScan,
+				"scanned", scanned,
 			)
-			all = all[:maxListAllMemories]
-			break
+			metrics.Inc(metrics.LifecycleScanTruncated)
+			return true, nil
 		}
-		cursor = nextCursor
 		if cursor == "" {
-			break
+			return false, nil
 		}
 	}
+}
 
+// listAll collects every memory matching filters (up to m.maxScan) into a
+// single slice. Prefer forEachPage for phases that do not need the full set.
+func (m *Manager) listAll(ctx context.Context, filters *store.SearchFilters) ([]models.Memory, error) {
+	var all []models.Memory
+	err := m.forEachPage(ctx, filters, func(page []models.Memory) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return all, nil
 }
 
+// deleteIDs deletes the given memories, incrementing counter for each
+// successful delete. In dry-run mode nothing is deleted and every ID counts.
+// Returns the number of memories deleted (or that would have been).
+func (m *Manager) deleteIDs(ctx context.Context, ids []string, dryRun bool, counter *expvar.Int, errMsg string) int {
+	if dryRun {
+		return len(ids)
+	}
+	deleted := 0
+	for _, id := range ids {
+		if delErr := m.store.Delete(ctx, id); delErr != nil {
+			m.logger.Error(errMsg, "id", id, "error", delErr)
+			continue
+		}
+		if counter != nil {
+			metrics.Inc(counter)
+		}
+		deleted++
+	}
+	return deleted
+}
+
 // expireTTL removes memories past their TTL.
 func (m *Manager) expireTTL(ctx context.Context, dryRun bool) (int, error) {
 	scope := models.ScopeTTL
 	filters := &store.SearchFilters{Scope: &scope}
-
-	memories, err := m.listAll(ctx, filters)
-	if err != nil {
-		return 0, fmt.Errorf("listing TTL memories: %w", err)
+	if m.ttlAllScopes {
+		filters = nil
 	}
 
 	now := time.Now().UTC()
-	expired := 0
+	var expired []string
 
-	for i := range memories {
-		mem := &memories[i]
-		if mem.TTLSeconds <= 0 {
-			continue
-		}
+	err := m.forEachPage(ctx, filters, func(page []models.Memory) error {
+		for i := range page {
+			mem := &page[i]
+			if mem.TTLSeconds <= 0 {
+				continue
+			}
 
-		expiresAt := mem.CreatedAt.Add(time.Duration(mem.TTLSeconds) * time.Second)
-		if now.After(expiresAt) {
-			m.logger.Info("expiring TTL memory", "id", mem.ID, "created", mem.CreatedAt, "ttl_seconds", mem.TTLSeconds)
-			if !dryRun {
-				if delErr := m.store.Delete(ctx, mem.ID); delErr != nil {
-					m.logger.Error("deleting expired memory", "id", mem.ID, "error", delErr)
-					continue
-				}
-				metrics.Inc(metrics.LifecycleExpired) // only incremented on actual deletes, not dry-run
+			expiresAt := mem.CreatedAt.Add(time.Duration(mem.TTLSeconds) * time.Second)
+			if now.After(expiresAt) {
+				m.logger.Info("expiring TTL memory", "id", mem.ID, "created", mem.CreatedAt, "ttl_seconds", mem.TTLSeconds)
+				expired = append(expired, mem.ID)
 			}
-			expired++
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("listing TTL memories: %w", err)
 	}
 
-	return expired, nil
+	// metrics.LifecycleExpired is only incremented on actual deletes, not dry-run.
+	return m.deleteIDs(ctx, expired, dryRun, metrics.LifecycleExpired, "deleting expired memory"), nil
 }
 
 // decaySessions removes old session-scoped memories that haven't been accessed recently.
@@ -173,115 +419,243 @@ func (m *Manager) decaySessions(ctx context.Context, dryRun bool) (int, error) {
 	scope := models.ScopeSession
 	filters := &store.SearchFilters{Scope: &scope}
 
-	memories, err := m.listAll(ctx, filters)
+	now := time.Now().UTC()
+	var decayed []string
+
+	err := m.forEachPage(ctx, filters, func(page []models.Memory) error {
+		for i := range page {
+			mem := &page[i]
+			lastAccess := mem.LastAccessed
+			if lastAccess.IsZero() {
+				lastAccess = mem.CreatedAt
+			}
+
+			if now.Sub(lastAccess) > m.sessionDecay {
+				m.logger.Info("decaying session memory", "id", mem.ID, "last_accessed", lastAccess)
+				decayed = append(decayed, mem.ID)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return 0, fmt.Errorf("listing session memories: %w", err)
 	}
 
-	now := time.Now().UTC()
-	decayed := 0
-	decayThreshold := 24 * time.Hour // Session memories expire after 24h without access
+	// metrics.LifecycleDecayed is only incremented on actual deletes, not dry-run.
+	return m.deleteIDs(ctx, decayed, dryRun, metrics.LifecycleDecayed, "deleting decayed memory"), nil
+}
 
-	for i := range memories {
-		mem := &memories[i]
-		lastAccess := mem.LastAccessed
-		if lastAccess.IsZero() {
-			lastAccess = mem.CreatedAt
+// evictOverCap deletes the least recently accessed memories of every scope
+// holding more than its SetMaxPerScope cap, down to the cap. Memories never
+// accessed rank by creation time. A scope whose scan hit max_scan is skipped:
+// List returns newest first, so the least recently accessed memories were
+// never seen and evicting from the partial set would delete the wrong ones.
+func (m *Manager) evictOverCap(ctx context.Context, dryRun bool) (int, error) {
+	type candidate struct {
+		id         string
+		lastAccess time.Time
+	}
+
+	evicted := 0
+	for _, scope := range models.ValidMemoryScopes {
+		limit := m.maxPerScope[scope]
+		if limit <= 0 {
+			continue
 		}
 
-		if now.Sub(lastAccess) > decayThreshold {
-			m.logger.Info("decaying session memory", "id", mem.ID, "last_accessed", lastAccess)
-			if !dryRun {
-				if delErr := m.store.Delete(ctx, mem.ID); delErr != nil {
-					m.logger.Error("deleting decayed memory", "id", mem.ID, "error", delErr)
-					continue
+		var all []candidate
+		truncated, err := m.scanPages(ctx, &store.SearchFilters{Scope: &scope}, func(page []models.Memory) error {
+			for i := range page {
+				last := page[i].LastAccessed
+				if last.IsZero() {
+					last = page[i].CreatedAt
 				}
-				metrics.Inc(metrics.LifecycleDecayed) // only incremented on actual deletes, not dry-run
+				all = append(all, candidate{id: page[i].ID, lastAccess: last})
 			}
-			decayed++
+			return nil
+		})
+		if err != nil {
+			return evicted, fmt.Errorf("listing %s memories: %w", scope, err)
+		}
+		if truncated {
+			m.logger.Warn("lifecycle: skipping scope cap eviction, scan was truncated; raise lifecycle.max_scan above the scope size",
+				"scope", scope, "max", limit, "max_scan", m.maxScan)
+			continue
+		}
+		if len(all) <= limit {
+			continue
 		}
-	}
 
-	return decayed, nil
+		sort.Slice(all, func(i, j int) bool {
+			if !all[i].lastAccess.Equal(all[j].lastAccess) {
+				return all[i].lastAccess.Before(all[j].lastAccess)
+			}
+			return all[i].id < all[j].id
+		})
+		excess := all[:len(all)-limit]
+		m.logger.Info("evicting memories over scope cap", "scope", scope, "count", len(all), "max", limit, "evicting", len(excess))
+		ids := make([]string, len(excess))
+		for i := range excess {
+			ids[i] = excess[i].id
+		}
+		// metrics.LifecycleEvicted is only incremented on actual deletes, not dry-run.
+		evicted += m.deleteIDs(ctx, ids, dryRun, metrics.LifecycleEvicted, "deleting evicted memory")
+	}
+	return evicted, nil
 }
 
 // consolidate merges near-duplicate permanent memories, keeping the higher-confidence one.
-// It embeds all memories in a single batch call to minimize Ollama round-trips, then
-// performs pairwise cosine similarity comparison in memory.
+// The pairs to merge come from planConsolidation; deletions are applied after the scan.
+// With a Merger set (see SetMerger), each cluster is instead replaced by an
+// LLM-merged memory, falling back to deletion when the merge fails.
 func (m *Manager) consolidate(ctx context.Context, dryRun bool) (int, error) {
+	pairs, err := m.planConsolidation(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if m.merger != nil && !dryRun {
+		return m.mergeClusters(ctx, pairs), nil
+	}
+	toDelete := make([]string, len(pairs))
+	for i := range pairs {
+		m.logger.Info("consolidating duplicate memories",
+			"keep", pairs[i].Keep,
+			"delete", pairs[i].Drop,
+			"similarity", pairs[i].Similarity,
+		)
+		toDelete[i] = pairs[i].Drop
+	}
+	return m.deleteIDs(ctx, toDelete, dryRun, nil, "consolidate: delete failed"), nil
+}
+
+// mergeClusters merges each cluster of pairs with mergeCluster, deleting the
+// non-survivors of any cluster that could not be merged.
+func (m *Manager) mergeClusters(ctx context.Context, pairs []ConsolidationPair) int {
+	consolidated := 0
+	for _, c := range ClusterPairs(pairs) {
+		retired, err := m.mergeCluster(ctx, c)
+		if err != nil {
+			m.logger.Warn("consolidate: merge failed, deleting duplicates instead", "survivor", c.Survivor, "error", err)
+			consolidated += m.deleteIDs(ctx, c.Members[1:], false, nil, "consolidate: delete failed")
+			continue
+		}
+		consolidated += retired
+	}
+	return consolidated
+}
+
+// planConsolidation finds the near-duplicate permanent memories consolidate
+// would merge, in the order it would merge them, without changing anything.
+// Permanent memories are streamed a page at a time: each page is embedded in a single
+// batch call, compared pairwise within the page, and then matched against the rest of
+// the current memories of the rest of the store. Only one page of memories and vectors plus the set of
+// memories chosen for deletion is held in memory, so usage stays bounded regardless of
+// collection size. Pairs in different partitions (see SetConsolidationPartition) and
+// memories with an excluded tag (see SetConsolidationExcludeTags) are never merged.
+func (m *Manager) planConsolidation(ctx context.Context) ([]ConsolidationPair, error) {
 	if m.emb == nil {
 		m.logger.Debug("lifecycle: consolidation skipped (no embedder configured)")
-		return 0, nil
+		return nil, nil
 	}
 
 	scope := models.ScopePermanent
 	filters := &store.SearchFilters{Scope: &scope}
-	memories, err := m.listAll(ctx, filters)
-	if err != nil {
-		return 0, fmt.Errorf("listing permanent memories: %w", err)
-	}
-
-	if len(memories) == 0 {
-		return 0, nil
-	}
 
-	// Collect content strings for a single batch embed call (1 Ollama round-trip instead of N).
-	contents := make([]string, len(memories))
-	for i := range memories {
-		contents[i] = memories[i].Content
+	deleted := make(map[string]bool)
+	var pairs []ConsolidationPair
+	markDeleted := func(keep, drop *models.Memory, sim float64) {
+		deleted[drop.ID] = true
+		pairs = append(pairs, ConsolidationPair{Keep: keep.ID, Drop: drop.ID, Similarity: sim})
 	}
 
-	vecs, batchErr := m.emb.EmbedBatch(ctx, contents)
-	if batchErr != nil {
-		return 0, fmt.Errorf("consolidate: batch embed failed: %w", batchErr)
-	}
-	if len(vecs) != len(memories) {
-		return 0, fmt.Errorf("consolidate: embedder returned %d vectors for %d memories (contract violation)",
-			len(vecs), len(memories))
-	}
+	err := m.forEachPage(ctx, filters, func(page []models.Memory) error {
+		// Memories already chosen for deletion by an earlier page's
+		// cross-page check, and protected memories, take no further part.
+		memories := make([]models.Memory, 0, len(page))
+		inPage := make(map[string]bool, len(page))
+		for i := range page {
+			if !deleted[page[i].ID] && !m.excludedFromConsolidation(&page[i]) {
+				memories = append(memories, page[i])
+				inPage[page[i].ID] = true
+			}
+		}
+		if len(memories) == 0 {
+			return nil
+		}
 
-	consolidated := 0
-	deleted := make(map[string]bool)
+		// Collect content strings for a single batch embed call (1 Ollama round-trip per page).
+		contents := make([]string, len(memories))
+		for i := range memories {
+			contents[i] = embedder.DocumentText(memories[i].Type, memories[i].Content)
+		}
 
-	for i := range memories {
-		if deleted[memories[i].ID] {
-			continue
+		vecs, batchErr := m.emb.EmbedBatch(ctx, contents)
+		if batchErr != nil {
+			return fmt.Errorf("consolidate: batch embed failed: %w", batchErr)
 		}
-		vecA := vecs[i]
-		for j := i + 1; j < len(memories); j++ {
-			if deleted[memories[j].ID] {
+		if len(vecs) != len(memories) {
+			return fmt.Errorf("consolidate: embedder returned %d vectors for %d memories (contract violation)",
+				len(vecs), len(memories))
+		}
+
+		for i := range memories {
+			if deleted[memories[i].ID] {
 				continue
 			}
-			vecB := vecs[j]
-			sim := vecmath.CosineSimilarity(vecA, vecB)
-			if sim > consolidationThreshold {
-				// Keep higher confidence, delete the other.
-				keepIdx, deleteIdx := i, j
-				if memories[j].Confidence > memories[i].Confidence {
-					keepIdx, deleteIdx = j, i
+			vecA := vecs[i]
+
+			// Pairwise comparison within the page.
+			for j := i + 1; j < len(memories); j++ {
+				if deleted[memories[j].ID] || !m.partition.same(&memories[i], &memories[j]) {
+					continue
 				}
-				m.logger.Info("consolidating duplicate memories",
-					"keep", memories[keepIdx].ID,
-					"delete", memories[deleteIdx].ID,
-					"similarity", sim,
-				)
-				if !dryRun {
-					if delErr := m.store.Delete(ctx, memories[deleteIdx].ID); delErr != nil {
-						m.logger.Error("consolidate: delete failed", "id", memories[deleteIdx].ID, "error", delErr)
-						continue
+				sim := vecmath.CosineSimilarity(vecA, vecs[j])
+				if sim > m.consolidationThreshold {
+					// Keep higher confidence, delete the other.
+					keep, drop := &memories[i], &memories[j]
+					if memories[j].Confidence > memories[i].Confidence {
+						keep, drop = drop, keep
 					}
+					markDeleted(keep, drop, sim)
+					// If the outer anchor was deleted, stop comparing against its stale vector.
+					if drop == &memories[i] {
+						break
+					}
+				}
+			}
+			if deleted[memories[i].ID] {
+				continue
+			}
+
+			// Match against permanent memories of the same partition outside this page.
+			dups, dupErr := m.store.Search(ctx, vecA, consolidationCandidates, m.partition.searchFilters(&memories[i]))
+			if dupErr != nil {
+				m.logger.Warn("consolidate: duplicate search failed", "id", memories[i].ID, "error", dupErr)
+				continue
+			}
+			for k := range dups {
+				other := &dups[k].Memory
+				if other.Scope != models.ScopePermanent || other.ValidTo != nil || inPage[other.ID] || deleted[other.ID] ||
+					dups[k].Score <= m.consolidationThreshold || !m.partition.same(&memories[i], other) ||
+					m.excludedFromConsolidation(other) {
+					continue
 				}
-				deleted[memories[deleteIdx].ID] = true
-				consolidated++
-				// If the outer anchor was deleted, stop comparing against its stale vector.
-				if deleteIdx == i {
+				keep, drop := &memories[i], other
+				if other.Confidence > memories[i].Confidence {
+					keep, drop = drop, keep
+				}
+				markDeleted(keep, drop, dups[k].Score)
+				if drop == &memories[i] {
 					break
 				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing permanent memories: %w", err)
 	}
-
-	return consolidated, nil
+	return pairs, nil
 }
 
 // resolveConflicts batch-resolves active conflict groups by picking a winner
@@ -329,40 +703,87 @@ func (m *Manager) resolveConflicts(ctx context.Context, dryRun bool) (int, error
 	return resolved, nil
 }
 
+// purgeTrash permanently deletes memories that have been in the trash longer
+// than the configured retention.
+func (m *Manager) purgeTrash(ctx context.Context, dryRun bool) (int, error) {
+	if m.trashRetention <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().UTC().Add(-m.trashRetention)
+	trashed, err := m.store.ListTrashed(ctx, cutoff, uint64(m.maxScan))
+	if err != nil {
+		return 0, fmt.Errorf("purgeTrash: listing trashed memories: %w", err)
+	}
+
+	purged := 0
+	for i := range trashed {
+		mem := &trashed[i]
+		m.logger.Info("purging trashed memory", "id", mem.ID, "deleted_at", mem.DeletedAt)
+		if !dryRun {
+			if delErr := m.store.Delete(ctx, mem.ID); delErr != nil {
+				m.logger.Error("purging trashed memory", "id", mem.ID, "error", delErr)
+				continue
+			}
+			metrics.Inc(metrics.LifecyclePurged)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// expireAt deletes memories of every scope whose ExpiresAt date has passed.
+// Only those memories are listed (see store.SearchFilters.ExpiresBefore).
+func (m *Manager) expireAt(ctx context.Context, dryRun bool) (int, error) {
+	now := time.Now().UTC()
+	var expired []string
+	err := m.forEachPage(ctx, &store.SearchFilters{ExpiresBefore: &now}, func(page []models.Memory) error {
+		for i := range page {
+			mem := &page[i]
+			m.logger.Info("expiring memory past its expires_at", "id", mem.ID, "expires_at", mem.ExpiresAt)
+			expired = append(expired, mem.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		// Delete what was found before the failure.
+		n := m.deleteIDs(ctx, expired, dryRun, metrics.LifecycleExpiredAt, "deleting expired memory")
+		return n, fmt.Errorf("expireAt: listing memories: %w", err)
+	}
+	// metrics.LifecycleExpiredAt is only incremented on actual deletes, not dry-run.
+	return m.deleteIDs(ctx, expired, dryRun, metrics.LifecycleExpiredAt, "deleting expired memory"), nil
+}
+
 // retireExpiredFacts deletes memories whose ValidUntil has passed.
 // It scans permanent and project memories (TTL-scoped memories are handled by expireTTL).
 // Returns the count of deleted memories.
 func (m *Manager) retireExpiredFacts(ctx context.Context, dryRun bool) (int, error) {
 	now := time.Now().UTC()
-	retired := 0
+	var retired []string
 
 	for _, scope := range []models.MemoryScope{models.ScopePermanent, models.ScopeProject} {
 		sc := scope
 		filters := &store.SearchFilters{Scope: &sc}
-		memories, err := m.listAll(ctx, filters)
-		if err != nil {
-			return retired, fmt.Errorf("retireExpiredFacts: listing %s memories: %w", scope, err)
-		}
-
-		for i := range memories {
-			mem := &memories[i]
-			if mem.ValidUntil.IsZero() {
-				continue
-			}
-			if !mem.ValidUntil.Before(now) {
-				continue
-			}
-			m.logger.Info("retiring expired fact", "id", mem.ID, "valid_until", mem.ValidUntil)
-			if !dryRun {
-				if delErr := m.store.Delete(ctx, mem.ID); delErr != nil {
-					m.logger.Error("deleting retired memory", "id", mem.ID, "error", delErr)
+		err := m.forEachPage(ctx, filters, func(page []models.Memory) error {
+			for i := range page {
+				mem := &page[i]
+				if mem.ValidUntil.IsZero() {
+					continue
+				}
+				if !mem.ValidUntil.Before(now) {
 					continue
 				}
-				metrics.Inc(metrics.LifecycleRetired) // only incremented on actual deletes, not dry-run
+				m.logger.Info("retiring expired fact", "id", mem.ID, "valid_until", mem.ValidUntil)
+				retired = append(retired, mem.ID)
 			}
-			retired++
+			return nil
+		})
+		if err != nil {
+			// Retire what was found before the failure.
+			n := m.deleteIDs(ctx, retired, dryRun, metrics.LifecycleRetired, "deleting retired memory")
+			return n, fmt.Errorf("retireExpiredFacts: listing %s memories: %w", scope, err)
 		}
 	}
 
-	return retired, nil
+	// metrics.LifecycleRetired is only incremented on actual deletes, not dry-run.
+	return m.deleteIDs(ctx, retired, dryRun, metrics.LifecycleRetired, "deleting retired memory"), nil
 }
@@ -0,0 +1,147 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/xmlutil"
+)
+
+const (
+	// MergeModeDelete keeps the highest-confidence memory of each
+	// near-duplicate cluster and deletes the rest (the default).
+	MergeModeDelete = "delete"
+	// MergeModeLLM asks Claude to merge each cluster into one canonical
+	// statement, stored as a new memory that supersedes the cluster.
+	MergeModeLLM = "llm"
+
+	// mergerMaxTokens bounds the length of a merged statement.
+	mergerMaxTokens = 512
+)
+
+// Merger uses Claude to combine near-duplicate memories into a single
+// statement that keeps every distinct detail, so consolidation does not lose
+// information held only by the memories it would otherwise delete.
+type Merger struct {
+	client llm.LLMClient
+	model  string
+	logger *slog.Logger
+}
+
+// NewMerger creates a Merger backed by the given LLM client.
+func NewMerger(client llm.LLMClient, model string, logger *slog.Logger) *Merger {
+	return &Merger{
+		client: client,
+		model:  model,
+		logger: logger,
+	}
+}
+
+// Merge returns one statement combining contents. On failure an error is
+// returned and callers should fall back to delete-only consolidation.
+func (mg *Merger) Merge(ctx context.Context, contents []string) (string, error) {
+	var sb strings.Builder
+	for i := range contents {
+		fmt.Fprintf(&sb, "[%d] %s\n", i, xmlutil.Escape(contents[i]))
+	}
+
+	prompt := fmt.Sprintf(`You are deduplicating an AI agent's long-term memory.
+
+The numbered memories below say nearly the same thing. Merge them into ONE concise canonical statement that keeps every distinct detail from any of them. Do not add facts that are not in the memories.
+
+Output only the merged statement.
+
+<memories>
+%s</memories>`, sb.String())
+
+	responseText, err := mg.client.Complete(ctx, mg.model, "", prompt, mergerMaxTokens)
+	if err != nil {
+		return "", fmt.Errorf("merger: calling Claude: %w", err)
+	}
+	responseText = strings.TrimSpace(llm.StripCodeFences(responseText))
+	if responseText == "" {
+		return "", fmt.Errorf("merger: empty response from Claude")
+	}
+	mg.logger.Debug("merged duplicate memories", "memories", len(contents), "length", len(responseText))
+	return responseText, nil
+}
+
+// SetMerger switches consolidation to MergeModeLLM: each near-duplicate
+// cluster is merged by mg into a new memory instead of keeping only its
+// highest-confidence member. Nil restores delete-only consolidation.
+func (m *Manager) SetMerger(mg *Merger) {
+	m.merger = mg
+}
+
+// mergeCluster replaces the memories of c with one merged memory. The new
+// memory is a successor of the survivor (so the survivor is invalidated and
+// kept as history), carries the union of the members' tags and the sum of
+// their access counts; the other members are invalidated too. It returns how
+// many members besides the survivor were retired, or an error when nothing
+// was written and the caller should fall back to deleting them.
+func (m *Manager) mergeCluster(ctx context.Context, c ConsolidationCluster) (int, error) {
+	members := make([]*models.Memory, 0, len(c.Members))
+	for _, id := range c.Members {
+		mem, err := m.store.Get(ctx, id)
+		if err != nil {
+			return 0, fmt.Errorf("loading %s: %w", id, err)
+		}
+		members = append(members, mem)
+	}
+	contents := make([]string, len(members))
+	for i := range members {
+		contents[i] = members[i].Content
+	}
+	merged, err := m.merger.Merge(ctx, contents)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	next := members[0].Successor(uuid.New().String(), now)
+	next.Content = merged
+	next.Tags = slices.Clone(members[0].Tags)
+	next.AccessCount = 0
+	for _, mem := range members {
+		next.AccessCount += mem.AccessCount
+		next.Confidence = max(next.Confidence, mem.Confidence)
+		for _, t := range mem.Tags {
+			if !slices.Contains(next.Tags, t) {
+				next.Tags = append(next.Tags, t)
+			}
+		}
+		for _, a := range mem.Attachments {
+			if !slices.Contains(next.Attachments, a) {
+				next.Attachments = append(next.Attachments, a)
+			}
+		}
+	}
+
+	vec, err := m.emb.Embed(ctx, embedder.DocumentText(next.Type, next.Content))
+	if err != nil {
+		return 0, fmt.Errorf("embedding merged memory: %w", err)
+	}
+	if err := m.store.Upsert(ctx, next, vec); err != nil {
+		return 0, fmt.Errorf("storing merged memory: %w", err)
+	}
+
+	retired := 0
+	for _, mem := range members[1:] {
+		if err := m.store.InvalidateMemory(ctx, mem.ID, now); err != nil {
+			m.logger.Error("consolidate: invalidate merged memory failed", "id", mem.ID, "error", err)
+			continue
+		}
+		retired++
+	}
+	m.logger.Info("merged duplicate memories", "new_id", next.ID, "members", c.Members)
+	return retired, nil
+}
@@ -0,0 +1,82 @@
+package lifecycle
+
+import (
+	"context"
+	"slices"
+)
+
+// ConsolidationPair is one merge the consolidation phase would perform: Drop
+// is deleted in favour of Keep.
+type ConsolidationPair struct {
+	Keep       string  `json:"keep"`
+	Drop       string  `json:"drop"`
+	Similarity float64 `json:"similarity"`
+}
+
+// ConsolidationCluster is a group of near-duplicate memories that
+// consolidation would collapse into Survivor. Members lists every memory in
+// the cluster, Survivor first; Pairs holds the similarities of the merges
+// that link them.
+type ConsolidationCluster struct {
+	Survivor string              `json:"survivor"`
+	Members  []string            `json:"members"`
+	Pairs    []ConsolidationPair `json:"pairs"`
+}
+
+// PreviewConsolidation returns the near-duplicate clusters the consolidation
+// phase would act on, without deleting anything, so users can review them
+// first. Clusters are ordered by size, largest first.
+func (m *Manager) PreviewConsolidation(ctx context.Context) ([]ConsolidationCluster, error) {
+	pairs, err := m.planConsolidation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ClusterPairs(pairs), nil
+}
+
+// ClusterPairs groups consolidation pairs by the memory that finally
+// survives: a memory kept in one merge may itself be dropped by a later one,
+// so each drop is followed through its keeps to the end of the chain.
+func ClusterPairs(pairs []ConsolidationPair) []ConsolidationCluster {
+	keptBy := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		keptBy[p.Drop] = p.Keep
+	}
+	survivor := func(id string) string {
+		for seen := 0; seen <= len(pairs); seen++ { // bounded in case of a cycle
+			next, ok := keptBy[id]
+			if !ok {
+				break
+			}
+			id = next
+		}
+		return id
+	}
+
+	byRoot := make(map[string]*ConsolidationCluster)
+	var order []string
+	for _, p := range pairs {
+		root := survivor(p.Drop)
+		c, ok := byRoot[root]
+		if !ok {
+			c = &ConsolidationCluster{Survivor: root, Members: []string{root}}
+			byRoot[root] = c
+			order = append(order, root)
+		}
+		c.Pairs = append(c.Pairs, p)
+		for _, id := range []string{p.Keep, p.Drop} {
+			if !slices.Contains(c.Members, id) {
+				c.Members = append(c.Members, id)
+			}
+		}
+	}
+
+	clusters := make([]ConsolidationCluster, len(order))
+	for i, root := range order {
+		clusters[i] = *byRoot[root]
+	}
+	slices.SortStableFunc(clusters, func(a, b ConsolidationCluster) int {
+		return len(b.Members) - len(a.Members)
+	})
+	return clusters
+}
@@ -0,0 +1,67 @@
+package lifecycle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/timeutil"
+)
+
+// TypeTTL assigns default time-to-live values by memory type at store time,
+// so that, for example, episodes age out after a week while rules persist.
+// The expire phase then deletes them like any other TTL memory.
+//
+// A nil *TypeTTL is valid and assigns nothing.
+type TypeTTL struct {
+	ttl map[models.MemoryType]time.Duration
+
+	// allScopes applies the defaults to every memory of a listed type, not
+	// only to ttl-scoped ones.
+	allScopes bool
+}
+
+// ParseTypeTTL builds a TypeTTL from config-style values: memory type names
+// mapped to durations such as "7d" or "36h". allScopes enables type-based
+// retention for all scopes. Returns nil when ttl is empty.
+func ParseTypeTTL(ttl map[string]string, allScopes bool) (*TypeTTL, error) {
+	if len(ttl) == 0 {
+		return nil, nil
+	}
+	t := &TypeTTL{ttl: make(map[models.MemoryType]time.Duration, len(ttl)), allScopes: allScopes}
+	for name, raw := range ttl {
+		mt := models.MemoryType(name)
+		if !mt.IsValid() {
+			return nil, fmt.Errorf("unknown memory type %q", name)
+		}
+		d, err := timeutil.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", name, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("type %s: ttl must be positive", name)
+		}
+		t.ttl[mt] = d
+	}
+	return t, nil
+}
+
+// Apply sets m.TTLSeconds from the default for m.Type. Memories that already
+// carry an explicit TTL are left alone, as are non-ttl-scoped memories unless
+// type-based retention covers all scopes.
+func (t *TypeTTL) Apply(m *models.Memory) {
+	if t == nil || m.TTLSeconds > 0 {
+		return
+	}
+	if m.Scope != models.ScopeTTL && !t.allScopes {
+		return
+	}
+	if d, ok := t.ttl[m.Type]; ok {
+		m.TTLSeconds = int64(d / time.Second)
+	}
+}
+
+// AllScopes reports whether the defaults apply to memories of every scope.
+func (t *TypeTTL) AllScopes() bool {
+	return t != nil && t.allScopes
+}
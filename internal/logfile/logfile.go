@@ -0,0 +1,147 @@
+// Package logfile provides a size-rotated log file writer for slog handlers.
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options controls when a Writer rotates and which rotated files it keeps.
+type Options struct {
+	// MaxSizeBytes rotates the file once a write would grow it past this
+	// size. 0 disables rotation.
+	MaxSizeBytes int64
+
+	// MaxBackups is how many rotated files (path.1, path.2, ...) to keep.
+	// 0 keeps none: the old file is removed on rotation.
+	MaxBackups int
+
+	// MaxAge removes rotated files last modified longer ago than this.
+	// 0 keeps them regardless of age.
+	MaxAge time.Duration
+}
+
+// Writer is an io.Writer that appends to a log file and rotates it by size.
+// Rotated files are renamed path.1 (newest) through path.N (oldest). It is
+// safe for concurrent use within one process; several processes appending to
+// the same file is fine, though each rotates based on its own view of the size.
+type Writer struct {
+	mu   sync.Mutex
+	path string
+	opts Options
+	file *os.File
+	size int64
+}
+
+// Open opens (or creates) the log file at path for appending, creating its
+// directory if needed.
+func Open(path string, opts Options) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("logfile: creating directory: %w", err)
+	}
+	w := &Writer{path: path, opts: opts}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("logfile: opening %s: %w", w.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("logfile: stat %s: %w", w.path, err)
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+// Write appends p to the log file, rotating first if p would push the file
+// past MaxSizeBytes. A single write larger than the limit still lands whole
+// in a fresh file.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return 0, fmt.Errorf("logfile: write to closed file")
+	}
+	if w.opts.MaxSizeBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.MaxSizeBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate shifts path.N-1 to path.N, ..., path to path.1, drops backups beyond
+// MaxBackups or older than MaxAge, and reopens path empty.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("logfile: closing %s: %w", w.path, err)
+	}
+	w.file = nil
+
+	if w.opts.MaxBackups > 0 {
+		for i := w.opts.MaxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(w.backup(i), w.backup(i+1))
+		}
+		if err := os.Rename(w.path, w.backup(1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("logfile: rotating %s: %w", w.path, err)
+		}
+	} else if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("logfile: removing %s: %w", w.path, err)
+	}
+	w.prune()
+	return w.open()
+}
+
+func (w *Writer) backup(i int) string {
+	return w.path + "." + strconv.Itoa(i)
+}
+
+// prune removes rotated files past MaxBackups or older than MaxAge.
+func (w *Writer) prune() {
+	matches, err := filepath.Glob(w.path + ".*")
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-w.opts.MaxAge)
+	for _, m := range matches {
+		i, convErr := strconv.Atoi(strings.TrimPrefix(m, w.path+"."))
+		if convErr != nil {
+			continue // not one of ours
+		}
+		if i > w.opts.MaxBackups {
+			_ = os.Remove(m)
+			continue
+		}
+		if w.opts.MaxAge > 0 {
+			if info, statErr := os.Stat(m); statErr == nil && info.ModTime().Before(cutoff) {
+				_ = os.Remove(m)
+			}
+		}
+	}
+}
+
+// Close closes the log file. Later writes return an error.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// statsResourceURI is the resource holding the current collection stats.
+	statsResourceURI = "cortex://stats"
+
+	// memoryResourcePrefix prefixes the ID in a cortex://memories/{id} URI.
+	memoryResourcePrefix = "cortex://memories/"
+)
+
+// buildStatsResource returns the MCP resource definition for collection stats.
+func buildStatsResource() mcpgo.Resource {
+	return mcpgo.NewResource(statsResourceURI, "Memory collection stats",
+		mcpgo.WithResourceDescription("Current memory counts by type and scope, computed on every read."),
+		mcpgo.WithMIMEType("application/json"),
+	)
+}
+
+// buildMemoryResourceTemplate returns the MCP resource template for reading a
+// single memory by ID.
+func buildMemoryResourceTemplate() mcpgo.ResourceTemplate {
+	return mcpgo.NewResourceTemplate(memoryResourcePrefix+"{id}", "Memory",
+		mcpgo.WithTemplateDescription("A single stored memory with all of its fields."),
+		mcpgo.WithTemplateMIMEType("application/json"),
+	)
+}
+
+// HandleStatsResource is the exported handler for the cortex://stats resource.
+func (s *Server) HandleStatsResource(ctx context.Context, req mcpgo.ReadResourceRequest) ([]mcpgo.ResourceContents, error) {
+	return s.handleStatsResource(ctx, req)
+}
+
+// HandleMemoryResource is the exported handler for cortex://memories/{id}.
+func (s *Server) HandleMemoryResource(ctx context.Context, req mcpgo.ReadResourceRequest) ([]mcpgo.ResourceContents, error) {
+	return s.handleMemoryResource(ctx, req)
+}
+
+// handleStatsResource returns the collection stats as JSON.
+func (s *Server) handleStatsResource(ctx context.Context, req mcpgo.ReadResourceRequest) ([]mcpgo.ResourceContents, error) {
+	if s.st == nil {
+		return nil, errors.New("store is unavailable")
+	}
+	stats, err := s.st.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("stats failed: %w", err)
+	}
+	return resourceJSON(req.Params.URI, stats)
+}
+
+// handleMemoryResource returns the memory named by the URI as JSON.
+func (s *Server) handleMemoryResource(ctx context.Context, req mcpgo.ReadResourceRequest) ([]mcpgo.ResourceContents, error) {
+	if s.st == nil {
+		return nil, errors.New("store is unavailable")
+	}
+	id := strings.TrimPrefix(req.Params.URI, memoryResourcePrefix)
+	if id == req.Params.URI || strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("invalid memory URI %q", req.Params.URI)
+	}
+	mem, err := s.st.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("memory not found: %w", err)
+	}
+	return resourceJSON(req.Params.URI, mem)
+}
+
+// resourceJSON marshals v to JSON and returns it as the text contents of uri.
+func resourceJSON(uri string, v any) ([]mcpgo.ResourceContents, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: marshaling resource: %w", err)
+	}
+	return []mcpgo.ResourceContents{mcpgo.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(b),
+	}}, nil
+}
@@ -13,11 +13,13 @@ import (
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 
+	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
-	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
 const (
@@ -38,6 +40,25 @@ type Server struct {
 	emb      embedder.Embedder
 	recaller *recall.Recaller
 	logger   *slog.Logger
+
+	minMemories int  // guaranteed recall memory count regardless of budget; 0 = disabled
+	skipAccess  bool // true = recall does not update access metadata
+	trash       bool // true = forget moves memories to trash instead of deleting
+	broaden     bool // true = a project recall that finds too little retries with global memories
+
+	synthesizer *recall.Synthesizer       // nil = recall mode "synthesize" is rejected
+	filter      *recall.RelevanceFilter   // nil = no LLM relevance filter on recall
+	typeTTL     *lifecycle.TypeTTL        // nil = no per-type default TTLs
+	tags        *store.TagNormalizer      // nil = tags are stored as given
+	freshness   recall.Freshness          // age buckets for the recall "freshness" parameter
+	cutoff      float64                   // default recall relevance cutoff ratio; 0 = disabled
+	perMemory   int                       // default recall per-memory token cap; 0 = disabled
+	attachRefs  bool                      // default recall "include_attachments"
+	onConflict  string                    // default recall "on_conflict"; "" = both
+	contradict  *capture.ConflictDetector // nil = no LLM contradiction check on recall
+	dedup       float64                   // default check_duplicate threshold; 0 = threshold is required
+	dedupScope  store.DedupScope          // comparison set for check_duplicate
+	conflicts   *capture.ConflictDetector // nil = no conflict check on remember
 }
 
 // NewServer creates a new MCP server. If st or emb are nil,
@@ -55,6 +76,7 @@ func NewServer(st store.Store, emb embedder.Embedder, recaller *recall.Recaller,
 		"openclaw-cortex",
 		"1.0.0",
 		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithResourceCapabilities(false, false),
 	)
 
 	mcpSrv.AddTool(buildRememberTool(), s.handleRemember)
@@ -62,13 +84,120 @@ func NewServer(st store.Store, emb embedder.Embedder, recaller *recall.Recaller,
 	mcpSrv.AddTool(buildForgetTool(), s.handleForget)
 	mcpSrv.AddTool(buildSearchTool(), s.handleSearch)
 	mcpSrv.AddTool(buildStatsTool(), s.handleStats)
+	mcpSrv.AddTool(buildCheckDuplicateTool(), s.handleCheckDuplicate)
 	mcpSrv.AddTool(buildEntitySearchTool(), s.handleEntitySearch)
 	mcpSrv.AddTool(buildEntityGetTool(), s.handleEntityGet)
 
+	mcpSrv.AddResource(buildStatsResource(), s.handleStatsResource)
+	mcpSrv.AddResourceTemplate(buildMemoryResourceTemplate(), s.handleMemoryResource)
+
 	s.mcp = mcpSrv
 	return s
 }
 
+// SetMinMemories guarantees that the recall tool returns at least n memories
+// even when the token budget would admit fewer. n <= 0 disables the guarantee.
+func (s *Server) SetMinMemories(n int) {
+	s.minMemories = n
+}
+
+// SetBroadenWhenEmpty makes the recall tool retry a project recall with
+// global memories included when it finds fewer than max(minMemories, 1)
+// memories, so a fresh project still gets context.
+func (s *Server) SetBroadenWhenEmpty(enabled bool) {
+	s.broaden = enabled
+}
+
+// SetRelevanceCutoff sets the default "relevance_cutoff" of the recall tool:
+// the context stops at the first memory whose score is below ratio times the
+// top score. ratio <= 0 disables it.
+func (s *Server) SetRelevanceCutoff(ratio float64) {
+	s.cutoff = ratio
+}
+
+// SetPerMemoryMaxTokens sets the default "per_memory_max_tokens" of the
+// recall tool: each memory is truncated to n tokens before budgeting.
+// n <= 0 disables it.
+func (s *Server) SetPerMemoryMaxTokens(n int) {
+	s.perMemory = n
+}
+
+// SetIncludeAttachments sets the default "include_attachments" of the recall
+// tool: each memory's attachment references are appended to the context.
+func (s *Server) SetIncludeAttachments(enabled bool) {
+	s.attachRefs = enabled
+}
+
+// SetOnConflict sets the default "on_conflict" of the recall tool: how
+// recalled memories that share an active conflict group are returned (see
+// recall.ResolveConflicts).
+func (s *Server) SetOnConflict(mode string) {
+	s.onConflict = mode
+}
+
+// SetRecallConflictCheck enables the LLM contradiction check among the top
+// recall tool results (see recall.DetectConflicts). nil disables it.
+func (s *Server) SetRecallConflictCheck(cd *capture.ConflictDetector) {
+	s.contradict = cd
+}
+
+// SetDedup sets the default threshold and comparison scope of the
+// check_duplicate tool. threshold <= 0 makes the tool's threshold argument
+// required.
+func (s *Server) SetDedup(threshold float64, scope store.DedupScope) {
+	s.dedup = threshold
+	s.dedupScope = scope
+}
+
+// SetTrackAccess controls whether the recall tool updates access metadata for
+// returned memories. Tracking is enabled by default.
+func (s *Server) SetTrackAccess(enabled bool) {
+	s.skipAccess = !enabled
+}
+
+// SetTrashEnabled controls whether the forget tool moves memories to the trash
+// (restorable until purged by lifecycle) instead of deleting them outright.
+func (s *Server) SetTrashEnabled(enabled bool) {
+	s.trash = enabled
+}
+
+// SetSynthesizer enables the "synthesize" mode of the recall tool, which
+// returns a single Claude-written summary as the context. nil disables it.
+func (s *Server) SetSynthesizer(syn *recall.Synthesizer) {
+	s.synthesizer = syn
+}
+
+// SetRelevanceFilter enables the LLM relevance filter on recall (see
+// recall.RelevanceFilter). nil disables it.
+func (s *Server) SetRelevanceFilter(f *recall.RelevanceFilter) {
+	s.filter = f
+}
+
+// SetFreshness sets the age buckets used by the "freshness" parameter of the
+// recall tool. The zero value uses the built-in defaults.
+func (s *Server) SetFreshness(f recall.Freshness) {
+	s.freshness = f
+}
+
+// SetConflictDetector enables the conflict check on the remember tool: when
+// the new memory contradicts a near-similar existing one, the result carries
+// a "conflict" entry with the conflicting ID. nil disables the check.
+func (s *Server) SetConflictDetector(cd *capture.ConflictDetector) {
+	s.conflicts = cd
+}
+
+// SetTypeTTL sets the per-type default TTLs applied to memories stored by the
+// remember tool. nil disables them.
+func (s *Server) SetTypeTTL(t *lifecycle.TypeTTL) {
+	s.typeTTL = t
+}
+
+// SetTagNormalizer sets the normalization applied to the tags of memories
+// stored by the remember tool. nil stores tags as given.
+func (s *Server) SetTagNormalizer(n *store.TagNormalizer) {
+	s.tags = n
+}
+
 // MCPServer returns the underlying mcp-go MCPServer for use with ServeStdio.
 func (s *Server) MCPServer() *mcpserver.MCPServer {
 	return s.mcp
@@ -95,6 +224,11 @@ func (s *Server) HandleSearch(ctx context.Context, req mcpgo.CallToolRequest) (*
 	return s.handleSearch(ctx, req)
 }
 
+// HandleCheckDuplicate is the exported handler for the "check_duplicate" tool.
+func (s *Server) HandleCheckDuplicate(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+	return s.handleCheckDuplicate(ctx, req)
+}
+
 // HandleStats is the exported handler for the "stats" tool.
 func (s *Server) HandleStats(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 	return s.handleStats(ctx, req)
@@ -149,8 +283,7 @@ func buildRecallTool() mcpgo.Tool {
 	return mcpgo.NewTool("recall",
 		mcpgo.WithDescription("Retrieve relevant memories using semantic search and multi-factor ranking."),
 		mcpgo.WithString("message",
-			mcpgo.Required(),
-			mcpgo.Description("The query to recall memories for"),
+			mcpgo.Description(`The query to recall memories for (required unless mode is "top")`),
 		),
 		mcpgo.WithString("project",
 			mcpgo.Description("Project context for scope boosting"),
@@ -158,6 +291,30 @@ func buildRecallTool() mcpgo.Tool {
 		mcpgo.WithNumber("budget",
 			mcpgo.Description("Token budget for returned context (default: 2000)"),
 		),
+		mcpgo.WithNumber("max_memories",
+			mcpgo.Description("Maximum number of memories to return regardless of budget (default: no cap)"),
+		),
+		mcpgo.WithString("mode",
+			mcpgo.Description(`"list" (default) returns the memories; "synthesize" returns one summary paragraph (slower, must be enabled on the server); "top" takes no message and returns the most important memories by recency, access frequency, type, scope and confidence`),
+		),
+		mcpgo.WithString("freshness",
+			mcpgo.Description(`"any" (default), "recent" (created in the last few days) or "established" (older, but still accessed)`),
+		),
+		mcpgo.WithString("format",
+			mcpgo.Description(`"plain" (default) lists memories by rank; "grouped" lists them under type headings such as "Rules:" and "Facts:"`),
+		),
+		mcpgo.WithNumber("relevance_cutoff",
+			mcpgo.Description("Stop adding memories once one scores below this fraction (0-1) of the top hit (default: server setting)"),
+		),
+		mcpgo.WithNumber("per_memory_max_tokens",
+			mcpgo.Description("Truncate each memory to this many tokens so more memories fit in the budget (default: server setting)"),
+		),
+		mcpgo.WithString("on_conflict",
+			mcpgo.Description(`Competing memories (same active conflict group): "both" keeps all with conflict markers, "newest" keeps the newest, "flag" keeps the newest marked as disputed (default: server setting)`),
+		),
+		mcpgo.WithBoolean("include_attachments",
+			mcpgo.Description("Append each memory's attachment references (file:, url:, commit:) to the context (default: server setting)"),
+		),
 	)
 }
 
@@ -193,6 +350,22 @@ func buildStatsTool() mcpgo.Tool {
 	)
 }
 
+func buildCheckDuplicateTool() mcpgo.Tool {
+	return mcpgo.NewTool("check_duplicate",
+		mcpgo.WithDescription("Check whether memories similar to the given content already exist, without storing anything. Returns the similar memories with similarity scores, most similar first, so you can decide to remember, skip or update."),
+		mcpgo.WithString("content",
+			mcpgo.Required(),
+			mcpgo.Description("The content you are about to remember"),
+		),
+		mcpgo.WithString("type",
+			mcpgo.Description("Memory type of the content: rule, fact, episode, procedure, preference (default: fact)"),
+		),
+		mcpgo.WithNumber("threshold",
+			mcpgo.Description("Minimum cosine similarity in (0, 1] (default: memory.dedup_threshold)"),
+		),
+	)
+}
+
 // --- tool handlers ---
 
 // handleRemember embeds content and upserts a new memory.
@@ -234,7 +407,7 @@ func (s *Server) handleRemember(ctx context.Context, req mcpgo.CallToolRequest)
 
 	project := req.GetString("project", "")
 
-	vec, err := s.emb.Embed(ctx, content)
+	vec, err := s.emb.Embed(ctx, embedder.DocumentText(memType, content))
 	if err != nil {
 		return mcpgo.NewToolResultErrorf("embedding failed: %s", err.Error()), nil
 	}
@@ -253,6 +426,13 @@ func (s *Server) handleRemember(ctx context.Context, req mcpgo.CallToolRequest)
 		UpdatedAt:    now,
 		LastAccessed: now,
 	}
+	s.typeTTL.Apply(&mem)
+	s.tags.Apply(&mem)
+
+	var conflict *capture.ConflictWarning
+	if s.conflicts != nil {
+		conflict = s.conflicts.CheckStore(ctx, s.st, mem.Content, mem.Confidence, vec)
+	}
 
 	if err := s.st.Upsert(ctx, mem, vec); err != nil {
 		return mcpgo.NewToolResultErrorf("store upsert failed: %s", err.Error()), nil
@@ -264,6 +444,9 @@ func (s *Server) handleRemember(ctx context.Context, req mcpgo.CallToolRequest)
 		"id":     mem.ID,
 		"stored": true,
 	}
+	if conflict != nil {
+		result["conflict"] = conflict
+	}
 	return toolResultJSON(result)
 }
 
@@ -279,8 +462,16 @@ func (s *Server) handleRecall(ctx context.Context, req mcpgo.CallToolRequest) (*
 		return mcpgo.NewToolResultError("recaller is unavailable"), nil
 	}
 
+	mode := req.GetString("mode", "")
+	if !recall.ValidMode(mode) {
+		return mcpgo.NewToolResultError(`mode must be "list", "synthesize" or "top"`), nil
+	}
+	topMode := mode == recall.ModeTop
 	message := req.GetString("message", "")
-	if strings.TrimSpace(message) == "" {
+	if topMode && message != "" {
+		return mcpgo.NewToolResultError(`mode "top" ranks without a query; omit message`), nil
+	}
+	if !topMode && strings.TrimSpace(message) == "" {
 		return mcpgo.NewToolResultError("message is required and must not be empty"), nil
 	}
 
@@ -289,41 +480,128 @@ func (s *Server) handleRecall(ctx context.Context, req mcpgo.CallToolRequest) (*
 	if budget <= 0 {
 		budget = defaultRecallBudget
 	}
-
-	vec, err := s.emb.Embed(ctx, message)
-	if err != nil {
-		return mcpgo.NewToolResultErrorf("embedding failed: %s", err.Error()), nil
+	maxMemories := req.GetInt("max_memories", 0)
+	if maxMemories < 0 {
+		return mcpgo.NewToolResultError("max_memories must be non-negative"), nil
+	}
+	if mode == recall.ModeSynthesize && s.synthesizer == nil {
+		return mcpgo.NewToolResultError("synthesize mode is not enabled on this server"), nil
+	}
+	freshness := req.GetString("freshness", "")
+	if !recall.ValidFreshness(freshness) {
+		return mcpgo.NewToolResultError(`freshness must be "any", "recent" or "established"`), nil
+	}
+	format := req.GetString("format", "")
+	if !recall.ValidFormat(format) {
+		return mcpgo.NewToolResultError(`format must be "plain" or "grouped"`), nil
+	}
+	cutoff := req.GetFloat("relevance_cutoff", 0)
+	if cutoff < 0 || cutoff > 1 {
+		return mcpgo.NewToolResultError("relevance_cutoff must be between 0 and 1"), nil
+	}
+	if cutoff == 0 {
+		cutoff = s.cutoff
+	}
+	perMemory := req.GetInt("per_memory_max_tokens", 0)
+	if perMemory < 0 {
+		return mcpgo.NewToolResultError("per_memory_max_tokens must be non-negative"), nil
+	}
+	if perMemory == 0 {
+		perMemory = s.perMemory
+	}
+	includeAttachments := req.GetBool("include_attachments", s.attachRefs)
+	onConflict := req.GetString("on_conflict", s.onConflict)
+	if !recall.ValidConflictMode(onConflict) {
+		return mcpgo.NewToolResultError(`on_conflict must be "both", "newest" or "flag"`), nil
 	}
 
+	ctx, span := tracing.Start(ctx, "mcp.recall", "recall.budget", budget, "recall.project", project)
+	defer span.End()
+
 	var filters *store.SearchFilters
 	if project != "" {
 		filters = &store.SearchFilters{Project: &project}
 	}
+	now := time.Now().UTC()
+	filters = s.freshness.ApplyFilters(freshness, filters, now)
+
+	var ranked []models.RecallResult
+	if topMode {
+		results, err := recall.TopCandidates(ctx, s.st, filters, recall.TopCandidateLimit)
+		if err != nil {
+			span.RecordError(err)
+			return mcpgo.NewToolResultErrorf("listing memories failed: %s", err.Error()), nil
+		}
+		results = s.freshness.FilterResults(freshness, results, now)
+		ranked = s.recaller.Rank(results, project, "")
+	} else {
+		vec, err := s.emb.Embed(ctx, embedder.QueryText(s.recaller.ExpandQuery(message)))
+		if err != nil {
+			span.RecordError(err)
+			return mcpgo.NewToolResultErrorf("embedding failed: %s", err.Error()), nil
+		}
 
-	results, err := s.st.Search(ctx, vec, recallSearchLimit, filters)
-	if err != nil {
-		return mcpgo.NewToolResultErrorf("search failed: %s", err.Error()), nil
+		results, broadened, err := store.SearchBroadening(ctx, s.st, vec, recallSearchLimit, filters, s.broaden, s.minMemories)
+		if err != nil {
+			span.RecordError(err)
+			return mcpgo.NewToolResultErrorf("search failed: %s", err.Error()), nil
+		}
+		if broadened {
+			s.logger.Debug("recall: broadened project recall to global memories", "project", project)
+		}
+		results = s.freshness.FilterResults(freshness, results, now)
+		ranked = s.recaller.RecallWithGraph(ctx, message, vec, results, project)
 	}
-
-	ranked := s.recaller.RecallWithGraph(ctx, message, vec, results, project)
+	if s.filter != nil {
+		ranked = s.filter.Filter(ctx, message, ranked, 0)
+	}
+	if s.contradict != nil {
+		ranked = recall.DetectConflicts(ctx, s.contradict, s.st, ranked, s.logger)
+	}
+	ranked, conflicts := recall.ResolveConflicts(ranked, onConflict)
 
 	var contents []string
 	for i := range ranked {
 		contents = append(contents, ranked[i].Memory.Content)
 	}
 
-	output, count := tokenizer.FormatMemoriesWithBudget(contents, budget)
+	_, formatSpan := tracing.Start(ctx, "recall.format", "recall.budget", budget, "recall.candidates", len(contents))
+	shown := recall.MarkConflicts(recall.TruncateEach(ranked, perMemory), conflicts, onConflict)
+	if includeAttachments {
+		shown = recall.WithAttachmentRefs(shown)
+	}
+	output, count := recall.FormatContext(format, shown, budget, s.minMemories, recall.RelevanceCap(ranked, cutoff, maxMemories))
+	formatSpan.SetAttributes("memory.count", count)
+	formatSpan.End()
+	span.SetAttributes("memory.count", count)
+
+	synthesized := false
+	if mode == recall.ModeSynthesize && count > 0 {
+		summary, synErr := s.synthesizer.Synthesize(ctx, message, contents[:count])
+		if synErr != nil {
+			s.logger.Warn("mcp: recall: synthesis failed, returning memory list", "error", synErr)
+		} else {
+			output = summary
+			synthesized = true
+		}
+	}
 
 	// Update access metadata for returned memories.
-	for i := 0; i < count && i < len(ranked); i++ {
-		if updateErr := s.st.UpdateAccessMetadata(ctx, ranked[i].Memory.ID); updateErr != nil {
-			s.logger.Warn("mcp: recall: failed to update access metadata", "id", ranked[i].Memory.ID, "error", updateErr)
+	if !s.skipAccess {
+		if updateErr := s.st.UpdateAccessMetadataBatch(ctx, recall.TopIDs(ranked, count)); updateErr != nil {
+			s.logger.Warn("mcp: recall: failed to update access metadata", "count", count, "error", updateErr)
 		}
 	}
 
 	result := map[string]any{
-		"context":      output,
-		"memory_count": count,
+		"context":          output,
+		"context_json":     recall.ContextItems(shown, count),
+		"memory_count":     count,
+		"total_candidates": len(contents),
+		"truncated":        count < len(contents),
+	}
+	if synthesized {
+		result["synthesized"] = true
 	}
 	return toolResultJSON(result)
 }
@@ -339,6 +617,19 @@ func (s *Server) handleForget(ctx context.Context, req mcpgo.CallToolRequest) (*
 		return mcpgo.NewToolResultError("id is required and must not be empty"), nil
 	}
 
+	if s.trash {
+		fullID, err := s.st.Trash(ctx, id)
+		if err != nil {
+			return mcpgo.NewToolResultErrorf("delete failed: %s", err.Error()), nil
+		}
+		s.logger.Info("mcp: forget moved memory to trash", "id", fullID)
+		return toolResultJSON(map[string]any{
+			"deleted": true,
+			"trashed": true,
+			"id":      fullID,
+		})
+	}
+
 	if err := s.st.Delete(ctx, id); err != nil {
 		return mcpgo.NewToolResultErrorf("delete failed: %s", err.Error()), nil
 	}
@@ -374,8 +665,12 @@ func (s *Server) handleSearch(ctx context.Context, req mcpgo.CallToolRequest) (*
 	}
 	project := req.GetString("project", "")
 
-	vec, err := s.emb.Embed(ctx, message)
+	ctx, span := tracing.Start(ctx, "mcp.search", "search.limit", limit, "search.project", project)
+	defer span.End()
+
+	vec, err := s.emb.Embed(ctx, embedder.QueryText(message))
 	if err != nil {
+		span.RecordError(err)
 		return mcpgo.NewToolResultErrorf("embedding failed: %s", err.Error()), nil
 	}
 
@@ -386,6 +681,7 @@ func (s *Server) handleSearch(ctx context.Context, req mcpgo.CallToolRequest) (*
 
 	results, err := s.st.Search(ctx, vec, uint64(limit), filters) //nolint:gosec // limit validated above
 	if err != nil {
+		span.RecordError(err)
 		return mcpgo.NewToolResultErrorf("search failed: %s", err.Error()), nil
 	}
 
@@ -395,6 +691,50 @@ func (s *Server) handleSearch(ctx context.Context, req mcpgo.CallToolRequest) (*
 	return toolResultJSON(result)
 }
 
+// handleCheckDuplicate runs the store-time dedup check for content and
+// returns the similar memories without storing anything.
+func (s *Server) handleCheckDuplicate(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+	if s.st == nil {
+		return mcpgo.NewToolResultError("store is unavailable"), nil
+	}
+	if s.emb == nil {
+		return mcpgo.NewToolResultError("embedder is unavailable"), nil
+	}
+
+	content := req.GetString("content", "")
+	if strings.TrimSpace(content) == "" {
+		return mcpgo.NewToolResultError("content is required and must not be empty"), nil
+	}
+	memType := models.MemoryType(req.GetString("type", string(models.MemoryTypeFact)))
+	if !memType.IsValid() {
+		return mcpgo.NewToolResultErrorf("invalid memory type %q", memType), nil
+	}
+	threshold := req.GetFloat("threshold", s.dedup)
+	if threshold == 0 {
+		return mcpgo.NewToolResultError("threshold is required: no default dedup threshold is configured"), nil
+	}
+	if err := store.ValidateDedupThreshold(threshold); err != nil {
+		return mcpgo.NewToolResultError(err.Error()), nil
+	}
+
+	vec, err := s.emb.Embed(ctx, embedder.DocumentText(memType, content))
+	if err != nil {
+		return mcpgo.NewToolResultErrorf("embedding failed: %s", err.Error()), nil
+	}
+
+	dupes, err := store.CheckDuplicates(ctx, s.st, vec, threshold, memType, s.dedupScope)
+	if err != nil {
+		return mcpgo.NewToolResultErrorf("duplicate check failed: %s", err.Error()), nil
+	}
+	if dupes == nil {
+		dupes = []models.SearchResult{}
+	}
+	return toolResultJSON(map[string]any{
+		"duplicates": dupes,
+		"threshold":  threshold,
+	})
+}
+
 // handleStats returns collection statistics.
 func (s *Server) handleStats(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 	if s.st == nil {
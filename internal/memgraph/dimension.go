@@ -0,0 +1,110 @@
+package memgraph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// vectorProperties maps each vector index to the label and property it
+// covers, for clearing vectors of the wrong size before a rebuild.
+var vectorProperties = map[string]struct{ label, property string }{
+	"memory_embedding":      {"Memory", "embedding"},
+	"entity_name_embedding": {"Entity", "name_embedding"},
+}
+
+// ParseVectorIndexDimensions returns the indexName → dimension map of SHOW
+// VECTOR INDEXES rows that report a "dimension" column.
+// Exported so tests/ can exercise the parsing logic without a live session.
+func ParseVectorIndexDimensions(rows []map[string]any) map[string]int {
+	dims := make(map[string]int)
+	for _, row := range rows {
+		name, _ := row["index_name"].(string)
+		var dim int
+		switch v := row["dimension"].(type) {
+		case int64:
+			dim = int(v)
+		case int:
+			dim = v
+		case float64:
+			dim = int(v)
+		}
+		if name != "" && dim > 0 {
+			dims[name] = dim
+		}
+	}
+	return dims
+}
+
+// CheckVectorIndexDimension returns an error when an existing vector index
+// was built for a different dimension than memory.vector_dimension. Such an
+// index rejects or mis-ranks every new vector, so startup fails until the
+// operator rebuilds it with "reembed --recreate-index". An unknown existing
+// dimension (0) is accepted.
+func CheckVectorIndexDimension(indexName string, existing, want int) error {
+	if existing == 0 || existing == want {
+		return nil
+	}
+	return fmt.Errorf("vector index %q was built for %d-dimensional vectors but memory.vector_dimension is %d; "+
+		"run `openclaw-cortex reembed --recreate-index` to rebuild it and re-embed every memory, "+
+		"or set memory.vector_dimension back", indexName, existing, want)
+}
+
+// SetRebuildOnDimensionChange makes EnsureCollection drop and recreate a
+// vector index whose dimension differs from the configured one instead of
+// failing. Stored vectors of the old size are cleared first, so the affected
+// memories report HasEmbedding false until they are re-embedded. Only the
+// reembed command enables this, after the operator confirms.
+func (s *MemgraphStore) SetRebuildOnDimensionChange(enabled bool) {
+	s.rebuildOnDimChange = enabled
+}
+
+// VectorIndexDimensions returns the dimension each existing vector index was
+// built for, keyed by index name (memory_embedding, entity_name_embedding).
+// An index is absent when it does not exist yet or Memgraph does not report
+// its dimension.
+func (s *MemgraphStore) VectorIndexDimensions(ctx context.Context) (map[string]int, error) {
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(rctx, session)
+
+	_, _, dims, err := showVectorIndexes(rctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return dims, nil
+}
+
+// rebuildVectorIndex clears every vector of the wrong size covered by
+// indexName, then drops the index and recreates it with ddl.
+func (g *GraphAdapter) rebuildVectorIndex(ctx context.Context, session neo4j.SessionWithContext, indexName, ddl string, vectorDim int) error {
+	target, ok := vectorProperties[indexName]
+	if !ok || !knownVectorIndexNames[indexName] {
+		return fmt.Errorf("rebuildVectorIndex: unknown index name %q", indexName)
+	}
+	g.store.logger.Warn("vector index dimension changed, clearing old vectors and rebuilding",
+		"index", indexName, "dimension", vectorDim)
+
+	// label and property come from the vectorProperties whitelist above.
+	statements := []struct {
+		cypher string
+		params map[string]any
+	}{
+		{fmt.Sprintf("MATCH (n:%s) WHERE n.%s IS NOT NULL AND size(n.%s) <> $dim SET n.%s = null",
+			target.label, target.property, target.property, target.property), map[string]any{"dim": int64(vectorDim)}},
+		{fmt.Sprintf("DROP VECTOR INDEX `%s`", indexName), nil},
+		{ddl, nil},
+	}
+	for _, stmt := range statements {
+		result, err := session.Run(ctx, stmt.cypher, stmt.params)
+		if err != nil {
+			return fmt.Errorf("rebuildVectorIndex %s: %w", indexName, err)
+		}
+		if _, err := result.Consume(ctx); err != nil {
+			return fmt.Errorf("rebuildVectorIndex %s: %w", indexName, err)
+		}
+	}
+	return nil
+}
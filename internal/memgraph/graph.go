@@ -122,20 +122,20 @@ func SanitizeTextSearchQuery(q string) string {
 	}, q)
 }
 
-// BuildMemoryVectorIndexDDL returns the CREATE VECTOR INDEX DDL for the given dimension.
-// Exported for testing.
-func BuildMemoryVectorIndexDDL(dim int) string {
+// BuildMemoryVectorIndexDDL returns the CREATE VECTOR INDEX DDL for the given
+// dimension and metric. Exported for testing.
+func BuildMemoryVectorIndexDDL(dim int, metric VectorMetric) string {
 	return fmt.Sprintf(
-		`CREATE VECTOR INDEX memory_embedding ON :Memory(embedding) WITH CONFIG {"dimension": %d, "metric": "cos", "capacity": 10000}`,
-		dim,
+		`CREATE VECTOR INDEX memory_embedding ON :Memory(embedding) WITH CONFIG {"dimension": %d, "metric": "%s", "capacity": 10000}`,
+		dim, metric.indexMetric(),
 	)
 }
 
 // BuildEntityVectorIndexDDL returns the CREATE VECTOR INDEX DDL for entities.
-func BuildEntityVectorIndexDDL(dim int) string {
+func BuildEntityVectorIndexDDL(dim int, metric VectorMetric) string {
 	return fmt.Sprintf(
-		`CREATE VECTOR INDEX entity_name_embedding ON :Entity(name_embedding) WITH CONFIG {"dimension": %d, "metric": "cos", "capacity": 10000}`,
-		dim,
+		`CREATE VECTOR INDEX entity_name_embedding ON :Entity(name_embedding) WITH CONFIG {"dimension": %d, "metric": "%s", "capacity": 10000}`,
+		dim, metric.indexMetric(),
 	)
 }
 
@@ -196,12 +196,29 @@ func CheckCreateAlreadyExistsErr(createErr error, showFailed bool, indexName, ex
 	return nil
 }
 
-// showVectorIndexes runs SHOW VECTOR INDEXES and returns a map of
-// indexName → propertyName for all existing vector indexes.
-func showVectorIndexes(ctx context.Context, session neo4j.SessionWithContext) (map[string]string, error) {
+// ParseVectorIndexMetrics returns the indexName → metric map of SHOW VECTOR
+// INDEXES rows that report a "metric" column.
+// Exported so tests/ can exercise the parsing logic without a live session.
+func ParseVectorIndexMetrics(rows []map[string]any) map[string]string {
+	metrics := make(map[string]string)
+	for _, row := range rows {
+		name, _ := row["index_name"].(string)
+		metric, _ := row["metric"].(string)
+		if name != "" && metric != "" {
+			metrics[name] = metric
+		}
+	}
+	return metrics
+}
+
+// showVectorIndexes runs SHOW VECTOR INDEXES and returns maps of
+// indexName → propertyName, indexName → metric and indexName → dimension for
+// all existing vector indexes. The metric and dimension maps are empty on
+// Memgraph versions that do not report them.
+func showVectorIndexes(ctx context.Context, session neo4j.SessionWithContext) (map[string]string, map[string]string, map[string]int, error) {
 	result, err := session.Run(ctx, "SHOW VECTOR INDEXES", nil)
 	if err != nil {
-		return nil, fmt.Errorf("show vector indexes: %w", err)
+		return nil, nil, nil, fmt.Errorf("show vector indexes: %w", err)
 	}
 
 	var rows []map[string]any
@@ -214,20 +231,26 @@ func showVectorIndexes(ctx context.Context, session neo4j.SessionWithContext) (m
 		if propVal, ok := record.Get("property_name"); ok {
 			row["property_name"] = propVal
 		}
+		if metricVal, ok := record.Get("metric"); ok {
+			row["metric"] = metricVal
+		}
+		if dimVal, ok := record.Get("dimension"); ok {
+			row["dimension"] = dimVal
+		}
 		rows = append(rows, row)
 	}
 	if err := result.Err(); err != nil {
 		// Consume to flush server-side state even on error, preventing a dirty session.
 		if _, consumeErr := result.Consume(ctx); consumeErr != nil {
-			return nil, fmt.Errorf("show vector indexes: consuming result after iteration error: %w (iteration error: %v)", consumeErr, err)
+			return nil, nil, nil, fmt.Errorf("show vector indexes: consuming result after iteration error: %w (iteration error: %v)", consumeErr, err)
 		}
-		return nil, fmt.Errorf("show vector indexes: iterating results: %w", err)
+		return nil, nil, nil, fmt.Errorf("show vector indexes: iterating results: %w", err)
 	}
 	// Flush any remaining server-side state so the session can be reused safely.
 	if _, consumeErr := result.Consume(ctx); consumeErr != nil {
-		return nil, fmt.Errorf("show vector indexes: consuming result: %w", consumeErr)
+		return nil, nil, nil, fmt.Errorf("show vector indexes: consuming result: %w", consumeErr)
 	}
-	return ParseVectorIndexRows(rows), nil
+	return ParseVectorIndexRows(rows), ParseVectorIndexMetrics(rows), ParseVectorIndexDimensions(rows), nil
 }
 
 // verifyOrRebuildVectorIndex checks whether the named vector index exists and is
@@ -331,8 +354,9 @@ func (g *GraphAdapter) EnsureSchema(ctx context.Context, vectorDim int) error {
 	session := g.store.driver.NewSession(ctx, g.store.sessionConfig())
 	defer g.store.closeSession(ctx, session)
 
-	memoryVectorDDL := BuildMemoryVectorIndexDDL(vectorDim)
-	entityVectorDDL := BuildEntityVectorIndexDDL(vectorDim)
+	metric := g.store.metric()
+	memoryVectorDDL := BuildMemoryVectorIndexDDL(vectorDim, metric)
+	entityVectorDDL := BuildEntityVectorIndexDDL(vectorDim, metric)
 
 	// Vector indexes require property verification — handled separately below.
 	vectorIndexes := []vectorIndexSpec{
@@ -346,24 +370,19 @@ func (g *GraphAdapter) EnsureSchema(ctx context.Context, vectorDim int) error {
 		// Uniqueness constraints — Memgraph-specific DDL (no IF NOT EXISTS)
 		"CREATE CONSTRAINT ON (m:Memory) ASSERT m.uuid IS UNIQUE",
 		"CREATE CONSTRAINT ON (e:Entity) ASSERT e.name IS UNIQUE",
-
-		// Property indexes for filtering
-		"CREATE INDEX ON :Memory(type)",
-		"CREATE INDEX ON :Memory(scope)",
-		"CREATE INDEX ON :Memory(project)",
-		"CREATE INDEX ON :Memory(source)",
-		"CREATE INDEX ON :Memory(uuid)",
-		// Temporal versioning indexes
-		"CREATE INDEX ON :Memory(valid_from)",
-		"CREATE INDEX ON :Memory(valid_to)",
-		"CREATE INDEX ON :Entity(uuid)",
-		"CREATE INDEX ON :Entity(project)",
-
+	}
+	// Property indexes for filtering and temporal versioning
+	for _, idx := range PropertyIndexes {
+		otherQueries = append(otherQueries, idx.ddl())
+	}
+	otherQueries = append(otherQueries,
 		// Text search index for entity fulltext (label-wide)
 		"CREATE TEXT INDEX entity_text ON :Entity",
+		// Text search index for hybrid memory search (SearchFilters.Keyword)
+		"CREATE TEXT INDEX memory_text ON :Memory",
 		// Note: Memgraph does not support text indexes on relationships.
 		// Fact text search uses property-level CONTAINS matching instead.
-	}
+	)
 
 	for i := range otherQueries {
 		// Memgraph requires auto-commit (implicit) transactions for DDL.
@@ -384,7 +403,7 @@ func (g *GraphAdapter) EnsureSchema(ctx context.Context, vectorDim int) error {
 
 	// Fetch existing vector indexes once — all specs share this snapshot,
 	// avoiding N round-trips and the TOCTOU window between specs.
-	indexes, indexErr := showVectorIndexes(ctx, session)
+	indexes, indexMetrics, indexDims, indexErr := showVectorIndexes(ctx, session)
 	showFailed := indexErr != nil
 	if showFailed {
 		g.store.logger.Warn("EnsureSchema: could not inspect existing vector indexes, will attempt creation",
@@ -392,6 +411,31 @@ func (g *GraphAdapter) EnsureSchema(ctx context.Context, vectorDim int) error {
 		indexes = make(map[string]string)
 	}
 
+	// An index built with another metric is not rebuilt automatically:
+	// rebuilding a large index is slow, so the operator drops it explicitly.
+	for _, spec := range vectorIndexes {
+		if metricErr := CheckVectorIndexMetric(spec.name, indexMetrics[spec.name], metric); metricErr != nil {
+			g.store.logger.Error("memgraph ensure schema: vector index metric mismatch", "error", metricErr)
+			return fmt.Errorf("memgraph ensure schema: %w", metricErr)
+		}
+	}
+
+	// A dimension change invalidates every stored vector, so the index is
+	// only rebuilt when the reembed command asks for it.
+	for _, spec := range vectorIndexes {
+		dimErr := CheckVectorIndexDimension(spec.name, indexDims[spec.name], vectorDim)
+		if dimErr == nil || indexes[spec.name] != spec.property {
+			continue
+		}
+		if !g.store.rebuildOnDimChange {
+			g.store.logger.Error("memgraph ensure schema: vector index dimension mismatch", "error", dimErr)
+			return fmt.Errorf("memgraph ensure schema: %w", dimErr)
+		}
+		if err := g.rebuildVectorIndex(ctx, session, spec.name, spec.ddl, vectorDim); err != nil {
+			return fmt.Errorf("memgraph ensure schema: %w", err)
+		}
+	}
+
 	// Verify (and if needed, rebuild) each vector index on the expected property.
 	for _, spec := range vectorIndexes {
 		if err := verifyOrRebuildVectorIndex(ctx, session, g.store.logger, indexes, showFailed, spec.name, spec.property, spec.ddl); err != nil {
@@ -935,33 +979,53 @@ func (g *GraphAdapter) RecallByGraph(ctx context.Context, query string, embeddin
 	return g.RecallByGraphWithDepth(ctx, query, embedding, limit, defaultGraphDepth)
 }
 
+// maxGraphRecallDepth caps the number of relationship hops followed by
+// RecallByGraphWithHops so a large --expand-entities value cannot fan out
+// across the whole graph.
+const maxGraphRecallDepth = 5
+
 // RecallByGraphWithDepth implements configurable-depth graph-aware recall.
+// It is RecallByGraphWithHops without the per-memory hop distances.
+func (g *GraphAdapter) RecallByGraphWithDepth(ctx context.Context, query string, embedding []float32, limit int, depth int) ([]string, error) {
+	ids, _, err := g.RecallByGraphWithHops(ctx, query, embedding, limit, depth)
+	return ids, err
+}
+
+// RecallByGraphWithHops implements configurable-depth graph-aware recall and
+// reports the hop distance at which each memory was first reached.
 //
 // Algorithm (Phase 4 – Graph-Aware Recall):
 //  1. Entity discovery: find Entity nodes whose name matches query terms via text search.
 //  2. 1-hop traversal: for each discovered entity, follow RELATES_TO edges (both directions)
 //     and collect source_memory_ids from adjacent fact relationships.
-//  3. 2-hop traversal (depth >= 2): for each neighbor entity found in step 2,
-//     repeat the 1-hop traversal to collect transitively connected memory IDs.
+//  3. N-hop traversal (depth >= 2): for each newly reached neighbor entity,
+//     repeat the 1-hop traversal to collect transitively connected memory IDs,
+//     up to depth hops (capped at maxGraphRecallDepth).
 //
-// Memories found at 1-hop score 1.0; 2-hop memories score 0.5.
-// Returns memory IDs sorted by graph distance score (descending).
-func (g *GraphAdapter) RecallByGraphWithDepth(ctx context.Context, query string, embedding []float32, limit int, depth int) ([]string, error) {
+// Memories found at hop h score 0.5^(h-1) (1-hop 1.0, 2-hop 0.5, 3-hop 0.25, ...).
+// Returns memory IDs sorted by graph distance score (descending). The hop map
+// is nil when the fact-text-search fallback was used.
+func (g *GraphAdapter) RecallByGraphWithHops(ctx context.Context, query string, embedding []float32, limit int, depth int) ([]string, map[string]int, error) {
 	if depth < 1 {
 		depth = 1
 	}
+	if depth > maxGraphRecallDepth {
+		depth = maxGraphRecallDepth
+	}
 
 	// Step 1: find entities matching the query (text search, up to 10 candidates).
 	entityCandidates, err := g.SearchEntities(ctx, query, embedding, "", 10)
 	if err != nil {
 		// Degrade gracefully: fall back to fact-text search if entity search fails.
 		g.store.logger.Warn("graph recall: entity search failed, falling back to fact text search", "error", err)
-		return g.recallByFactSearch(ctx, query, embedding, limit)
+		ids, factErr := g.recallByFactSearch(ctx, query, embedding, limit)
+		return ids, nil, factErr
 	}
 
 	if len(entityCandidates) == 0 {
 		// No entity hits — fall back to fact text search.
-		return g.recallByFactSearch(ctx, query, embedding, limit)
+		ids, factErr := g.recallByFactSearch(ctx, query, embedding, limit)
+		return ids, nil, factErr
 	}
 
 	// Collect entity IDs from candidates.
@@ -970,72 +1034,80 @@ func (g *GraphAdapter) RecallByGraphWithDepth(ctx context.Context, query string,
 		entityIDs = append(entityIDs, entityCandidates[i].ID)
 	}
 
-	type scoredID struct {
-		id    string
-		score float64
-	}
-	seen := make(map[string]float64)
-
 	// Step 2: 1-hop traversal — Entity → RELATES_TO → fact → source_memory_ids.
-	hop1MemIDs, hop1NeighbourIDs, err := g.traverseEntityFacts(ctx, entityIDs)
+	hop1MemIDs, frontier, err := g.traverseEntityFacts(ctx, entityIDs)
 	if err != nil {
 		g.store.logger.Warn("graph recall: 1-hop traversal failed", "error", err)
-		return g.recallByFactSearch(ctx, query, embedding, limit)
+		ids, factErr := g.recallByFactSearch(ctx, query, embedding, limit)
+		return ids, nil, factErr
 	}
 
-	// Score 1-hop memories at 1.0 (closest to query entities).
+	hops := make(map[string]int)
 	for _, mid := range hop1MemIDs {
-		if _, exists := seen[mid]; !exists {
-			seen[mid] = 1.0
+		if _, exists := hops[mid]; !exists {
+			hops[mid] = 1
 		}
 	}
 
-	// Step 3: 2-hop traversal — neighbor entities → their facts → memory IDs.
-	if depth >= 2 && len(hop1NeighbourIDs) > 0 {
-		// Exclude already-visited entity IDs to avoid cycles.
-		visitedEntityIDs := make(map[string]struct{}, len(entityIDs))
-		for _, eid := range entityIDs {
-			visitedEntityIDs[eid] = struct{}{}
-		}
-		var hop2EntityIDs []string
-		for _, nid := range hop1NeighbourIDs {
+	// Step 3: N-hop traversal — neighbor entities → their facts → memory IDs.
+	// Already-visited entity IDs are excluded to avoid cycles.
+	visitedEntityIDs := make(map[string]struct{}, len(entityIDs))
+	for _, eid := range entityIDs {
+		visitedEntityIDs[eid] = struct{}{}
+	}
+	for hop := 2; hop <= depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, nid := range frontier {
 			if _, visited := visitedEntityIDs[nid]; !visited {
-				hop2EntityIDs = append(hop2EntityIDs, nid)
+				next = append(next, nid)
 				visitedEntityIDs[nid] = struct{}{}
 			}
 		}
+		if len(next) == 0 {
+			break
+		}
 
-		if len(hop2EntityIDs) > 0 {
-			hop2MemIDs, _, hop2Err := g.traverseEntityFacts(ctx, hop2EntityIDs)
-			if hop2Err != nil {
-				// Non-fatal: 2-hop failure just means we return 1-hop results only.
-				g.store.logger.Warn("graph recall: 2-hop traversal failed", "error", hop2Err)
-			} else {
-				// Score 2-hop memories at 0.5 (half weight of direct neighbors).
-				for _, mid := range hop2MemIDs {
-					if _, exists := seen[mid]; !exists {
-						seen[mid] = 0.5
-					}
-				}
+		hopMemIDs, hopNeighbourIDs, hopErr := g.traverseEntityFacts(ctx, next)
+		if hopErr != nil {
+			// Non-fatal: a deeper traversal failure just means we return the
+			// memories reached so far.
+			g.store.logger.Warn("graph recall: traversal failed", "hop", hop, "error", hopErr)
+			break
+		}
+		for _, mid := range hopMemIDs {
+			if _, exists := hops[mid]; !exists {
+				hops[mid] = hop
 			}
 		}
+		frontier = hopNeighbourIDs
 	}
 
-	// Sort by graph distance score (descending), then apply limit.
-	ranked := make([]scoredID, 0, len(seen))
-	for id, score := range seen {
-		ranked = append(ranked, scoredID{id: id, score: score})
+	// Sort by hop distance (closest first), then apply limit.
+	type scoredID struct {
+		id  string
+		hop int
+	}
+	ranked := make([]scoredID, 0, len(hops))
+	for id, hop := range hops {
+		ranked = append(ranked, scoredID{id: id, hop: hop})
 	}
-	sort.Slice(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].hop != ranked[j].hop {
+			return ranked[i].hop < ranked[j].hop
+		}
+		return ranked[i].id < ranked[j].id
+	})
 
 	result := make([]string, 0, len(ranked))
+	resultHops := make(map[string]int, len(ranked))
 	for _, s := range ranked {
 		if limit > 0 && len(result) >= limit {
 			break
 		}
 		result = append(result, s.id)
+		resultHops[s.id] = s.hop
 	}
-	return result, nil
+	return result, resultHops, nil
 }
 
 // traverseEntityFacts fetches all active RELATES_TO facts for the given entity IDs
@@ -1225,7 +1297,7 @@ func (g *GraphAdapter) GetEpisodesForMemory(ctx context.Context, memoryID string
 }
 
 func (g *GraphAdapter) Healthy(ctx context.Context) bool {
-	err := g.store.driver.VerifyConnectivity(ctx)
+	err := g.store.Ping(ctx)
 	if err != nil {
 		g.store.logger.Warn("memgraph health check failed", "error", err)
 		return false
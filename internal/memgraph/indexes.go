@@ -0,0 +1,153 @@
+package memgraph
+
+import (
+	"context"
+	"fmt"
+)
+
+// PropertyIndex is a label-property index that EnsureSchema creates so
+// filters on that property avoid a full label scan.
+type PropertyIndex struct {
+	Label    string
+	Property string
+}
+
+// String returns the index in Cypher notation, e.g. ":Memory(type)".
+func (p PropertyIndex) String() string {
+	return fmt.Sprintf(":%s(%s)", p.Label, p.Property)
+}
+
+// ddl returns the CREATE INDEX statement for p. Label and Property come from
+// the PropertyIndexes list, never from user input.
+func (p PropertyIndex) ddl() string {
+	return "CREATE INDEX ON " + p.String()
+}
+
+// PropertyIndexes lists every label-property index the schema expects. Add a
+// property here when it becomes filterable; existing databases pick it up on
+// the next EnsureCollection or with "openclaw-cortex reindex-fields".
+var PropertyIndexes = []PropertyIndex{
+	// Filtering
+	{"Memory", "type"},
+	{"Memory", "scope"},
+	{"Memory", "project"},
+	{"Memory", "source"},
+	{"Memory", "uuid"},
+	{"Memory", "preference_subject"},
+	// Temporal versioning
+	{"Memory", "valid_from"},
+	{"Memory", "valid_to"},
+	{"Entity", "uuid"},
+	{"Entity", "project"},
+}
+
+// ParsePropertyIndexRows converts raw SHOW INDEX INFO rows (maps with
+// "index type", "label" and "property" keys) into the set of existing
+// single-property label indexes. The property column may be a string or, on
+// newer Memgraph versions, a list of property names.
+// Exported so tests/ can exercise the parsing logic without a live session.
+func ParsePropertyIndexRows(rows []map[string]any) map[PropertyIndex]bool {
+	existing := make(map[PropertyIndex]bool)
+	for _, row := range rows {
+		if kind, _ := row["index type"].(string); kind != "label+property" {
+			continue
+		}
+		label, _ := row["label"].(string)
+		var prop string
+		switch v := row["property"].(type) {
+		case string:
+			prop = v
+		case []any:
+			if len(v) == 1 {
+				prop, _ = v[0].(string)
+			}
+		}
+		if label != "" && prop != "" {
+			existing[PropertyIndex{Label: label, Property: prop}] = true
+		}
+	}
+	return existing
+}
+
+// ParseIndexCount returns the entry count SHOW INDEX INFO reports for idx.
+// Memgraph maintains it alongside the index, so reading it is O(1), but it
+// may include entries not yet garbage-collected.
+func ParseIndexCount(rows []map[string]any, idx PropertyIndex) (int64, bool) {
+	for _, row := range rows {
+		if existing := ParsePropertyIndexRows([]map[string]any{row}); !existing[idx] {
+			continue
+		}
+		if n, ok := row["count"].(int64); ok {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// showIndexInfo returns the rows of SHOW INDEX INFO with their "index type",
+// "label", "property" and "count" columns.
+func (s *MemgraphStore) showIndexInfo(ctx context.Context) ([]map[string]any, error) {
+	session := s.driver.NewSession(ctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	result, err := session.Run(ctx, "SHOW INDEX INFO", nil)
+	if err != nil {
+		return nil, fmt.Errorf("show index info: %w", err)
+	}
+	var rows []map[string]any
+	for result.Next(ctx) {
+		record := result.Record()
+		row := make(map[string]any, 4)
+		for _, key := range []string{"index type", "label", "property", "count"} {
+			if v, ok := record.Get(key); ok {
+				row[key] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("show index info: iterating results: %w", err)
+	}
+	if _, err := result.Consume(ctx); err != nil {
+		return nil, fmt.Errorf("show index info: consuming result: %w", err)
+	}
+	return rows, nil
+}
+
+// MissingPropertyIndexes returns the entries of PropertyIndexes that do not
+// exist in the database yet.
+func (s *MemgraphStore) MissingPropertyIndexes(ctx context.Context) ([]PropertyIndex, error) {
+	rows, err := s.showIndexInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := ParsePropertyIndexRows(rows)
+	var missing []PropertyIndex
+	for _, idx := range PropertyIndexes {
+		if !existing[idx] {
+			missing = append(missing, idx)
+		}
+	}
+	return missing, nil
+}
+
+// CreatePropertyIndex creates idx. An index that already exists is not an
+// error.
+func (s *MemgraphStore) CreatePropertyIndex(ctx context.Context, idx PropertyIndex) error {
+	session := s.driver.NewSession(ctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	// Memgraph requires auto-commit (implicit) transactions for DDL.
+	result, err := session.Run(ctx, idx.ddl(), nil)
+	if err != nil {
+		if IsAlreadyExistsErr(err) {
+			return nil
+		}
+		return fmt.Errorf("creating index %s: %w", idx, err)
+	}
+	if _, err := result.Consume(ctx); err != nil && !IsAlreadyExistsErr(err) {
+		return fmt.Errorf("creating index %s: %w", idx, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package memgraph
+
+import "fmt"
+
+// VectorMetric is the distance function of the Memgraph vector indexes,
+// selected with memgraph.vector_metric.
+type VectorMetric string
+
+const (
+	// MetricCosine ranks by cosine similarity (the default).
+	MetricCosine VectorMetric = "cosine"
+	// MetricDot ranks by inner product, for models tuned for dot-product
+	// retrieval.
+	MetricDot VectorMetric = "dot"
+	// MetricEuclid ranks by squared Euclidean distance.
+	MetricEuclid VectorMetric = "euclid"
+)
+
+// indexMetric returns the metric name used in CREATE VECTOR INDEX and
+// reported by SHOW VECTOR INDEXES. The empty metric means MetricCosine.
+func (m VectorMetric) indexMetric() string {
+	switch m {
+	case MetricDot:
+		return "ip"
+	case MetricEuclid:
+		return "l2sq"
+	default:
+		return "cos"
+	}
+}
+
+// BuildVectorScoreClause returns the YIELD ... WITH clause that follows
+// CALL vector_search.search and binds the node and a score on the cosine
+// scale, so dedup thresholds and recall weights keep their meaning whichever
+// metric the index uses. For unit-length embeddings the dot and euclid scores
+// equal the cosine similarity: 1 - distance for inner product, and
+// 1 - distance/2 for squared Euclidean distance.
+// Exported so tests/ can check the clause without a live session.
+func BuildVectorScoreClause(m VectorMetric) string {
+	switch m {
+	case MetricDot:
+		return "YIELD node, distance\n\t\tWITH node, 1.0 - distance AS score"
+	case MetricEuclid:
+		return "YIELD node, distance\n\t\tWITH node, 1.0 - distance / 2.0 AS score"
+	default:
+		return "YIELD node, similarity\n\t\tWITH node, similarity AS score"
+	}
+}
+
+// CheckVectorIndexMetric returns an error when an existing vector index was
+// built with a different metric than the configured one. Changing
+// memgraph.vector_metric does not rebuild existing indexes, so searches would
+// silently keep the old metric while scores are interpreted with the new one.
+// An empty existing metric (older Memgraph versions do not report it) is
+// accepted.
+func CheckVectorIndexMetric(indexName, existing string, want VectorMetric) error {
+	if existing == "" || existing == want.indexMetric() {
+		return nil
+	}
+	return fmt.Errorf("vector index %q uses metric %q but memgraph.vector_metric is %q (%s); "+
+		"run `DROP VECTOR INDEX %s;` in Memgraph to rebuild it with the new metric on the next start, "+
+		"or set memgraph.vector_metric back", indexName, existing, want, want.indexMetric(), indexName)
+}
@@ -0,0 +1,75 @@
+package memgraph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// CountProject returns how many memories (including trashed ones) and
+// entities belong to project.
+func (s *MemgraphStore) CountProject(ctx context.Context, project string) (memories, entities int64, err error) {
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	raw, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return projectCounts(rctx, tx, `
+			MATCH (m:Memory {project: $project}) RETURN count(m) AS n
+		`, `
+			MATCH (e:Entity {project: $project}) RETURN count(e) AS n
+		`, map[string]any{"project": project})
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("memgraph count project %q: %w", project, err)
+	}
+	counts, _ := raw.([2]int64)
+	return counts[0], counts[1], nil
+}
+
+// MoveProject reassigns every memory (including trashed ones) and entity of
+// project from to project to in one transaction. Only the project property
+// changes: vectors, timestamps and relationships are left as they are.
+func (s *MemgraphStore) MoveProject(ctx context.Context, from, to string) (memories, entities int64, err error) {
+	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(wctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	raw, err := session.ExecuteWrite(wctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return projectCounts(wctx, tx, `
+			MATCH (m:Memory {project: $from}) SET m.project = $to RETURN count(m) AS n
+		`, `
+			MATCH (e:Entity {project: $from}) SET e.project = $to RETURN count(e) AS n
+		`, map[string]any{"from": from, "to": to})
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("memgraph move project %q to %q: %w", from, to, err)
+	}
+	counts, _ := raw.([2]int64)
+	return counts[0], counts[1], nil
+}
+
+// projectCounts runs the memory and entity queries in tx; each must return a
+// single count column "n".
+func projectCounts(ctx context.Context, tx neo4j.ManagedTransaction, memoryQuery, entityQuery string, params map[string]any) (any, error) {
+	var counts [2]int64
+	for i, query := range []string{memoryQuery, entityQuery} {
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := rec.Get("n"); ok {
+			counts[i] = toInt64(v)
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,122 @@
+package memgraph
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// SampleStats is a cheaper Stats for very large collections. The total comes
+// from the :Memory(uuid) index count, which Memgraph keeps without scanning
+// (it may still include soft-deleted memories), and the per-type and
+// per-scope breakdowns are extrapolated from the first sampleSize live
+// memories. Health metrics are skipped because they need a full scan. The
+// result has Approximate set; callers must present the counts as estimates.
+func (s *MemgraphStore) SampleStats(ctx context.Context, sampleSize int) (*models.CollectionStats, error) {
+	if sampleSize <= 0 {
+		return nil, fmt.Errorf("memgraph sample stats: sample size must be positive, got %d", sampleSize)
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	total, ok := int64(0), false
+	if rows, err := s.showIndexInfo(rctx); err != nil {
+		s.logger.Warn("memgraph sample stats: reading index counts", "error", err)
+	} else {
+		total, ok = ParseIndexCount(rows, PropertyIndex{Label: "Memory", Property: "uuid"})
+	}
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	if !ok {
+		// No index count to read; fall back to the exact count.
+		cnt, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			res, txErr := tx.Run(rctx, `MATCH (m:Memory) WHERE m.deleted_at IS NULL RETURN count(m) AS total`, nil)
+			if txErr != nil {
+				return int64(0), txErr
+			}
+			if res.Next(rctx) {
+				if v, found := res.Record().Get("total"); found {
+					return toInt64(v), nil
+				}
+			}
+			return int64(0), res.Err()
+		})
+		if err != nil {
+			return nil, fmt.Errorf("memgraph sample stats total count: %w", err)
+		}
+		total, _ = cnt.(int64)
+	}
+
+	type sample struct {
+		byType  map[string]int64
+		byScope map[string]int64
+		n       int
+	}
+	sampled, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx,
+			`MATCH (m:Memory) WHERE m.deleted_at IS NULL RETURN m.type AS type, m.scope AS scope LIMIT $n`,
+			map[string]any{"n": sampleSize})
+		if txErr != nil {
+			return nil, txErr
+		}
+		out := sample{byType: make(map[string]int64), byScope: make(map[string]int64)}
+		for res.Next(rctx) {
+			record := res.Record()
+			if v, found := record.Get("type"); found {
+				if t, isStr := v.(string); isStr {
+					out.byType[t]++
+				}
+			}
+			if v, found := record.Get("scope"); found {
+				if sc, isStr := v.(string); isStr {
+					out.byScope[sc]++
+				}
+			}
+			out.n++
+		}
+		return out, res.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memgraph sample stats: sampling memories: %w", err)
+	}
+	smp, _ := sampled.(sample)
+
+	// A short sample covered every live memory, so its size is the exact
+	// total (the index count may include soft-deleted memories).
+	if smp.n < sampleSize || int64(smp.n) > total {
+		total = int64(smp.n)
+	}
+
+	stats := &models.CollectionStats{
+		TotalMemories: total,
+		ByType:        ScaleSample(smp.byType, smp.n, total),
+		ByScope:       ScaleSample(smp.byScope, smp.n, total),
+		Approximate:   true,
+		SampleSize:    smp.n,
+	}
+	stats.StorageEstimate = stats.TotalMemories * 768 * 4
+	return stats, nil
+}
+
+// ScaleSample extrapolates counts observed in a sample of sampled memories to
+// a collection of total memories, rounding each bucket to the nearest whole
+// memory. An empty sample yields an empty map.
+// Exported so tests/ can check the arithmetic without a live session.
+func ScaleSample(counts map[string]int64, sampled int, total int64) map[string]int64 {
+	scaled := make(map[string]int64, len(counts))
+	if sampled <= 0 {
+		return scaled
+	}
+	ratio := float64(total) / float64(sampled)
+	for k, c := range counts {
+		scaled[k] = int64(math.Round(float64(c) * ratio))
+	}
+	return scaled
+}
@@ -6,10 +6,10 @@ package memgraph
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +18,7 @@ import (
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
 // Compile-time assertions that MemgraphStore fully implements store.Store and store.ResettableStore.
@@ -40,6 +41,9 @@ type MemgraphStore struct {
 	logger                *slog.Logger
 	contradictionDetector store.ContradictionDetector
 	vectorDim             int
+	embeddingModel        string
+	vectorMetric          VectorMetric
+	rebuildOnDimChange    bool // EnsureCollection rebuilds a vector index whose dimension changed
 }
 
 // SetContradictionDetector attaches a contradiction detector to the store.
@@ -49,6 +53,27 @@ func (s *MemgraphStore) SetContradictionDetector(d store.ContradictionDetector)
 	s.contradictionDetector = d
 }
 
+// SetEmbeddingModel sets the model identifier recorded alongside every vector
+// written by Upsert. When empty, the memory's own EmbeddingModel is kept.
+func (s *MemgraphStore) SetEmbeddingModel(model string) {
+	s.embeddingModel = model
+}
+
+// SetVectorMetric selects the distance function the vector indexes are
+// created with and searched by. The empty metric means MetricCosine. Must be
+// called before EnsureCollection.
+func (s *MemgraphStore) SetVectorMetric(m VectorMetric) {
+	s.vectorMetric = m
+}
+
+// metric returns the configured vector metric, defaulting to cosine.
+func (s *MemgraphStore) metric() VectorMetric {
+	if s.vectorMetric == "" {
+		return MetricCosine
+	}
+	return s.vectorMetric
+}
+
 // New creates a new MemgraphStore and verifies connectivity.
 func New(ctx context.Context, uri, username, password, database string, vectorDim int, logger *slog.Logger) (*MemgraphStore, error) {
 	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""))
@@ -95,9 +120,45 @@ func (s *MemgraphStore) EnsureCollection(ctx context.Context) error {
 	return ga.EnsureSchema(ctx, s.vectorDim)
 }
 
-// Upsert inserts or updates a memory node with its embedding vector.
-// When memory.SupersedesID is set, the superseded memory is invalidated (valid_to = now).
-func (s *MemgraphStore) Upsert(ctx context.Context, memory models.Memory, vector []float32) error {
+// memoryUpsertCypher merges one Memory node from the parameter map bound to
+// p (see memoryToParams). Upsert binds p with WITH, UpsertBatch with UNWIND.
+const memoryUpsertCypher = `
+			MERGE (m:Memory {uuid: p.uuid})
+			SET m.type             = p.type,
+			    m.scope            = p.scope,
+			    m.visibility       = p.visibility,
+			    m.content          = p.content,
+			    m.confidence       = p.confidence,
+			    m.source           = p.source,
+			    m.project          = p.project,
+			    m.ttl_seconds      = p.ttl_seconds,
+			    m.tags             = p.tags,
+			    m.metadata         = p.metadata,
+			    m.preference_subject = p.preference_subject,
+			    m.created_at       = p.created_at,
+			    m.updated_at       = p.updated_at,
+			    m.last_accessed    = p.last_accessed,
+			    m.access_count     = p.access_count,
+			    m.supersedes_id    = p.supersedes_id,
+			    m.conflict_group_id = p.conflict_group_id,
+			    m.conflict_status  = p.conflict_status,
+			    m.valid_until_unix = p.valid_until_unix,
+			    m.expires_at_unix  = p.expires_at_unix,
+			    m.valid_from       = p.valid_from,
+			    m.valid_to         = p.valid_to,
+			    m.reinforced_at_unix = p.reinforced_at_unix,
+			    m.reinforced_count = p.reinforced_count,
+			    m.boost            = p.boost,
+			    m.attachments      = p.attachments,
+			    m.user_id          = p.user_id,
+			    m.embedding        = CASE WHEN p.has_embedding THEN p.embedding ELSE m.embedding END,
+			    m.embedding_model  = CASE WHEN p.has_embedding THEN p.embedding_model ELSE m.embedding_model END
+`
+
+// prepareUpsert applies the write-time defaults shared by Upsert and
+// UpsertBatch and returns the memory's parameter map. When memory.SupersedesID
+// is set, the superseded memory is invalidated (valid_to = now).
+func (s *MemgraphStore) prepareUpsert(ctx context.Context, memory models.Memory, vector []float32) map[string]any {
 	// If superseding another memory, invalidate it first (non-fatal).
 	if memory.SupersedesID != "" {
 		now := time.Now().UTC()
@@ -112,45 +173,32 @@ func (s *MemgraphStore) Upsert(ctx context.Context, memory models.Memory, vector
 		memory.ValidFrom = time.Now().UTC()
 	}
 
+	if vector != nil && s.embeddingModel != "" {
+		memory.EmbeddingModel = s.embeddingModel
+	}
+	return memoryToParams(memory, vector)
+}
+
+// Upsert inserts or updates a memory node with its embedding vector.
+// When memory.SupersedesID is set, the superseded memory is invalidated (valid_to = now).
+func (s *MemgraphStore) Upsert(ctx context.Context, memory models.Memory, vector []float32) error {
+	ctx, span := tracing.Start(ctx, "store.upsert", "db.system", "memgraph", "memory.type", string(memory.Type))
+	defer span.End()
+
+	params := s.prepareUpsert(ctx, memory, vector)
+
 	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
 	defer cancel()
 
 	session := s.driver.NewSession(wctx, s.sessionConfig())
 	defer s.closeSession(ctx, session)
 
-	params := memoryToParams(memory, vector)
-
 	_, err := session.ExecuteWrite(wctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		_, txErr := tx.Run(wctx, `
-			MERGE (m:Memory {uuid: $uuid})
-			SET m.type             = $type,
-			    m.scope            = $scope,
-			    m.visibility       = $visibility,
-			    m.content          = $content,
-			    m.confidence       = $confidence,
-			    m.source           = $source,
-			    m.project          = $project,
-			    m.ttl_seconds      = $ttl_seconds,
-			    m.tags             = $tags,
-			    m.metadata         = $metadata,
-			    m.created_at       = $created_at,
-			    m.updated_at       = $updated_at,
-			    m.last_accessed    = $last_accessed,
-			    m.access_count     = $access_count,
-			    m.supersedes_id    = $supersedes_id,
-			    m.conflict_group_id = $conflict_group_id,
-			    m.conflict_status  = $conflict_status,
-			    m.valid_until_unix = $valid_until_unix,
-			    m.valid_from       = $valid_from,
-			    m.valid_to         = $valid_to,
-			    m.reinforced_at_unix = $reinforced_at_unix,
-			    m.reinforced_count = $reinforced_count,
-			    m.user_id          = $user_id,
-			    m.embedding        = CASE WHEN $has_embedding THEN $embedding ELSE m.embedding END
-		`, params)
+		_, txErr := tx.Run(wctx, "WITH $p AS p"+memoryUpsertCypher, map[string]any{"p": params})
 		return nil, txErr
 	})
 	if err != nil {
+		span.RecordError(err)
 		return fmt.Errorf("memgraph upsert %s: %w", memory.ID, err)
 	}
 
@@ -158,8 +206,130 @@ func (s *MemgraphStore) Upsert(ctx context.Context, memory models.Memory, vector
 	return nil
 }
 
-// Search finds memories similar to the query vector using Memgraph's vector search.
+// UpsertBatch writes all memories in one UNWIND query and one transaction,
+// so either every memory is stored or none is.
+func (s *MemgraphStore) UpsertBatch(ctx context.Context, memories []models.Memory, vectors [][]float32) error {
+	if len(memories) != len(vectors) {
+		return fmt.Errorf("memgraph upsert batch: %d memories but %d vectors", len(memories), len(vectors))
+	}
+	if len(memories) == 0 {
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "store.upsert_batch", "db.system", "memgraph", "batch.size", len(memories))
+	defer span.End()
+
+	rows := make([]any, len(memories))
+	for i := range memories {
+		rows[i] = s.prepareUpsert(ctx, memories[i], vectors[i])
+	}
+
+	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(wctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	_, err := session.ExecuteWrite(wctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		_, txErr := tx.Run(wctx, "UNWIND $rows AS p"+memoryUpsertCypher, map[string]any{"rows": rows})
+		return nil, txErr
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("memgraph upsert batch of %d: %w", len(memories), err)
+	}
+
+	s.logger.Debug("upserted memory batch", "count", len(memories))
+	return nil
+}
+
+// Search finds memories similar to the query vector using Memgraph's vector
+// search. With filters.Keyword set it also runs a full-text search over the
+// memory_text index and fuses both rankings with store.FuseRRF.
 func (s *MemgraphStore) Search(ctx context.Context, vector []float32, limit uint64, filters *store.SearchFilters) ([]models.SearchResult, error) {
+	if filters == nil || filters.Keyword == "" {
+		return s.vectorSearch(ctx, vector, limit, filters)
+	}
+
+	ctx, span := tracing.Start(ctx, "store.hybrid_search", "db.system", "memgraph", "search.limit", limit)
+	defer span.End()
+
+	candidates := limit * store.HybridOverfetch
+	vecResults, err := s.vectorSearch(ctx, vector, candidates, filters)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	kwResults, err := s.keywordSearch(ctx, filters.Keyword, candidates, filters)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	fused := store.FuseRRF(vecResults, kwResults, limit)
+	span.SetAttributes("result.count", len(fused))
+	return fused, nil
+}
+
+// keywordSearch ranks memories by a full-text match of keyword against the
+// memory_text index, honoring the same filters as vector search.
+func (s *MemgraphStore) keywordSearch(ctx context.Context, keyword string, limit uint64, filters *store.SearchFilters) ([]models.SearchResult, error) {
+	query := SanitizeTextSearchQuery(keyword)
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	whereClauses, filterParams := buildWhereClause(filters, "node")
+	whereStr := ""
+	if len(whereClauses) > 0 {
+		whereStr = "WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	cypher := fmt.Sprintf(`
+		CALL text_search.search_all("memory_text", $query)
+		YIELD node, score
+		WITH node, score
+		%s
+		RETURN node, score
+		ORDER BY score DESC
+		LIMIT $limit
+	`, whereStr)
+
+	params := map[string]any{
+		"query": query,
+		"limit": int64(limit),
+	}
+	for k, v := range filterParams {
+		params[k] = v
+	}
+
+	results, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx, cypher, params)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return collectSearchResults(rctx, res)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memgraph keyword search: %w", err)
+	}
+
+	sr, ok := results.([]models.SearchResult)
+	if !ok {
+		return nil, fmt.Errorf("memgraph keyword search: unexpected result type %T", results)
+	}
+	return sr, nil
+}
+
+// vectorSearch is Search without keyword fusion.
+func (s *MemgraphStore) vectorSearch(ctx context.Context, vector []float32, limit uint64, filters *store.SearchFilters) ([]models.SearchResult, error) {
+	ctx, span := tracing.Start(ctx, "store.search", "db.system", "memgraph", "search.limit", limit)
+	defer span.End()
+
 	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
 	defer cancel()
 
@@ -175,11 +345,10 @@ func (s *MemgraphStore) Search(ctx context.Context, vector []float32, limit uint
 	// Memgraph requires WITH between YIELD and WHERE — cannot use WHERE directly after YIELD.
 	query := fmt.Sprintf(`
 		CALL vector_search.search("memory_embedding", $limit, $query_vector)
-		YIELD node, similarity
-		WITH node, similarity AS score
+		%s
 		%s
 		RETURN node, score
-	`, whereStr)
+	`, BuildVectorScoreClause(s.metric()), whereStr)
 
 	params := map[string]any{
 		"limit":        int64(limit),
@@ -197,6 +366,7 @@ func (s *MemgraphStore) Search(ctx context.Context, vector []float32, limit uint
 		return collectSearchResults(rctx, res)
 	})
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("memgraph search: %w", err)
 	}
 
@@ -204,11 +374,17 @@ func (s *MemgraphStore) Search(ctx context.Context, vector []float32, limit uint
 	if !ok {
 		return nil, fmt.Errorf("memgraph search: unexpected result type %T", results)
 	}
+	// The vector index returns results in index order; make ties deterministic.
+	store.SortSearchResults(sr)
+	span.SetAttributes("result.count", len(sr))
 	return sr, nil
 }
 
 // Get retrieves a single memory by ID.
 func (s *MemgraphStore) Get(ctx context.Context, id string) (*models.Memory, error) {
+	ctx, span := tracing.Start(ctx, "store.get", "db.system", "memgraph")
+	defer span.End()
+
 	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
 	defer cancel()
 
@@ -216,7 +392,7 @@ func (s *MemgraphStore) Get(ctx context.Context, id string) (*models.Memory, err
 	defer s.closeSession(ctx, session)
 
 	result, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		res, txErr := tx.Run(rctx, `MATCH (m:Memory {uuid: $id}) RETURN m`, map[string]any{"id": id})
+		res, txErr := tx.Run(rctx, `MATCH (m:Memory {uuid: $id}) WHERE m.deleted_at IS NULL RETURN m`, map[string]any{"id": id})
 		if txErr != nil {
 			return nil, txErr
 		}
@@ -230,6 +406,7 @@ func (s *MemgraphStore) Get(ctx context.Context, id string) (*models.Memory, err
 		return nil, nil
 	})
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("memgraph get %s: %w", id, err)
 	}
 
@@ -244,6 +421,103 @@ func (s *MemgraphStore) Get(ctx context.Context, id string) (*models.Memory, err
 	return mem, nil
 }
 
+// GetMany retrieves several memories in one query, keyed by ID. Trashed and
+// unknown IDs are omitted from the result.
+func (s *MemgraphStore) GetMany(ctx context.Context, ids []string) (map[string]models.Memory, error) {
+	ctx, span := tracing.Start(ctx, "store.get_many", "db.system", "memgraph", "memory.ids", len(ids))
+	defer span.End()
+
+	if len(ids) == 0 {
+		return map[string]models.Memory{}, nil
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	byID := make(map[string]models.Memory, len(ids))
+	_, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx, `
+			MATCH (m:Memory)
+			WHERE m.uuid IN $ids AND m.deleted_at IS NULL
+			RETURN m
+		`, map[string]any{"ids": ids})
+		if txErr != nil {
+			return nil, txErr
+		}
+		for res.Next(rctx) {
+			mem, convErr := recordToMemory(res.Record(), "m")
+			if convErr != nil {
+				return nil, convErr
+			}
+			byID[mem.ID] = *mem
+		}
+		return nil, res.Err()
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("memgraph get many: %w", err)
+	}
+
+	span.SetAttributes("result.count", len(byID))
+	return byID, nil
+}
+
+// GetVector returns the stored embedding of a single memory.
+func (s *MemgraphStore) GetVector(ctx context.Context, id string) ([]float32, error) {
+	vecs, err := s.GetVectors(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	vec, ok := vecs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", store.ErrNotFound, id)
+	}
+	return vec, nil
+}
+
+// GetVectors returns the stored embeddings of the given memories in one query.
+// Trashed and unknown IDs are omitted from the result.
+func (s *MemgraphStore) GetVectors(ctx context.Context, ids []string) (map[string][]float32, error) {
+	ctx, span := tracing.Start(ctx, "store.get_vectors", "db.system", "memgraph", "vector.ids", len(ids))
+	defer span.End()
+
+	out := make(map[string][]float32, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	_, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx, `
+			MATCH (m:Memory)
+			WHERE m.uuid IN $ids AND m.deleted_at IS NULL
+			RETURN m.uuid AS id, coalesce(m.embedding, []) AS embedding
+		`, map[string]any{"ids": ids})
+		if txErr != nil {
+			return nil, txErr
+		}
+		for res.Next(rctx) {
+			record := res.Record()
+			id, _, _ := neo4j.GetRecordValue[string](record, "id")
+			out[id] = getFloat32Slice(record, "embedding")
+		}
+		return nil, res.Err()
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("memgraph get vectors: %w", err)
+	}
+	return out, nil
+}
+
 // Delete removes a memory by ID. Returns store.ErrNotFound if nothing was deleted.
 // If id is shorter than 36 characters (a full UUID), prefix matching is used instead
 // of exact matching. If the prefix matches more than one memory, an error is returned.
@@ -331,9 +605,142 @@ func (s *MemgraphStore) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// resolveMemoryID maps id (a full UUID or an unambiguous prefix) to the UUID
+// of a memory whose trash state matches trashed.
+func (s *MemgraphStore) resolveMemoryID(ctx context.Context, session neo4j.SessionWithContext, id string, trashed bool) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("%w: %s", store.ErrNotFound, id)
+	}
+	trashCond := "m.deleted_at IS NULL"
+	if trashed {
+		trashCond = "m.deleted_at IS NOT NULL"
+	}
+	query := fmt.Sprintf(`
+		MATCH (m:Memory)
+		WHERE (m.uuid = $id OR m.uuid STARTS WITH $id) AND %s
+		RETURN m.uuid AS uuid, m.uuid = $id AS exact
+		LIMIT 2
+	`, trashCond)
+
+	raw, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(ctx, query, map[string]any{"id": id})
+		if txErr != nil {
+			return nil, txErr
+		}
+		var ids []string
+		for res.Next(ctx) {
+			rec := res.Record()
+			uuid, _ := rec.Get("uuid")
+			if exact, _ := rec.Get("exact"); exact == true {
+				return []string{fmt.Sprint(uuid)}, nil
+			}
+			ids = append(ids, fmt.Sprint(uuid))
+		}
+		return ids, res.Err()
+	})
+	if err != nil {
+		return "", fmt.Errorf("memgraph resolve id %s: %w", id, err)
+	}
+	ids, _ := raw.([]string)
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", store.ErrNotFound, id)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous prefix: matches %d memories", len(ids))
+	}
+}
+
+// setDeletedAt resolves id among memories in the given trash state and sets
+// (or, when deletedAt is empty, removes) its deleted_at tombstone.
+func (s *MemgraphStore) setDeletedAt(ctx context.Context, id string, trashed bool, deletedAt string) (string, error) {
+	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(wctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	fullID, err := s.resolveMemoryID(wctx, session, id, trashed)
+	if err != nil {
+		return "", err
+	}
+
+	query := `MATCH (m:Memory {uuid: $id}) SET m.deleted_at = $deleted_at`
+	if deletedAt == "" {
+		query = `MATCH (m:Memory {uuid: $id}) REMOVE m.deleted_at`
+	}
+	_, err = session.ExecuteWrite(wctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		_, txErr := tx.Run(wctx, query, map[string]any{"id": fullID, "deleted_at": deletedAt})
+		return nil, txErr
+	})
+	if err != nil {
+		return "", fmt.Errorf("memgraph set deleted_at %s: %w", fullID, err)
+	}
+	return fullID, nil
+}
+
+// Trash soft-deletes a live memory by setting its deleted_at tombstone.
+func (s *MemgraphStore) Trash(ctx context.Context, id string) (string, error) {
+	fullID, err := s.setDeletedAt(ctx, id, false, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return "", fmt.Errorf("memgraph trash: %w", err)
+	}
+	s.logger.Debug("trashed memory", "id", fullID)
+	return fullID, nil
+}
+
+// Restore clears the deleted_at tombstone on a trashed memory.
+func (s *MemgraphStore) Restore(ctx context.Context, id string) (string, error) {
+	fullID, err := s.setDeletedAt(ctx, id, true, "")
+	if err != nil {
+		return "", fmt.Errorf("memgraph restore: %w", err)
+	}
+	s.logger.Debug("restored memory", "id", fullID)
+	return fullID, nil
+}
+
+// ListTrashed returns trashed memories deleted before the given time, oldest first.
+func (s *MemgraphStore) ListTrashed(ctx context.Context, before time.Time, limit uint64) ([]models.Memory, error) {
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	raw, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx, `
+			MATCH (m:Memory)
+			WHERE m.deleted_at IS NOT NULL AND m.deleted_at < $before
+			RETURN m
+			ORDER BY m.deleted_at ASC
+			LIMIT $limit
+		`, map[string]any{
+			"before": before.UTC().Format(time.RFC3339Nano),
+			"limit":  int64(limit),
+		})
+		if txErr != nil {
+			return nil, txErr
+		}
+		return collectMemories(rctx, res, "m")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memgraph list trashed: %w", err)
+	}
+
+	memories, ok := raw.([]models.Memory)
+	if !ok {
+		return nil, fmt.Errorf("memgraph list trashed: unexpected result type %T", raw)
+	}
+	return memories, nil
+}
+
 // List returns memories matching the given filters with cursor-based pagination.
 // The cursor is the SKIP offset encoded as a decimal string; "" means page 0.
 func (s *MemgraphStore) List(ctx context.Context, filters *store.SearchFilters, limit uint64, cursor string) ([]models.Memory, string, error) {
+	ctx, span := tracing.Start(ctx, "store.list", "db.system", "memgraph", "list.limit", limit)
+	defer span.End()
+
 	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
 	defer cancel()
 
@@ -379,6 +786,7 @@ func (s *MemgraphStore) List(ctx context.Context, filters *store.SearchFilters,
 		return collectMemories(rctx, res, "m")
 	})
 	if err != nil {
+		span.RecordError(err)
 		return nil, "", fmt.Errorf("memgraph list: %w", err)
 	}
 
@@ -386,6 +794,7 @@ func (s *MemgraphStore) List(ctx context.Context, filters *store.SearchFilters,
 	if !ok {
 		return nil, "", fmt.Errorf("memgraph list: unexpected result type %T", raw)
 	}
+	span.SetAttributes("result.count", len(memories))
 
 	var nextCursor string
 	if uint64(len(memories)) == limit {
@@ -398,6 +807,9 @@ func (s *MemgraphStore) List(ctx context.Context, filters *store.SearchFilters,
 // FindDuplicates returns memories whose vector similarity to the given vector
 // is at or above the threshold.
 func (s *MemgraphStore) FindDuplicates(ctx context.Context, vector []float32, threshold float64) ([]models.SearchResult, error) {
+	ctx, span := tracing.Start(ctx, "store.find_duplicates", "db.system", "memgraph", "dedup.threshold", threshold)
+	defer span.End()
+
 	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
 	defer cancel()
 
@@ -407,9 +819,8 @@ func (s *MemgraphStore) FindDuplicates(ctx context.Context, vector []float32, th
 	results, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
 		res, txErr := tx.Run(rctx, `
 			CALL vector_search.search("memory_embedding", 10, $vector)
-			YIELD node, similarity
-			WITH node, similarity AS score
-			WHERE score >= $threshold
+			`+BuildVectorScoreClause(s.metric())+`
+			WHERE score >= $threshold AND node.deleted_at IS NULL
 			RETURN node, score
 		`, map[string]any{
 			"vector":    float32SliceToAny(vector),
@@ -421,6 +832,7 @@ func (s *MemgraphStore) FindDuplicates(ctx context.Context, vector []float32, th
 		return collectSearchResults(rctx, res)
 	})
 	if err != nil {
+		span.RecordError(err)
 		return nil, fmt.Errorf("memgraph find duplicates: %w", err)
 	}
 
@@ -428,10 +840,13 @@ func (s *MemgraphStore) FindDuplicates(ctx context.Context, vector []float32, th
 	if !ok {
 		return nil, fmt.Errorf("memgraph find duplicates: unexpected result type %T", results)
 	}
+	span.SetAttributes("result.count", len(sr))
 	return sr, nil
 }
 
 // UpdateAccessMetadata increments access count and updates last_accessed time.
+// The increment happens inside the query, so concurrent recalls never lose a
+// count; a node written without access_count starts from zero.
 func (s *MemgraphStore) UpdateAccessMetadata(ctx context.Context, id string) error {
 	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
 	defer cancel()
@@ -444,7 +859,7 @@ func (s *MemgraphStore) UpdateAccessMetadata(ctx context.Context, id string) err
 	_, err := session.ExecuteWrite(wctx, func(tx neo4j.ManagedTransaction) (any, error) {
 		_, txErr := tx.Run(wctx, `
 			MATCH (m:Memory {uuid: $id})
-			SET m.access_count  = m.access_count + 1,
+			SET m.access_count  = coalesce(m.access_count, 0) + 1,
 			    m.last_accessed = $now
 		`, map[string]any{"id": id, "now": now})
 		return nil, txErr
@@ -456,6 +871,41 @@ func (s *MemgraphStore) UpdateAccessMetadata(ctx context.Context, id string) err
 	return nil
 }
 
+// UpdateAccessMetadataBatch increments access count and updates last_accessed
+// for all ids in a single UNWIND write. IDs with no matching node are skipped.
+func (s *MemgraphStore) UpdateAccessMetadataBatch(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "store.update_access_batch", "db.system", "memgraph", "batch.size", len(ids))
+	defer span.End()
+
+	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(wctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	_, err := session.ExecuteWrite(wctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		_, txErr := tx.Run(wctx, `
+			UNWIND $ids AS id
+			MATCH (m:Memory {uuid: id})
+			SET m.access_count  = coalesce(m.access_count, 0) + 1,
+			    m.last_accessed = $now
+		`, map[string]any{"ids": ids, "now": now})
+		return nil, txErr
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("memgraph update access metadata batch (%d ids): %w", len(ids), err)
+	}
+
+	return nil
+}
+
 // Stats returns collection statistics including type and scope counts plus health metrics.
 func (s *MemgraphStore) Stats(ctx context.Context) (*models.CollectionStats, error) {
 	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
@@ -472,7 +922,7 @@ func (s *MemgraphStore) Stats(ctx context.Context) (*models.CollectionStats, err
 
 	// Total count.
 	totalResult, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		res, txErr := tx.Run(rctx, `MATCH (m:Memory) RETURN count(m) AS total`, nil)
+		res, txErr := tx.Run(rctx, `MATCH (m:Memory) WHERE m.deleted_at IS NULL RETURN count(m) AS total`, nil)
 		if txErr != nil {
 			return int64(0), txErr
 		}
@@ -524,7 +974,7 @@ func (s *MemgraphStore) Stats(ctx context.Context) (*models.CollectionStats, err
 
 // countByField executes a filtered COUNT query for a single field=value combination.
 func (s *MemgraphStore) countByField(ctx context.Context, session neo4j.SessionWithContext, field, value string) (int64, error) {
-	query := fmt.Sprintf(`MATCH (m:Memory) WHERE m.%s = $value RETURN count(m) AS cnt`, field)
+	query := fmt.Sprintf(`MATCH (m:Memory) WHERE m.%s = $value AND m.deleted_at IS NULL RETURN count(m) AS cnt`, field)
 	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
 		res, txErr := tx.Run(ctx, query, map[string]any{"value": value})
 		if txErr != nil {
@@ -566,6 +1016,7 @@ func (s *MemgraphStore) populateHealthMetrics(ctx context.Context, session neo4j
 		raw, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
 			res, txErr := tx.Run(rctx, `
 				MATCH (m:Memory)
+				WHERE m.deleted_at IS NULL
 				RETURN m
 				ORDER BY m.created_at DESC
 				SKIP $skip
@@ -677,7 +1128,8 @@ func insertTopEntry(top []topEntry, entry topEntry, maxLen int) []topEntry {
 	return top
 }
 
-// UpsertEntity inserts or updates an entity node.
+// UpsertEntity inserts or updates an entity node. An entity without a
+// NameEmbedding keeps the embedding already stored on the node.
 func (s *MemgraphStore) UpsertEntity(ctx context.Context, entity models.Entity) error {
 	wctx, cancel := context.WithTimeout(ctx, memgraphWriteTimeout)
 	defer cancel()
@@ -699,6 +1151,7 @@ func (s *MemgraphStore) UpsertEntity(ctx context.Context, entity models.Entity)
 			    e.project      = $project,
 			    e.summary      = $summary,
 			    e.community_id = $community_id,
+			    e.name_embedding = CASE WHEN $has_embedding THEN $name_embedding ELSE null END,
 			    e.created_at   = $created_at,
 			    e.updated_at   = $updated_at
 			ON MATCH SET
@@ -709,6 +1162,7 @@ func (s *MemgraphStore) UpsertEntity(ctx context.Context, entity models.Entity)
 			    e.project      = $project,
 			    e.summary      = $summary,
 			    e.community_id = $community_id,
+			    e.name_embedding = CASE WHEN $has_embedding THEN $name_embedding ELSE e.name_embedding END,
 			    e.updated_at   = $updated_at
 		`, params)
 		return nil, txErr
@@ -808,6 +1262,90 @@ func (s *MemgraphStore) SearchEntities(ctx context.Context, name, entityType str
 	return entities, nil
 }
 
+// ListEntitiesWithoutEmbedding returns up to limit entities that have no name
+// embedding, ordered by ID and starting after the ID given as after ("" =
+// from the start). Pass the last ID returned to fetch the next page; IDs are
+// unique, so entities that share a name are never skipped between pages.
+func (s *MemgraphStore) ListEntitiesWithoutEmbedding(ctx context.Context, after string, limit int) ([]models.Entity, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	raw, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx, `
+			MATCH (e:Entity)
+			WHERE e.name_embedding IS NULL AND e.uuid > $after
+			RETURN e ORDER BY e.uuid LIMIT $limit
+		`, map[string]any{"after": after, "limit": int64(limit)})
+		if txErr != nil {
+			return nil, txErr
+		}
+		return collectEntities(rctx, res, "e")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memgraph list entities without embedding: %w", err)
+	}
+
+	entities, ok := raw.([]models.Entity)
+	if !ok {
+		return nil, fmt.Errorf("memgraph list entities without embedding: unexpected result type %T", raw)
+	}
+	return entities, nil
+}
+
+// SearchEntitiesByVector queries the entity_name_embedding vector index.
+// limit caps the number of results (0 = default 10).
+func (s *MemgraphStore) SearchEntitiesByVector(ctx context.Context, vector []float32, limit int) ([]models.EntitySearchResult, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	raw, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, txErr := tx.Run(rctx, `
+			CALL vector_search.search("entity_name_embedding", $limit, $query_vector)
+			`+BuildVectorScoreClause(s.metric())+`
+			RETURN node AS e, score
+		`, map[string]any{
+			"limit":        int64(limit),
+			"query_vector": float32SliceToAny(vector),
+		})
+		if txErr != nil {
+			return nil, txErr
+		}
+		var results []models.EntitySearchResult
+		for res.Next(rctx) {
+			record := res.Record()
+			e, eErr := recordToEntity(record, "e")
+			if eErr != nil {
+				return nil, eErr
+			}
+			score, _ := record.Get("score")
+			results = append(results, models.EntitySearchResult{Entity: *e, Score: toFloat64(score)})
+		}
+		return results, res.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memgraph search entities by vector: %w", err)
+	}
+
+	results, ok := raw.([]models.EntitySearchResult)
+	if !ok && raw != nil {
+		return nil, fmt.Errorf("memgraph search entities by vector: unexpected result type %T", raw)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
+
 // redactURI returns a version of rawURI with credentials removed, safe to log.
 func redactURI(rawURI string) string {
 	u, err := url.Parse(rawURI)
@@ -868,28 +1406,58 @@ func (s *MemgraphStore) LinkMemoryToEntity(ctx context.Context, entityID, memory
 
 // GetChain follows the supersedes_id chain and returns the full history, newest first.
 // Stops when supersedes_id is empty, the referenced memory is not found, or a cycle is detected.
+// supersedes_id is a property rather than an edge, so the chain is walked hop by hop,
+// but all hops share one session and read transaction.
 func (s *MemgraphStore) GetChain(ctx context.Context, id string) ([]models.Memory, error) {
-	var chain []models.Memory
-	visited := make(map[string]bool)
-	currentID := id
+	ctx, span := tracing.Start(ctx, "store.get_chain", "db.system", "memgraph")
+	defer span.End()
 
-	for currentID != "" {
-		if visited[currentID] {
-			break
-		}
-		visited[currentID] = true
+	rctx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
 
-		mem, err := s.Get(ctx, currentID)
-		if err != nil {
-			if errors.Is(err, store.ErrNotFound) {
+	session := s.driver.NewSession(rctx, s.sessionConfig())
+	defer s.closeSession(ctx, session)
+
+	result, err := session.ExecuteRead(rctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		var chain []models.Memory
+		visited := make(map[string]bool)
+		currentID := id
+
+		for currentID != "" {
+			if visited[currentID] {
+				break
+			}
+			visited[currentID] = true
+
+			res, txErr := tx.Run(rctx, `MATCH (m:Memory {uuid: $id}) WHERE m.deleted_at IS NULL RETURN m`, map[string]any{"id": currentID})
+			if txErr != nil {
+				return nil, txErr
+			}
+			if !res.Next(rctx) {
+				if consumeErr := res.Err(); consumeErr != nil {
+					return nil, consumeErr
+				}
 				break // legitimate chain termination
 			}
-			return nil, fmt.Errorf("memgraph get chain: reading %s: %w", currentID, err)
+			mem, convErr := recordToMemory(res.Record(), "m")
+			if convErr != nil {
+				return nil, convErr
+			}
+			chain = append(chain, *mem)
+			currentID = mem.SupersedesID
 		}
-		chain = append(chain, *mem)
-		currentID = mem.SupersedesID
+		return chain, nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("memgraph get chain %s: %w", id, err)
 	}
 
+	chain, ok := result.([]models.Memory)
+	if !ok {
+		return nil, fmt.Errorf("memgraph get chain: unexpected result type %T", result)
+	}
+	span.SetAttributes("result.count", len(chain))
 	return chain, nil
 }
 
@@ -1090,6 +1658,17 @@ func (s *MemgraphStore) DeleteAllMemories(ctx context.Context) error {
 	return nil
 }
 
+// Ping verifies that Memgraph is reachable. It opens (or reuses) a pooled
+// connection and runs no query.
+func (s *MemgraphStore) Ping(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, memgraphReadTimeout)
+	defer cancel()
+	if err := s.driver.VerifyConnectivity(pingCtx); err != nil {
+		return fmt.Errorf("memgraph ping: %w", err)
+	}
+	return nil
+}
+
 // Close releases the driver connection.
 func (s *MemgraphStore) Close() error {
 	closeCtx, cancel := context.WithTimeout(context.Background(), memgraphReadTimeout)
@@ -1108,6 +1687,10 @@ func memoryToParams(m models.Memory, vector []float32) map[string]any {
 	if !m.ValidUntil.IsZero() {
 		validUntilUnix = m.ValidUntil.Unix()
 	}
+	var expiresAtUnix int64
+	if !m.ExpiresAt.IsZero() {
+		expiresAtUnix = m.ExpiresAt.Unix()
+	}
 	var reinforcedAtUnix int64
 	if !m.ReinforcedAt.IsZero() {
 		reinforcedAtUnix = m.ReinforcedAt.Unix()
@@ -1120,6 +1703,20 @@ func memoryToParams(m models.Memory, vector []float32) map[string]any {
 		}
 	}
 
+	var attachStr string
+	if len(m.Attachments) > 0 {
+		if b, marshalErr := json.Marshal(m.Attachments); marshalErr == nil {
+			attachStr = string(b)
+		}
+	}
+
+	// The preference subject is duplicated into its own indexed property so
+	// GET /v1/preferences can look it up without scanning metadata strings.
+	var prefSubject string
+	if p, ok := m.PreferenceTriple(); ok {
+		prefSubject = models.NormalizePreferenceSubject(p.Subject)
+	}
+
 	// Convert tags to []any for Cypher list parameters.
 	tags := make([]any, len(m.Tags))
 	for i, t := range m.Tags {
@@ -1127,25 +1724,27 @@ func memoryToParams(m models.Memory, vector []float32) map[string]any {
 	}
 
 	return map[string]any{
-		"uuid":              m.ID,
-		"type":              string(m.Type),
-		"scope":             string(m.Scope),
-		"visibility":        string(m.Visibility),
-		"content":           m.Content,
-		"confidence":        m.Confidence,
-		"source":            m.Source,
-		"project":           m.Project,
-		"ttl_seconds":       m.TTLSeconds,
-		"tags":              tags,
-		"metadata":          metaStr,
-		"created_at":        m.CreatedAt.UTC().Format(time.RFC3339Nano),
-		"updated_at":        m.UpdatedAt.UTC().Format(time.RFC3339Nano),
-		"last_accessed":     m.LastAccessed.UTC().Format(time.RFC3339Nano),
-		"access_count":      m.AccessCount,
-		"supersedes_id":     m.SupersedesID,
-		"conflict_group_id": m.ConflictGroupID,
-		"conflict_status":   string(m.ConflictStatus),
-		"valid_until_unix":  validUntilUnix,
+		"uuid":               m.ID,
+		"type":               string(m.Type),
+		"scope":              string(m.Scope),
+		"visibility":         string(m.Visibility),
+		"content":            m.Content,
+		"confidence":         m.Confidence,
+		"source":             m.Source,
+		"project":            m.Project,
+		"ttl_seconds":        m.TTLSeconds,
+		"tags":               tags,
+		"metadata":           metaStr,
+		"preference_subject": prefSubject,
+		"created_at":         m.CreatedAt.UTC().Format(time.RFC3339Nano),
+		"updated_at":         m.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		"last_accessed":      m.LastAccessed.UTC().Format(time.RFC3339Nano),
+		"access_count":       m.AccessCount,
+		"supersedes_id":      m.SupersedesID,
+		"conflict_group_id":  m.ConflictGroupID,
+		"conflict_status":    string(m.ConflictStatus),
+		"valid_until_unix":   validUntilUnix,
+		"expires_at_unix":    expiresAtUnix,
 		"valid_from": func() string {
 			if m.ValidFrom.IsZero() {
 				return m.CreatedAt.UTC().Format(time.RFC3339Nano)
@@ -1160,8 +1759,11 @@ func memoryToParams(m models.Memory, vector []float32) map[string]any {
 		}(),
 		"reinforced_at_unix": reinforcedAtUnix,
 		"reinforced_count":   int64(m.ReinforcedCount),
+		"boost":              m.Boost,
+		"attachments":        attachStr,
 		"has_embedding":      vector != nil,
 		"user_id":            m.UserID,
+		"embedding_model":    m.EmbeddingModel,
 		"embedding":          float32SliceToAny(vector),
 	}
 }
@@ -1185,9 +1787,11 @@ func recordToMemory(record *neo4j.Record, alias string) (*models.Memory, error)
 		Visibility:      models.MemoryVisibility(propString(props, "visibility")),
 		Content:         propString(props, "content"),
 		Confidence:      propFloat64(props, "confidence"),
+		Boost:           propFloat64(props, "boost"),
 		Source:          propString(props, "source"),
 		Project:         propString(props, "project"),
 		UserID:          propString(props, "user_id"),
+		EmbeddingModel:  propString(props, "embedding_model"),
 		TTLSeconds:      propInt64(props, "ttl_seconds"),
 		AccessCount:     propInt64(props, "access_count"),
 		SupersedesID:    propString(props, "supersedes_id"),
@@ -1209,9 +1813,16 @@ func recordToMemory(record *neo4j.Record, alias string) (*models.Memory, error)
 	if unix := propInt64(props, "valid_until_unix"); unix != 0 {
 		m.ValidUntil = time.Unix(unix, 0).UTC()
 	}
+	if unix := propInt64(props, "expires_at_unix"); unix != 0 {
+		m.ExpiresAt = time.Unix(unix, 0).UTC()
+	}
 	if unix := propInt64(props, "reinforced_at_unix"); unix != 0 {
 		m.ReinforcedAt = time.Unix(unix, 0).UTC()
 	}
+	if ts := propString(props, "deleted_at"); ts != "" {
+		t := parseTime(ts)
+		m.DeletedAt = &t
+	}
 
 	// Tags stored as a Cypher list.
 	if raw, exists := props["tags"]; exists {
@@ -1232,6 +1843,14 @@ func recordToMemory(record *neo4j.Record, alias string) (*models.Memory, error)
 		}
 	}
 
+	// Attachments stored as a JSON array string.
+	if attachStr := propString(props, "attachments"); attachStr != "" {
+		var attachments []models.Attachment
+		if unmarshalErr := json.Unmarshal([]byte(attachStr), &attachments); unmarshalErr == nil {
+			m.Attachments = attachments
+		}
+	}
+
 	// HasEmbedding is derived from whether the embedding property is non-empty.
 	// This allows callers (e.g. cmd_reembed) to skip memories that already have a vector.
 	// The neo4j-go-driver may return list properties as []any, []float64, or
@@ -1268,17 +1887,19 @@ func entityToParams(e models.Entity) map[string]any {
 	}
 
 	return map[string]any{
-		"uuid":         e.ID,
-		"name":         e.Name,
-		"type":         string(e.Type),
-		"aliases":      aliases,
-		"memory_ids":   memIDs,
-		"metadata":     metaStr,
-		"project":      e.Project,
-		"summary":      e.Summary,
-		"community_id": e.CommunityID,
-		"created_at":   e.CreatedAt.UTC().Format(time.RFC3339Nano),
-		"updated_at":   e.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		"uuid":           e.ID,
+		"name":           e.Name,
+		"type":           string(e.Type),
+		"aliases":        aliases,
+		"memory_ids":     memIDs,
+		"metadata":       metaStr,
+		"project":        e.Project,
+		"summary":        e.Summary,
+		"community_id":   e.CommunityID,
+		"has_embedding":  len(e.NameEmbedding) > 0,
+		"name_embedding": float32SliceToAny(e.NameEmbedding),
+		"created_at":     e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		"updated_at":     e.UpdatedAt.UTC().Format(time.RFC3339Nano),
 	}
 }
 
@@ -1360,12 +1981,15 @@ func buildWhereClause(f *store.SearchFilters, nodeAlias string) ([]string, map[s
 		sensitiveRequested = true
 	}
 
+	// Trashed memories are hidden until restored.
+	trashClause := fmt.Sprintf("%s.deleted_at IS NULL", nodeAlias)
+
 	if f == nil {
-		return []string{fmt.Sprintf("%s.visibility <> $exclude_sensitive", nodeAlias)},
+		return []string{trashClause, fmt.Sprintf("%s.visibility <> $exclude_sensitive", nodeAlias)},
 			map[string]any{"exclude_sensitive": string(models.VisibilitySensitive)}
 	}
 
-	var clauses []string
+	clauses := []string{trashClause}
 	params := make(map[string]any)
 
 	if !sensitiveRequested {
@@ -1389,6 +2013,14 @@ func buildWhereClause(f *store.SearchFilters, nodeAlias string) ([]string, map[s
 		clauses = append(clauses, fmt.Sprintf("%s.project = $filter_project", nodeAlias))
 		params["filter_project"] = *f.Project
 	}
+	if len(f.Projects) > 0 {
+		clauses = append(clauses, fmt.Sprintf("(%[1]s.project IN $filter_projects OR %[1]s.project IS NULL OR %[1]s.project = '')", nodeAlias))
+		projects := make([]any, len(f.Projects))
+		for i, p := range f.Projects {
+			projects[i] = p
+		}
+		params["filter_projects"] = projects
+	}
 	if f.Source != nil {
 		clauses = append(clauses, fmt.Sprintf("%s.source = $filter_source", nodeAlias))
 		params["filter_source"] = *f.Source
@@ -1397,6 +2029,22 @@ func buildWhereClause(f *store.SearchFilters, nodeAlias string) ([]string, map[s
 		clauses = append(clauses, fmt.Sprintf("%s.user_id = $filter_user_id", nodeAlias))
 		params["filter_user_id"] = f.UserID
 	}
+	if f.SessionID != "" {
+		// Metadata is persisted as a compact JSON object (see memoryToParams), so
+		// an exact key/value pair appears verbatim in the stored string.
+		needle, _ := json.Marshal(map[string]string{"session_id": f.SessionID})
+		clauses = append(clauses, fmt.Sprintf("%s.metadata CONTAINS $filter_session_id", nodeAlias))
+		params["filter_session_id"] = strings.TrimSuffix(strings.TrimPrefix(string(needle), "{"), "}")
+	}
+	if f.TurnID != "" {
+		needle, _ := json.Marshal(map[string]string{"turn_id": f.TurnID})
+		clauses = append(clauses, fmt.Sprintf("%s.metadata CONTAINS $filter_turn_id", nodeAlias))
+		params["filter_turn_id"] = strings.TrimSuffix(strings.TrimPrefix(string(needle), "{"), "}")
+	}
+	if f.PreferenceSubject != "" {
+		clauses = append(clauses, fmt.Sprintf("%s.preference_subject = $filter_preference_subject", nodeAlias))
+		params["filter_preference_subject"] = models.NormalizePreferenceSubject(f.PreferenceSubject)
+	}
 	if f.ConflictStatus != nil {
 		clauses = append(clauses, fmt.Sprintf("%s.conflict_status = $filter_conflict_status", nodeAlias))
 		params["filter_conflict_status"] = string(*f.ConflictStatus)
@@ -1449,6 +2097,32 @@ func buildWhereClause(f *store.SearchFilters, nodeAlias string) ([]string, map[s
 		params["filter_valid_after"] = f.ValidAfter.UTC().Format(time.RFC3339)
 	}
 
+	// created_at and last_accessed are stored as RFC3339Nano, whose fraction
+	// has no fixed width, so bounds compare at whole-second precision: the
+	// lower bound omits the "Z" (".5Z" and "Z" both sort after it) and the
+	// upper bound keeps it ("." sorts before "Z"). Both ends are inclusive.
+	for _, r := range []struct {
+		prop, param, op, layout string
+		t                       *time.Time
+	}{
+		{"created_at", "filter_created_after", ">=", "2006-01-02T15:04:05", f.CreatedAfter},
+		{"created_at", "filter_created_before", "<=", time.RFC3339, f.CreatedBefore},
+		{"last_accessed", "filter_accessed_after", ">=", "2006-01-02T15:04:05", f.AccessedAfter},
+		{"last_accessed", "filter_accessed_before", "<=", time.RFC3339, f.AccessedBefore},
+	} {
+		if r.t == nil {
+			continue
+		}
+		clauses = append(clauses, fmt.Sprintf("%s.%s %s $%s", nodeAlias, r.prop, r.op, r.param))
+		params[r.param] = r.t.UTC().Format(r.layout)
+	}
+
+	// expires_at_unix is 0 when a memory has no expiry date.
+	if f.ExpiresBefore != nil {
+		clauses = append(clauses, fmt.Sprintf("(%s.expires_at_unix > 0 AND %s.expires_at_unix < $filter_expires_before)", nodeAlias, nodeAlias))
+		params["filter_expires_before"] = f.ExpiresBefore.Unix()
+	}
+
 	return clauses, params
 }
 
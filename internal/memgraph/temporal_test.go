@@ -72,11 +72,56 @@ func TestBuildWhereClause_AsOf(t *testing.T) {
 // (matches the opt-in behavior of matchesFilters in MockStore).
 func TestBuildWhereClause_NilFilters(t *testing.T) {
 	clauses, params := buildWhereClause(nil, "m")
-	// Nil filters should produce exactly one clause: the sensitive-memory exclusion.
-	if len(clauses) != 1 {
-		t.Errorf("expected 1 clause (sensitive exclusion) for nil filters, got %v", clauses)
+	// Nil filters should produce exactly two clauses: the trash exclusion and
+	// the sensitive-memory exclusion.
+	if len(clauses) != 2 {
+		t.Errorf("expected 2 clauses (trash and sensitive exclusion) for nil filters, got %v", clauses)
 	}
 	if _, ok := params["exclude_sensitive"]; !ok {
 		t.Errorf("expected exclude_sensitive param for nil filters, got %v", params)
 	}
 }
+
+// TestBuildWhereClause_CreatedAndAccessedRange verifies the created_at and
+// last_accessed bounds and their whole-second parameter formats.
+func TestBuildWhereClause_CreatedAndAccessedRange(t *testing.T) {
+	after := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 3, 7, 23, 59, 59, 0, time.UTC)
+	f := &store.SearchFilters{CreatedAfter: &after, CreatedBefore: &before, AccessedAfter: &after}
+	clauses, params := buildWhereClause(f, "m")
+
+	joined := strings.Join(clauses, " AND ")
+	for _, want := range []string{
+		"m.created_at >= $filter_created_after",
+		"m.created_at <= $filter_created_before",
+		"m.last_accessed >= $filter_accessed_after",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected clause %q, got %v", want, clauses)
+		}
+	}
+	if strings.Contains(joined, "filter_accessed_before") {
+		t.Errorf("unexpected accessed_before clause: %v", clauses)
+	}
+	if got := params["filter_created_after"]; got != "2026-03-01T00:00:00" {
+		t.Errorf("filter_created_after = %v, want 2026-03-01T00:00:00", got)
+	}
+	if got := params["filter_created_before"]; got != "2026-03-07T23:59:59Z" {
+		t.Errorf("filter_created_before = %v, want 2026-03-07T23:59:59Z", got)
+	}
+}
+
+// TestBuildWhereClause_ExpiresBefore verifies that ExpiresBefore skips
+// memories without an expiry and compares expires_at_unix in seconds.
+func TestBuildWhereClause_ExpiresBefore(t *testing.T) {
+	before := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	clauses, params := buildWhereClause(&store.SearchFilters{ExpiresBefore: &before}, "m")
+
+	want := "(m.expires_at_unix > 0 AND m.expires_at_unix < $filter_expires_before)"
+	if !strings.Contains(strings.Join(clauses, " AND "), want) {
+		t.Errorf("expected clause %q, got %v", want, clauses)
+	}
+	if got := params["filter_expires_before"]; got != before.Unix() {
+		t.Errorf("filter_expires_before = %v, want %d", got, before.Unix())
+	}
+}
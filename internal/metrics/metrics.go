@@ -11,9 +11,26 @@ var (
 	CaptureTotal     = expvar.NewInt("cortex_capture_total")
 	StoreTotal       = expvar.NewInt("cortex_store_total")
 	DedupSkipped     = expvar.NewInt("cortex_dedup_skipped_total")
+	FastDedupSkipped = expvar.NewInt("cortex_fast_dedup_skipped_total")
 	LifecycleExpired = expvar.NewInt("cortex_lifecycle_expired_total")
 	LifecycleDecayed = expvar.NewInt("cortex_lifecycle_decayed_total")
 	LifecycleRetired = expvar.NewInt("cortex_lifecycle_retired_total")
+	LifecyclePurged  = expvar.NewInt("cortex_lifecycle_purged_total")
+	LifecycleEvicted = expvar.NewInt("cortex_lifecycle_evicted_total")
+
+	// LifecycleExpiredAt counts memories deleted because their ExpiresAt
+	// date passed.
+	LifecycleExpiredAt = expvar.NewInt("cortex_lifecycle_expired_at_total")
+
+	// LifecycleScanTruncated counts lifecycle scans cut short by the
+	// lifecycle.max_scan cap.
+	LifecycleScanTruncated = expvar.NewInt("cortex_lifecycle_scan_truncated_total")
+)
+
+// Embedding cache counters (embedder.cache_size).
+var (
+	EmbedCacheHits   = expvar.NewInt("cortex_embed_cache_hits_total")
+	EmbedCacheMisses = expvar.NewInt("cortex_embed_cache_misses_total")
 )
 
 // Async pipeline counters.
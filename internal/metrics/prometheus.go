@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Latency histograms, in seconds.
+var (
+	EmbedLatency  = NewHistogram("cortex_embed_latency_seconds", "Time spent generating an embedding for an API request.", LatencyBuckets)
+	SearchLatency = NewHistogram("cortex_search_latency_seconds", "Time spent serving POST /v1/search.", LatencyBuckets)
+)
+
+// RecallTokens tracks the estimated tokens returned by POST /v1/recall.
+var RecallTokens = NewHistogram("cortex_recall_tokens", "Estimated tokens in the context returned by POST /v1/recall.", TokenBuckets)
+
+// API request counters. Errors count requests answered with a 4xx or 5xx
+// status.
+var (
+	APIRememberRequests = expvar.NewInt("cortex_api_remember_requests_total")
+	APIRememberErrors   = expvar.NewInt("cortex_api_remember_errors_total")
+	APIRecallRequests   = expvar.NewInt("cortex_api_recall_requests_total")
+	APIRecallErrors     = expvar.NewInt("cortex_api_recall_errors_total")
+	APISearchRequests   = expvar.NewInt("cortex_api_search_requests_total")
+	APISearchErrors     = expvar.NewInt("cortex_api_search_errors_total")
+)
+
+// Bucket upper bounds shared by the histograms above.
+var (
+	LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	TokenBuckets   = []float64{50, 100, 250, 500, 1000, 2000, 4000, 8000}
+)
+
+// gauges lists the expvar names exported as Prometheus gauges rather than
+// counters because they can go down.
+var gauges = map[string]bool{
+	"cortex_async_in_flight": true,
+}
+
+var (
+	histMu     sync.Mutex
+	histograms []*Histogram
+)
+
+// Histogram counts observations into cumulative buckets, Prometheus style.
+// It is also published through expvar so it shows up on /debug/vars.
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64
+
+	mu     sync.Mutex
+	counts []uint64 // per bucket, non-cumulative; last entry is +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram creates and registers a histogram with the given bucket upper
+// bounds, which must be sorted ascending. It panics if name is already
+// registered, like expvar.NewInt.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)+1),
+	}
+	expvar.Publish(name, h)
+	histMu.Lock()
+	histograms = append(histograms, h)
+	histMu.Unlock()
+	return h
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	i := len(h.buckets)
+	for j, upper := range h.buckets {
+		if v <= upper {
+			i = j
+			break
+		}
+	}
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += v
+	h.count++
+	h.mu.Unlock()
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Count returns the number of observations recorded.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// String implements expvar.Var.
+func (h *Histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b, _ := json.Marshal(map[string]any{"count": h.count, "sum": h.sum})
+	return string(b)
+}
+
+// writeTo appends h in Prometheus text format.
+func (h *Histogram) writeTo(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	var cumulative uint64
+	for i, upper := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", h.name, formatFloat(upper), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n%s_count %d\n", h.name, formatFloat(h.sum), h.name, h.count)
+}
+
+// WritePrometheus writes every cortex_* expvar counter and every registered
+// histogram in the Prometheus text exposition format (version 0.0.4).
+func WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	expvar.Do(func(kv expvar.KeyValue) {
+		v, ok := kv.Value.(*expvar.Int)
+		if !ok || !strings.HasPrefix(kv.Key, "cortex_") {
+			return
+		}
+		kind := "counter"
+		if gauges[kv.Key] {
+			kind = "gauge"
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n%s %d\n", kv.Key, kind, kv.Key, v.Value())
+	})
+	histMu.Lock()
+	hs := append([]*Histogram(nil), histograms...)
+	histMu.Unlock()
+	for _, h := range hs {
+		h.writeTo(bw)
+	}
+	return bw.Flush()
+}
+
+// formatFloat renders v the way Prometheus expects (no exponent for typical
+// bucket bounds, "+Inf" for infinity).
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
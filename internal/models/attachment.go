@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AttachmentType classifies what an attachment reference points at.
+type AttachmentType string
+
+const (
+	AttachmentFile   AttachmentType = "file"
+	AttachmentURL    AttachmentType = "url"
+	AttachmentCommit AttachmentType = "commit"
+)
+
+// ValidAttachmentTypes is the set of all valid attachment types.
+var ValidAttachmentTypes = []AttachmentType{
+	AttachmentFile,
+	AttachmentURL,
+	AttachmentCommit,
+}
+
+// IsValid returns true if the attachment type is recognized.
+func (at AttachmentType) IsValid() bool {
+	for _, v := range ValidAttachmentTypes {
+		if at == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Attachment points a memory at external material (a file path, URL or
+// commit SHA) instead of embedding its full content. Only the reference is
+// stored; the target is never fetched.
+type Attachment struct {
+	Type AttachmentType `json:"type"`
+	Ref  string         `json:"ref"`
+}
+
+// String returns the attachment as "type:ref", the form accepted by
+// ParseAttachment.
+func (a Attachment) String() string {
+	return string(a.Type) + ":" + a.Ref
+}
+
+// Validate reports whether a has a recognized type and a non-empty ref.
+func (a Attachment) Validate() error {
+	if !a.Type.IsValid() {
+		return fmt.Errorf("invalid attachment type %q", a.Type)
+	}
+	if strings.TrimSpace(a.Ref) == "" {
+		return fmt.Errorf("attachment %q has an empty ref", a.Type)
+	}
+	return nil
+}
+
+// ParseAttachment parses "type:ref", e.g. "file:docs/auth.md",
+// "url:https://example.com/rfc" or "commit:3f2a9c1". Only the first colon
+// separates the type, so refs may contain colons themselves.
+func ParseAttachment(s string) (Attachment, error) {
+	typ, ref, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return Attachment{}, fmt.Errorf("attachment %q must be type:ref", s)
+	}
+	a := Attachment{Type: AttachmentType(strings.ToLower(strings.TrimSpace(typ))), Ref: strings.TrimSpace(ref)}
+	if err := a.Validate(); err != nil {
+		return Attachment{}, err
+	}
+	return a, nil
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // EntityType classifies the kind of entity.
 type EntityType string
@@ -49,3 +52,56 @@ type Entity struct {
 	NameEmbedding []float32 `json:"name_embedding,omitempty"`
 	CommunityID   string    `json:"community_id,omitempty"`
 }
+
+// EntityMentionLimit caps how many linked memories EmbeddingTextWith includes.
+const EntityMentionLimit = 3
+
+// entityMentionRunes caps the length of each memory excerpt in the embedding text.
+const entityMentionRunes = 200
+
+// EmbeddingText returns the text embedded into NameEmbedding: the name,
+// aliases and summary, one per line, so semantic entity search matches what
+// an entity is about and not only what it is called.
+func (e Entity) EmbeddingText() string {
+	return e.EmbeddingTextWith(nil)
+}
+
+// EmbeddingTextWith is EmbeddingText followed by excerpts of up to
+// EntityMentionLimit memories that mention the entity, so entities without
+// a summary still embed what is said about them. Blank mentions are skipped
+// and each excerpt is truncated to a couple of hundred characters.
+func (e Entity) EmbeddingTextWith(mentions []string) string {
+	lines := []string{e.Name}
+	if len(e.Aliases) > 0 {
+		lines = append(lines, "Also known as: "+strings.Join(e.Aliases, ", "))
+	}
+	if e.Summary != "" {
+		lines = append(lines, e.Summary)
+	}
+	added := 0
+	for _, m := range mentions {
+		if added == EntityMentionLimit {
+			break
+		}
+		m = strings.Join(strings.Fields(m), " ")
+		if m == "" {
+			continue
+		}
+		if r := []rune(m); len(r) > entityMentionRunes {
+			m = string(r[:entityMentionRunes]) + "…"
+		}
+		if added == 0 {
+			lines = append(lines, "Mentioned in:")
+		}
+		lines = append(lines, "- "+m)
+		added++
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EntitySearchResult is an entity returned by a vector search, with its
+// cosine similarity to the query.
+type EntitySearchResult struct {
+	Entity Entity  `json:"entity"`
+	Score  float64 `json:"score"`
+}
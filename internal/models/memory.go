@@ -1,6 +1,8 @@
 package models
 
 import (
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -99,6 +101,23 @@ const (
 	VisibilitySensitive MemoryVisibility = "sensitive"
 )
 
+// ValidMemoryVisibilities is the set of all valid memory visibilities.
+var ValidMemoryVisibilities = []MemoryVisibility{
+	VisibilityPrivate,
+	VisibilityShared,
+	VisibilitySensitive,
+}
+
+// IsValid returns true if the memory visibility is recognized.
+func (mv MemoryVisibility) IsValid() bool {
+	for _, v := range ValidMemoryVisibilities {
+		if mv == v {
+			return true
+		}
+	}
+	return false
+}
+
 // Memory is the core data structure for a stored memory.
 type Memory struct {
 	ID         string           `json:"id"`
@@ -125,10 +144,26 @@ type Memory struct {
 	// ReinforcedCount is how many times this memory has been reinforced.
 	ReinforcedCount int `json:"reinforced_count,omitempty"`
 
+	// Boost multiplies the memory's final recall score so users can promote
+	// authoritative memories while still respecting query relevance. Zero
+	// means unset and ranks as DefaultBoost.
+	Boost float64 `json:"boost,omitempty"`
+
+	// Attachments point at external material (files, URLs, commits) the
+	// memory refers to, so the content itself can stay short.
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	Metadata     map[string]any `json:"metadata,omitempty"`
 	SupersedesID string         `json:"supersedes_id,omitempty"` // ID of memory this replaces
 	ValidUntil   time.Time      `json:"valid_until,omitempty"`   // zero = never expires
 
+	// ExpiresAt is a hard deletion date: once it passes, the lifecycle
+	// deletes the memory whatever its scope. Unlike ValidUntil, which marks a
+	// permanent or project fact as no longer true and retires it, ExpiresAt
+	// says nothing about truth and also applies to session and TTL memories.
+	// Zero = no expiry date.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+
 	// ValidFrom is when this memory version became the current truth.
 	// Set to CreatedAt on first write. Immutable after initial store.
 	ValidFrom time.Time `json:"valid_from,omitempty"`
@@ -154,6 +189,32 @@ type Memory struct {
 	// Excluded from JSON serialization — this is a transient, store-layer field
 	// that must not leak into MCP tool responses or capture --json output.
 	HasEmbedding bool `json:"-"`
+
+	// EmbeddingModel identifies the embedding model (e.g. "ollama/nomic-embed-text")
+	// that produced the stored vector. Empty for memories written before the
+	// model was tracked. Used by reembed --reembed-stale to migrate incrementally.
+	EmbeddingModel string `json:"embedding_model,omitempty"`
+
+	// DeletedAt is set when the memory has been moved to the trash by forget.
+	// Nil = live. Trashed memories can be restored until purged by lifecycle.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+const (
+	// DefaultBoost is the recall score multiplier of a memory without a boost.
+	DefaultBoost = 1.0
+
+	// MaxBoost is the largest accepted Boost.
+	MaxBoost = 10.0
+)
+
+// RankBoost returns the recall score multiplier of m: Boost, or DefaultBoost
+// when it is unset.
+func (m *Memory) RankBoost() float64 {
+	if m.Boost <= 0 {
+		return DefaultBoost
+	}
+	return m.Boost
 }
 
 // SearchResult wraps a Memory with its similarity score.
@@ -180,17 +241,73 @@ type RecallResult struct {
 	ReinforcementScore  float64 `json:"reinforcement_score"`
 	TagAffinityScore    float64 `json:"tag_affinity_score"`
 	GraphProximityScore float64 `json:"graph_proximity_score"`
+	WriteRecencyBoost   float64 `json:"write_recency_boost,omitempty"` // bonus added to the weighted sum for recently created memories
+	QueryTypeBoost      float64 `json:"query_type_boost,omitempty"`    // bonus added to the weighted sum when the memory's type matches the query's intent
+	ManualBoost         float64 `json:"manual_boost"`                  // the memory's own Boost multiplier
 	SupersessionPenalty float64 `json:"supersession_penalty"`
 	ConflictPenalty     float64 `json:"conflict_penalty"`
 	FinalScore          float64 `json:"final_score"`
 }
 
+// ContentAppendSeparator joins appended details to existing memory content.
+const ContentAppendSeparator = "\n"
+
+// AppendContent returns existing with addition appended after
+// ContentAppendSeparator. Surrounding whitespace is trimmed from both parts and
+// an empty part contributes nothing.
+func AppendContent(existing, addition string) string {
+	existing = strings.TrimSpace(existing)
+	addition = strings.TrimSpace(addition)
+	switch {
+	case addition == "":
+		return existing
+	case existing == "":
+		return addition
+	}
+	return existing + ContentAppendSeparator + addition
+}
+
+// Successor returns a new version of m with the given ID that supersedes it:
+// content, classification, owner, tags, attachments and access statistics carry forward,
+// timestamps are reset to now and SupersedesID points back at m. The old
+// memory stays in the store as history (see Store.GetChain).
+func (m Memory) Successor(id string, now time.Time) Memory {
+	return Memory{
+		ID:              id,
+		Type:            m.Type,
+		Scope:           m.Scope,
+		Visibility:      m.Visibility,
+		Content:         m.Content,
+		Confidence:      m.Confidence,
+		Boost:           m.Boost,
+		Source:          m.Source,
+		Tags:            m.Tags,
+		Project:         m.Project,
+		UserID:          m.UserID,
+		TTLSeconds:      m.TTLSeconds,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		LastAccessed:    now,
+		AccessCount:     m.AccessCount,
+		ReinforcedCount: m.ReinforcedCount,
+		SupersedesID:    m.ID,
+		ValidUntil:      m.ValidUntil,
+		ExpiresAt:       m.ExpiresAt,
+		Attachments:     slices.Clone(m.Attachments),
+		Metadata:        m.Metadata,
+	}
+}
+
 // CapturedMemory is a memory extracted from a conversation by the LLM.
 type CapturedMemory struct {
 	Content    string     `json:"content"`
 	Type       MemoryType `json:"type"`
 	Confidence float64    `json:"confidence"`
 	Tags       []string   `json:"tags"`
+
+	// Preference is the structured triple for preference memories. Only
+	// requested from the LLM when structured preference extraction is enabled.
+	Preference *Preference `json:"preference,omitempty"`
 }
 
 // MemoryPreview is a lightweight summary of a memory used in stats output.
@@ -214,4 +331,9 @@ type CollectionStats struct {
 	ActiveConflicts    int64            `json:"active_conflicts"`
 	PendingTTLExpiry   int64            `json:"pending_ttl_expiry"`
 	StorageEstimate    int64            `json:"storage_estimate_bytes"`
+
+	// Approximate is set when the counts were extrapolated from a sample of
+	// SampleSize memories instead of counted exactly.
+	Approximate bool `json:"approximate,omitempty"`
+	SampleSize  int  `json:"sample_size,omitempty"`
 }
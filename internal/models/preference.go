@@ -0,0 +1,77 @@
+package models
+
+import "strings"
+
+// Metadata keys under which a structured preference triple is stored. They
+// are namespaced so they cannot collide with caller-supplied metadata.
+const (
+	MetaPreferenceSubject   = "preference_subject"
+	MetaPreferencePredicate = "preference_predicate"
+	MetaPreferenceObject    = "preference_object"
+)
+
+// Un-namespaced keys used by earlier releases; still read so existing
+// preference memories keep their triple.
+const (
+	legacyMetaPreferenceSubject   = "subject"
+	legacyMetaPreferencePredicate = "predicate"
+	legacyMetaPreferenceObject    = "object"
+)
+
+// Preference is the structured subject/predicate/object reading of a
+// preference memory, e.g. {"Ajit", "likes", "drone piloting"}.
+type Preference struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+}
+
+// IsComplete reports whether all three parts of the triple are non-blank.
+func (p Preference) IsComplete() bool {
+	return strings.TrimSpace(p.Subject) != "" &&
+		strings.TrimSpace(p.Predicate) != "" &&
+		strings.TrimSpace(p.Object) != ""
+}
+
+// NormalizePreferenceSubject returns the form of subject used for indexing and
+// lookup: trimmed and lowercased, so "Ajit" and " ajit " match.
+func NormalizePreferenceSubject(subject string) string {
+	return strings.ToLower(strings.TrimSpace(subject))
+}
+
+// SetPreference records p in m.Metadata, allocating the map if needed, and
+// drops any triple stored under the legacy keys.
+func (m *Memory) SetPreference(p Preference) {
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]any, 3)
+	}
+	delete(m.Metadata, legacyMetaPreferenceSubject)
+	delete(m.Metadata, legacyMetaPreferencePredicate)
+	delete(m.Metadata, legacyMetaPreferenceObject)
+	m.Metadata[MetaPreferenceSubject] = strings.TrimSpace(p.Subject)
+	m.Metadata[MetaPreferencePredicate] = strings.TrimSpace(p.Predicate)
+	m.Metadata[MetaPreferenceObject] = strings.TrimSpace(p.Object)
+}
+
+// PreferenceTriple returns the structured preference stored on a preference
+// memory. ok is false for other memory types or when the triple is incomplete.
+// Memories written before the keys were namespaced are read from the legacy
+// keys.
+func (m Memory) PreferenceTriple() (p Preference, ok bool) {
+	if m.Type != MemoryTypePreference {
+		return Preference{}, false
+	}
+	p.Subject, _ = m.Metadata[MetaPreferenceSubject].(string)
+	p.Predicate, _ = m.Metadata[MetaPreferencePredicate].(string)
+	p.Object, _ = m.Metadata[MetaPreferenceObject].(string)
+	if !p.IsComplete() {
+		var legacy Preference
+		legacy.Subject, _ = m.Metadata[legacyMetaPreferenceSubject].(string)
+		legacy.Predicate, _ = m.Metadata[legacyMetaPreferencePredicate].(string)
+		legacy.Object, _ = m.Metadata[legacyMetaPreferenceObject].(string)
+		if legacy.IsComplete() {
+			return legacy, true
+		}
+	}
+	return p, p.IsComplete()
+}
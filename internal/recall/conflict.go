@@ -0,0 +1,262 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
+)
+
+const (
+	// ConflictBoth keeps every memory of a conflict group and marks each
+	// with the short IDs of its competitors (the default).
+	ConflictBoth = "both"
+
+	// ConflictNewest keeps only the most recently created memory of a
+	// conflict group, without a marker.
+	ConflictNewest = "newest"
+
+	// ConflictFlag keeps only the most recently created memory of a conflict
+	// group and marks it as disputed.
+	ConflictFlag = "flag"
+
+	// detectedConflictTop is how many top-ranked results DetectConflicts
+	// compares, bounding it to detectedConflictTop-1 LLM calls.
+	detectedConflictTop = 5
+
+	// detectedConflictMinSimilarity is the cosine similarity two results need
+	// before DetectConflicts asks whether they contradict each other.
+	detectedConflictMinSimilarity = 0.75
+
+	// detectedGroupPrefix marks conflict groups found by DetectConflicts
+	// rather than stored by the conflict engine.
+	detectedGroupPrefix = "detected:"
+)
+
+// ContradictionChecker reports whether content contradicts one of candidates
+// and which. *capture.ConflictDetector satisfies it.
+type ContradictionChecker interface {
+	Detect(ctx context.Context, content string, candidates []models.Memory) (bool, string, string, error)
+}
+
+// VectorSource returns stored embeddings by memory ID; store.Store satisfies it.
+type VectorSource interface {
+	GetVectors(ctx context.Context, ids []string) (map[string][]float32, error)
+}
+
+// ValidConflictMode reports whether m is a recognised on-conflict mode. The
+// empty string is accepted and means ConflictBoth.
+func ValidConflictMode(m string) bool {
+	return m == "" || m == ConflictBoth || m == ConflictNewest || m == ConflictFlag
+}
+
+// Conflict describes competing memories found together in one recall: the
+// members of an active conflict group (see the conflict engine), in rank
+// order. Kept is the memory the newest and flag modes retain.
+type Conflict struct {
+	GroupID string   `json:"group_id"`
+	IDs     []string `json:"ids"`
+	Kept    string   `json:"kept"`
+}
+
+// DetectConflicts asks checker whether any of the top-ranked results
+// contradicts a similar higher-ranked one that no stored conflict group
+// already links it to. Each contradicting pair is put into a shared active
+// conflict group on copies of the results, so ResolveConflicts handles it
+// like a stored conflict. A nil checker, a vector lookup failure or a checker
+// error leaves ranked unchanged. Run it before ResolveConflicts.
+func DetectConflicts(ctx context.Context, checker ContradictionChecker, vs VectorSource, ranked []models.RecallResult, logger *slog.Logger) []models.RecallResult {
+	if checker == nil || vs == nil || len(ranked) < 2 {
+		return ranked
+	}
+	top := min(len(ranked), detectedConflictTop)
+	ids := make([]string, top)
+	for i := range ids {
+		ids[i] = ranked[i].Memory.ID
+	}
+	vecs, err := vs.GetVectors(ctx, ids)
+	if err != nil {
+		logger.Warn("recall: conflict check vector lookup failed, skipping", "error", err)
+		return ranked
+	}
+
+	var out []models.RecallResult
+	for i := 1; i < top; i++ {
+		vi := vecs[ranked[i].Memory.ID]
+		if len(vi) == 0 {
+			continue
+		}
+		var candidates []models.Memory
+		for j := 0; j < i; j++ {
+			vj := vecs[ranked[j].Memory.ID]
+			if len(vj) == 0 || sameActiveGroup(ranked[i].Memory, ranked[j].Memory) {
+				continue
+			}
+			if vecmath.CosineSimilarity(vi, vj) >= detectedConflictMinSimilarity {
+				candidates = append(candidates, ranked[j].Memory)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		contradicts, otherID, reason, detectErr := checker.Detect(ctx, ranked[i].Memory.Content, candidates)
+		if detectErr != nil || !contradicts || otherID == "" {
+			continue
+		}
+		if out == nil {
+			out = make([]models.RecallResult, len(ranked))
+			copy(out, ranked)
+		}
+		j := 0
+		for j < i && out[j].Memory.ID != otherID {
+			j++
+		}
+		group := out[j].Memory.ConflictGroupID
+		if group == "" || out[j].Memory.ConflictStatus != models.ConflictStatusActive {
+			group = detectedGroupPrefix + otherID
+		}
+		for _, k := range []int{i, j} {
+			out[k].Memory.ConflictGroupID = group
+			out[k].Memory.ConflictStatus = models.ConflictStatusActive
+		}
+		logger.Debug("recall: detected conflicting memories", "id", ranked[i].Memory.ID, "conflicts_with", otherID, "reason", reason)
+	}
+	if out == nil {
+		return ranked
+	}
+	return out
+}
+
+// sameActiveGroup reports whether a and b are already members of one active
+// conflict group.
+func sameActiveGroup(a, b models.Memory) bool {
+	return a.ConflictGroupID != "" && a.ConflictGroupID == b.ConflictGroupID &&
+		a.ConflictStatus == models.ConflictStatusActive && b.ConflictStatus == models.ConflictStatusActive
+}
+
+// ResolveConflicts finds ranked results that share an active conflict group
+// and, for ConflictNewest and ConflictFlag, keeps only the most recently
+// created memory of each group. Those two modes first drop superseded
+// versions with CollapseSupersessions, so what remains are genuinely
+// competing facts. A group only counts when at least two of its members are
+// in ranked. Rank order is preserved and ranked itself is not modified; pass
+// the conflicts to MarkConflicts to annotate the context.
+func ResolveConflicts(ranked []models.RecallResult, mode string) ([]models.RecallResult, []Conflict) {
+	if mode == ConflictNewest || mode == ConflictFlag {
+		ranked = CollapseSupersessions(ranked)
+	}
+	members := make(map[string][]int)
+	var order []string
+	for i := range ranked {
+		g := ranked[i].Memory.ConflictGroupID
+		if g == "" || ranked[i].Memory.ConflictStatus != models.ConflictStatusActive {
+			continue
+		}
+		if _, seen := members[g]; !seen {
+			order = append(order, g)
+		}
+		members[g] = append(members[g], i)
+	}
+
+	var conflicts []Conflict
+	drop := make(map[int]bool)
+	for _, g := range order {
+		idxs := members[g]
+		if len(idxs) < 2 {
+			continue
+		}
+		c := Conflict{GroupID: g}
+		newest := idxs[0]
+		for _, i := range idxs {
+			c.IDs = append(c.IDs, ranked[i].Memory.ID)
+			if ranked[i].Memory.CreatedAt.After(ranked[newest].Memory.CreatedAt) {
+				newest = i
+			}
+		}
+		c.Kept = ranked[newest].Memory.ID
+		conflicts = append(conflicts, c)
+
+		if mode == ConflictNewest || mode == ConflictFlag {
+			for _, i := range idxs {
+				if i != newest {
+					drop[i] = true
+				}
+			}
+		}
+	}
+	if len(drop) == 0 {
+		return ranked, conflicts
+	}
+
+	out := make([]models.RecallResult, 0, len(ranked)-len(drop))
+	for i := range ranked {
+		if !drop[i] {
+			out = append(out, ranked[i])
+		}
+	}
+	return out, conflicts
+}
+
+// MarkConflicts returns a copy of results with conflict markers appended to
+// the content of the memories involved in conflicts: with ConflictBoth each
+// lists the short IDs of its competitors, with ConflictFlag the kept memory
+// is marked as disputed, and ConflictNewest adds nothing. Apply it after
+// TruncateEach so truncation never cuts a marker.
+func MarkConflicts(results []models.RecallResult, conflicts []Conflict, mode string) []models.RecallResult {
+	if len(conflicts) == 0 || mode == ConflictNewest {
+		return results
+	}
+	marker := make(map[string]string)
+	for _, c := range conflicts {
+		if mode == ConflictFlag {
+			marker[c.Kept] = fmt.Sprintf(" [disputed: %d competing memories, newest shown]", len(c.IDs))
+			continue
+		}
+		for _, id := range c.IDs {
+			var others []string
+			for _, other := range c.IDs {
+				if other != id {
+					others = append(others, shortID(other))
+				}
+			}
+			marker[id] = fmt.Sprintf(" [conflicts with: %s]", strings.Join(others, ", "))
+		}
+	}
+	out := make([]models.RecallResult, len(results))
+	copy(out, results)
+	for i := range out {
+		out[i].Memory.Content += marker[out[i].Memory.ID]
+	}
+	return out
+}
+
+// ConflictsIn returns the conflicts with at least one member among results,
+// e.g. the memories that made it into the recalled context.
+func ConflictsIn(conflicts []Conflict, results []models.RecallResult) []Conflict {
+	present := make(map[string]bool, len(results))
+	for i := range results {
+		present[results[i].Memory.ID] = true
+	}
+	var out []Conflict
+	for _, c := range conflicts {
+		for _, id := range c.IDs {
+			if present[id] {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// shortID returns the first 8 characters of id, the form used in conflict
+// markers.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
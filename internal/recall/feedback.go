@@ -0,0 +1,121 @@
+package recall
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+const (
+	// FeedbackEventRecall marks a log line describing a served recall.
+	FeedbackEventRecall = "recall"
+
+	// FeedbackEventFeedback marks a log line carrying usefulness judgements
+	// for an earlier recall.
+	FeedbackEventFeedback = "feedback"
+)
+
+// FeedbackResult is one ranked memory in a logged recall. Signals holds the
+// unweighted ranking signals, Bonus the unweighted write recency bonus and
+// Penalty the combined supersession, conflict and manual boost multiplier, so
+// the result can be re-scored under other Weights.
+type FeedbackResult struct {
+	ID      string  `json:"id"`
+	Score   float64 `json:"score"`
+	Signals Weights `json:"signals"`
+	Bonus   float64 `json:"bonus,omitempty"`
+	Penalty float64 `json:"penalty"`
+}
+
+// FeedbackEntry is a single line of the recall feedback log. Recall lines
+// carry the query and its ranked results; feedback lines reference a recall
+// by RecallID and list which of its memories were useful. Joining the two on
+// RecallID yields training data for tuning Weights or an offline reranker.
+type FeedbackEntry struct {
+	Event       string           `json:"event"`
+	RecallID    string           `json:"recall_id"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Query       string           `json:"query,omitempty"`
+	Project     string           `json:"project,omitempty"`
+	QueryVector []float32        `json:"query_vector,omitempty"`
+	Results     []FeedbackResult `json:"results,omitempty"`
+	Returned    int              `json:"returned,omitempty"` // results that made it into the context
+	Useful      []string         `json:"useful,omitempty"`
+	NotUseful   []string         `json:"not_useful,omitempty"`
+}
+
+// FeedbackLog appends recall and feedback events to a JSONL file. It is safe
+// for concurrent use.
+type FeedbackLog struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// OpenFeedbackLog opens (or creates) the JSONL file at path for appending.
+func OpenFeedbackLog(path string) (*FeedbackLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("recall: opening feedback log: %w", err)
+	}
+	return &FeedbackLog{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// LogRecall records a served recall: the query, its embedding and every
+// ranked result with its final score. returned is how many of the results
+// were included in the response.
+func (l *FeedbackLog) LogRecall(recallID, query, project string, vec []float32, ranked []models.RecallResult, returned int) error {
+	results := make([]FeedbackResult, len(ranked))
+	for i := range ranked {
+		rr := &ranked[i]
+		results[i] = FeedbackResult{
+			ID:      rr.Memory.ID,
+			Score:   rr.FinalScore,
+			Signals: Signals(rr),
+			Bonus:   rr.WriteRecencyBoost + rr.QueryTypeBoost,
+			Penalty: rr.SupersessionPenalty * rr.ConflictPenalty * rr.ManualBoost,
+		}
+	}
+	return l.write(FeedbackEntry{
+		Event:       FeedbackEventRecall,
+		RecallID:    recallID,
+		Timestamp:   time.Now().UTC(),
+		Query:       query,
+		Project:     project,
+		QueryVector: vec,
+		Results:     results,
+		Returned:    returned,
+	})
+}
+
+// LogFeedback records which memories of the recall identified by recallID
+// were useful and which were not.
+func (l *FeedbackLog) LogFeedback(recallID string, useful, notUseful []string) error {
+	return l.write(FeedbackEntry{
+		Event:     FeedbackEventFeedback,
+		RecallID:  recallID,
+		Timestamp: time.Now().UTC(),
+		Useful:    useful,
+		NotUseful: notUseful,
+	})
+}
+
+func (l *FeedbackLog) write(e FeedbackEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.enc.Encode(e); err != nil {
+		return fmt.Errorf("recall: writing feedback log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (l *FeedbackLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
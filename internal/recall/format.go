@@ -0,0 +1,144 @@
+package recall
+
+import (
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
+)
+
+const (
+	// FormatPlain lists the selected memories in rank order (the default).
+	FormatPlain = "plain"
+
+	// FormatGrouped lists the selected memories under a heading per memory
+	// type ("Rules:", "Facts:", ...), keeping rank order within each type.
+	FormatGrouped = "grouped"
+)
+
+// ValidFormat reports whether f is a recognised context format. The empty
+// string is accepted and means FormatPlain.
+func ValidFormat(f string) bool {
+	return f == "" || f == FormatPlain || f == FormatGrouped
+}
+
+// typeHeadings are the section headings used by FormatGrouped.
+var typeHeadings = map[models.MemoryType]string{
+	models.MemoryTypeRule:       "Rules:",
+	models.MemoryTypeFact:       "Facts:",
+	models.MemoryTypeEpisode:    "Episodes:",
+	models.MemoryTypeProcedure:  "Procedures:",
+	models.MemoryTypePreference: "Preferences:",
+}
+
+// FormatContext renders the ranked memories in format within budget, applying
+// the same minCount and maxCount rules as tokenizer.FormatMemoriesWithLimits.
+// It returns the context and how many of the top-ranked memories it contains.
+func FormatContext(format string, ranked []models.RecallResult, budget, minCount, maxCount int) (string, int) {
+	contents := make([]string, len(ranked))
+	for i := range ranked {
+		contents[i] = ranked[i].Memory.Content
+	}
+	if format != FormatGrouped {
+		return tokenizer.FormatMemoriesWithLimits(contents, budget, minCount, maxCount)
+	}
+	headings := make([]string, len(ranked))
+	for i := range ranked {
+		h, ok := typeHeadings[ranked[i].Memory.Type]
+		if !ok {
+			h = "Other:"
+		}
+		headings[i] = h
+	}
+	return tokenizer.FormatGroupedWithLimits(contents, headings, budget, minCount, maxCount)
+}
+
+// ContextItem describes one memory included in a recall context, for clients
+// that want structure alongside the formatted string.
+type ContextItem struct {
+	ID      string            `json:"id"`
+	Type    models.MemoryType `json:"type"`
+	Content string            `json:"content"` // as it appears in the context
+	Score   float64           `json:"score"`
+	Tokens  int               `json:"tokens"`
+}
+
+// ContextItems returns the first count memories of shown, the slice that was
+// passed to FormatContext, in rank order. Content is taken from shown, so it
+// carries the same truncation and markers as the formatted context.
+func ContextItems(shown []models.RecallResult, count int) []ContextItem {
+	count = min(count, len(shown))
+	items := make([]ContextItem, count)
+	for i := range items {
+		rr := &shown[i]
+		items[i] = ContextItem{
+			ID:      rr.Memory.ID,
+			Type:    rr.Memory.Type,
+			Content: rr.Memory.Content,
+			Score:   rr.FinalScore,
+			Tokens:  tokenizer.EstimateTokens(rr.Memory.Content),
+		}
+	}
+	return items
+}
+
+// TruncateEach returns a copy of ranked with each memory's content cut to at
+// most maxTokens tokens at a word boundary, so more memories fit in a token
+// budget at the cost of per-memory detail. ranked itself is not modified.
+// maxTokens <= 0 returns ranked unchanged.
+func TruncateEach(ranked []models.RecallResult, maxTokens int) []models.RecallResult {
+	if maxTokens <= 0 {
+		return ranked
+	}
+	out := make([]models.RecallResult, len(ranked))
+	copy(out, ranked)
+	for i := range out {
+		out[i].Memory.Content = tokenizer.TruncateToTokenBudget(out[i].Memory.Content, maxTokens)
+	}
+	return out
+}
+
+// WithAttachmentRefs returns a copy of ranked with each memory's attachment
+// references appended to its content on a "Refs:" line, so the agent knows
+// where to find the source material. Memories without attachments are left
+// as they are and ranked itself is not modified. Apply it after TruncateEach
+// so truncation never cuts the references.
+func WithAttachmentRefs(ranked []models.RecallResult) []models.RecallResult {
+	out := make([]models.RecallResult, len(ranked))
+	copy(out, ranked)
+	for i := range out {
+		atts := out[i].Memory.Attachments
+		if len(atts) == 0 {
+			continue
+		}
+		refs := make([]string, len(atts))
+		for j := range atts {
+			refs[j] = atts[j].String()
+		}
+		out[i].Memory.Content += "\nRefs: " + strings.Join(refs, ", ")
+	}
+	return out
+}
+
+// RelevanceCap narrows maxCount (0 = no cap) so that the context stops at the
+// first ranked memory whose FinalScore is below ratio times the top score.
+// This sizes the context to how specific the query is: a sharp query with one
+// strong hit returns little, a broad one returns more. ratio <= 0 leaves
+// maxCount unchanged. The top-ranked memory always fits.
+func RelevanceCap(ranked []models.RecallResult, ratio float64, maxCount int) int {
+	if ratio <= 0 || len(ranked) == 0 {
+		return maxCount
+	}
+	top := ranked[0].FinalScore
+	for i := range ranked {
+		top = max(top, ranked[i].FinalScore)
+	}
+	n := 1
+	for n < len(ranked) && ranked[n].FinalScore >= ratio*top {
+		n++
+	}
+	if maxCount > 0 && maxCount < n {
+		return maxCount
+	}
+	return n
+}
@@ -0,0 +1,107 @@
+package recall
+
+import (
+	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+const (
+	// FreshnessAny applies no age filter (the default).
+	FreshnessAny = "any"
+
+	// FreshnessRecent keeps memories created within Freshness.RecentWindow.
+	FreshnessRecent = "recent"
+
+	// FreshnessEstablished keeps memories older than Freshness.EstablishedAge
+	// that were accessed within Freshness.EstablishedAccessWindow.
+	FreshnessEstablished = "established"
+
+	defaultRecentWindow            = 7 * 24 * time.Hour
+	defaultEstablishedAge          = 30 * 24 * time.Hour
+	defaultEstablishedAccessWindow = 30 * 24 * time.Hour
+)
+
+// ValidFreshness reports whether f is a recognised freshness bucket. The
+// empty string is accepted and means FreshnessAny.
+func ValidFreshness(f string) bool {
+	return f == "" || f == FreshnessAny || f == FreshnessRecent || f == FreshnessEstablished
+}
+
+// Freshness maps the named freshness buckets onto time-range filters, so
+// callers can ask for "recent" or "established" knowledge without picking
+// timestamps. Zero fields fall back to the defaults (7, 30 and 30 days).
+type Freshness struct {
+	RecentWindow            time.Duration
+	EstablishedAge          time.Duration
+	EstablishedAccessWindow time.Duration
+}
+
+// NewFreshness builds a Freshness from day counts as found in config.
+func NewFreshness(recentDays, establishedDays, establishedAccessDays int) Freshness {
+	const day = 24 * time.Hour
+	return Freshness{
+		RecentWindow:            time.Duration(recentDays) * day,
+		EstablishedAge:          time.Duration(establishedDays) * day,
+		EstablishedAccessWindow: time.Duration(establishedAccessDays) * day,
+	}
+}
+
+func (f Freshness) withDefaults() Freshness {
+	if f.RecentWindow <= 0 {
+		f.RecentWindow = defaultRecentWindow
+	}
+	if f.EstablishedAge <= 0 {
+		f.EstablishedAge = defaultEstablishedAge
+	}
+	if f.EstablishedAccessWindow <= 0 {
+		f.EstablishedAccessWindow = defaultEstablishedAccessWindow
+	}
+	return f
+}
+
+// ApplyFilters narrows filters to the valid_from range of bucket and returns
+// the result, allocating filters when nil. An existing ValidAfter or
+// ValidBefore is only replaced by a tighter bound. FreshnessAny and "" return
+// filters unchanged.
+func (f Freshness) ApplyFilters(bucket string, filters *store.SearchFilters, now time.Time) *store.SearchFilters {
+	if bucket != FreshnessRecent && bucket != FreshnessEstablished {
+		return filters
+	}
+	f = f.withDefaults()
+	if filters == nil {
+		filters = &store.SearchFilters{}
+	}
+	switch bucket {
+	case FreshnessRecent:
+		after := now.Add(-f.RecentWindow)
+		if filters.ValidAfter == nil || filters.ValidAfter.Before(after) {
+			filters.ValidAfter = &after
+		}
+	case FreshnessEstablished:
+		before := now.Add(-f.EstablishedAge)
+		if filters.ValidBefore == nil || filters.ValidBefore.After(before) {
+			filters.ValidBefore = &before
+		}
+	}
+	return filters
+}
+
+// FilterResults applies the part of bucket that the store cannot express: an
+// established memory must also have been accessed within
+// EstablishedAccessWindow. Other buckets return results unchanged.
+func (f Freshness) FilterResults(bucket string, results []models.SearchResult, now time.Time) []models.SearchResult {
+	if bucket != FreshnessEstablished {
+		return results
+	}
+	f = f.withDefaults()
+	cutoff := now.Add(-f.EstablishedAccessWindow)
+	out := make([]models.SearchResult, 0, len(results))
+	for i := range results {
+		if results[i].Memory.LastAccessed.After(cutoff) {
+			out = append(out, results[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package recall
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// queryIntent pairs a memory type with phrasings that ask for it.
+type queryIntent struct {
+	memType  models.MemoryType
+	patterns []*regexp.Regexp
+}
+
+// queryIntents are checked in order and the first match wins, so the more
+// specific asks ("what are the steps", "what are the rules") come before the
+// generic fact questions ("what is", "what are").
+var queryIntents = []queryIntent{
+	{models.MemoryTypeProcedure, compileIntentPatterns(
+		"how do i", "how do we", "how does one", "how to", "how can i", "how can we", "how should i",
+		"steps to", "what are the steps", "walk me through", "process for", "procedure for",
+	)},
+	{models.MemoryTypeRule, compileIntentPatterns(
+		"am i allowed", "are we allowed", "is it allowed", "is it ok to", "may i",
+		"should i", "should we", "must i", "must we", "what are the rules", "rules for", "policy",
+	)},
+	{models.MemoryTypePreference, compileIntentPatterns(
+		"do i prefer", "do i like", "what do i prefer", "which do i prefer", "my preference",
+		"my favorite", "my favourite", "do i usually",
+	)},
+	{models.MemoryTypeEpisode, compileIntentPatterns(
+		"what happened", "when did", "last time", "did we", "have we ever", "what went wrong",
+	)},
+	{models.MemoryTypeFact, compileIntentPatterns(
+		"what is", "what's", "what are", "where is", "where are", "who is", "which",
+	)},
+}
+
+func compileIntentPatterns(phrases ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(phrases))
+	for i, p := range phrases {
+		compiled[i] = regexp.MustCompile(`\b` + regexp.QuoteMeta(p) + `\b`)
+	}
+	return compiled
+}
+
+// DetectQueryType guesses which memory type a recall query is asking for
+// from its phrasing, e.g. "how do I deploy?" asks for a procedure. It
+// returns false when the query matches no known phrasing.
+func DetectQueryType(query string) (models.MemoryType, bool) {
+	q := strings.ToLower(query)
+	for _, intent := range queryIntents {
+		for _, re := range intent.patterns {
+			if re.MatchString(q) {
+				return intent.memType, true
+			}
+		}
+	}
+	return "", false
+}
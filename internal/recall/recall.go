@@ -13,6 +13,7 @@ import (
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/sentry"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
 )
 
 const (
@@ -22,6 +23,10 @@ const (
 	// recencyHalfLifeHours is the exponential decay half-life (7 days) for recency scoring.
 	recencyHalfLifeHours = 168.0
 
+	// writeRecencyHalfLifeHours is the half-life (1 day) of the write recency
+	// bonus, measured from a memory's creation time.
+	writeRecencyHalfLifeHours = 24.0
+
 	// ln2 is the natural log of 2, used in exponential decay calculations.
 	ln2 = 0.693
 
@@ -65,12 +70,15 @@ func DefaultWeights() Weights {
 	}
 }
 
-// Validate checks that the weights are non-negative and sum to approximately 1.0.
-func (w Weights) Validate() error {
-	fields := []struct {
-		name  string
-		value float64
-	}{
+// weightField pairs a weight's config/JSON name with its value.
+type weightField struct {
+	name  string
+	value float64
+}
+
+// fields lists the weights in declaration order.
+func (w Weights) fields() []weightField {
+	return []weightField{
 		{"similarity", w.Similarity},
 		{"recency", w.Recency},
 		{"frequency", w.Frequency},
@@ -81,6 +89,38 @@ func (w Weights) Validate() error {
 		{"tag_affinity", w.TagAffinity},
 		{"graph_proximity", w.GraphProximity},
 	}
+}
+
+// WeightNames returns the config/JSON names of the ranking weights, in
+// declaration order.
+func WeightNames() []string {
+	fields := Weights{}.fields()
+	names := make([]string, len(fields))
+	for i := range fields {
+		names[i] = fields[i].name
+	}
+	return names
+}
+
+// Signals returns the unweighted ranking signals of rr, one per weight, so
+// that a result can be compared against or re-scored under any Weights.
+func Signals(rr *models.RecallResult) Weights {
+	return Weights{
+		Similarity:     rr.SimilarityScore,
+		Recency:        rr.RecencyScore,
+		Frequency:      rr.FrequencyScore,
+		TypeBoost:      rr.TypeBoost,
+		ScopeBoost:     rr.ScopeBoost,
+		Confidence:     rr.ConfidenceScore,
+		Reinforcement:  rr.ReinforcementScore,
+		TagAffinity:    rr.TagAffinityScore,
+		GraphProximity: rr.GraphProximityScore,
+	}
+}
+
+// Validate checks that the weights are non-negative and sum to approximately 1.0.
+func (w Weights) Validate() error {
+	fields := w.fields()
 	for i := range fields {
 		if fields[i].value < 0 {
 			return fmt.Errorf("recall weight %q must be >= 0, got %f", fields[i].name, fields[i].value)
@@ -128,6 +168,11 @@ type Recaller struct {
 	graphDepth    int
 	vectorWeight  float64
 	graphWeight   float64
+
+	writeRecencyBoost float64  // 0 = no bonus for recently created memories
+	synonyms          Synonyms // nil = no query expansion
+	collapse          bool     // drop results superseded by another result
+	queryTypeBoost    float64  // 0 = no bonus for the query's detected memory type
 }
 
 // SetGraphClient attaches an optional graph client and backing store to the
@@ -156,6 +201,41 @@ func (r *Recaller) SetGraphWeights(vectorWeight, graphWeight float64) {
 	}
 }
 
+// SetWriteRecencyBoost adds a temporary ranking bonus for recently created
+// memories, distinct from the access-based recency signal: a memory gains
+// boost at creation, halving every day. boost <= 0 disables it.
+func (r *Recaller) SetWriteRecencyBoost(boost float64) {
+	r.writeRecencyBoost = math.Max(0, boost)
+}
+
+// SetQueryTypeBoost adds a ranking bonus, for a single query, to memories
+// of the type the query asks for (see DetectQueryType), so "how do I ..."
+// favours procedures without changing the global TypePriority table.
+// boost <= 0 disables it.
+func (r *Recaller) SetQueryTypeBoost(boost float64) {
+	r.queryTypeBoost = math.Max(0, boost)
+}
+
+// SetSynonyms enables query expansion from a term → expansion map (see
+// Synonyms.Expand). nil or empty disables it.
+func (r *Recaller) SetSynonyms(s map[string]string) {
+	r.synonyms = s
+}
+
+// SetCollapseSupersessions makes ranking drop results that are superseded by
+// another result in the same set (see CollapseSupersessions), so recall shows
+// one current version of an evolving fact instead of its history. When
+// disabled, superseded results are kept with SupersessionPenaltyFactor.
+func (r *Recaller) SetCollapseSupersessions(enabled bool) {
+	r.collapse = enabled
+}
+
+// ExpandQuery returns query with synonym expansions appended, for embedding
+// recall queries. It is a no-op unless SetSynonyms was given a map.
+func (r *Recaller) ExpandQuery(query string) string {
+	return r.synonyms.Expand(query)
+}
+
 // graphDepthOrDefault returns graphDepth if set, otherwise the package default.
 func (r *Recaller) graphDepthOrDefault() int {
 	if r.graphDepth > 0 {
@@ -193,6 +273,12 @@ func NewRecaller(weights Weights, logger *slog.Logger) *Recaller {
 	}
 }
 
+// Weights returns the weights the recaller ranks with, which are the
+// defaults when the configured weights were invalid.
+func (r *Recaller) Weights() Weights {
+	return r.weights
+}
+
 // Rank re-ranks search results using multi-factor scoring.
 // All component scores are normalized to [0,1] before weighting.
 // This is a thin wrapper around RankWithGraphProximity with a nil proximity map.
@@ -212,6 +298,11 @@ func (r *Recaller) RankWithGraphProximity(
 	now := time.Now().UTC()
 	ranked := make([]models.RecallResult, 0, len(results))
 
+	var queryType models.MemoryType
+	if r.queryTypeBoost > 0 {
+		queryType, _ = DetectQueryType(query)
+	}
+
 	// Build set of superseded IDs by scanning all results.
 	supersededIDs := make(map[string]struct{}, len(results))
 	for i := range results {
@@ -254,6 +345,11 @@ func (r *Recaller) RankWithGraphProximity(
 		freqScore := frequencyScore(sr.Memory.AccessCount)
 		tBoost := typeBoostScore(sr.Memory.Type)
 		sBoost := scopeBoostScore(sr.Memory, project)
+		wBoost := writeRecencyBonus(sr.Memory.CreatedAt, now, r.writeRecencyBoost)
+		qBoost := 0.0
+		if queryType != "" && sr.Memory.Type == queryType {
+			qBoost = r.queryTypeBoost
+		}
 
 		weightedSum := r.weights.Similarity*simScore +
 			r.weights.Recency*recScore +
@@ -265,7 +361,8 @@ func (r *Recaller) RankWithGraphProximity(
 			r.weights.TagAffinity*tagScore +
 			r.weights.GraphProximity*graphProximityScore
 
-		finalScore := weightedSum * supersessionPen * conflictPen
+		manualBoost := sr.Memory.RankBoost()
+		finalScore := (weightedSum + wBoost + qBoost) * supersessionPen * conflictPen * manualBoost
 
 		rr := models.RecallResult{
 			Memory:              sr.Memory,
@@ -278,6 +375,9 @@ func (r *Recaller) RankWithGraphProximity(
 			ReinforcementScore:  reinfScore,
 			TagAffinityScore:    tagScore,
 			GraphProximityScore: graphProximityScore,
+			WriteRecencyBoost:   wBoost,
+			QueryTypeBoost:      qBoost,
+			ManualBoost:         manualBoost,
 			SupersessionPenalty: supersessionPen,
 			ConflictPenalty:     conflictPen,
 			FinalScore:          finalScore,
@@ -291,9 +391,36 @@ func (r *Recaller) RankWithGraphProximity(
 		return ranked[i].FinalScore > ranked[j].FinalScore
 	})
 
+	if r.collapse {
+		ranked = CollapseSupersessions(ranked)
+	}
 	return ranked
 }
 
+// CollapseSupersessions returns ranked without the results that another
+// result in ranked supersedes, keeping rank order. When every version of a
+// chain is present only the newest survives. Chains are only followed
+// through results in ranked, so two versions whose connecting version is
+// missing are both kept.
+func CollapseSupersessions(ranked []models.RecallResult) []models.RecallResult {
+	superseded := make(map[string]struct{})
+	for i := range ranked {
+		if id := ranked[i].Memory.SupersedesID; id != "" && id != ranked[i].Memory.ID {
+			superseded[id] = struct{}{}
+		}
+	}
+	if len(superseded) == 0 {
+		return ranked
+	}
+	out := make([]models.RecallResult, 0, len(ranked))
+	for i := range ranked {
+		if _, ok := superseded[ranked[i].Memory.ID]; !ok {
+			out = append(out, ranked[i])
+		}
+	}
+	return out
+}
+
 // ShouldRerank returns true when the top-4 results are close enough in score
 // that Claude re-ranking may improve ordering. Returns false when:
 //   - threshold is <= 0 (feature disabled)
@@ -339,9 +466,41 @@ func (r *Recaller) RecallWithGraph(
 	embedding []float32,
 	searchResults []models.SearchResult,
 	project string,
+) []models.RecallResult {
+	return r.RecallWithGraphDepth(ctx, query, embedding, searchResults, project, 0)
+}
+
+// RecallWithGraphDepth is RecallWithGraph with a per-call entity expansion
+// depth: the graph is traversed up to depth relationship hops from the query
+// entities, and memories reached at hop h receive a graph proximity boost of
+// 0.5^(h-1). depth <= 0 uses the recaller's configured depth (SetGraphDepth).
+// Unlike SetGraphDepth it is safe to vary per request on a shared Recaller.
+func (r *Recaller) RecallWithGraphDepth(
+	ctx context.Context,
+	query string,
+	embedding []float32,
+	searchResults []models.SearchResult,
+	project string,
+	depth int,
 ) []models.RecallResult {
 	finish := sentry.StartSpan(ctx, "recall.with_graph", "Recaller.RecallWithGraph")
 	defer finish()
+	ctx, span := tracing.Start(ctx, "recall.rank",
+		"recall.candidates", len(searchResults), "recall.graph", r.graphClient != nil, "recall.graph_depth", depth)
+	defer span.End()
+	ranked := r.recallWithGraphDepth(ctx, query, embedding, searchResults, project, depth)
+	span.SetAttributes("result.count", len(ranked))
+	return ranked
+}
+
+func (r *Recaller) recallWithGraphDepth(
+	ctx context.Context,
+	query string,
+	embedding []float32,
+	searchResults []models.SearchResult,
+	project string,
+	depth int,
+) []models.RecallResult {
 	if r.graphClient == nil {
 		return r.Rank(searchResults, project, query)
 	}
@@ -354,15 +513,19 @@ func (r *Recaller) RecallWithGraph(
 	gCtx, cancel := context.WithTimeout(ctx, time.Duration(budgetMs)*time.Millisecond)
 	defer cancel()
 
+	if depth <= 0 {
+		depth = r.graphDepthOrDefault()
+	}
+
 	// Support configurable depth with optional hop-distance tracking.
 	// Prefer depthRecallerWithHops (returns hop distances) → depthRecaller → base interface.
 	var graphIDs []string
 	var graphMemoryHops map[string]int
 	var err error
 	if dh, ok := r.graphClient.(depthRecallerWithHops); ok {
-		graphIDs, graphMemoryHops, err = dh.RecallByGraphWithHops(gCtx, query, embedding, 50, r.graphDepthOrDefault())
+		graphIDs, graphMemoryHops, err = dh.RecallByGraphWithHops(gCtx, query, embedding, 50, depth)
 	} else if dr, ok := r.graphClient.(depthRecaller); ok {
-		graphIDs, err = dr.RecallByGraphWithDepth(gCtx, query, embedding, 50, r.graphDepthOrDefault())
+		graphIDs, err = dr.RecallByGraphWithDepth(gCtx, query, embedding, 50, depth)
 	} else {
 		graphIDs, err = r.graphClient.RecallByGraph(gCtx, query, embedding, 50)
 	}
@@ -372,19 +535,12 @@ func (r *Recaller) RecallWithGraph(
 	}
 
 	// Build a proximityMap from hop distances (when available).
-	// 1-hop → 1.0, 2-hop → 0.5, 3+-hop → 0.25.
+	// The boost halves with every hop: 1-hop → 1.0, 2-hop → 0.5, 3-hop → 0.25, ...
 	var proximityMap map[string]float64
 	if graphMemoryHops != nil {
 		proximityMap = make(map[string]float64, len(graphMemoryHops))
 		for memID, hopDist := range graphMemoryHops {
-			switch hopDist {
-			case 1:
-				proximityMap[memID] = 1.0
-			case 2:
-				proximityMap[memID] = 0.5
-			default:
-				proximityMap[memID] = 0.25
-			}
+			proximityMap[memID] = hopProximity(hopDist)
 		}
 	}
 
@@ -413,21 +569,32 @@ func (r *Recaller) RecallWithGraph(
 		existing[id] = struct{}{}
 	}
 
-	// Add graph-only memories (not in vector results).
+	// Add graph-only memories (not in vector results), fetched in one batch.
+	var missingIDs []string
 	for _, id := range graphIDs {
-		if _, ok := existing[id]; ok {
-			continue
+		if _, ok := existing[id]; !ok {
+			missingIDs = append(missingIDs, id)
 		}
-		mem, fetchErr := r.store.Get(ctx, id)
+	}
+	if len(missingIDs) > 0 {
+		graphMems, fetchErr := r.store.GetMany(ctx, missingIDs)
 		if fetchErr != nil {
-			r.logger.Warn("failed to fetch graph memory", "id", id, "error", fetchErr)
-			continue
+			r.logger.Warn("failed to fetch graph memories", "count", len(missingIDs), "error", fetchErr)
+		}
+		for _, id := range missingIDs {
+			mem, ok := graphMems[id]
+			if !ok {
+				continue
+			}
+			if _, dup := existing[id]; dup {
+				continue
+			}
+			merged = append(merged, models.SearchResult{
+				Memory: mem,
+				Score:  blended[id],
+			})
+			existing[id] = struct{}{}
 		}
-		merged = append(merged, models.SearchResult{
-			Memory: *mem,
-			Score:  blended[id],
-		})
-		existing[id] = struct{}{}
 	}
 
 	// Community sweep: when the query is short and targets exactly one known entity,
@@ -443,6 +610,31 @@ func (r *Recaller) RecallWithGraph(
 	return r.RankWithGraphProximity(merged, project, query, proximityMap)
 }
 
+// TopIDs returns the memory IDs of the first n results (all of them when n
+// exceeds len(results)), e.g. for a single UpdateAccessMetadataBatch call.
+func TopIDs(results []models.RecallResult, n int) []string {
+	if n > len(results) {
+		n = len(results)
+	}
+	if n <= 0 {
+		return nil
+	}
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		ids[i] = results[i].Memory.ID
+	}
+	return ids
+}
+
+// hopProximity returns the graph proximity boost for a memory reached at the
+// given hop distance: 0.5^(hop-1), with hop distances below 1 treated as 1.
+func hopProximity(hop int) float64 {
+	if hop < 1 {
+		hop = 1
+	}
+	return math.Pow(0.5, float64(hop-1))
+}
+
 // communitySweep applies a broad entity query heuristic: when the query is short
 // (< 10 words) and exactly one known entity name is found in the query, it fetches
 // memories from that entity's community and merges them into the result set.
@@ -482,19 +674,33 @@ func (r *Recaller) communitySweep(
 		return merged
 	}
 
+	var newIDs []string
 	for _, memID := range communityMemIDs {
-		if _, ok := existing[memID]; ok {
+		if _, ok := existing[memID]; !ok {
+			newIDs = append(newIDs, memID)
+		}
+	}
+	if len(newIDs) == 0 {
+		return merged
+	}
+	communityMems, err := r.store.GetMany(ctx, newIDs)
+	if err != nil {
+		r.logger.Warn("community sweep: failed to fetch memories", "count", len(newIDs), "error", err)
+		return merged
+	}
+
+	for _, memID := range newIDs {
+		mem, ok := communityMems[memID]
+		if !ok {
 			continue
 		}
-		mem, fetchErr := r.store.Get(ctx, memID)
-		if fetchErr != nil {
-			r.logger.Warn("community sweep: failed to fetch memory", "id", memID, "error", fetchErr)
+		if _, dup := existing[memID]; dup {
 			continue
 		}
 		// Assign a modest blended score (lower than graph-traversal results).
 		blended[memID] = 1.0 / float64(1+rrfK)
 		merged = append(merged, models.SearchResult{
-			Memory: *mem,
+			Memory: mem,
 			Score:  blended[memID],
 		})
 		existing[memID] = struct{}{}
@@ -541,6 +747,16 @@ func recencyScore(lastAccessed time.Time, now time.Time) float64 {
 	return math.Exp(-ln2 * hoursAgo / recencyHalfLifeHours)
 }
 
+// writeRecencyBonus decays boost exponentially with the time since createdAt.
+// Returns 0 when boost is disabled or the creation time is unknown.
+func writeRecencyBonus(createdAt, now time.Time, boost float64) float64 {
+	if boost <= 0 || createdAt.IsZero() {
+		return 0
+	}
+	hoursAgo := math.Max(0, now.Sub(createdAt).Hours())
+	return boost * math.Exp(-ln2*hoursAgo/writeRecencyHalfLifeHours)
+}
+
 // frequencyScore uses log scale on access count. Returns [0,1].
 func frequencyScore(accessCount int64) float64 {
 	if accessCount <= 0 {
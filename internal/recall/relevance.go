@@ -0,0 +1,154 @@
+package recall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/xmlutil"
+)
+
+const (
+	// defaultFilterCandidates is how many results the RelevanceFilter asks
+	// Claude to judge per call.
+	defaultFilterCandidates = 10
+
+	// DefaultFilterJudged is how many top-ranked results the RelevanceFilter
+	// judges when no limit is set; lower-ranked results pass through.
+	DefaultFilterJudged = 30
+
+	// filterConcurrency caps the Claude calls one Filter makes at once.
+	filterConcurrency = 4
+
+	// filterMaxTokens is the maximum tokens Claude can use for the verdict.
+	filterMaxTokens = 256
+)
+
+// RelevanceFilter uses Claude to drop recall results that are topically
+// similar to the query but do not help answer it. Unlike the Reasoner it
+// removes results rather than reordering them, trading a Claude round-trip
+// per recall for fewer false positives.
+//
+// On any API failure the filter degrades gracefully and keeps every result.
+type RelevanceFilter struct {
+	client llm.LLMClient
+	model  string
+	logger *slog.Logger
+	judged int // top results judged per Filter; 0 = DefaultFilterJudged
+}
+
+// NewRelevanceFilter creates a RelevanceFilter backed by the given LLM client.
+func NewRelevanceFilter(client llm.LLMClient, model string, logger *slog.Logger) *RelevanceFilter {
+	return &RelevanceFilter{
+		client: client,
+		model:  model,
+		logger: logger,
+	}
+}
+
+// SetJudged sets how many top-ranked results Filter judges; results beyond
+// them pass through unjudged. n <= 0 selects DefaultFilterJudged.
+func (f *RelevanceFilter) SetJudged(n int) {
+	f.judged = n
+}
+
+// Filter asks Claude which of the top-ranked results are relevant to query
+// and returns those, in their original order, followed by the unjudged
+// remainder. The top results (see SetJudged) are judged in batches of
+// maxCandidates, at most filterConcurrency Claude calls at a time. Call it
+// after ranking and before the token budget is applied.
+//
+// If maxCandidates <= 0, defaultFilterCandidates is used. An empty query
+// (recall mode "top") leaves results untouched. On any error (API failure,
+// unexpected response) every result in the affected batch is kept.
+func (f *RelevanceFilter) Filter(ctx context.Context, query string, results []models.RecallResult, maxCandidates int) []models.RecallResult {
+	if len(results) == 0 || strings.TrimSpace(query) == "" {
+		return results
+	}
+	if maxCandidates <= 0 {
+		maxCandidates = defaultFilterCandidates
+	}
+	judged := f.judged
+	if judged <= 0 {
+		judged = DefaultFilterJudged
+	}
+	candidates, tail := results, []models.RecallResult(nil)
+	if len(results) > judged {
+		candidates, tail = results[:judged], results[judged:]
+	}
+
+	batches := make([][]models.RecallResult, (len(candidates)+maxCandidates-1)/maxCandidates)
+	sem := make(chan struct{}, filterConcurrency)
+	var wg sync.WaitGroup
+	for i := range batches {
+		batch := candidates[i*maxCandidates : min((i+1)*maxCandidates, len(candidates))]
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			batches[i] = f.judge(ctx, query, batch)
+		})
+	}
+	wg.Wait()
+
+	filtered := make([]models.RecallResult, 0, len(results))
+	for _, kept := range batches {
+		filtered = append(filtered, kept...)
+	}
+	f.logger.Debug("relevance filter: judged results", "candidates", len(candidates), "kept", len(filtered), "unjudged", len(tail))
+	return append(filtered, tail...)
+}
+
+// judge asks Claude which of candidates are relevant to query and returns
+// those in their original order, or every candidate on error.
+func (f *RelevanceFilter) judge(ctx context.Context, query string, candidates []models.RecallResult) []models.RecallResult {
+	var sb strings.Builder
+	for i := range candidates {
+		fmt.Fprintf(&sb, "[%d] %s\n", i, xmlutil.Escape(candidates[i].Memory.Content))
+	}
+
+	prompt := fmt.Sprintf(`You are a memory relevance judge for an AI agent memory system.
+
+Given the query and a numbered list of memory snippets, decide for each snippet whether it helps answer or act on the query. A snippet that is merely about the same topic is NOT relevant.
+
+Output ONLY a valid JSON array of the indices of the relevant snippets, nothing else. Output [] if none are relevant. Example: [0, 3]
+
+<query>%s</query>
+
+<memories>
+%s</memories>`,
+		xmlutil.Escape(query),
+		sb.String(),
+	)
+
+	responseText, err := f.client.Complete(ctx, f.model, "", prompt, filterMaxTokens)
+	if err != nil {
+		f.logger.Warn("relevance filter: Claude API call failed, keeping all results", "error", err)
+		return candidates
+	}
+
+	var keep []int
+	if err := json.Unmarshal([]byte(llm.StripCodeFences(responseText)), &keep); err != nil {
+		f.logger.Warn("relevance filter: could not parse Claude response, keeping all results",
+			"response", responseText, "error", err)
+		return candidates
+	}
+
+	relevant := make(map[int]bool, len(keep))
+	for _, idx := range keep {
+		if idx >= 0 && idx < len(candidates) {
+			relevant[idx] = true
+		}
+	}
+	kept := make([]models.RecallResult, 0, len(relevant))
+	for i := range candidates {
+		if relevant[i] {
+			kept = append(kept, candidates[i])
+		}
+	}
+	return kept
+}
@@ -0,0 +1,74 @@
+package recall
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Synonyms maps terms, typically acronyms, to their expansions ("rbac" →
+// "role-based access control") for recall query expansion.
+type Synonyms map[string]string
+
+// Expand appends to query the counterpart of every synonym term it mentions,
+// in both directions: a query naming "RBAC" gains "role-based access
+// control" and a query spelling out the expansion gains "RBAC". Terms match
+// case-insensitively on word boundaries. The query is returned unchanged when
+// nothing matches, so embeddings of plain queries are unaffected.
+func (s Synonyms) Expand(query string) string {
+	if len(s) == 0 {
+		return query
+	}
+	lower := strings.ToLower(query)
+
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var extra []string
+	for _, k := range keys {
+		term := strings.ToLower(strings.TrimSpace(k))
+		expansion := strings.ToLower(strings.TrimSpace(s[k]))
+		if term == "" || expansion == "" {
+			continue
+		}
+		hasTerm, hasExpansion := containsWord(lower, term), containsWord(lower, expansion)
+		switch {
+		case hasTerm && !hasExpansion:
+			extra = append(extra, strings.TrimSpace(s[k]))
+		case hasExpansion && !hasTerm:
+			extra = append(extra, strings.TrimSpace(k))
+		}
+	}
+	if len(extra) == 0 {
+		return query
+	}
+	return query + " (" + strings.Join(extra, ", ") + ")"
+}
+
+// containsWord reports whether phrase occurs in text with no letter or
+// digit directly before or after it. Both must already be lowercased.
+func containsWord(text, phrase string) bool {
+	for start := 0; start <= len(text)-len(phrase); {
+		i := strings.Index(text[start:], phrase)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(phrase)
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (i == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,87 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/llm"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/xmlutil"
+)
+
+const (
+	// ModeList returns the recalled memories as a formatted list (the default).
+	ModeList = "list"
+
+	// ModeSynthesize returns a single summary paragraph written by Claude
+	// from the recalled memories.
+	ModeSynthesize = "synthesize"
+
+	// synthesizerMaxTokens bounds the length of a synthesized summary.
+	synthesizerMaxTokens = 1024
+)
+
+// ValidMode reports whether mode is a recognised recall mode. The empty
+// string is accepted and means ModeList.
+func ValidMode(mode string) bool {
+	return mode == "" || mode == ModeList || mode == ModeSynthesize || mode == ModeTop
+}
+
+// Synthesizer uses Claude to turn the memories selected by recall into one
+// coherent paragraph answering the query. It adds a Claude round-trip to
+// every recall, so callers opt in per request.
+type Synthesizer struct {
+	client llm.LLMClient
+	model  string
+	logger *slog.Logger
+}
+
+// NewSynthesizer creates a Synthesizer backed by the given LLM client.
+func NewSynthesizer(client llm.LLMClient, model string, logger *slog.Logger) *Synthesizer {
+	return &Synthesizer{
+		client: client,
+		model:  model,
+		logger: logger,
+	}
+}
+
+// Synthesize returns a summary of memories grounded in their content and
+// focused on query. memories should already be ranked and budgeted. An empty
+// memories slice yields an empty summary without calling Claude. On failure
+// an error is returned and callers should fall back to the memory list.
+func (s *Synthesizer) Synthesize(ctx context.Context, query string, memories []string) (string, error) {
+	if len(memories) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	for i := range memories {
+		fmt.Fprintf(&sb, "[%d] %s\n", i, xmlutil.Escape(memories[i]))
+	}
+
+	prompt := fmt.Sprintf(`You are summarizing an AI agent's long-term memory.
+
+Using ONLY the numbered memories below, write one concise paragraph that answers or gives context for the query. Do not add facts that are not in the memories. If memories conflict, say so. If none are relevant, say that nothing relevant is remembered.
+
+Output only the paragraph.
+
+<query>%s</query>
+
+<memories>
+%s</memories>`,
+		xmlutil.Escape(query),
+		sb.String(),
+	)
+
+	responseText, err := s.client.Complete(ctx, s.model, "", prompt, synthesizerMaxTokens)
+	if err != nil {
+		return "", fmt.Errorf("synthesizer: calling Claude: %w", err)
+	}
+	responseText = strings.TrimSpace(responseText)
+	if responseText == "" {
+		return "", fmt.Errorf("synthesizer: empty response from Claude")
+	}
+	s.logger.Debug("synthesized recall context", "memories", len(memories), "length", len(responseText))
+	return responseText, nil
+}
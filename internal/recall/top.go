@@ -0,0 +1,47 @@
+package recall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+// ModeTop recalls without a query: the newest TopCandidateLimit memories
+// matching the filters are ranked by the non-similarity factors alone
+// (recency, frequency, type, scope, confidence, ...), for dashboards and
+// session-start context.
+const ModeTop = "top"
+
+// TopCandidateLimit caps how many memories ModeTop ranks, newest first, so
+// a large collection is not scanned in full.
+const TopCandidateLimit = 1000
+
+// topPageSize is the List page size used to collect ModeTop candidates.
+const topPageSize = 250
+
+// TopCandidates lists up to limit memories matching filters, newest first,
+// as search results with a zero similarity score. Ranking them with an
+// empty query leaves every memory the same similarity and tag-affinity
+// component, so order is decided by the remaining factors.
+func TopCandidates(ctx context.Context, st store.Store, filters *store.SearchFilters, limit int) ([]models.SearchResult, error) {
+	var (
+		results []models.SearchResult
+		cursor  string
+	)
+	for len(results) < limit {
+		page, next, err := st.List(ctx, filters, uint64(min(topPageSize, limit-len(results))), cursor) //nolint:gosec // bounded by limit
+		if err != nil {
+			return nil, fmt.Errorf("recall: listing top candidates: %w", err)
+		}
+		for i := range page {
+			results = append(results, models.SearchResult{Memory: page[i]})
+		}
+		if next == "" || len(page) == 0 {
+			break
+		}
+		cursor = next
+	}
+	return results, nil
+}
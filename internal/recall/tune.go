@@ -0,0 +1,235 @@
+package recall
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// TuneExample is one logged recall joined with its feedback.
+type TuneExample struct {
+	Results []FeedbackResult
+	Useful  map[string]bool
+}
+
+// LoadTuneExamples reads a feedback log and joins each recall with the
+// feedback given for it. Recalls without any useful memory among their
+// results carry no ranking signal and are dropped.
+func LoadTuneExamples(r io.Reader) ([]TuneExample, error) {
+	recalls := make(map[string][]FeedbackResult)
+	useful := make(map[string]map[string]bool)
+	var order []string
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e FeedbackEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("recall: feedback log line %d: %w", line, err)
+		}
+		switch e.Event {
+		case FeedbackEventRecall:
+			if _, seen := recalls[e.RecallID]; !seen {
+				order = append(order, e.RecallID)
+			}
+			recalls[e.RecallID] = e.Results
+		case FeedbackEventFeedback:
+			set := useful[e.RecallID]
+			if set == nil {
+				set = make(map[string]bool)
+				useful[e.RecallID] = set
+			}
+			for _, id := range e.Useful {
+				set[id] = true
+			}
+			for _, id := range e.NotUseful {
+				delete(set, id)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("recall: reading feedback log: %w", err)
+	}
+
+	var examples []TuneExample
+	for _, id := range order {
+		ex := TuneExample{Results: recalls[id], Useful: useful[id]}
+		for i := range ex.Results {
+			if ex.Useful[ex.Results[i].ID] {
+				examples = append(examples, ex)
+				break
+			}
+		}
+	}
+	return examples, nil
+}
+
+// TuneMetric scores a set of weights against the examples.
+type TuneMetric struct {
+	HitRate float64 `json:"hit_rate"` // share of recalls with a useful memory in the top K
+	MRR     float64 `json:"mrr"`      // mean reciprocal rank of the first useful memory
+}
+
+// better reports whether m beats o, comparing HitRate first and MRR second.
+func (m TuneMetric) better(o TuneMetric) bool {
+	const eps = 1e-9
+	if m.HitRate > o.HitRate+eps {
+		return true
+	}
+	return m.HitRate > o.HitRate-eps && m.MRR > o.MRR+eps
+}
+
+// Evaluate re-ranks each example under w and measures how often a useful
+// memory lands in the top k.
+func Evaluate(w Weights, examples []TuneExample, k int) TuneMetric {
+	if len(examples) == 0 {
+		return TuneMetric{}
+	}
+	var hits, rr float64
+	var scores []float64
+	var idx []int
+	for e := range examples {
+		ex := &examples[e]
+		scores = scores[:0]
+		idx = idx[:0]
+		for i := range ex.Results {
+			scores = append(scores, w.score(ex.Results[i]))
+			idx = append(idx, i)
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return scores[idx[a]] > scores[idx[b]] })
+		for rank, i := range idx {
+			if ex.Useful[ex.Results[i].ID] {
+				if rank < k {
+					hits++
+				}
+				rr += 1 / float64(rank+1)
+				break
+			}
+		}
+	}
+	n := float64(len(examples))
+	return TuneMetric{HitRate: hits / n, MRR: rr / n}
+}
+
+// score mirrors the ranking formula in Recaller.Rank.
+func (w Weights) score(r FeedbackResult) float64 {
+	ws, ss := w.fields(), r.Signals.fields()
+	var sum float64
+	for i := range ws {
+		sum += ws[i].value * ss[i].value
+	}
+	return (sum + r.Bonus) * r.Penalty
+}
+
+// TuneResult reports the outcome of Tune.
+type TuneResult struct {
+	Weights  Weights    `json:"weights"`
+	Baseline TuneMetric `json:"baseline"`
+	Best     TuneMetric `json:"best"`
+	Examples int        `json:"examples"`
+}
+
+// Tune searches for weights that maximise Evaluate on examples, starting
+// from start. It runs coordinate descent: each weight in turn is nudged up or
+// down while the others are rescaled so the total stays 1.0, keeping every
+// candidate valid under Weights.Validate. The step halves whenever a full
+// pass finds no improvement.
+func Tune(start Weights, examples []TuneExample, k int) TuneResult {
+	cur := start.normalized()
+	best := Evaluate(cur, examples, k)
+	res := TuneResult{Baseline: Evaluate(start, examples, k), Examples: len(examples)}
+
+	for step := 0.2; step >= 0.005; {
+		improved := false
+		for i := range cur.fields() {
+			for _, delta := range []float64{step, -step} {
+				cand, ok := cur.shift(i, delta)
+				if !ok {
+					continue
+				}
+				if m := Evaluate(cand, examples, k); m.better(best) {
+					cur, best, improved = cand, m, true
+				}
+			}
+		}
+		if !improved {
+			step /= 2
+		}
+	}
+
+	res.Weights = cur
+	res.Best = best
+	return res
+}
+
+// shift moves weight i by delta and rescales the others so the weights still
+// sum to 1. It reports false when the move would leave the valid range.
+func (w Weights) shift(i int, delta float64) (Weights, bool) {
+	vals := w.Values()
+	target := vals[i] + delta
+	if target < 0 || target > 1 {
+		return w, false
+	}
+	rest := 1 - vals[i]
+	for j := range vals {
+		if j == i {
+			continue
+		}
+		if rest > 0 {
+			vals[j] *= (1 - target) / rest
+		} else {
+			vals[j] = (1 - target) / float64(len(vals)-1)
+		}
+	}
+	vals[i] = target
+	return weightsFromValues(vals), true
+}
+
+// normalized rescales w to sum to 1, falling back to DefaultWeights when all
+// weights are zero.
+func (w Weights) normalized() Weights {
+	vals := w.Values()
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	if sum <= 0 {
+		return DefaultWeights()
+	}
+	for i := range vals {
+		vals[i] /= sum
+	}
+	return weightsFromValues(vals)
+}
+
+// Values returns the weights in WeightNames order.
+func (w Weights) Values() []float64 {
+	fields := w.fields()
+	vals := make([]float64, len(fields))
+	for i := range fields {
+		vals[i] = fields[i].value
+	}
+	return vals
+}
+
+// weightsFromValues is the inverse of Values.
+func weightsFromValues(v []float64) Weights {
+	return Weights{
+		Similarity:     v[0],
+		Recency:        v[1],
+		Frequency:      v[2],
+		TypeBoost:      v[3],
+		ScopeBoost:     v[4],
+		Confidence:     v[5],
+		Reinforcement:  v[6],
+		TagAffinity:    v[7],
+		GraphProximity: v[8],
+	}
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// deleteByFilterPageSize is the List page size DeleteByFilter collects IDs with.
+const deleteByFilterPageSize = 100
+
+// DeleteByFilter deletes up to limit memories matching filters and reports
+// how many were deleted and whether more matches remain. With trash set the
+// memories are moved to the trash (see Store.Trash) instead of being removed.
+// Matching IDs are collected before anything is deleted, so deletion cannot
+// disturb the List cursor. A nil or empty filter is rejected: deleting every
+// memory is what "reset" is for.
+func DeleteByFilter(ctx context.Context, st Store, filters *SearchFilters, limit int, trash bool) (deleted int, more bool, err error) {
+	if !narrows(filters) {
+		return 0, false, errors.New("delete by filter: at least one filter is required")
+	}
+	if limit <= 0 {
+		return 0, false, fmt.Errorf("delete by filter: limit must be > 0, got %d", limit)
+	}
+
+	var ids []string
+	cursor := ""
+	for len(ids) <= limit {
+		page, next, listErr := st.List(ctx, filters, deleteByFilterPageSize, cursor)
+		if listErr != nil {
+			return 0, false, fmt.Errorf("delete by filter: listing: %w", listErr)
+		}
+		for i := range page {
+			ids = append(ids, page[i].ID)
+		}
+		if next == "" || len(page) == 0 {
+			break
+		}
+		cursor = next
+	}
+	if len(ids) > limit {
+		ids, more = ids[:limit], true
+	}
+
+	for _, id := range ids {
+		if trash {
+			_, err = st.Trash(ctx, id)
+		} else {
+			err = st.Delete(ctx, id)
+		}
+		if errors.Is(err, ErrNotFound) {
+			continue // deleted concurrently
+		}
+		if err != nil {
+			return deleted, true, fmt.Errorf("delete by filter: deleting %s: %w", id, err)
+		}
+		deleted++
+	}
+	return deleted, more, nil
+}
+
+// narrows reports whether f restricts which memories match. Options such as
+// IncludeInvalidated widen or shift a search without narrowing it.
+func narrows(f *SearchFilters) bool {
+	if f == nil {
+		return false
+	}
+	return f.Type != nil || f.Scope != nil || f.Visibility != nil ||
+		(f.Project != nil && *f.Project != "") || len(f.Projects) > 0 ||
+		len(f.Tags) > 0 || f.Source != nil || f.ConflictStatus != nil ||
+		f.UserID != "" || f.SessionID != "" || f.PreferenceSubject != "" ||
+		f.CreatedAfter != nil || f.CreatedBefore != nil ||
+		f.AccessedAfter != nil || f.AccessedBefore != nil || f.ExpiresBefore != nil
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// ContainsPhrase reports whether content contains phrase, ignoring case.
+// An empty phrase matches everything.
+func ContainsPhrase(content, phrase string) bool {
+	if phrase == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(content), strings.ToLower(phrase))
+}
+
+// FilterByContent returns the results whose memory content contains phrase
+// (case-insensitive), preserving order. It is applied on top of vector search
+// to add a hard keyword constraint to semantic results. An empty phrase
+// returns results unchanged.
+func FilterByContent(results []models.SearchResult, phrase string) []models.SearchResult {
+	if phrase == "" {
+		return results
+	}
+	out := make([]models.SearchResult, 0, len(results))
+	for i := range results {
+		if ContainsPhrase(results[i].Memory.Content, phrase) {
+			out = append(out, results[i])
+		}
+	}
+	return out
+}
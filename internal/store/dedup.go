@@ -5,8 +5,77 @@ import (
 	"fmt"
 	"sort"
 	"time"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 )
 
+// dedupScopedSearchLimit is how many nearest neighbours a scoped dedup check
+// inspects; it matches the candidate count of the unscoped FindDuplicates.
+const dedupScopedSearchLimit = 10
+
+// DedupScope restricts which existing memories a store-time dedup check
+// compares against. Most true duplicates are recent and of the same type, so
+// narrowing the comparison set trades a little recall for a cheaper check.
+// The zero value compares against the whole collection.
+type DedupScope struct {
+	// SameType only compares against memories of the new memory's type.
+	SameType bool
+
+	// Window only compares against memories created within this duration
+	// of now. Zero means no time limit.
+	Window time.Duration
+}
+
+// IsZero reports whether s places no restriction on the comparison set.
+func (s DedupScope) IsZero() bool {
+	return !s.SameType && s.Window <= 0
+}
+
+// FindDuplicatesInScope is like Store.FindDuplicates but only considers
+// memories inside scope. memType is the type of the memory being stored and
+// is only used when scope.SameType is set. A zero scope delegates to
+// FindDuplicates unchanged. Scoped checks go through Search, so sensitive
+// memories are not compared against.
+func FindDuplicatesInScope(ctx context.Context, st Store, vector []float32, threshold float64, memType models.MemoryType, scope DedupScope) ([]models.SearchResult, error) {
+	if scope.IsZero() {
+		return st.FindDuplicates(ctx, vector, threshold)
+	}
+
+	filters := &SearchFilters{IncludeInvalidated: true}
+	if scope.SameType && memType != "" {
+		filters.Type = &memType
+	}
+	if scope.Window > 0 {
+		since := time.Now().UTC().Add(-scope.Window)
+		filters.ValidAfter = &since
+	}
+
+	results, err := st.Search(ctx, vector, dedupScopedSearchLimit, filters)
+	if err != nil {
+		return nil, err
+	}
+	dupes := results[:0]
+	for i := range results {
+		if results[i].Score >= threshold {
+			dupes = append(dupes, results[i])
+		}
+	}
+	return dupes, nil
+}
+
+// CheckDuplicates runs the store-time dedup check without storing anything:
+// it returns the memories in scope whose similarity to vector is at least
+// threshold, most similar first, so a caller can decide to store, skip or
+// update before writing.
+func CheckDuplicates(ctx context.Context, st Store, vector []float32, threshold float64, memType models.MemoryType, scope DedupScope) ([]models.SearchResult, error) {
+	dupes, err := FindDuplicatesInScope(ctx, st, vector, threshold, memType, scope)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(dupes, func(i, j int) bool { return dupes[i].Score > dupes[j].Score })
+	return dupes, nil
+}
+
 // DedupResult describes the outcome of a store-time deduplication check.
 type DedupResult struct {
 	// IsDuplicate is true when a near-identical memory was found and the new
@@ -38,7 +107,13 @@ type DedupResult struct {
 // the caller); it is reused when updating the existing memory to avoid a
 // redundant embedding call.
 func CheckAndHandleDuplicate(ctx context.Context, st Store, vec []float32, newContent string, threshold float64) (DedupResult, error) {
-	dupes, err := st.FindDuplicates(ctx, vec, threshold)
+	return CheckAndHandleDuplicateInScope(ctx, st, vec, newContent, threshold, "", DedupScope{})
+}
+
+// CheckAndHandleDuplicateInScope is CheckAndHandleDuplicate with the
+// comparison set narrowed by scope (see FindDuplicatesInScope).
+func CheckAndHandleDuplicateInScope(ctx context.Context, st Store, vec []float32, newContent string, threshold float64, memType models.MemoryType, scope DedupScope) (DedupResult, error) {
+	dupes, err := FindDuplicatesInScope(ctx, st, vec, threshold, memType, scope)
 	if err != nil {
 		return DedupResult{}, fmt.Errorf("dedup: finding duplicates: %w", err)
 	}
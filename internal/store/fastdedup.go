@@ -0,0 +1,230 @@
+package store
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	// minHashSize is the number of hash functions in each MinHash signature.
+	minHashSize = 64
+
+	// lshBands × lshRows must equal minHashSize. With 16 bands of 4 rows the
+	// LSH candidate threshold sits around Jaccard 0.5, comfortably below any
+	// useful FastDedupThreshold, so true near-duplicates are rarely missed.
+	lshBands = 16
+	lshRows  = minHashSize / lshBands
+
+	// shingleWidth is the number of consecutive words in each shingle.
+	shingleWidth = 3
+
+	// DefaultFastDedupThreshold is the estimated Jaccard similarity above which
+	// ShingleIndex treats two texts as duplicates.
+	DefaultFastDedupThreshold = 0.9
+
+	// SharedShingleIndexCapacity bounds the texts held by each index returned
+	// by SharedShingleIndex (roughly 0.5 KB per text).
+	SharedShingleIndexCapacity = 50000
+)
+
+var (
+	sharedShinglesMu sync.Mutex
+	sharedShingles   = map[float64]*ShingleIndex{}
+)
+
+// minHashSeeds are fixed per-function seeds so signatures are stable across runs.
+var minHashSeeds = func() [minHashSize]uint64 {
+	var seeds [minHashSize]uint64
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range seeds {
+		x = splitMix64(x)
+		seeds[i] = x
+	}
+	return seeds
+}()
+
+type minHashSignature [minHashSize]uint64
+
+// ShingleIndex is a cheap, in-memory textual near-duplicate detector based on
+// MinHash signatures over word shingles, bucketed with locality-sensitive
+// hashing. It is intended as a pre-filter in front of the embedding +
+// vector-search dedup during bulk ingestion: content that is textually almost
+// identical to something already seen in the same run is rejected before an
+// embedding is computed. Semantic (reworded) duplicates are still left to
+// FindDuplicates.
+//
+// ShingleIndex is safe for concurrent use.
+type ShingleIndex struct {
+	mu         sync.Mutex
+	threshold  float64
+	capacity   int // 0 = unbounded
+	signatures []minHashSignature
+	buckets    [lshBands]map[uint64][]int
+}
+
+// NewShingleIndex creates an empty index. threshold is the estimated Jaccard
+// similarity (0, 1] at or above which content is considered a duplicate;
+// values <= 0 select DefaultFastDedupThreshold.
+func NewShingleIndex(threshold float64) *ShingleIndex {
+	if threshold <= 0 {
+		threshold = DefaultFastDedupThreshold
+	}
+	idx := &ShingleIndex{threshold: threshold}
+	for b := range idx.buckets {
+		idx.buckets[b] = make(map[uint64][]int)
+	}
+	return idx
+}
+
+// NewBoundedShingleIndex is like NewShingleIndex but holds at most capacity
+// texts: once full, the oldest half is forgotten. capacity <= 0 = unbounded.
+func NewBoundedShingleIndex(threshold float64, capacity int) *ShingleIndex {
+	idx := NewShingleIndex(threshold)
+	idx.capacity = max(capacity, 0)
+	return idx
+}
+
+// SharedShingleIndex returns the process-wide index for threshold, bounded
+// by SharedShingleIndexCapacity. Callers in one process (hooks, import)
+// share it, so a text seen by an earlier call is caught by a later one.
+func SharedShingleIndex(threshold float64) *ShingleIndex {
+	if threshold <= 0 {
+		threshold = DefaultFastDedupThreshold
+	}
+	sharedShinglesMu.Lock()
+	defer sharedShinglesMu.Unlock()
+	idx, ok := sharedShingles[threshold]
+	if !ok {
+		idx = NewBoundedShingleIndex(threshold, SharedShingleIndexCapacity)
+		sharedShingles[threshold] = idx
+	}
+	return idx
+}
+
+// CheckAndAdd reports whether content is a near-duplicate of anything already
+// in the index. Content that is not a duplicate is added so later calls can
+// match against it. Empty content is never reported as a duplicate.
+func (idx *ShingleIndex) CheckAndAdd(content string) bool {
+	sig, ok := minHashOf(content)
+	if !ok {
+		return false
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	var keys [lshBands]uint64
+	checked := make(map[int]struct{})
+	for b := 0; b < lshBands; b++ {
+		keys[b] = bandKey(sig, b)
+		for _, cand := range idx.buckets[b][keys[b]] {
+			if _, seen := checked[cand]; seen {
+				continue
+			}
+			checked[cand] = struct{}{}
+			if estimateJaccard(sig, idx.signatures[cand]) >= idx.threshold {
+				return true
+			}
+		}
+	}
+
+	if idx.capacity > 0 && len(idx.signatures) >= idx.capacity {
+		idx.evictOldestHalf()
+	}
+	pos := len(idx.signatures)
+	idx.signatures = append(idx.signatures, sig)
+	for b := 0; b < lshBands; b++ {
+		idx.buckets[b][keys[b]] = append(idx.buckets[b][keys[b]], pos)
+	}
+	return false
+}
+
+// evictOldestHalf drops the older half of the signatures and rebuilds the
+// buckets. The caller must hold idx.mu.
+func (idx *ShingleIndex) evictOldestHalf() {
+	kept := append([]minHashSignature(nil), idx.signatures[len(idx.signatures)/2:]...)
+	idx.signatures = kept
+	for b := range idx.buckets {
+		idx.buckets[b] = make(map[uint64][]int)
+		for pos := range kept {
+			key := bandKey(kept[pos], b)
+			idx.buckets[b][key] = append(idx.buckets[b][key], pos)
+		}
+	}
+}
+
+// Len returns the number of distinct texts held by the index.
+func (idx *ShingleIndex) Len() int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return len(idx.signatures)
+}
+
+// minHashOf computes the MinHash signature of content's word shingles.
+// Words are lowercased and stripped of surrounding punctuation so trivial
+// formatting differences do not defeat the match. Returns false when content
+// has no words.
+func minHashOf(content string) (minHashSignature, bool) {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	var sig minHashSignature
+	if len(words) == 0 {
+		return sig, false
+	}
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+
+	width := shingleWidth
+	if len(words) < width {
+		width = len(words)
+	}
+	for i := 0; i+width <= len(words); i++ {
+		h := fnv.New64a()
+		for j, w := range words[i : i+width] {
+			if j > 0 {
+				_, _ = h.Write([]byte{' '})
+			}
+			_, _ = h.Write([]byte(w))
+		}
+		base := h.Sum64()
+		for k := range sig {
+			if v := splitMix64(base ^ minHashSeeds[k]); v < sig[k] {
+				sig[k] = v
+			}
+		}
+	}
+	return sig, true
+}
+
+// bandKey hashes the rows of band b into a single bucket key.
+func bandKey(sig minHashSignature, b int) uint64 {
+	key := uint64(b) + 1
+	for _, v := range sig[b*lshRows : (b+1)*lshRows] {
+		key = splitMix64(key ^ v)
+	}
+	return key
+}
+
+// estimateJaccard returns the fraction of signature slots that agree, an
+// unbiased estimate of the Jaccard similarity of the underlying shingle sets.
+func estimateJaccard(a, b minHashSignature) float64 {
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(minHashSize)
+}
+
+// splitMix64 is a fast, well-distributed 64-bit mixing function.
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
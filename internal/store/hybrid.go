@@ -0,0 +1,58 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// hybridRRFK is the Reciprocal Rank Fusion constant (Cormack et al. 2009).
+const hybridRRFK = 60
+
+// HybridOverfetch multiplies the per-list limit of a hybrid search so the
+// fused ranking has candidates beyond the final limit to choose from.
+const HybridOverfetch = 2
+
+// KeywordScore is the fraction of keyword's whitespace-separated terms found
+// in content, ignoring case. It is the keyword ranking MockStore uses for
+// hybrid search; 0 means no term matched.
+func KeywordScore(content, keyword string) float64 {
+	terms := strings.Fields(strings.ToLower(keyword))
+	if len(terms) == 0 {
+		return 0
+	}
+	lc := strings.ToLower(content)
+	hits := 0
+	for _, t := range terms {
+		if strings.Contains(lc, t) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(terms))
+}
+
+// FuseRRF merges a vector ranking and a keyword ranking with Reciprocal Rank
+// Fusion. Each result's Score becomes the sum of 1/(rank+1+k) over the lists
+// it appears in, so a memory ranked well by both beats one found by only one.
+// At most limit results are returned.
+func FuseRRF(vector, keyword []models.SearchResult, limit uint64) []models.SearchResult {
+	index := make(map[string]int, len(vector)+len(keyword))
+	var fused []models.SearchResult
+	for _, list := range [][]models.SearchResult{vector, keyword} {
+		for rank := range list {
+			score := 1.0 / float64(rank+1+hybridRRFK)
+			id := list[rank].Memory.ID
+			if i, ok := index[id]; ok {
+				fused[i].Score += score
+				continue
+			}
+			index[id] = len(fused)
+			fused = append(fused, models.SearchResult{Memory: list[rank].Memory, Score: score})
+		}
+	}
+	SortSearchResults(fused)
+	if uint64(len(fused)) > limit {
+		fused = fused[:limit]
+	}
+	return fused
+}
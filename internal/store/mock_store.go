@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +42,19 @@ func (m *MockStore) EnsureCollection(_ context.Context) error {
 	return nil
 }
 
+// UpsertBatch upserts each memory in order, mirroring Upsert.
+func (m *MockStore) UpsertBatch(ctx context.Context, memories []models.Memory, vectors [][]float32) error {
+	if len(memories) != len(vectors) {
+		return fmt.Errorf("upsert batch: %d memories but %d vectors", len(memories), len(vectors))
+	}
+	for i := range memories {
+		if err := m.Upsert(ctx, memories[i], vectors[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Upsert inserts or updates a memory in the mock store.
 func (m *MockStore) Upsert(_ context.Context, memory models.Memory, vector []float32) error {
 	m.mu.Lock()
@@ -50,6 +65,9 @@ func (m *MockStore) Upsert(_ context.Context, memory models.Memory, vector []flo
 		copy(tags, memory.Tags)
 		memory.Tags = tags
 	}
+	if len(memory.Attachments) > 0 {
+		memory.Attachments = append([]models.Attachment(nil), memory.Attachments...)
+	}
 	if len(memory.Metadata) > 0 {
 		meta := make(map[string]any, len(memory.Metadata))
 		for k, v := range memory.Metadata {
@@ -76,6 +94,13 @@ func (m *MockStore) Upsert(_ context.Context, memory models.Memory, vector []flo
 	}
 	memory.IsCurrentVersion = memory.ValidTo == nil
 
+	// Upsert never changes trash state; only Trash and Restore do.
+	memory.DeletedAt = nil
+	if existing, ok := m.memories[memory.ID]; ok && existing.memory.DeletedAt != nil {
+		t := *existing.memory.DeletedAt
+		memory.DeletedAt = &t
+	}
+
 	m.memories[memory.ID] = &storedMemory{memory: memory, vector: vector}
 	return nil
 }
@@ -97,6 +122,9 @@ func (m *MockStore) Search(_ context.Context, vector []float32, limit uint64, fi
 			copy(tags, mem.Tags)
 			mem.Tags = tags
 		}
+		if len(mem.Attachments) > 0 {
+			mem.Attachments = append([]models.Attachment(nil), mem.Attachments...)
+		}
 		if len(mem.Metadata) > 0 {
 			meta := make(map[string]any, len(mem.Metadata))
 			for k, v := range mem.Metadata {
@@ -110,13 +138,17 @@ func (m *MockStore) Search(_ context.Context, vector []float32, limit uint64, fi
 		})
 	}
 
-	// Sort by score descending (simple bubble sort for tests)
-	for i := 0; i < len(results); i++ {
-		for j := i + 1; j < len(results); j++ {
-			if results[j].Score > results[i].Score {
-				results[i], results[j] = results[j], results[i]
+	SortSearchResults(results)
+
+	if filters != nil && filters.Keyword != "" {
+		var keyword []models.SearchResult
+		for _, r := range results {
+			if ks := KeywordScore(r.Memory.Content, filters.Keyword); ks > 0 {
+				keyword = append(keyword, models.SearchResult{Memory: r.Memory, Score: ks})
 			}
 		}
+		SortSearchResults(keyword)
+		return FuseRRF(results, keyword, limit), nil
 	}
 
 	if uint64(len(results)) > limit {
@@ -131,16 +163,39 @@ func (m *MockStore) Get(_ context.Context, id string) (*models.Memory, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	sm, ok := m.memories[id]
-	if !ok {
+	if !ok || sm.memory.DeletedAt != nil {
 		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
 	}
-	mem := sm.memory
-	// Deep-copy mutable fields to prevent callers from mutating stored data.
+	mem := copyMemory(sm.memory)
+	return &mem, nil
+}
+
+// GetMany returns copies of the live memories among ids, keyed by ID.
+func (m *MockStore) GetMany(_ context.Context, ids []string) (map[string]models.Memory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]models.Memory, len(ids))
+	for _, id := range ids {
+		sm, ok := m.memories[id]
+		if !ok || sm.memory.DeletedAt != nil {
+			continue
+		}
+		out[id] = copyMemory(sm.memory)
+	}
+	return out, nil
+}
+
+// copyMemory deep-copies mutable fields to prevent callers from mutating
+// stored data. The caller must hold m.mu.
+func copyMemory(mem models.Memory) models.Memory {
 	if len(mem.Tags) > 0 {
 		tags := make([]string, len(mem.Tags))
 		copy(tags, mem.Tags)
 		mem.Tags = tags
 	}
+	if len(mem.Attachments) > 0 {
+		mem.Attachments = append([]models.Attachment(nil), mem.Attachments...)
+	}
 	if len(mem.Metadata) > 0 {
 		meta := make(map[string]any, len(mem.Metadata))
 		for k, v := range mem.Metadata {
@@ -149,7 +204,7 @@ func (m *MockStore) Get(_ context.Context, id string) (*models.Memory, error) {
 		mem.Metadata = meta
 	}
 	mem.IsCurrentVersion = mem.ValidTo == nil
-	return &mem, nil
+	return mem
 }
 
 // Delete removes a memory by ID.
@@ -163,6 +218,95 @@ func (m *MockStore) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+// resolveID maps id (full or unambiguous prefix) to a stored memory ID whose
+// trash state matches trashed. Caller must hold m.mu.
+func (m *MockStore) resolveID(id string, trashed bool) (string, error) {
+	if sm, ok := m.memories[id]; ok && (sm.memory.DeletedAt != nil) == trashed {
+		return id, nil
+	}
+	var match string
+	n := 0
+	for mid, sm := range m.memories {
+		if (sm.memory.DeletedAt != nil) == trashed && strings.HasPrefix(mid, id) {
+			match = mid
+			n++
+		}
+	}
+	switch {
+	case n == 0 || id == "":
+		return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+	case n > 1:
+		return "", fmt.Errorf("ambiguous prefix: matches %d memories", n)
+	}
+	return match, nil
+}
+
+// Trash sets the deleted_at tombstone on a live memory.
+func (m *MockStore) Trash(_ context.Context, id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fullID, err := m.resolveID(id, false)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	m.memories[fullID].memory.DeletedAt = &now
+	return fullID, nil
+}
+
+// GetVector returns a copy of the stored vector for a live memory.
+func (m *MockStore) GetVector(_ context.Context, id string) ([]float32, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sm, ok := m.memories[id]
+	if !ok || sm.memory.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return append([]float32(nil), sm.vector...), nil
+}
+
+// GetVectors returns copies of the stored vectors for the live memories among ids.
+func (m *MockStore) GetVectors(_ context.Context, ids []string) (map[string][]float32, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string][]float32, len(ids))
+	for _, id := range ids {
+		if sm, ok := m.memories[id]; ok && sm.memory.DeletedAt == nil {
+			out[id] = append([]float32(nil), sm.vector...)
+		}
+	}
+	return out, nil
+}
+
+// Restore clears the deleted_at tombstone on a trashed memory.
+func (m *MockStore) Restore(_ context.Context, id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fullID, err := m.resolveID(id, true)
+	if err != nil {
+		return "", err
+	}
+	m.memories[fullID].memory.DeletedAt = nil
+	return fullID, nil
+}
+
+// ListTrashed returns trashed memories deleted before the given time, oldest first.
+func (m *MockStore) ListTrashed(_ context.Context, before time.Time, limit uint64) ([]models.Memory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []models.Memory
+	for _, sm := range m.memories {
+		if sm.memory.DeletedAt != nil && sm.memory.DeletedAt.Before(before) {
+			out = append(out, sm.memory)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DeletedAt.Before(*out[j].DeletedAt) })
+	if limit > 0 && uint64(len(out)) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
 // List returns memories matching filters with cursor-based pagination.
 func (m *MockStore) List(_ context.Context, filters *SearchFilters, limit uint64, cursor string) ([]models.Memory, string, error) {
 	m.mu.RLock()
@@ -183,6 +327,9 @@ func (m *MockStore) List(_ context.Context, filters *SearchFilters, limit uint64
 			copy(tags, mem.Tags)
 			mem.Tags = tags
 		}
+		if len(mem.Attachments) > 0 {
+			mem.Attachments = append([]models.Attachment(nil), mem.Attachments...)
+		}
 		if len(mem.Metadata) > 0 {
 			meta := make(map[string]any, len(mem.Metadata))
 			for k, v := range mem.Metadata {
@@ -234,6 +381,9 @@ func (m *MockStore) FindDuplicates(_ context.Context, vector []float32, threshol
 
 	var results []models.SearchResult
 	for _, sm := range m.memories {
+		if sm.memory.DeletedAt != nil {
+			continue
+		}
 		score := vecmath.CosineSimilarity(vector, sm.vector)
 		if score >= threshold {
 			mem := sm.memory
@@ -242,6 +392,9 @@ func (m *MockStore) FindDuplicates(_ context.Context, vector []float32, threshol
 				copy(tags, mem.Tags)
 				mem.Tags = tags
 			}
+			if len(mem.Attachments) > 0 {
+				mem.Attachments = append([]models.Attachment(nil), mem.Attachments...)
+			}
 			if len(mem.Metadata) > 0 {
 				meta := make(map[string]any, len(mem.Metadata))
 				for k, v := range mem.Metadata {
@@ -271,6 +424,23 @@ func (m *MockStore) UpdateAccessMetadata(_ context.Context, id string) error {
 	return nil
 }
 
+// UpdateAccessMetadataBatch updates access metadata for every existing ID under
+// a single lock acquisition. Unknown IDs are skipped.
+func (m *MockStore) UpdateAccessMetadataBatch(_ context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	for _, id := range ids {
+		sm, ok := m.memories[id]
+		if !ok {
+			continue
+		}
+		sm.memory.LastAccessed = now
+		sm.memory.AccessCount++
+	}
+	return nil
+}
+
 // Stats returns collection statistics computed from the in-memory store.
 func (m *MockStore) Stats(_ context.Context) (*models.CollectionStats, error) {
 	m.mu.RLock()
@@ -391,6 +561,13 @@ func (m *MockStore) UpsertEntity(_ context.Context, entity models.Entity) error
 		}
 		e.Metadata = meta
 	}
+	// Like the Memgraph store, an upsert without an embedding keeps the
+	// entity's existing one.
+	if len(entity.NameEmbedding) > 0 {
+		e.NameEmbedding = append([]float32(nil), entity.NameEmbedding...)
+	} else if prev, ok := m.entities[e.ID]; ok {
+		e.NameEmbedding = prev.NameEmbedding
+	}
 
 	m.entities[e.ID] = &e
 	return nil
@@ -477,6 +654,41 @@ func (m *MockStore) SearchEntities(_ context.Context, name, entityType string, l
 	return results, nil
 }
 
+// SearchEntitiesByVector ranks entities that have an embedding by cosine
+// similarity to vector. limit caps results (0 = 10).
+func (m *MockStore) SearchEntitiesByVector(_ context.Context, vector []float32, limit int) ([]models.EntitySearchResult, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []models.EntitySearchResult
+	for _, e := range m.entities {
+		if len(e.NameEmbedding) == 0 {
+			continue
+		}
+		cp := *e
+		cp.Aliases = append([]string(nil), e.Aliases...)
+		cp.MemoryIDs = append([]string(nil), e.MemoryIDs...)
+		cp.NameEmbedding = nil
+		results = append(results, models.EntitySearchResult{
+			Entity: cp,
+			Score:  vecmath.CosineSimilarity(vector, e.NameEmbedding),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Entity.ID < results[j].Entity.ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
 // LinkMemoryToEntity adds a memory ID to an entity's MemoryIDs list.
 func (m *MockStore) LinkMemoryToEntity(_ context.Context, entityID, memoryID string) error {
 	m.mu.Lock()
@@ -532,7 +744,10 @@ func (m *MockStore) UpdateReinforcement(_ context.Context, id string, boost floa
 // GetChain follows the SupersedesID chain and returns the full history.
 // The chain is returned newest first. Stops when SupersedesID is empty or the
 // referenced memory is not found. A visited set prevents infinite loops.
-func (m *MockStore) GetChain(ctx context.Context, id string) ([]models.Memory, error) {
+func (m *MockStore) GetChain(_ context.Context, id string) ([]models.Memory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	var chain []models.Memory
 	visited := make(map[string]bool)
 	currentID := id
@@ -543,12 +758,13 @@ func (m *MockStore) GetChain(ctx context.Context, id string) ([]models.Memory, e
 		}
 		visited[currentID] = true
 
-		mem, err := m.Get(ctx, currentID)
-		if err != nil {
+		sm, ok := m.memories[currentID]
+		if !ok || sm.memory.DeletedAt != nil {
 			// Stop at a missing link — not an error for the caller.
 			break
 		}
-		chain = append(chain, *mem)
+		mem := copyMemory(sm.memory)
+		chain = append(chain, mem)
 		currentID = mem.SupersedesID
 	}
 
@@ -560,6 +776,11 @@ func (m *MockStore) Close() error {
 	return nil
 }
 
+// Ping always succeeds for the in-memory store.
+func (m *MockStore) Ping(_ context.Context) error {
+	return nil
+}
+
 // InvalidateMemory sets valid_to on a memory without deleting it.
 func (m *MockStore) InvalidateMemory(_ context.Context, id string, validTo time.Time) error {
 	m.mu.Lock()
@@ -614,6 +835,10 @@ func (m *MockStore) DeleteAllMemories(_ context.Context) error {
 // --- helpers ---
 
 func matchesFilters(mem models.Memory, f *SearchFilters) bool {
+	// Trashed memories are invisible until restored.
+	if mem.DeletedAt != nil {
+		return false
+	}
 	// Sensitive memories are opt-in: only returned when explicitly requested.
 	if mem.Visibility == models.VisibilitySensitive {
 		if f == nil || f.Visibility == nil || *f.Visibility != models.VisibilitySensitive {
@@ -636,12 +861,33 @@ func matchesFilters(mem models.Memory, f *SearchFilters) bool {
 	if f.Project != nil && mem.Project != *f.Project {
 		return false
 	}
+	if len(f.Projects) > 0 && mem.Project != "" && !slices.Contains(f.Projects, mem.Project) {
+		return false
+	}
 	if f.UserID != "" && mem.UserID != f.UserID {
 		return false
 	}
 	if f.Source != nil && mem.Source != *f.Source {
 		return false
 	}
+	if f.SessionID != "" {
+		sid, _ := mem.Metadata["session_id"].(string)
+		if sid != f.SessionID {
+			return false
+		}
+	}
+	if f.TurnID != "" {
+		tid, _ := mem.Metadata["turn_id"].(string)
+		if tid != f.TurnID {
+			return false
+		}
+	}
+	if f.PreferenceSubject != "" {
+		p, ok := mem.PreferenceTriple()
+		if !ok || models.NormalizePreferenceSubject(p.Subject) != models.NormalizePreferenceSubject(f.PreferenceSubject) {
+			return false
+		}
+	}
 	for _, required := range f.Tags {
 		found := false
 		for _, t := range mem.Tags {
@@ -681,5 +927,22 @@ func matchesFilters(mem models.Memory, f *SearchFilters) bool {
 		return false
 	}
 
+	if !inTimeRange(mem.CreatedAt, f.CreatedAfter, f.CreatedBefore) ||
+		!inTimeRange(mem.LastAccessed, f.AccessedAfter, f.AccessedBefore) {
+		return false
+	}
+	if f.ExpiresBefore != nil && (mem.ExpiresAt.IsZero() || !mem.ExpiresAt.Before(*f.ExpiresBefore)) {
+		return false
+	}
+
 	return true
 }
+
+// inTimeRange reports whether t lies within the inclusive [after, before]
+// window; a nil bound is open.
+func inTimeRange(t time.Time, after, before *time.Time) bool {
+	if after != nil && t.Before(*after) {
+		return false
+	}
+	return before == nil || !t.After(*before)
+}
@@ -0,0 +1,28 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// scoreTieEpsilon is the score difference below which two search results
+// are treated as tied. Vector indexes report float32 similarities, so
+// "equal" scores often differ in the last few bits.
+const scoreTieEpsilon = 1e-6
+
+// SortSearchResults orders results by score descending. Ties are broken by
+// LastAccessed (most recent first) and then by ID, so the order is the same
+// on every run regardless of how the backend returned them.
+func SortSearchResults(results []models.SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := &results[i], &results[j]
+		if d := a.Score - b.Score; d > scoreTieEpsilon || d < -scoreTieEpsilon {
+			return d > 0
+		}
+		if !a.Memory.LastAccessed.Equal(b.Memory.LastAccessed) {
+			return a.Memory.LastAccessed.After(b.Memory.LastAccessed)
+		}
+		return a.Memory.ID < b.Memory.ID
+	})
+}
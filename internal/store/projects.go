@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// ProjectFilter builds the project part of a search filter. A lone project
+// matches that project exactly. When projects is non-empty, project is merged
+// into it and the filter matches any listed project or a global memory (see
+// SearchFilters.Projects). Returns nil when neither is set.
+func ProjectFilter(project string, projects []string) *SearchFilters {
+	if len(projects) == 0 {
+		if project == "" {
+			return nil
+		}
+		return &SearchFilters{Project: &project}
+	}
+	all := make([]string, 0, len(projects)+1)
+	if project != "" {
+		all = append(all, project)
+	}
+	for _, p := range projects {
+		if p = strings.TrimSpace(p); p != "" && !slices.Contains(all, p) {
+			all = append(all, p)
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	return &SearchFilters{Projects: all}
+}
+
+// BroadenProjectFilter returns a copy of f that matches global memories as
+// well as f's project: a filter pinned to a single project becomes "that
+// project or global" (see SearchFilters.Projects). Other fields are kept.
+// Returns nil when f is not pinned to a single project.
+func BroadenProjectFilter(f *SearchFilters) *SearchFilters {
+	if f == nil || f.Project == nil || *f.Project == "" {
+		return nil
+	}
+	broad := *f
+	broad.Projects = []string{*f.Project}
+	broad.Project = nil
+	return &broad
+}
+
+// SearchBroadening runs s.Search and, when broaden is set and a search pinned
+// to a single project finds fewer than max(minResults, 1) memories, searches
+// again with BroadenProjectFilter. This keeps recall in a fresh project from
+// coming back empty while global knowledge exists. It reports whether the
+// broadened search was used.
+func SearchBroadening(ctx context.Context, s Store, vec []float32, limit uint64, filters *SearchFilters, broaden bool, minResults int) ([]models.SearchResult, bool, error) {
+	results, err := s.Search(ctx, vec, limit, filters)
+	if err != nil || !broaden || len(results) >= max(minResults, 1) {
+		return results, false, err
+	}
+	broad := BroadenProjectFilter(filters)
+	if broad == nil {
+		return results, false, nil
+	}
+	results, err = s.Search(ctx, vec, limit, broad)
+	return results, err == nil, err
+}
@@ -21,15 +21,47 @@ type Store interface {
 	// Upsert inserts or updates a memory with its embedding vector.
 	Upsert(ctx context.Context, memory models.Memory, vector []float32) error
 
+	// UpsertBatch is Upsert for many memories in one round-trip. vectors[i]
+	// is the embedding of memories[i]; the lengths must match.
+	UpsertBatch(ctx context.Context, memories []models.Memory, vectors [][]float32) error
+
 	// Search finds memories similar to the query vector.
 	Search(ctx context.Context, vector []float32, limit uint64, filters *SearchFilters) ([]models.SearchResult, error)
 
 	// Get retrieves a single memory by ID.
 	Get(ctx context.Context, id string) (*models.Memory, error)
 
+	// GetMany retrieves the memories with the given IDs in a single
+	// round-trip, keyed by ID. Unknown and trashed IDs are omitted.
+	GetMany(ctx context.Context, ids []string) (map[string]models.Memory, error)
+
+	// GetVector returns the stored embedding vector of a memory. The result is
+	// empty (not an error) when the memory exists but has no embedding.
+	// Returns ErrNotFound when no live memory has the given ID.
+	GetVector(ctx context.Context, id string) ([]float32, error)
+
+	// GetVectors returns the stored embedding vectors for the given IDs in a
+	// single round-trip, keyed by ID. Unknown and trashed IDs are omitted.
+	GetVectors(ctx context.Context, ids []string) (map[string][]float32, error)
+
 	// Delete removes a memory by ID.
 	Delete(ctx context.Context, id string) error
 
+	// Trash soft-deletes a memory by setting its deleted_at tombstone. Trashed
+	// memories are hidden from Get, Search, List and FindDuplicates until
+	// restored or purged. Returns the full ID of the trashed memory; id may be
+	// an unambiguous prefix. Returns ErrNotFound when no live memory matches.
+	Trash(ctx context.Context, id string) (string, error)
+
+	// Restore clears the tombstone on a trashed memory. id may be an
+	// unambiguous prefix. Returns the full ID of the restored memory, or
+	// ErrNotFound when no trashed memory matches.
+	Restore(ctx context.Context, id string) (string, error)
+
+	// ListTrashed returns up to limit trashed memories whose deleted_at is
+	// before the given time, oldest first.
+	ListTrashed(ctx context.Context, before time.Time, limit uint64) ([]models.Memory, error)
+
 	// List returns memories matching the given filters.
 	// The cursor parameter is opaque; pass "" for the first page.
 	// The returned cursor is empty when no more results remain.
@@ -41,6 +73,10 @@ type Store interface {
 	// UpdateAccessMetadata increments access count and updates last_accessed time.
 	UpdateAccessMetadata(ctx context.Context, id string) error
 
+	// UpdateAccessMetadataBatch applies UpdateAccessMetadata to every ID in a
+	// single round-trip. IDs that do not exist are skipped.
+	UpdateAccessMetadataBatch(ctx context.Context, ids []string) error
+
 	// Stats returns collection statistics.
 	Stats(ctx context.Context) (*models.CollectionStats, error)
 
@@ -55,6 +91,12 @@ type Store interface {
 	// limit caps the number of results (0 = use implementation default).
 	SearchEntities(ctx context.Context, name, entityType string, limit int) ([]models.Entity, error)
 
+	// SearchEntitiesByVector finds entities whose embedding (see
+	// models.Entity.EmbeddingTextWith) is most similar to vector, best first.
+	// Entities without an embedding are never returned.
+	// limit caps the number of results (0 = implementation default).
+	SearchEntitiesByVector(ctx context.Context, vector []float32, limit int) ([]models.EntitySearchResult, error)
+
 	// LinkMemoryToEntity adds a memory ID to an entity's memory list.
 	LinkMemoryToEntity(ctx context.Context, entityID, memoryID string) error
 
@@ -88,6 +130,11 @@ type Store interface {
 	// recall, search, and forget --query because vector search skips them.
 	CountZeroEmbeddingMemories(ctx context.Context) (int64, error)
 
+	// Ping checks that the store is reachable with the cheapest available
+	// round-trip. Use it for liveness and readiness checks instead of ad-hoc
+	// List or Stats calls.
+	Ping(ctx context.Context) error
+
 	// Close cleans up resources.
 	Close() error
 }
@@ -126,9 +173,34 @@ type SearchFilters struct {
 	Source         *string                  `json:"source,omitempty"`
 	ConflictStatus *models.ConflictStatus   `json:"conflict_status,omitempty"` // filter by conflict status ("active", "resolved", "")
 
+	// Projects filters results to memories belonging to any of these projects
+	// or to no project at all (globals), for umbrella setups where knowledge
+	// spans several project names. Empty = no filter. Combined with Project,
+	// a memory must satisfy both.
+	Projects []string `json:"projects,omitempty"`
+
 	// UserID filters results to memories owned by this user. Empty = no filter (returns all).
 	UserID string `json:"user_id,omitempty"`
 
+	// SessionID filters results to memories whose metadata["session_id"] equals
+	// this value, i.e. memories captured during that conversation. Empty = no filter.
+	SessionID string `json:"session_id,omitempty"`
+
+	// TurnID filters results to memories whose metadata["turn_id"] equals this
+	// value, i.e. memories captured from that conversation turn (see
+	// capture.TurnID). Empty = no filter.
+	TurnID string `json:"turn_id,omitempty"`
+
+	// PreferenceSubject filters results to preference memories whose structured
+	// subject matches, compared case-insensitively. Empty = no filter.
+	PreferenceSubject string `json:"preference_subject,omitempty"`
+
+	// Keyword switches Search to hybrid retrieval: the vector ranking is
+	// fused with a keyword match on content by Reciprocal Rank Fusion (see
+	// FuseRRF), so exact terms such as error codes are not missed. Scores are
+	// then RRF scores rather than similarities. Empty = vector search only.
+	Keyword string `json:"keyword,omitempty"`
+
 	// IncludeInvalidated includes memories with valid_to set (historical versions).
 	// Default: false (only return currently-valid memories).
 	IncludeInvalidated bool `json:"include_invalidated,omitempty"`
@@ -145,4 +217,17 @@ type SearchFilters struct {
 	// ValidAfter filters memories whose valid_from is at or after this time.
 	// Memories with no valid_from (null) are excluded by this filter.
 	ValidAfter *time.Time `json:"valid_after,omitempty"`
+
+	// CreatedAfter and CreatedBefore bound created_at, AccessedAfter and
+	// AccessedBefore bound last_accessed, for windows like "what did I learn
+	// last week". Bounds are inclusive; nil = unbounded.
+	CreatedAfter   *time.Time `json:"created_after,omitempty"`
+	CreatedBefore  *time.Time `json:"created_before,omitempty"`
+	AccessedAfter  *time.Time `json:"accessed_after,omitempty"`
+	AccessedBefore *time.Time `json:"accessed_before,omitempty"`
+
+	// ExpiresBefore keeps only memories with an ExpiresAt set and strictly
+	// before this time, so the lifecycle can find expired memories without
+	// scanning the store. nil = no filter.
+	ExpiresBefore *time.Time `json:"expires_before,omitempty"`
 }
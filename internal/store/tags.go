@@ -0,0 +1,75 @@
+package store
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+// NormalizeTags converges tags to their canonical form so that variants such
+// as "Go", " go" and "golang" do not fragment tag filtering. Each tag is
+// trimmed and lowercased, then mapped through synonyms (keys are matched
+// case-insensitively). Empty tags are dropped and duplicates removed, keeping
+// first-seen order. Returns nil when no tags remain.
+func NormalizeTags(tags []string, synonyms map[string]string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	canonical := make(map[string]string, len(synonyms))
+	for k, v := range synonyms {
+		canonical[strings.ToLower(strings.TrimSpace(k))] = strings.ToLower(strings.TrimSpace(v))
+	}
+
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		norm := strings.ToLower(strings.TrimSpace(t))
+		if mapped, ok := canonical[norm]; ok && mapped != "" {
+			norm = mapped
+		}
+		if norm == "" {
+			continue
+		}
+		if _, dup := seen[norm]; dup {
+			continue
+		}
+		seen[norm] = struct{}{}
+		out = append(out, norm)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// TagNormalizer applies NormalizeTags to memories before they are written, so
+// every write path (CLI, API, MCP, capture) converges tags the same way. A nil
+// *TagNormalizer leaves tags untouched.
+type TagNormalizer struct {
+	synonyms map[string]string
+}
+
+// NewTagNormalizer returns a TagNormalizer that maps tags through synonyms
+// (see NormalizeTags).
+func NewTagNormalizer(synonyms map[string]string) *TagNormalizer {
+	return &TagNormalizer{synonyms: synonyms}
+}
+
+// Apply normalizes m.Tags in place. If normalization changed the tags, the
+// originals are preserved in m.Metadata["raw_tags"] so no information is lost.
+func (n *TagNormalizer) Apply(m *models.Memory) {
+	if n == nil || len(m.Tags) == 0 {
+		return
+	}
+	normalized := NormalizeTags(m.Tags, n.synonyms)
+	if slices.Equal(normalized, m.Tags) {
+		return
+	}
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]any)
+	}
+	m.Metadata["raw_tags"] = m.Tags
+	m.Tags = normalized
+}
@@ -11,6 +11,11 @@ import (
 // single source of truth.
 const MinContentLen = 10
 
+// MaxContentLen is the maximum number of characters accepted for a single
+// memory stored from a file. It stays well inside the context window of the
+// supported embedding models, so the whole file is represented by its vector.
+const MaxContentLen = 32000
+
 // ErrContentTooShort is returned when a memory's trimmed content is shorter
 // than MinContentLen.
 type ErrContentTooShort struct {
@@ -23,6 +28,18 @@ func (e *ErrContentTooShort) Error() string {
 		e.Actual, e.Minimum)
 }
 
+// ErrContentTooLong is returned when a memory's trimmed content is longer
+// than MaxContentLen.
+type ErrContentTooLong struct {
+	Actual  int
+	Maximum int
+}
+
+func (e *ErrContentTooLong) Error() string {
+	return fmt.Sprintf("content too long (%d chars, maximum %d); use index to chunk large documents",
+		e.Actual, e.Maximum)
+}
+
 // ErrDedupThresholdRange is returned when a caller-supplied dedup threshold
 // falls outside the valid half-open interval (0.0, 1.0].
 type ErrDedupThresholdRange struct {
@@ -46,6 +63,16 @@ func ValidateContentLength(content string) error {
 	return nil
 }
 
+// ValidateMaxContentLength checks that content (after trimming whitespace)
+// does not exceed MaxContentLen runes. Returns ErrContentTooLong when it does.
+func ValidateMaxContentLength(content string) error {
+	runeCount := utf8.RuneCountInString(strings.TrimSpace(content))
+	if runeCount > MaxContentLen {
+		return &ErrContentTooLong{Actual: runeCount, Maximum: MaxContentLen}
+	}
+	return nil
+}
+
 // ValidateDedupThreshold checks that v is in the half-open interval (0.0, 1.0].
 // Returns ErrDedupThresholdRange when the value is out of range.
 func ValidateDedupThreshold(v float64) error {
@@ -0,0 +1,179 @@
+// Package tracing wraps the OpenTelemetry SDK with a small span API used on
+// the recall, search, embedder and store hot paths. Spans are exported to an
+// OTLP/HTTP collector with the otlptracehttp exporter.
+//
+// All functions are no-ops until Init is called with tracing enabled, so the
+// package is safe to call unconditionally on hot paths. A nil *Span is valid
+// and every method on it does nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/config"
+)
+
+// instrumentationName is the OpenTelemetry instrumentation scope of every span.
+const instrumentationName = "github.com/ajitpratap0/openclaw-cortex"
+
+// provider is the process-wide tracer provider; nil when tracing is disabled.
+var provider atomic.Pointer[sdktrace.TracerProvider]
+
+// propagator reads and writes W3C traceparent/tracestate headers.
+var propagator = propagation.TraceContext{}
+
+// Span is an in-flight unit of work. Obtain one with Start and finish it with End.
+type Span struct {
+	span trace.Span
+}
+
+// Init starts exporting spans according to cfg and returns a shutdown
+// function that flushes buffered spans. When cfg.Enabled is false, Init does
+// nothing and the returned function is a no-op.
+func Init(cfg config.TracingConfig, logger *slog.Logger) func(context.Context) error {
+	if !cfg.Enabled || cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }
+	}
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "openclaw-cortex"
+	}
+
+	// The exporter connects lazily, so New only fails on invalid options.
+	exp, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(strings.TrimRight(cfg.Endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		logger.Warn("tracing: creating OTLP exporter failed, tracing disabled", "error", err)
+		return func(context.Context) error { return nil }
+	}
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Debug("tracing: export failed", "error", err)
+	}))
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		// Root spans are sampled at SampleRate; children, including those of
+		// a remote parent from a traceparent header, follow their parent.
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+	)
+	provider.Store(tp)
+	return func(ctx context.Context) error {
+		provider.CompareAndSwap(tp, nil)
+		return tp.Shutdown(ctx)
+	}
+}
+
+// Enabled reports whether spans are currently being recorded.
+func Enabled() bool { return provider.Load() != nil }
+
+// Extract returns ctx carrying the remote span context from the W3C
+// traceparent/tracestate headers in h, if any, so spans started from it join
+// the caller's trace. Malformed headers are ignored.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(h))
+}
+
+// Start begins a span named name as a child of any span carried by ctx and
+// returns a context carrying the new span. kv is an alternating list of
+// string keys and values, in the style of log/slog. Root spans are sampled
+// according to the configured sample rate; children follow their parent.
+// Returns a nil *Span when tracing is disabled or the trace is not sampled.
+func Start(ctx context.Context, name string, kv ...any) (context.Context, *Span) {
+	tp := provider.Load()
+	if tp == nil {
+		return ctx, nil
+	}
+	ctx, span := tp.Tracer(instrumentationName).Start(ctx, name)
+	if !span.IsRecording() {
+		// ctx still carries the unsampled span context, so descendants are
+		// dropped too.
+		return ctx, nil
+	}
+	sp := &Span{span: span}
+	sp.SetAttributes(kv...)
+	return ctx, sp
+}
+
+// SetAttributes records alternating key/value pairs on the span. Non-string
+// keys and a trailing unpaired key are ignored.
+func (s *Span) SetAttributes(kv ...any) {
+	if s == nil {
+		return
+	}
+	attrs := make([]attribute.KeyValue, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+		attrs = append(attrs, toAttribute(key, kv[i+1]))
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// RecordError marks the span as failed. A nil err is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End finishes the span and queues it for export. Calling End more than once
+// has no further effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+func toAttribute(key string, v any) attribute.KeyValue {
+	switch x := v.(type) {
+	case string:
+		return attribute.String(key, x)
+	case bool:
+		return attribute.Bool(key, x)
+	case int:
+		return attribute.Int(key, x)
+	case int8:
+		return attribute.Int64(key, int64(x))
+	case int16:
+		return attribute.Int64(key, int64(x))
+	case int32:
+		return attribute.Int64(key, int64(x))
+	case int64:
+		return attribute.Int64(key, x)
+	case uint8:
+		return attribute.Int64(key, int64(x))
+	case uint16:
+		return attribute.Int64(key, int64(x))
+	case uint32:
+		return attribute.Int64(key, int64(x))
+	case float32:
+		return attribute.Float64(key, float64(x))
+	case float64:
+		return attribute.Float64(key, x)
+	case time.Duration:
+		return attribute.Float64(key, float64(x)/float64(time.Millisecond))
+	default:
+		return attribute.String(key, fmt.Sprint(x))
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"strings"
 )
 
+// memorySeparator is placed between formatted memories.
+const memorySeparator = "\n---\n"
+
 // EstimateTokens returns an approximate token count for the given text.
 // Heuristic: calibrated to cl100k_base tokenization used by Claude and GPT-4.
 // Accuracy: ±15% for typical English prose; may undercount for code or non-ASCII.
@@ -61,13 +64,17 @@ func TruncateToTokenBudget(text string, budget int) string {
 
 // FormatMemoriesWithBudget formats multiple memory strings within a token budget.
 // Returns the formatted string and the number of memories that fit.
+//
+// The per-memory estimates used during selection are additive approximations,
+// so once the output is assembled its token count is recomputed as a whole and
+// trailing memories are dropped until it fits. The returned string is
+// guaranteed to satisfy EstimateTokens(out) <= budget.
 func FormatMemoriesWithBudget(memories []string, budget int) (string, int) {
 	if budget <= 0 || len(memories) == 0 {
 		return "", 0
 	}
 
-	var builder strings.Builder
-	count := 0
+	selected := make([]string, 0, len(memories))
 	usedTokens := 0
 
 	for _, mem := range memories {
@@ -75,13 +82,144 @@ func FormatMemoriesWithBudget(memories []string, budget int) (string, int) {
 		if usedTokens+memTokens > budget {
 			break
 		}
-		if count > 0 {
-			builder.WriteString("\n---\n")
+		selected = append(selected, mem)
+		usedTokens += memTokens
+	}
+
+	// Hard post-formatting check: the heuristic can overshoot once memories
+	// are joined, so trim from the tail until the whole output fits.
+	out := strings.Join(selected, memorySeparator)
+	for len(selected) > 0 && EstimateTokens(out) > budget {
+		selected = selected[:len(selected)-1]
+		out = strings.Join(selected, memorySeparator)
+	}
+
+	return out, len(selected)
+}
+
+// minTruncatedMemoryTokens is the smallest per-memory allowance used when
+// FormatMemoriesWithMinimum has to shrink memories to reach its minimum count.
+const minTruncatedMemoryTokens = 16
+
+// FormatMemoriesWithMinimum behaves like FormatMemoriesWithBudget but
+// guarantees that at least minCount memories (or all of them, if fewer are
+// available) are returned. When the strict budget would yield fewer, the top
+// minCount memories are each truncated to an equal share of the budget, with
+// a floor of minTruncatedMemoryTokens. The output may therefore exceed budget;
+// callers opt into this trade-off by passing a positive minCount.
+func FormatMemoriesWithMinimum(memories []string, budget, minCount int) (string, int) {
+	out, count := FormatMemoriesWithBudget(memories, budget)
+	if minCount <= 0 || count >= minCount || count == len(memories) {
+		return out, count
+	}
+
+	n := minCount
+	if n > len(memories) {
+		n = len(memories)
+	}
+	perMemory := budget/n - 2 // -2 for separator
+	if perMemory < minTruncatedMemoryTokens {
+		perMemory = minTruncatedMemoryTokens
+	}
+
+	truncated := make([]string, n)
+	for i := 0; i < n; i++ {
+		truncated[i] = TruncateToTokenBudget(memories[i], perMemory)
+	}
+	return strings.Join(truncated, memorySeparator), n
+}
+
+// FormatMemoriesWithLimits behaves like FormatMemoriesWithMinimum but never
+// returns more than maxCount memories, even when the budget has room for
+// more. The count cap takes precedence over minCount. maxCount <= 0 disables
+// the cap.
+func FormatMemoriesWithLimits(memories []string, budget, minCount, maxCount int) (string, int) {
+	if maxCount > 0 && len(memories) > maxCount {
+		memories = memories[:maxCount]
+	}
+	return FormatMemoriesWithMinimum(memories, budget, minCount)
+}
+
+// groupSeparator is placed between the sections of a grouped context.
+const groupSeparator = "\n\n"
+
+// FormatGroupedWithLimits behaves like FormatMemoriesWithLimits but lays the
+// selected memories out under headings: headings[i] names the group of
+// memories[i]. Groups appear in order of their first (highest-ranked) member
+// and memories keep their relative order within a group. Headings count
+// against the budget, and selection still takes memories strictly in input
+// order, so the count has the same meaning as for the flat formatters.
+func FormatGroupedWithLimits(memories, headings []string, budget, minCount, maxCount int) (string, int) {
+	if maxCount > 0 && len(memories) > maxCount {
+		memories = memories[:maxCount]
+	}
+	if len(headings) < len(memories) {
+		memories = memories[:len(headings)]
+	}
+	if budget <= 0 || len(memories) == 0 {
+		return "", 0
+	}
+
+	count := 0
+	usedTokens := 0
+	seen := make(map[string]bool)
+	for i, mem := range memories {
+		memTokens := EstimateTokens(mem) + 2 // +2 for separator
+		if !seen[headings[i]] {
+			memTokens += EstimateTokens(headings[i]) + 2
+		}
+		if usedTokens+memTokens > budget {
+			break
 		}
-		builder.WriteString(mem)
+		seen[headings[i]] = true
 		usedTokens += memTokens
 		count++
 	}
 
-	return builder.String(), count
+	out := joinGrouped(memories[:count], headings)
+	for count > 0 && EstimateTokens(out) > budget {
+		count--
+		out = joinGrouped(memories[:count], headings)
+	}
+
+	if minCount <= 0 || count >= minCount || count == len(memories) {
+		return out, count
+	}
+
+	// Same trade-off as FormatMemoriesWithMinimum: shrink the top minCount
+	// memories to an equal share of the budget.
+	n := minCount
+	if n > len(memories) {
+		n = len(memories)
+	}
+	perMemory := budget/n - 2 // -2 for separator
+	if perMemory < minTruncatedMemoryTokens {
+		perMemory = minTruncatedMemoryTokens
+	}
+	truncated := make([]string, n)
+	for i := 0; i < n; i++ {
+		truncated[i] = TruncateToTokenBudget(memories[i], perMemory)
+	}
+	return joinGrouped(truncated, headings), n
+}
+
+// joinGrouped renders memories under headings[i] (see FormatGroupedWithLimits).
+func joinGrouped(memories, headings []string) string {
+	if len(memories) == 0 {
+		return ""
+	}
+	var order []string
+	groups := make(map[string][]string)
+	for i, mem := range memories {
+		h := headings[i]
+		if _, ok := groups[h]; !ok {
+			order = append(order, h)
+		}
+		groups[h] = append(groups[h], mem)
+	}
+	sections := make([]string, len(order))
+	for i, h := range order {
+		sections[i] = h + "\n" + strings.Join(groups[h], memorySeparator)
+	}
+	return strings.Join(sections, groupSeparator)
 }
@@ -20,3 +20,32 @@ func CosineSimilarity(a, b []float32) float64 {
 	}
 	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
+
+// Norm returns the Euclidean (L2) norm of v.
+func Norm(v []float32) float64 {
+	var sum float64
+	for i := range v {
+		sum += float64(v[i]) * float64(v[i])
+	}
+	return math.Sqrt(sum)
+}
+
+// Blend returns the weighted average (1-weight)*â + weight*b̂ of the unit
+// vectors of a and b, so neither input dominates because of its magnitude.
+// weight is clamped to [0, 1]. Returns nil if the lengths differ, either
+// vector is empty or either has a zero norm.
+func Blend(a, b []float32, weight float64) []float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return nil
+	}
+	normA, normB := Norm(a), Norm(b)
+	if normA == 0 || normB == 0 {
+		return nil
+	}
+	weight = math.Max(0, math.Min(1, weight))
+	out := make([]float32, len(a))
+	for i := range a {
+		out[i] = float32((1-weight)*float64(a[i])/normA + weight*float64(b[i])/normB)
+	}
+	return out
+}
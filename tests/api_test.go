@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/api"
+	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
@@ -102,6 +105,40 @@ func TestAPI_Healthz(t *testing.T) {
 	assert.Equal(t, "ok", result["status"])
 }
 
+// pingFailStore is a MockStore whose Ping fails, simulating an unreachable store.
+type pingFailStore struct {
+	*store.MockStore
+}
+
+func (s *pingFailStore) Ping(_ context.Context) error {
+	return errors.New("connection refused")
+}
+
+// TestAPI_Readyz verifies that GET /readyz reflects store reachability.
+func TestAPI_Readyz(t *testing.T) {
+	ts, _ := newTestServer(t, "secret")
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/readyz", nil, "")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "no auth required")
+	var result map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, "ready", result["status"])
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	srv := api.NewServer(&pingFailStore{store.NewMockStore()}, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	down := httptest.NewServer(srv.Handler())
+	t.Cleanup(down.Close)
+
+	resp2 := doRequest(t, http.MethodGet, down.URL+"/readyz", nil, "")
+	defer resp2.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp2.StatusCode)
+
+	resp3 := doRequest(t, http.MethodGet, down.URL+"/healthz", nil, "")
+	defer resp3.Body.Close()
+	assert.Equal(t, http.StatusOK, resp3.StatusCode, "liveness does not depend on the store")
+}
+
 // TestAPI_Remember stores a memory and verifies the response.
 func TestAPI_Remember(t *testing.T) {
 	ts, st := newTestServer(t, "")
@@ -131,6 +168,119 @@ func TestAPI_Remember(t *testing.T) {
 	assert.Equal(t, "Go is a statically typed language", mem.Content)
 }
 
+// TestAPI_RememberExpiresAt stores the expiry date sent with a memory.
+func TestAPI_RememberExpiresAt(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	body := jsonBody(t, map[string]any{
+		"content":    "The staging cluster is decommissioned after Q3",
+		"scope":      "permanent",
+		"expires_at": "2030-10-01T00:00:00Z",
+	})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/remember", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	mem, err := st.Get(context.Background(), result["id"].(string))
+	require.NoError(t, err)
+	assert.True(t, mem.ExpiresAt.Equal(time.Date(2030, 10, 1, 0, 0, 0, 0, time.UTC)), "got %v", mem.ExpiresAt)
+}
+
+// TestAPI_RememberAttachments stores attachment references with a memory and
+// rejects malformed ones.
+func TestAPI_RememberAttachments(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	body := jsonBody(t, map[string]any{
+		"content":     "The auth flow is described in the design doc",
+		"attachments": []map[string]string{{"type": "file", "ref": "docs/auth.md"}},
+	})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/remember", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	mem, err := st.Get(context.Background(), result["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, []models.Attachment{{Type: models.AttachmentFile, Ref: "docs/auth.md"}}, mem.Attachments)
+
+	bad := jsonBody(t, map[string]any{
+		"content":     "The auth flow is described in the design doc",
+		"attachments": []map[string]string{{"type": "ticket", "ref": "JIRA-1"}},
+	})
+	resp2 := doRequest(t, http.MethodPost, ts.URL+"/v1/remember", bad, "")
+	defer resp2.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
+}
+
+// TestAPI_RememberNormalizesTags verifies that the configured tag normalizer
+// applies to memories stored through the API, as it does on the CLI.
+func TestAPI_RememberNormalizesTags(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	srv.SetTagNormalizer(store.NewTagNormalizer(map[string]string{"golang": "go"}))
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	body := jsonBody(t, map[string]any{"content": "Prefer table-driven tests", "tags": []string{" GoLang", "Testing", "go"}})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/remember", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	mem, err := st.Get(context.Background(), result["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "testing"}, mem.Tags)
+	assert.Equal(t, []string{" GoLang", "Testing", "go"}, mem.Metadata["raw_tags"])
+}
+
+// TestAPI_ConsolidatePreview verifies that the preview reports the duplicate
+// cluster consolidation would merge without deleting anything.
+func TestAPI_ConsolidatePreview(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	ctx := context.Background()
+	now := time.Now().UTC()
+	vec, _ := (&apiTestEmbedder{}).Embed(ctx, "")
+	for id, conf := range map[string]float64{"dup-a": 0.5, "dup-b": 0.9, "dup-c": 0.7} {
+		require.NoError(t, st.Upsert(ctx, models.Memory{
+			ID: id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Content: "Deploys go through the staging cluster first", Confidence: conf,
+			CreatedAt: now, UpdatedAt: now, LastAccessed: now,
+		}, vec))
+	}
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/lifecycle/consolidate/preview", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Clusters []struct {
+			Survivor string   `json:"survivor"`
+			Members  []string `json:"members"`
+			Pairs    []struct {
+				Similarity float64 `json:"similarity"`
+			} `json:"pairs"`
+		} `json:"clusters"`
+		WouldDelete int `json:"would_delete"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Clusters, 1)
+	assert.Equal(t, "dup-b", result.Clusters[0].Survivor, "highest confidence survives")
+	assert.ElementsMatch(t, []string{"dup-a", "dup-b", "dup-c"}, result.Clusters[0].Members)
+	assert.Greater(t, result.Clusters[0].Pairs[0].Similarity, 0.9)
+	assert.Equal(t, 2, result.WouldDelete)
+
+	for _, id := range []string{"dup-a", "dup-b", "dup-c"} {
+		_, err := st.Get(ctx, id)
+		assert.NoError(t, err, "preview must not delete %s", id)
+	}
+}
+
 // TestAPI_GetMemory retrieves a stored memory by ID.
 func TestAPI_GetMemory(t *testing.T) {
 	ts, st := newTestServer(t, "")
@@ -208,6 +358,49 @@ func TestAPI_DeleteMemory(t *testing.T) {
 }
 
 // TestAPI_DeleteMemory_NotFound verifies 404 for a non-existent ID.
+func TestAPI_DeleteMemory_Trash(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	srv.SetTrashEnabled(true)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	now := time.Now().UTC()
+	mem := models.Memory{
+		ID:         "trash-test-001",
+		Type:       models.MemoryTypeFact,
+		Scope:      models.ScopePermanent,
+		Visibility: models.VisibilityShared,
+		Content:    "Trash me",
+		Confidence: 0.9,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	require.NoError(t, st.Upsert(context.Background(), mem, make([]float32, 768)))
+
+	resp := doRequest(t, http.MethodDelete, ts.URL+"/v1/memories/trash-test-001", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var result map[string]bool
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.True(t, result["trashed"])
+
+	// Trashed memories are hidden but still restorable.
+	getResp := doRequest(t, http.MethodGet, ts.URL+"/v1/memories/trash-test-001", nil, "")
+	defer getResp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, getResp.StatusCode)
+	_, err := st.Restore(context.Background(), "trash-test-001")
+	require.NoError(t, err)
+
+	// permanent=true bypasses the trash.
+	permResp := doRequest(t, http.MethodDelete, ts.URL+"/v1/memories/trash-test-001?permanent=true", nil, "")
+	defer permResp.Body.Close()
+	require.Equal(t, http.StatusOK, permResp.StatusCode)
+	_, err = st.Restore(context.Background(), "trash-test-001")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
 func TestAPI_DeleteMemory_NotFound(t *testing.T) {
 	ts, _ := newTestServer(t, "")
 
@@ -295,6 +488,53 @@ func TestAPI_Search(t *testing.T) {
 	assert.GreaterOrEqual(t, len(results), 1)
 }
 
+// TestAPI_CheckDuplicate returns similar memories without storing anything.
+func TestAPI_CheckDuplicate(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	mem := models.Memory{
+		ID:           "dup-check-001",
+		Type:         models.MemoryTypeFact,
+		Scope:        models.ScopePermanent,
+		Visibility:   models.VisibilityPrivate,
+		Content:      "Rust is a systems language",
+		Confidence:   0.9,
+		Source:       "test",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		LastAccessed: now,
+	}
+	vec := make([]float32, 768)
+	for i := range vec {
+		vec[i] = 0.1
+	}
+	require.NoError(t, st.Upsert(context.Background(), mem, vec))
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/check-duplicate",
+		jsonBody(t, map[string]any{"content": "Rust is a systems programming language"}), "")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "dedup is off on the test server, so threshold is required")
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/check-duplicate",
+		jsonBody(t, map[string]any{"content": "Rust is a systems programming language", "threshold": 0.9}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Duplicates []models.SearchResult `json:"duplicates"`
+		Threshold  float64               `json:"threshold"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Duplicates, 1)
+	assert.Equal(t, "dup-check-001", result.Duplicates[0].Memory.ID)
+	assert.InDelta(t, 0.9, result.Threshold, 1e-9)
+
+	stats, err := st.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalMemories, "check-duplicate must not store")
+}
+
 // TestAPI_Stats returns collection statistics.
 func TestAPI_Stats(t *testing.T) {
 	ts, st := newTestServer(t, "")
@@ -427,6 +667,82 @@ func TestAPI_Search_MissingMessage(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+// TestAPI_PrecomputedVector verifies that recall and search accept a
+// client-supplied query vector without calling the embedder, and reject one
+// of the wrong dimension.
+func TestAPI_PrecomputedVector(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	rec := recall.NewRecaller(recall.DefaultWeights(), logger)
+	// failEmbedder (dimension 3) errors on every call, so any success below
+	// proves the embedder was skipped.
+	srv := api.NewServer(st, rec, &failEmbedder{}, logger, "", "")
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	now := time.Now().UTC()
+	mem := models.Memory{
+		ID:           "vec-001",
+		Type:         models.MemoryTypeFact,
+		Scope:        models.ScopePermanent,
+		Visibility:   models.VisibilityPrivate,
+		Content:      "Rust is a systems language",
+		Confidence:   0.9,
+		Source:       "test",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		LastAccessed: now,
+	}
+	require.NoError(t, st.Upsert(context.Background(), mem, []float32{1, 0, 0}))
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/search", jsonBody(t, map[string]any{"vector": []float32{1, 0, 0}}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var search struct {
+		Results []models.SearchResult `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&search))
+	require.Len(t, search.Results, 1)
+	assert.Equal(t, "vec-001", search.Results[0].Memory.ID)
+
+	resp2 := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"vector": []float32{1, 0, 0}}), "")
+	defer resp2.Body.Close()
+	require.Equal(t, http.StatusOK, resp2.StatusCode)
+	var recallResp map[string]any
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&recallResp))
+	assert.Contains(t, recallResp["context"], "Rust is a systems language")
+
+	for _, path := range []string{"/v1/search", "/v1/recall"} {
+		bad := doRequest(t, http.MethodPost, ts.URL+path, jsonBody(t, map[string]any{"message": "rust", "vector": []float32{1, 0}}), "")
+		assert.Equal(t, http.StatusBadRequest, bad.StatusCode, path)
+		_ = bad.Body.Close()
+	}
+}
+
+// unknownDimEmbedder reports dimension 0, like LM Studio before its first call.
+type unknownDimEmbedder struct{ failEmbedder }
+
+func (*unknownDimEmbedder) Dimension() int { return 0 }
+
+// TestAPI_PrecomputedVector_ConfiguredDimension verifies that supplied vectors
+// are checked against the configured dimension when the embedder does not
+// know its own.
+func TestAPI_PrecomputedVector_ConfiguredDimension(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	srv := api.NewServer(store.NewMockStore(), recall.NewRecaller(recall.DefaultWeights(), logger), &unknownDimEmbedder{}, logger, "", "")
+	srv.SetVectorDimension(3)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/search", jsonBody(t, map[string]any{"vector": []float32{1, 0}}), "")
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+	_ = bad.Body.Close()
+
+	good := doRequest(t, http.MethodPost, ts.URL+"/v1/search", jsonBody(t, map[string]any{"vector": []float32{1, 0, 0}}), "")
+	assert.Equal(t, http.StatusOK, good.StatusCode)
+	_ = good.Body.Close()
+}
+
 // TestAPISearch_LimitCapped verifies that an absurdly large search limit is
 // silently capped to 1000 rather than blowing up.
 func TestAPISearch_LimitCapped(t *testing.T) {
@@ -486,3 +802,668 @@ func TestAPI_Remember_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
+
+// TestAPI_Recall_NegativeExpandDepth verifies 400 for a negative expand_depth.
+func TestAPI_Recall_NegativeExpandDepth(t *testing.T) {
+	ts, _ := newTestServer(t, "")
+
+	body := jsonBody(t, map[string]any{"message": "x", "expand_depth": -1})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", body, "")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAPI_Recall_MaxMemories(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	vec := make([]float32, 768)
+	for i := range vec {
+		vec[i] = 0.1
+	}
+	for _, id := range []string{"cap-1", "cap-2", "cap-3"} {
+		require.NoError(t, st.Upsert(context.Background(), models.Memory{
+			ID: id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Visibility: models.VisibilityShared, Content: "Go fact " + id, Confidence: 0.9,
+			CreatedAt: now, UpdatedAt: now, LastAccessed: now,
+		}, vec))
+	}
+
+	body := jsonBody(t, map[string]any{"message": "Go", "budget": 2000, "max_memories": 2})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, float64(2), result["memory_count"])
+	assert.Equal(t, float64(3), result["total_candidates"])
+	assert.Equal(t, true, result["truncated"])
+
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "x", "max_memories": -1}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
+func TestAPI_Recall_ContextJSON(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	vec := make([]float32, 768)
+	for i := range vec {
+		vec[i] = 0.1
+	}
+	for _, id := range []string{"cj-1", "cj-2", "cj-3"} {
+		require.NoError(t, st.Upsert(context.Background(), models.Memory{
+			ID: id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Visibility: models.VisibilityShared, Content: "Go fact " + id, Confidence: 0.9,
+			CreatedAt: now, UpdatedAt: now, LastAccessed: now,
+		}, vec))
+	}
+
+	body := jsonBody(t, map[string]any{"message": "Go", "max_memories": 2})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Context     string               `json:"context"`
+		MemoryCount int                  `json:"memory_count"`
+		ContextJSON []recall.ContextItem `json:"context_json"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.ContextJSON, result.MemoryCount, "exactly the memories in the budget")
+	require.Len(t, result.ContextJSON, 2)
+	for _, item := range result.ContextJSON {
+		assert.Contains(t, result.Context, item.Content)
+		assert.Equal(t, models.MemoryTypeFact, item.Type)
+		assert.Positive(t, item.Tokens)
+	}
+	assert.GreaterOrEqual(t, result.ContextJSON[0].Score, result.ContextJSON[1].Score, "rank order")
+}
+
+func TestAPI_Recall_TopMode(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	vec := make([]float32, 768)
+	for i := range vec {
+		vec[i] = 0.1
+	}
+	mems := []models.Memory{
+		{ID: "top-episode", Type: models.MemoryTypeEpisode, Content: "We fixed the flaky deploy yesterday", LastAccessed: now.Add(-60 * 24 * time.Hour)},
+		{ID: "top-rule", Type: models.MemoryTypeRule, Content: "Always run migrations before deploying", LastAccessed: now, AccessCount: 40},
+	}
+	for _, m := range mems {
+		m.Scope, m.Visibility, m.Confidence = models.ScopePermanent, models.VisibilityShared, 0.9
+		m.CreatedAt, m.UpdatedAt = now, now
+		require.NoError(t, st.Upsert(context.Background(), m, vec))
+	}
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"mode": "top"}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var result struct {
+		MemoryCount int                  `json:"memory_count"`
+		ContextJSON []recall.ContextItem `json:"context_json"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Equal(t, 2, result.MemoryCount)
+	assert.Equal(t, "top-rule", result.ContextJSON[0].ID, "ranked by the non-similarity factors")
+
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"mode": "top", "message": "deploy"}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode, "top takes no query")
+
+	missing := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{}), "")
+	defer missing.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, missing.StatusCode, "an empty message without mode top is still an error")
+}
+
+func TestAPI_GetVector(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	vec := []float32{0.1, 0.2, 0.3}
+	for _, id := range []string{"vec-1", "vec-2"} {
+		require.NoError(t, st.Upsert(context.Background(), models.Memory{
+			ID: id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Content: "vector " + id, CreatedAt: now, UpdatedAt: now,
+		}, vec))
+	}
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/memories/vec-1/vector", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var one struct {
+		ID        string    `json:"id"`
+		Dimension int       `json:"dimension"`
+		Vector    []float32 `json:"vector"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&one))
+	assert.Equal(t, "vec-1", one.ID)
+	assert.Equal(t, 3, one.Dimension)
+	assert.Equal(t, vec, one.Vector)
+
+	missing := doRequest(t, http.MethodGet, ts.URL+"/v1/memories/nope/vector", nil, "")
+	defer missing.Body.Close()
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+
+	bulk := doRequest(t, http.MethodPost, ts.URL+"/v1/vectors", jsonBody(t, map[string]any{"ids": []string{"vec-2", "nope"}}), "")
+	defer bulk.Body.Close()
+	require.Equal(t, http.StatusOK, bulk.StatusCode)
+	var many struct {
+		Vectors []struct {
+			ID string `json:"id"`
+		} `json:"vectors"`
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.NewDecoder(bulk.Body).Decode(&many))
+	require.Equal(t, 1, many.Count)
+	assert.Equal(t, "vec-2", many.Vectors[0].ID)
+
+	filtered := doRequest(t, http.MethodPost, ts.URL+"/v1/vectors", jsonBody(t, map[string]any{"scope": "permanent"}), "")
+	defer filtered.Body.Close()
+	require.Equal(t, http.StatusOK, filtered.StatusCode)
+	require.NoError(t, json.NewDecoder(filtered.Body).Decode(&many))
+	assert.Equal(t, 2, many.Count)
+}
+
+// TestAPI_SessionMemories verifies that only memories captured in the
+// requested session are returned, ordered by creation time.
+func TestAPI_SessionMemories(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	base := time.Now().UTC()
+	seed := []struct {
+		id      string
+		session string
+		offset  time.Duration
+	}{
+		{"sess-b", "sess-1", 2 * time.Minute},
+		{"sess-a", "sess-1", 1 * time.Minute},
+		{"sess-other", "sess-2", 0},
+		{"sess-c", "sess-1", 3 * time.Minute},
+	}
+	vec := make([]float32, 768)
+	for _, sd := range seed {
+		created := base.Add(sd.offset)
+		mem := models.Memory{
+			ID:           sd.id,
+			Type:         models.MemoryTypeFact,
+			Scope:        models.ScopeSession,
+			Visibility:   models.VisibilityPrivate,
+			Content:      "content " + sd.id,
+			Confidence:   0.9,
+			Source:       "test",
+			CreatedAt:    created,
+			UpdatedAt:    created,
+			LastAccessed: created,
+			Metadata:     map[string]any{"session_id": sd.session},
+		}
+		require.NoError(t, st.Upsert(context.Background(), mem, vec))
+	}
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/sessions/sess-1/memories", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got struct {
+		SessionID string          `json:"session_id"`
+		Memories  []models.Memory `json:"memories"`
+		Count     int             `json:"count"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, "sess-1", got.SessionID)
+	assert.Equal(t, 3, got.Count)
+	require.Len(t, got.Memories, 3)
+	assert.Equal(t, "sess-a", got.Memories[0].ID)
+	assert.Equal(t, "sess-b", got.Memories[1].ID)
+	assert.Equal(t, "sess-c", got.Memories[2].ID)
+}
+
+// TestAPI_SessionMemories_Empty verifies an unknown session yields an empty list.
+func TestAPI_SessionMemories_Empty(t *testing.T) {
+	ts, _ := newTestServer(t, "")
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/sessions/none/memories", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, []any{}, got["memories"])
+}
+
+func TestAPI_Preferences(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	base := time.Now().UTC()
+	vec := make([]float32, 768)
+	seed := []struct {
+		id     string
+		typ    models.MemoryType
+		pref   *models.Preference
+		offset time.Duration
+	}{
+		{"pref-old", models.MemoryTypePreference, &models.Preference{Subject: "Ajit", Predicate: "likes", Object: "drone piloting"}, 0},
+		{"pref-new", models.MemoryTypePreference, &models.Preference{Subject: " ajit ", Predicate: "dislikes", Object: "long meetings"}, time.Minute},
+		{"pref-other", models.MemoryTypePreference, &models.Preference{Subject: "Sam", Predicate: "likes", Object: "tea"}, 0},
+		{"pref-untyped", models.MemoryTypePreference, nil, 0},
+	}
+	for _, sd := range seed {
+		created := base.Add(sd.offset)
+		mem := models.Memory{
+			ID:           sd.id,
+			Type:         sd.typ,
+			Scope:        models.ScopePermanent,
+			Visibility:   models.VisibilityPrivate,
+			Content:      "content " + sd.id,
+			Confidence:   0.9,
+			Source:       "test",
+			CreatedAt:    created,
+			UpdatedAt:    created,
+			LastAccessed: created,
+		}
+		if sd.pref != nil {
+			mem.SetPreference(*sd.pref)
+		}
+		require.NoError(t, st.Upsert(context.Background(), mem, vec))
+	}
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/preferences?subject=AJIT", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got struct {
+		Subject     string `json:"subject"`
+		Preferences []struct {
+			ID        string `json:"id"`
+			Subject   string `json:"subject"`
+			Predicate string `json:"predicate"`
+			Object    string `json:"object"`
+		} `json:"preferences"`
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, 2, got.Count)
+	assert.Equal(t, "pref-new", got.Preferences[0].ID, "newest first")
+	assert.Equal(t, "ajit", got.Preferences[0].Subject)
+	assert.Equal(t, "dislikes", got.Preferences[0].Predicate)
+	assert.Equal(t, "drone piloting", got.Preferences[1].Object)
+
+	missing := doRequest(t, http.MethodGet, ts.URL+"/v1/preferences", nil, "")
+	defer missing.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, missing.StatusCode)
+}
+
+func TestAPI_Recall_SynthesizeMode(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	now := time.Now().UTC()
+	vec := make([]float32, 768)
+	for i := range vec {
+		vec[i] = 0.1
+	}
+	require.NoError(t, st.Upsert(context.Background(), models.Memory{
+		ID: "syn-1", Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		Visibility: models.VisibilityShared, Content: "Deploys run on Fridays", Confidence: 0.9,
+		CreatedAt: now, UpdatedAt: now, LastAccessed: now,
+	}, vec))
+
+	// Not enabled: rejected.
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "deploys", "mode": "synthesize"}), "")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Unknown mode: rejected.
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "deploys", "mode": "essay"}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+
+	srv.SetSynthesizer(recall.NewSynthesizer(&mockLLMClient{Resp: "  Deploys happen on Fridays.  "}, "claude-haiku", logger))
+	ok := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "deploys", "mode": "synthesize"}), "")
+	defer ok.Body.Close()
+	require.Equal(t, http.StatusOK, ok.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(ok.Body).Decode(&result))
+	assert.Equal(t, "Deploys happen on Fridays.", result["context"])
+	assert.Equal(t, true, result["synthesized"])
+	assert.Equal(t, float64(1), result["memory_count"])
+}
+
+func TestAPI_BatchGet(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	vec := make([]float32, 768)
+	for _, id := range []string{"bg-1", "bg-2", "bg-trashed"} {
+		require.NoError(t, st.Upsert(context.Background(), models.Memory{
+			ID: id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Visibility: models.VisibilityShared, Content: "content " + id, Confidence: 0.9,
+			CreatedAt: now, UpdatedAt: now, LastAccessed: now,
+		}, vec))
+	}
+	_, err := st.Trash(context.Background(), "bg-trashed")
+	require.NoError(t, err)
+
+	body := jsonBody(t, map[string]any{"ids": []string{"bg-2", "nope", "bg-1", "bg-2", "bg-trashed"}})
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/memories/batch-get", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got struct {
+		Memories []models.Memory `json:"memories"`
+		Missing  []string        `json:"missing"`
+		Count    int             `json:"count"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, 2, got.Count)
+	assert.Equal(t, "bg-2", got.Memories[0].ID, "request order is preserved")
+	assert.Equal(t, "bg-1", got.Memories[1].ID)
+	assert.Equal(t, []string{"nope", "bg-trashed"}, got.Missing)
+
+	empty := doRequest(t, http.MethodPost, ts.URL+"/v1/memories/batch-get", jsonBody(t, map[string]any{"ids": []string{}}), "")
+	defer empty.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, empty.StatusCode)
+}
+
+func TestAPI_Recall_Freshness(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	vec := make([]float32, 768)
+	for i := range vec {
+		vec[i] = 0.1
+	}
+	old := now.Add(-90 * 24 * time.Hour)
+	require.NoError(t, st.Upsert(context.Background(), models.Memory{
+		ID: "fresh-old", Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		Visibility: models.VisibilityShared, Content: "Old deploy rule", Confidence: 0.9,
+		CreatedAt: old, UpdatedAt: old, LastAccessed: old,
+	}, vec))
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "deploys", "freshness": "recent"}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.EqualValues(t, 0, got["memory_count"], "a 90-day-old memory is not recent")
+
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "deploys", "freshness": "ancient"}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
+// blockingEmbedder embeds like apiTestEmbedder but first waits for release,
+// signalling on started once it holds the call.
+type blockingEmbedder struct {
+	apiTestEmbedder
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.apiTestEmbedder.Embed(ctx, text)
+}
+
+func TestAPI_EmbedConcurrencyLimit(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError + 4}))
+	emb := &blockingEmbedder{started: make(chan struct{}, 1), release: make(chan struct{})}
+	srv := api.NewServer(store.NewMockStore(), recall.NewRecaller(recall.DefaultWeights(), logger), emb, logger, "", "")
+	srv.SetEmbedConcurrency(1, 50*time.Millisecond)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	body := map[string]any{"message": "deploys"}
+	firstDone := make(chan int, 1)
+	go func() {
+		resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, body), "")
+		_ = resp.Body.Close()
+		firstDone <- resp.StatusCode
+	}()
+	<-emb.started // the first request now holds the only slot
+
+	busy := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, body), "")
+	defer busy.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, busy.StatusCode)
+	assert.NotEmpty(t, busy.Header.Get("Retry-After"))
+
+	close(emb.release)
+	assert.Equal(t, http.StatusOK, <-firstDone)
+}
+
+func TestAPI_Schema(t *testing.T) {
+	ts, _ := newTestServer(t, "")
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/schema", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got struct {
+		MemoryTypes        []string `json:"memory_types"`
+		MemoryScopes       []string `json:"memory_scopes"`
+		MemoryVisibilities []string `json:"memory_visibilities"`
+		EntityTypes        []string `json:"entity_types"`
+		RecallWeightFields []string `json:"recall_weight_fields"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Len(t, got.MemoryTypes, len(models.ValidMemoryTypes))
+	assert.Contains(t, got.MemoryTypes, "preference")
+	assert.Contains(t, got.MemoryScopes, "ttl")
+	assert.Equal(t, []string{"private", "shared", "sensitive"}, got.MemoryVisibilities)
+	assert.Contains(t, got.EntityTypes, "person")
+	assert.Contains(t, got.RecallWeightFields, "similarity")
+	assert.Contains(t, got.RecallWeightFields, "graph_proximity")
+}
+
+func TestAPI_RecallFeedbackLog(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	seedMemory(t, st, newTestMemory("fb-1", models.MemoryTypeRule, "Deploys go through the staging cluster first"))
+
+	// Feedback is rejected while logging is disabled.
+	off := doRequest(t, http.MethodPost, ts.URL+"/v1/recall/feedback", jsonBody(t, map[string]any{"recall_id": "x", "useful": []string{"fb-1"}}), "")
+	defer off.Body.Close()
+	assert.Equal(t, http.StatusNotFound, off.StatusCode)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	path := filepath.Join(t.TempDir(), "feedback.jsonl")
+	fl, err := recall.OpenFeedbackLog(path)
+	require.NoError(t, err)
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	srv.SetFeedbackLog(fl)
+	logged := httptest.NewServer(srv.Handler())
+	t.Cleanup(logged.Close)
+
+	resp := doRequest(t, http.MethodPost, logged.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "deploys"}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	recallID, _ := got["recall_id"].(string)
+	require.NotEmpty(t, recallID)
+
+	fb := doRequest(t, http.MethodPost, logged.URL+"/v1/recall/feedback", jsonBody(t, map[string]any{"recall_id": recallID, "useful": []string{"fb-1"}}), "")
+	defer fb.Body.Close()
+	assert.Equal(t, http.StatusOK, fb.StatusCode)
+
+	bad := doRequest(t, http.MethodPost, logged.URL+"/v1/recall/feedback", jsonBody(t, map[string]any{"recall_id": recallID}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+
+	require.NoError(t, fl.Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var rec, feedback recall.FeedbackEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &feedback))
+	assert.Equal(t, recall.FeedbackEventRecall, rec.Event)
+	assert.Equal(t, recallID, rec.RecallID)
+	assert.Equal(t, "deploys", rec.Query)
+	assert.Len(t, rec.QueryVector, 768)
+	require.NotEmpty(t, rec.Results)
+	assert.Equal(t, "fb-1", rec.Results[0].ID)
+	assert.Equal(t, recall.FeedbackEventFeedback, feedback.Event)
+	assert.Equal(t, recallID, feedback.RecallID)
+	assert.Equal(t, []string{"fb-1"}, feedback.Useful)
+}
+
+func TestAPI_Remember_ConflictWarning(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	existing := newTestMemory("existing", models.MemoryTypeFact, "Releases ship on Tuesdays")
+	existing.Confidence = 0.9
+	seedMemory(t, st, existing)
+
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	srv.SetConflictDetector(capture.NewConflictDetector(&mockLLMClient{
+		Resp: `{"contradicts": true, "contradicted_id": "existing", "reason": "different release day"}`,
+	}, "test-model", logger))
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/remember", jsonBody(t, map[string]any{"content": "Releases ship on Fridays"}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got struct {
+		ID       string `json:"id"`
+		Stored   bool   `json:"stored"`
+		Conflict *struct {
+			ConflictingID string `json:"conflicting_id"`
+			Reason        string `json:"reason"`
+		} `json:"conflict"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.True(t, got.Stored, "a conflict warns but still stores")
+	require.NotNil(t, got.Conflict)
+	assert.Equal(t, "existing", got.Conflict.ConflictingID)
+
+	_, err := st.Get(context.Background(), got.ID)
+	assert.NoError(t, err)
+}
+
+func TestAPI_Remember_DryRunAndDedup(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	srv.SetDedup(0.92, store.DedupScope{})
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	type rememberResult struct {
+		ID          string `json:"id"`
+		Stored      bool   `json:"stored"`
+		DuplicateOf string `json:"duplicate_of"`
+		WouldStore  *bool  `json:"would_store"`
+	}
+	remember := func(body map[string]any) rememberResult {
+		t.Helper()
+		resp := doRequest(t, http.MethodPost, ts.URL+"/v1/remember", jsonBody(t, body), "")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var got rememberResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		return got
+	}
+	count := func() int64 {
+		t.Helper()
+		stats, err := st.Stats(context.Background())
+		require.NoError(t, err)
+		return stats.TotalMemories
+	}
+
+	// Dry run on an empty store: would store, writes nothing.
+	got := remember(map[string]any{"content": "Releases ship on Tuesdays", "dry_run": true})
+	require.NotNil(t, got.WouldStore)
+	assert.True(t, *got.WouldStore)
+	assert.False(t, got.Stored)
+	assert.Empty(t, got.DuplicateOf)
+	assert.Equal(t, int64(0), count())
+
+	seedMemory(t, st, newTestMemory("existing", models.MemoryTypeFact, "Releases ship on Tuesdays"))
+
+	// Dry run against a near-identical memory: reports the duplicate.
+	got = remember(map[string]any{"content": "Releases ship on Tuesdays", "dry_run": true})
+	require.NotNil(t, got.WouldStore)
+	assert.False(t, *got.WouldStore)
+	assert.Equal(t, "existing", got.DuplicateOf)
+	assert.Equal(t, int64(1), count())
+
+	// Without dedup_on_remember a real remember stores the duplicate.
+	got = remember(map[string]any{"content": "Releases ship on Tuesdays"})
+	assert.Nil(t, got.WouldStore, "would_store is only set for dry runs")
+	assert.True(t, got.Stored)
+	assert.Empty(t, got.DuplicateOf)
+	assert.Equal(t, int64(2), count())
+
+	// With it, a real remember of the duplicate is skipped and names the
+	// most similar existing memory.
+	dedupSrv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	dedupSrv.SetDedup(0.92, store.DedupScope{})
+	dedupSrv.SetDedupOnRemember(true)
+	ts = httptest.NewServer(dedupSrv.Handler())
+	t.Cleanup(ts.Close)
+	got = remember(map[string]any{"content": "Releases ship on Tuesdays"})
+	assert.False(t, got.Stored)
+	assert.NotEmpty(t, got.DuplicateOf)
+	assert.Equal(t, got.DuplicateOf, got.ID)
+	assert.Equal(t, int64(2), count())
+}
+
+func TestAPI_Recall_GroupedFormat(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	seedMemory(t, st, newTestMemory("g-rule", models.MemoryTypeRule, "Always run the linter before pushing"))
+	seedMemory(t, st, newTestMemory("g-fact", models.MemoryTypeFact, "The linter config lives in .golangci.yml"))
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "linter", "format": "grouped"}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	ctxText, _ := got["context"].(string)
+	assert.Contains(t, ctxText, "Rules:\nAlways run the linter before pushing")
+	assert.Contains(t, ctxText, "Facts:\nThe linter config lives in .golangci.yml")
+	assert.EqualValues(t, 2, got["memory_count"])
+
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "linter", "format": "table"}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
+func TestAPI_Recall_RelevanceCutoff(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	seedMemory(t, st, newTestMemory("cut-rule", models.MemoryTypeRule, "Always run the linter before pushing"))
+	seedMemory(t, st, newTestMemory("cut-episode", models.MemoryTypeEpisode, "Ran the linter yesterday"))
+
+	recallCount := func(cutoff float64) float64 {
+		t.Helper()
+		resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "linter", "relevance_cutoff": cutoff}), "")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var got map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		n, _ := got["memory_count"].(float64)
+		return n
+	}
+
+	// Same similarity, but the rule's type boost puts the episode a few
+	// points below the top score.
+	assert.EqualValues(t, 2, recallCount(0))
+	assert.EqualValues(t, 2, recallCount(0.5))
+	assert.EqualValues(t, 1, recallCount(0.99))
+
+	bad := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{"message": "linter", "relevance_cutoff": 1.5}), "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/api"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func seedProjectMemories(t *testing.T, st *store.MockStore, project string, n int) {
+	t.Helper()
+	now := time.Now().UTC()
+	for i := range n {
+		mem := models.Memory{
+			ID:         fmt.Sprintf("%s-%03d", project, i),
+			Type:       models.MemoryTypeFact,
+			Scope:      models.ScopeProject,
+			Visibility: models.VisibilityShared,
+			Content:    fmt.Sprintf("%s memory %d", project, i),
+			Project:    project,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		require.NoError(t, st.Upsert(context.Background(), mem, make([]float32, 768)))
+	}
+}
+
+func TestDeleteByFilter_DeletesOnlyMatches(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMockStore()
+	seedProjectMemories(t, st, "old", 150)
+	seedProjectMemories(t, st, "keep", 3)
+
+	project := "old"
+	deleted, more, err := store.DeleteByFilter(ctx, st, &store.SearchFilters{Project: &project}, 1000, false)
+	require.NoError(t, err)
+	assert.Equal(t, 150, deleted)
+	assert.False(t, more)
+
+	stats, err := st.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalMemories)
+}
+
+func TestDeleteByFilter_LimitReportsMore(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMockStore()
+	seedProjectMemories(t, st, "old", 5)
+
+	project := "old"
+	deleted, more, err := store.DeleteByFilter(ctx, st, &store.SearchFilters{Project: &project}, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.True(t, more)
+}
+
+func TestDeleteByFilter_RequiresFilter(t *testing.T) {
+	st := store.NewMockStore()
+	_, _, err := store.DeleteByFilter(context.Background(), st, nil, 10, false)
+	assert.Error(t, err)
+	_, _, err = store.DeleteByFilter(context.Background(), st, &store.SearchFilters{IncludeInvalidated: true}, 10, false)
+	assert.Error(t, err)
+}
+
+func TestAPI_BulkDelete(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+	srv := api.NewServer(st, recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "", "")
+	srv.SetTrashEnabled(true)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	seedProjectMemories(t, st, "old", 4)
+	seedProjectMemories(t, st, "keep", 2)
+
+	t.Run("requires confirm", func(t *testing.T) {
+		resp := doRequest(t, http.MethodDelete, ts.URL+"/v1/memories", jsonBody(t, map[string]any{"project": "old"}), "")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("requires a filter", func(t *testing.T) {
+		resp := doRequest(t, http.MethodDelete, ts.URL+"/v1/memories", jsonBody(t, map[string]any{"confirm": true}), "")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("trashes matching memories", func(t *testing.T) {
+		resp := doRequest(t, http.MethodDelete, ts.URL+"/v1/memories",
+			jsonBody(t, map[string]any{"project": "old", "confirm": true}), "")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out struct {
+			Deleted int  `json:"deleted"`
+			Trashed bool `json:"trashed"`
+			More    bool `json:"more"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, 4, out.Deleted)
+		assert.True(t, out.Trashed)
+		assert.False(t, out.More)
+
+		_, err := st.Get(context.Background(), "old-000")
+		require.ErrorIs(t, err, store.ErrNotFound)
+		_, err = st.Get(context.Background(), "keep-000")
+		require.NoError(t, err)
+		_, err = st.Restore(context.Background(), "old-000")
+		require.NoError(t, err)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
@@ -334,3 +335,134 @@ func TestEntityExtractorExtract_CodeFenceWrapped(t *testing.T) {
 		t.Errorf("expected name Bob, got %q", entities[0].Name)
 	}
 }
+
+func TestClaudeCapturerExtract_StructuredPreferences(t *testing.T) {
+	resp := `[
+		{"content":"Ajit likes drone piloting","type":"preference","confidence":0.9,"tags":[],
+		 "preference":{"subject":"Ajit","predicate":"likes","object":"drone piloting"}},
+		{"content":"Go has goroutines","type":"fact","confidence":0.9,"tags":[],
+		 "preference":{"subject":"Go","predicate":"has","object":"goroutines"}},
+		{"content":"Ajit prefers tea","type":"preference","confidence":0.9,"tags":[],
+		 "preference":{"subject":"Ajit","predicate":"prefers","object":""}}
+	]`
+
+	// Disabled: triples are dropped even if the model returns them.
+	mems, err := newCapturer(&mockLLMClient{Resp: resp}).Extract(context.Background(), "u", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, m := range mems {
+		if m.Preference != nil {
+			t.Errorf("preference kept with structured extraction disabled: %q", m.Content)
+		}
+	}
+
+	// Enabled: only complete triples on preference memories survive.
+	mems, err = newCapturer(&mockLLMClient{Resp: resp}).WithStructuredPreferences(true).
+		Extract(context.Background(), "u", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mems) != 3 {
+		t.Fatalf("expected 3 memories, got %d", len(mems))
+	}
+	if mems[0].Preference == nil || mems[0].Preference.Object != "drone piloting" {
+		t.Errorf("expected triple on preference memory, got %+v", mems[0].Preference)
+	}
+	if mems[1].Preference != nil {
+		t.Error("triple on non-preference memory should be dropped")
+	}
+	if mems[2].Preference != nil {
+		t.Error("incomplete triple should be dropped")
+	}
+}
+
+func TestClaudeCapturerExtract_MinWords(t *testing.T) {
+	resp := `[
+		{"content":"ok, done","type":"episode","confidence":0.9,"tags":[]},
+		{"content":"Always run go vet before committing","type":"rule","confidence":0.9,"tags":[]}
+	]`
+
+	// Disabled by default: short fragments are kept.
+	mems, err := newCapturer(&mockLLMClient{Resp: resp}).Extract(context.Background(), "u", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mems) != 2 {
+		t.Fatalf("expected 2 memories without a word minimum, got %d", len(mems))
+	}
+
+	mems, err = newCapturer(&mockLLMClient{Resp: resp}).WithMinWords(3).
+		Extract(context.Background(), "u", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mems) != 1 || mems[0].Type != models.MemoryTypeRule {
+		t.Fatalf("expected only the rule to survive min_words=3, got %+v", mems)
+	}
+}
+
+// promptRecordingLLM returns resp and records the last user prompt it saw.
+type promptRecordingLLM struct {
+	resp   string
+	prompt string
+}
+
+func (p *promptRecordingLLM) Complete(_ context.Context, _, _, userMessage string, _ int) (string, error) {
+	p.prompt = userMessage
+	return p.resp, nil
+}
+
+func TestNewExtractionTemplate_Validation(t *testing.T) {
+	tmpl, err := capture.NewExtractionTemplate("")
+	if err != nil || tmpl != nil {
+		t.Fatalf("empty template: got %v, %v; want nil, nil", tmpl, err)
+	}
+
+	cases := map[string]string{
+		"parse error":       "{{.UserMessage",
+		"missing assistant": `{{.UserMessage}} return content, type, confidence`,
+		"missing fields":    `{{.UserMessage}} {{.AssistantMessage}} return a list`,
+		"unknown field":     `{{.UserMessage}} {{.AssistantMessage}} {{.Transcript}} content type confidence`,
+	}
+	for name, text := range cases {
+		if _, err := capture.NewExtractionTemplate(text); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestClaudeCapturerExtract_CustomPromptTemplate(t *testing.T) {
+	tmpl, err := capture.NewExtractionTemplate(`Extract API contracts only.
+Return a JSON array of {"content", "type", "confidence", "tags"}.
+{{if .PriorTurns}}<prior_turns>{{.PriorTurns}}</prior_turns>{{end}}
+<user_message>{{.UserMessage}}</user_message>
+<assistant_message>{{.AssistantMessage}}</assistant_message>`)
+	if err != nil {
+		t.Fatalf("NewExtractionTemplate: %v", err)
+	}
+
+	llm := &promptRecordingLLM{resp: `[
+		{"content":"GET /users returns 200 with a JSON list","type":"api_contract","confidence":0.9},
+		{"content":"  ","type":"fact","confidence":0.9}
+	]`}
+	c := capture.NewCapturer(llm, "claude-haiku", slog.Default()).WithPromptTemplate(tmpl)
+
+	mems, err := c.ExtractWithContext(context.Background(), "what does <GET /users> return?", "a list",
+		[]capture.ConversationTurn{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(llm.prompt, "Extract API contracts only.") {
+		t.Errorf("custom prompt not used: %q", llm.prompt)
+	}
+	if !strings.Contains(llm.prompt, "&lt;GET /users&gt;") || !strings.Contains(llm.prompt, "[user]: hi") {
+		t.Errorf("conversation not escaped into the prompt: %q", llm.prompt)
+	}
+	if len(mems) != 1 {
+		t.Fatalf("expected 1 memory (empty content dropped), got %d", len(mems))
+	}
+	if mems[0].Type != "" {
+		t.Errorf("unknown type should be cleared for the classifier, got %q", mems[0].Type)
+	}
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,6 +24,7 @@ func TestConfigDefaults(t *testing.T) {
 	assert.Equal(t, float64(0.92), cfg.Memory.DedupThreshold)
 	assert.Equal(t, uint64(768), cfg.Memory.VectorDimension)
 	assert.Greater(t, cfg.Memory.ChunkSize, 0)
+	assert.True(t, cfg.Recall.TrackAccess, "access tracking should be on by default")
 }
 
 func TestConfigEnvOverride(t *testing.T) {
@@ -35,6 +38,54 @@ func TestConfigEnvOverride(t *testing.T) {
 	assert.Equal(t, "test-key-12345", cfg.Claude.APIKey)
 }
 
+func TestConfigLoadProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+	t.Setenv("OPENCLAW_CORTEX_MEMGRAPH_URI", "")
+	t.Setenv("OPENCLAW_CORTEX_OLLAMA_BASE_URL", "")
+	t.Setenv(config.ProfileEnv, "")
+
+	dir := filepath.Join(home, ".openclaw-cortex")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(`
+memgraph:
+  uri: bolt://localhost:7687
+ollama:
+  model: nomic-embed-text
+profiles:
+  prod:
+    memgraph:
+      uri: bolt://memgraph.prod:7687
+    ollama:
+      base_url: http://ollama.prod:11434
+`), 0o600))
+
+	cfg, err := config.LoadProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, "bolt://localhost:7687", cfg.Memgraph.URI)
+
+	cfg, err = config.LoadProfile("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "bolt://memgraph.prod:7687", cfg.Memgraph.URI)
+	assert.Equal(t, "http://ollama.prod:11434", cfg.Ollama.BaseURL)
+	assert.Equal(t, "nomic-embed-text", cfg.Ollama.Model, "settings the profile omits come from the top level")
+
+	t.Setenv(config.ProfileEnv, "prod")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "bolt://memgraph.prod:7687", cfg.Memgraph.URI, "Load applies the profile named by the env var")
+
+	t.Setenv("OPENCLAW_CORTEX_MEMGRAPH_URI", "bolt://override:7687")
+	cfg, err = config.LoadProfile("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "bolt://override:7687", cfg.Memgraph.URI, "env vars win over the profile")
+
+	_, err = config.LoadProfile("staging")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"staging" not found`)
+}
+
 func TestConfigValidationChunkOverlap(t *testing.T) {
 	cfg := &config.Config{
 		Memgraph: config.MemgraphConfig{URI: "bolt://localhost:7687"},
@@ -201,6 +252,7 @@ func TestConfigValidationValid(t *testing.T) {
 			RetryDelaySeconds: 5,
 			WALCompactEvery:   1000,
 		},
+		Lifecycle: config.LifecycleConfig{SessionDecayHours: 24},
 	}
 	err := cfg.Validate()
 	assert.NoError(t, err)
@@ -248,6 +300,7 @@ func validBaseConfig() config.Config {
 			RetryDelaySeconds: 5,
 			WALCompactEvery:   1000,
 		},
+		Lifecycle: config.LifecycleConfig{SessionDecayHours: 24},
 	}
 }
 
@@ -259,6 +312,20 @@ func TestConfig_Validate_UnknownProvider(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_EmbedderRetry(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Embedder.MaxRetries = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for negative embedder.max_retries")
+	}
+
+	cfg = validBaseConfig()
+	cfg.Embedder.RetryBaseDelayMs = -5
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for negative embedder.retry_base_delay_ms")
+	}
+}
+
 func TestConfig_Validate_LMStudioMissingModel(t *testing.T) {
 	cfg := validBaseConfig()
 	cfg.Embedder.Provider = "lmstudio"
@@ -277,3 +344,154 @@ func TestConfig_Validate_LMStudio_Valid(t *testing.T) {
 		t.Fatalf("expected valid lmstudio config, got: %v", err)
 	}
 }
+
+func TestConfig_Validate_NegativeMinMemories(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Recall.MinMemories = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recall.min_memories")
+}
+
+func TestConfig_Validate_FastDedupThresholdRange(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Memory.FastDedupThreshold = 1.5
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory.fast_dedup_threshold")
+
+	cfg.Memory.FastDedupThreshold = 0
+	require.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_TracingSampleRate(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Tracing.SampleRate = 1.5
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tracing.sample_rate")
+}
+
+func TestConfig_Validate_TrashRetentionHours(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Memory.TrashRetentionHours = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory.trash_retention_hours")
+
+	cfg.Memory.TrashRetentionHours = 0
+	require.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_LifecycleScanLimits(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Lifecycle.MaxScan = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lifecycle.max_scan")
+
+	cfg = validBaseConfig()
+	cfg.Lifecycle.PageSize = -1
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lifecycle.page_size")
+}
+
+func TestConfig_Validate_LifecycleThresholds(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Lifecycle.SessionDecayHours = 1
+	cfg.Lifecycle.ConsolidationThreshold = 0.85
+	require.NoError(t, cfg.Validate())
+
+	for _, hours := range []float64{0, -1} {
+		cfg.Lifecycle.SessionDecayHours = hours
+		err := cfg.Validate()
+		require.Error(t, err, "session_decay_hours %v", hours)
+		assert.Contains(t, err.Error(), "lifecycle.session_decay_hours")
+	}
+
+	cfg = validBaseConfig()
+	cfg.Lifecycle.ConsolidationThreshold = 1.5
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lifecycle.consolidation_threshold")
+}
+
+func TestConfig_Validate_EmbedderTemplates(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Embedder.InputTemplate = "{{.Type}}: {{.Content}}"
+	cfg.Embedder.QueryTemplate = "{{.Content}}"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Embedder.InputTemplate = "{{.Type"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "embedder.input_template")
+}
+
+func TestConfig_Validate_DedupScope(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Memory.DedupScope = config.DedupScopeConfig{SameType: true, WindowDays: 14}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Memory.DedupScope.WindowDays = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory.dedup_scope.window_days")
+}
+
+func TestConfig_Validate_TypeTTL(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Memory.TypeTTL = map[string]string{"episode": "7d", "fact": "36h"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Memory.TypeTTL = map[string]string{"memo": "7d"}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory.type_ttl")
+
+	cfg.Memory.TypeTTL = map[string]string{"episode": "-1h"}
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memory.type_ttl.episode")
+}
+
+func TestConfig_Validate_MemgraphRequireTLS(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Memgraph.URI = "bolt://memgraph.internal:7687"
+	require.NoError(t, cfg.Validate(), "plain bolt is allowed when TLS is not required")
+
+	cfg.Memgraph.RequireTLS = true
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memgraph.require_tls")
+
+	for _, uri := range []string{"bolt+s://memgraph.internal:7687", "bolt+ssc://memgraph.internal:7687", "neo4j+s://memgraph.internal:7687"} {
+		cfg.Memgraph.URI = uri
+		assert.NoError(t, cfg.Validate(), uri)
+	}
+}
+
+func TestConfig_Validate_MemgraphVectorMetric(t *testing.T) {
+	cfg := validBaseConfig()
+	for _, metric := range []string{"", "cosine", "dot", "euclid"} {
+		cfg.Memgraph.VectorMetric = metric
+		assert.NoError(t, cfg.Validate(), metric)
+	}
+
+	cfg.Memgraph.VectorMetric = "manhattan"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "memgraph.vector_metric")
+}
+
+func TestConfig_Validate_LLMFilterCandidates(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.Recall.LLMFilterCandidates = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recall.llm_filter_candidates")
+
+	cfg.Recall.LLMFilterCandidates = 0
+	require.NoError(t, cfg.Validate())
+}
@@ -170,3 +170,28 @@ func TestPostTurnHook_WithConflictDetector_ChainedCall(t *testing.T) {
 	returned := hook.WithConflictDetector(cd)
 	assert.Equal(t, hook, returned, "WithConflictDetector should return the same hook instance")
 }
+
+// TestConflictDetector_CheckStore verifies that CheckStore only flags
+// near-similar, high-confidence memories and reports the conflicting ID.
+func TestConflictDetector_CheckStore(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+	vec := testVector(0.1)
+	existing := newTestMemory("existing", models.MemoryTypeFact, "The API gateway listens on port 8080")
+	existing.Confidence = 0.9
+	require.NoError(t, ms.Upsert(ctx, existing, vec))
+
+	llmClient := &mockLLMClient{Resp: `{"contradicts": true, "contradicted_id": "existing", "reason": "different port"}`}
+	cd := capture.NewConflictDetector(llmClient, "test-model", slog.Default())
+
+	warn := cd.CheckStore(ctx, ms, "The API gateway listens on port 9090", 0.9, vec)
+	require.NotNil(t, warn)
+	assert.Equal(t, "existing", warn.ConflictingID)
+	assert.Equal(t, "different port", warn.Reason)
+
+	assert.Nil(t, cd.CheckStore(ctx, ms, "The API gateway listens on port 9090", 0.3, vec),
+		"low-confidence memories are not checked")
+
+	llmClient.Resp = `{"contradicts": false, "contradicted_id": "", "reason": ""}`
+	assert.Nil(t, cd.CheckStore(ctx, ms, "The API gateway also serves /metrics", 0.9, vec))
+}
@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestFilterByContent(t *testing.T) {
+	results := []models.SearchResult{
+		{Memory: models.Memory{ID: "a", Content: "Always wrap errors with fmt.Errorf"}, Score: 0.9},
+		{Memory: models.Memory{ID: "b", Content: "Use errors.Is to compare sentinel errors"}, Score: 0.8},
+		{Memory: models.Memory{ID: "c", Content: "Prefer ERRORS.IS over equality checks"}, Score: 0.7},
+	}
+
+	got := store.FilterByContent(results, "errors.is")
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "b", got[0].Memory.ID)
+		assert.Equal(t, "c", got[1].Memory.ID)
+	}
+
+	assert.Empty(t, store.FilterByContent(results, "panic"))
+	assert.Len(t, store.FilterByContent(results, ""), 3)
+}
+
+func TestContainsPhrase(t *testing.T) {
+	assert.True(t, store.ContainsPhrase("Deploy with Docker Compose", "docker compose"))
+	assert.False(t, store.ContainsPhrase("Deploy with Docker", "docker compose"))
+	assert.True(t, store.ContainsPhrase("anything", ""))
+}
@@ -651,6 +651,10 @@ func (s *upsertFailIndexStore) Upsert(_ context.Context, _ models.Memory, _ []fl
 	return s.upsertErr
 }
 
+func (s *upsertFailIndexStore) UpsertBatch(_ context.Context, _ []models.Memory, _ [][]float32) error {
+	return s.upsertErr
+}
+
 // TestIndexer_IndexFile_UpsertError covers the error-log-and-continue path in IndexFile
 // (indexer.go lines 144-147) when Upsert fails after FindDuplicates succeeds.
 func TestIndexer_IndexFile_UpsertError(t *testing.T) {
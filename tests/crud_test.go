@@ -282,6 +282,102 @@ func TestAPI_UpdateMemory_InvalidBody(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+// TestAPI_UpdateMemory_AppendContent verifies that PATCH with append_content
+// adds to the existing content instead of replacing it.
+func TestAPI_UpdateMemory_AppendContent(t *testing.T) {
+	ts, st := newTestServer(t, "")
+
+	now := time.Now().UTC()
+	id := seedMemory(t, st, models.Memory{
+		ID:           "update-append-001",
+		Type:         models.MemoryTypeFact,
+		Scope:        models.ScopePermanent,
+		Visibility:   models.VisibilityPrivate,
+		Content:      "Deploys run on Fridays",
+		Confidence:   0.9,
+		Source:       "test",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		LastAccessed: now,
+	})
+
+	body := jsonBody(t, map[string]any{"append_content": "  Except during code freeze. "})
+	resp := doRequest(t, http.MethodPatch, ts.URL+"/v1/memories/"+id, body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	stored, err := st.Get(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Deploys run on Fridays\nExcept during code freeze.", stored.Content)
+
+	both := jsonBody(t, map[string]any{"content": "x", "append_content": "y"})
+	bad := doRequest(t, http.MethodPatch, ts.URL+"/v1/memories/"+id, both, "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
+// --- PATCH /v1/memories/{id}/supersede ---
+
+// TestAPI_SupersedeMemory verifies that superseding stores a new version,
+// keeps the old one in the chain and hides it from search.
+func TestAPI_SupersedeMemory(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	oldID := seedMemory(t, st, models.Memory{
+		ID:           "supersede-001",
+		Type:         models.MemoryTypeRule,
+		Scope:        models.ScopePermanent,
+		Visibility:   models.VisibilityShared,
+		Content:      "Deploy on Fridays",
+		Confidence:   0.9,
+		Tags:         []string{"deploy"},
+		UserID:       "alice",
+		Attachments:  []models.Attachment{{Type: models.AttachmentFile, Ref: "docs/deploy.md"}},
+		AccessCount:  4,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		LastAccessed: now,
+	})
+
+	body := jsonBody(t, map[string]any{"content": "Never deploy on Fridays"})
+	resp := doRequest(t, http.MethodPatch, ts.URL+"/v1/memories/"+oldID+"/supersede", body, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var got models.Memory
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.NotEqual(t, oldID, got.ID)
+	assert.Equal(t, oldID, got.SupersedesID)
+	assert.Equal(t, "Never deploy on Fridays", got.Content)
+	assert.Equal(t, []string{"deploy"}, got.Tags)
+	assert.Equal(t, int64(4), got.AccessCount)
+	assert.Equal(t, "alice", got.UserID)
+	assert.Equal(t, []models.Attachment{{Type: models.AttachmentFile, Ref: "docs/deploy.md"}}, got.Attachments)
+
+	chain, err := st.GetChain(ctx, got.ID)
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	assert.Equal(t, got.ID, chain[0].ID, "newest first")
+	assert.Equal(t, oldID, chain[1].ID)
+	assert.Equal(t, "Deploy on Fridays", chain[1].Content)
+
+	results, err := st.Search(ctx, make([]float32, 768), 10, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1, "only the newest version is searchable")
+	assert.Equal(t, got.ID, results[0].Memory.ID)
+}
+
+// TestAPI_SupersedeMemory_NotFound verifies 404 for an unknown ID.
+func TestAPI_SupersedeMemory_NotFound(t *testing.T) {
+	ts, _ := newTestServer(t, "")
+	body := jsonBody(t, map[string]any{"content": "anything"})
+	resp := doRequest(t, http.MethodPatch, ts.URL+"/v1/memories/missing/supersede", body, "")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 // --- GET /v1/memories ---
 
 // TestAPI_ListMemories verifies that all memories are returned when no filter.
@@ -455,6 +551,30 @@ func TestAPI_ListMemories_ProjectFilter(t *testing.T) {
 	}
 }
 
+func TestAPI_Recall_MultipleProjects(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	for _, p := range []string{"alpha", "beta", "gamma", ""} {
+		mem := newTestMemory("multi-"+p, models.MemoryTypeFact, "deploy notes for "+p+" project")
+		mem.Project = p
+		seedMemory(t, st, mem)
+	}
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/recall", jsonBody(t, map[string]any{
+		"message":  "deploy notes",
+		"projects": []string{"alpha", "beta"},
+	}), "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	ctxText, _ := got["context"].(string)
+	assert.Contains(t, ctxText, "for alpha project")
+	assert.Contains(t, ctxText, "for beta project")
+	assert.Contains(t, ctxText, "for  project", "global memories are included")
+	assert.NotContains(t, ctxText, "gamma")
+}
+
 // TestAPI_ListMemories_Limit verifies the limit query param.
 func TestAPI_ListMemories_Limit(t *testing.T) {
 	ts, st := newTestServer(t, "")
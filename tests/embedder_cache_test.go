@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
+)
+
+// countingEmbedder embeds a text as [len(text)] and records every text it
+// was asked to embed.
+type countingEmbedder struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (c *countingEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.texts = append(c.texts, text)
+	return []float32{float32(len(text))}, nil
+}
+
+func (c *countingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i], _ = c.Embed(ctx, t)
+	}
+	return out, nil
+}
+
+func (c *countingEmbedder) Dimension() int { return 1 }
+
+func TestCachingEmbedder_EmbedHitsCache(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingEmbedder{}
+	emb := embedder.NewCachingEmbedder(inner, "ollama/test", 10)
+
+	hits, misses := metrics.EmbedCacheHits.Value(), metrics.EmbedCacheMisses.Value()
+
+	v1, err := emb.Embed(ctx, "same query")
+	require.NoError(t, err)
+	v2, err := emb.Embed(ctx, "same query")
+	require.NoError(t, err)
+
+	assert.Equal(t, v1, v2)
+	assert.Equal(t, []string{"same query"}, inner.texts)
+	assert.Equal(t, int64(1), metrics.EmbedCacheHits.Value()-hits)
+	assert.Equal(t, int64(1), metrics.EmbedCacheMisses.Value()-misses)
+
+	// Mutating a returned vector must not corrupt the cache.
+	v2[0] = -1
+	v3, err := emb.Embed(ctx, "same query")
+	require.NoError(t, err)
+	assert.Equal(t, float32(len("same query")), v3[0])
+}
+
+func TestCachingEmbedder_EmbedBatchMergesInOrder(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingEmbedder{}
+	emb := embedder.NewCachingEmbedder(inner, "ollama/test", 10)
+
+	_, err := emb.Embed(ctx, "bb")
+	require.NoError(t, err)
+	inner.texts = nil
+
+	vecs, err := emb.EmbedBatch(ctx, []string{"a", "bb", "cccc", "a"})
+	require.NoError(t, err)
+	require.Len(t, vecs, 4)
+	assert.Equal(t, []float32{1}, vecs[0])
+	assert.Equal(t, []float32{2}, vecs[1])
+	assert.Equal(t, []float32{4}, vecs[2])
+	assert.Equal(t, []float32{1}, vecs[3])
+	assert.Equal(t, []string{"a", "cccc"}, inner.texts, "only distinct uncached texts reach the inner embedder")
+}
+
+func TestCachingEmbedder_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingEmbedder{}
+	emb := embedder.NewCachingEmbedder(inner, "ollama/test", 2)
+
+	for _, text := range []string{"a", "b", "a", "c"} { // "b" is least recently used when "c" arrives
+		_, err := emb.Embed(ctx, text)
+		require.NoError(t, err)
+	}
+	inner.texts = nil
+
+	for _, text := range []string{"a", "c", "b"} {
+		_, err := emb.Embed(ctx, text)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"b"}, inner.texts)
+	assert.Equal(t, 2, emb.(*embedder.CachingEmbedder).Len())
+}
+
+func TestCachingEmbedder_ZeroSizeDisablesCache(t *testing.T) {
+	inner := &countingEmbedder{}
+	assert.Same(t, inner, embedder.NewCachingEmbedder(inner, "ollama/test", 0))
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,3 +128,47 @@ func TestOllamaEmbedder_Embed_ContextCancelledDuringRetry(t *testing.T) {
 	// Either context error or API error is acceptable — we're testing the path is covered
 	_ = err
 }
+
+// TestEmbedderWarmup verifies that Warmup issues a single embedding request
+// and surfaces provider errors.
+func TestEmbedderWarmup(t *testing.T) {
+	const dim = 8
+	var callCount atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"embedding": make([]float64, dim)})
+	}))
+	t.Cleanup(srv.Close)
+
+	emb := embedder.NewOllamaEmbedder(srv.URL, "model", dim, slog.Default())
+	_, err := embedder.Warmup(context.Background(), emb)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), callCount.Load())
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(failing.Close)
+
+	_, err = embedder.Warmup(context.Background(), embedder.NewOllamaEmbedder(failing.URL, "model", dim, slog.Default()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "embedder warmup")
+}
+
+// TestOllamaEmbedder_WithRetry_DisabledFailsOnFirstError verifies that
+// WithRetry(0, …) makes a single attempt.
+func TestOllamaEmbedder_WithRetry_DisabledFailsOnFirstError(t *testing.T) {
+	var callCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	emb := embedder.NewOllamaEmbedder(srv.URL, "model", 8, slog.Default()).WithRetry(0, time.Millisecond)
+	_, err := emb.Embed(context.Background(), "test")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), callCount.Load())
+}
@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/config"
 	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
@@ -70,6 +72,73 @@ func TestLMStudioEmbedder_ServerError(t *testing.T) {
 	}
 }
 
+// TestLMStudioEmbedder_RetriesTransientFailures verifies that two 503
+// responses are retried and the third, successful response is returned.
+func TestLMStudioEmbedder_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= 2 {
+			http.Error(w, "loading model", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{{"embedding": []float64{0.5, 0.25}}},
+		})
+	}))
+	defer srv.Close()
+
+	emb := embedder.NewLMStudioEmbedder(srv.URL, "model").WithRetry(2, time.Millisecond)
+	got, err := emb.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != 0.5 {
+		t.Errorf("got %v, want [0.5 0.25]", got)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("server called %d times, want 3", n)
+	}
+}
+
+// TestLMStudioEmbedder_ClientErrorFailsFast verifies that a 400 response is
+// not retried.
+func TestLMStudioEmbedder_ClientErrorFailsFast(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, "bad input", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	emb := embedder.NewLMStudioEmbedder(srv.URL, "model").WithRetry(3, time.Millisecond)
+	if _, err := emb.Embed(context.Background(), "hello"); err == nil {
+		t.Fatal("expected error for 400 response, got nil")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("server called %d times, want 1", n)
+	}
+}
+
+// TestLMStudioEmbedder_RetryGivesUp verifies that retries stop after
+// MaxRetries and the last status is reported.
+func TestLMStudioEmbedder_RetryGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	emb := embedder.NewLMStudioEmbedder(srv.URL, "model").WithRetry(1, time.Millisecond)
+	_, err := emb.Embed(context.Background(), "hello")
+	if err == nil || !containsString(err.Error(), "429") {
+		t.Fatalf("expected 429 error, got %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server called %d times, want 2", n)
+	}
+}
+
 // TestLMStudioEmbedder_EmptyResponse verifies that an empty data array is
 // treated as an error rather than returning a nil slice silently.
 func TestLMStudioEmbedder_EmptyResponse(t *testing.T) {
@@ -298,3 +367,19 @@ func containsString(s, sub string) bool {
 			return false
 		}())
 }
+
+// TestEmbedderModelID verifies the provider-qualified model identifier used
+// to tag stored vectors.
+func TestEmbedderModelID(t *testing.T) {
+	ollaCfg := config.OllamaConfig{Model: "nomic-embed-text"}
+	if got := embedder.ModelID(ollaCfg, config.EmbedderConfig{}); got != "ollama/nomic-embed-text" {
+		t.Errorf("default provider: got %q", got)
+	}
+	lmCfg := config.EmbedderConfig{
+		Provider: "lmstudio",
+		LMStudio: config.LMStudioConfig{Model: "text-embedding-bge"},
+	}
+	if got := embedder.ModelID(ollaCfg, lmCfg); got != "lmstudio/text-embedding-bge" {
+		t.Errorf("lmstudio provider: got %q", got)
+	}
+}
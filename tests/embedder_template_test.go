@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+)
+
+func TestInputTemplate_Render(t *testing.T) {
+	tmpl, err := embedder.NewInputTemplate("{{.Type}}: {{.Content}}", "query: {{.Content}}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "rule: always wrap errors", tmpl.Document(models.MemoryTypeRule, "always wrap errors"))
+	assert.Equal(t, "query: how do we wrap errors?", tmpl.Query("how do we wrap errors?"))
+
+	// A nil template embeds text unchanged.
+	var none *embedder.InputTemplate
+	assert.Equal(t, "raw", none.Document(models.MemoryTypeFact, "raw"))
+	assert.Equal(t, "raw", none.Query("raw"))
+}
+
+func TestInputTemplate_EmptyAndInvalid(t *testing.T) {
+	tmpl, err := embedder.NewInputTemplate("", "")
+	require.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	_, err = embedder.NewInputTemplate("{{.Type", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "input_template")
+}
+
+func TestInputTemplate_ProcessWide(t *testing.T) {
+	t.Cleanup(func() { embedder.SetInputTemplate(nil) })
+
+	assert.Equal(t, "content", embedder.DocumentText(models.MemoryTypeFact, "content"))
+
+	tmpl, err := embedder.NewInputTemplate("{{.Type}}: {{.Content}}", "")
+	require.NoError(t, err)
+	embedder.SetInputTemplate(tmpl)
+
+	assert.Equal(t, "fact: content", embedder.DocumentText(models.MemoryTypeFact, "content"))
+	assert.Equal(t, "query", embedder.QueryText("query"), "query side is unchanged without a query template")
+}
@@ -138,7 +138,7 @@ func TestEnsureSchema_VectorIndex_DropRecreateOnMismatch(t *testing.T) {
 	}
 
 	// Confirm the recreate DDL targets the correct property so the rebuild fixes the corruption.
-	recreateDDL := memgraph.BuildMemoryVectorIndexDDL(768)
+	recreateDDL := memgraph.BuildMemoryVectorIndexDDL(768, memgraph.MetricCosine)
 	if !strings.Contains(recreateDDL, ":Memory(embedding)") {
 		t.Errorf("recreate DDL does not target correct property 'embedding': %s", recreateDDL)
 	}
@@ -152,7 +152,7 @@ func TestEnsureSchema_VectorIndex_DropRecreateOnMismatch(t *testing.T) {
 // vector index DDL targets the correct index name and property so that
 // verifyOrRebuildVectorIndex can match against "memory_embedding" / "embedding".
 func TestBuildMemoryVectorIndexDDL_IndexAndPropertyNames(t *testing.T) {
-	ddl := memgraph.BuildMemoryVectorIndexDDL(768)
+	ddl := memgraph.BuildMemoryVectorIndexDDL(768, memgraph.MetricCosine)
 	if !strings.Contains(ddl, "memory_embedding") {
 		t.Errorf("memory DDL must reference index name 'memory_embedding', got: %s", ddl)
 	}
@@ -165,7 +165,7 @@ func TestBuildMemoryVectorIndexDDL_IndexAndPropertyNames(t *testing.T) {
 // vector index DDL targets the correct index name and property so that
 // verifyOrRebuildVectorIndex can match against "entity_name_embedding" / "name_embedding".
 func TestBuildEntityVectorIndexDDL_IndexAndPropertyNames(t *testing.T) {
-	ddl := memgraph.BuildEntityVectorIndexDDL(768)
+	ddl := memgraph.BuildEntityVectorIndexDDL(768, memgraph.MetricCosine)
 	if !strings.Contains(ddl, "entity_name_embedding") {
 		t.Errorf("entity DDL must reference index name 'entity_name_embedding', got: %s", ddl)
 	}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -119,3 +120,60 @@ func TestEntityGetNotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d", rec.Code)
 	}
 }
+
+func TestEntitySemanticSearchEndpoint(t *testing.T) {
+	ms := store.NewMockStore()
+	ctx := context.Background()
+
+	ms.UpsertEntity(ctx, models.Entity{ID: "e1", Name: "Stripe", Type: models.EntityTypeSystem, NameEmbedding: []float32{1, 0, 0, 0}})
+	ms.UpsertEntity(ctx, models.Entity{ID: "e2", Name: "Alice", Type: models.EntityTypePerson, NameEmbedding: []float32{0, 1, 0, 0}})
+	ms.UpsertEntity(ctx, models.Entity{ID: "e3", Name: "Unembedded", Type: models.EntityTypeConcept})
+
+	srv := api.NewServer(ms, nil, &hookMockEmbedder{vec: []float32{0.9, 0.1, 0, 0}}, nil, "test-token", "")
+	handler := srv.Handler()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/entities/search", strings.NewReader(`{"query":"payments","limit":5}`))
+	req.Header.Set("Authorization", "Bearer test-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result struct {
+		Results []models.EntitySearchResult `json:"results"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("expected the 2 embedded entities, got %d", len(result.Results))
+	}
+	assert.Equal(t, "Stripe", result.Results[0].Entity.Name)
+	assert.Greater(t, result.Results[0].Score, result.Results[1].Score)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/entities/search", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer test-token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEntityEmbeddingText(t *testing.T) {
+	e := models.Entity{Name: "Alice", Aliases: []string{"Al", "A."}, Summary: "Backend developer"}
+	assert.Equal(t, "Alice\nAlso known as: Al, A.\nBackend developer", e.EmbeddingText())
+	assert.Equal(t, "Bob", models.Entity{Name: "Bob"}.EmbeddingText())
+}
+
+func TestEntityEmbeddingTextWith_Mentions(t *testing.T) {
+	e := models.Entity{Name: "Bob"}
+	assert.Equal(t, "Bob", e.EmbeddingTextWith(nil))
+	assert.Equal(t, "Bob\nMentioned in:\n- Bob owns the billing service",
+		e.EmbeddingTextWith([]string{"  ", "Bob owns\n the billing service"}))
+
+	got := e.EmbeddingTextWith([]string{"one", "two", "three", "four", strings.Repeat("x", 500)})
+	assert.Equal(t, "Bob\nMentioned in:\n- one\n- two\n- three", got)
+
+	long := e.EmbeddingTextWith([]string{strings.Repeat("x", 500)})
+	assert.Equal(t, "Bob\nMentioned in:\n- "+strings.Repeat("x", 200)+"…", long)
+}
@@ -28,6 +28,10 @@ func (f *failingUpsertStore) Upsert(_ context.Context, _ models.Memory, _ []floa
 	return f.err
 }
 
+func (f *failingUpsertStore) UpsertBatch(_ context.Context, _ []models.Memory, _ [][]float32) error {
+	return f.err
+}
+
 func (f *failingUpsertStore) Search(ctx context.Context, vector []float32, limit uint64, filters *store.SearchFilters) ([]models.SearchResult, error) {
 	return f.inner.Search(ctx, vector, limit, filters)
 }
@@ -36,10 +40,34 @@ func (f *failingUpsertStore) Get(ctx context.Context, id string) (*models.Memory
 	return f.inner.Get(ctx, id)
 }
 
+func (f *failingUpsertStore) GetMany(ctx context.Context, ids []string) (map[string]models.Memory, error) {
+	return f.inner.GetMany(ctx, ids)
+}
+
 func (f *failingUpsertStore) Delete(ctx context.Context, id string) error {
 	return f.inner.Delete(ctx, id)
 }
 
+func (f *failingUpsertStore) Trash(ctx context.Context, id string) (string, error) {
+	return f.inner.Trash(ctx, id)
+}
+
+func (f *failingUpsertStore) Restore(ctx context.Context, id string) (string, error) {
+	return f.inner.Restore(ctx, id)
+}
+
+func (f *failingUpsertStore) GetVector(ctx context.Context, id string) ([]float32, error) {
+	return f.inner.GetVector(ctx, id)
+}
+
+func (f *failingUpsertStore) GetVectors(ctx context.Context, ids []string) (map[string][]float32, error) {
+	return f.inner.GetVectors(ctx, ids)
+}
+
+func (f *failingUpsertStore) ListTrashed(ctx context.Context, before time.Time, limit uint64) ([]models.Memory, error) {
+	return f.inner.ListTrashed(ctx, before, limit)
+}
+
 func (f *failingUpsertStore) List(ctx context.Context, filters *store.SearchFilters, limit uint64, cursor string) ([]models.Memory, string, error) {
 	return f.inner.List(ctx, filters, limit, cursor)
 }
@@ -52,6 +80,10 @@ func (f *failingUpsertStore) UpdateAccessMetadata(ctx context.Context, id string
 	return f.inner.UpdateAccessMetadata(ctx, id)
 }
 
+func (f *failingUpsertStore) UpdateAccessMetadataBatch(ctx context.Context, ids []string) error {
+	return f.inner.UpdateAccessMetadataBatch(ctx, ids)
+}
+
 func (f *failingUpsertStore) Stats(ctx context.Context) (*models.CollectionStats, error) {
 	return f.inner.Stats(ctx)
 }
@@ -64,6 +96,10 @@ func (f *failingUpsertStore) GetEntity(ctx context.Context, id string) (*models.
 	return f.inner.GetEntity(ctx, id)
 }
 
+func (f *failingUpsertStore) SearchEntitiesByVector(ctx context.Context, vector []float32, limit int) ([]models.EntitySearchResult, error) {
+	return f.inner.SearchEntitiesByVector(ctx, vector, limit)
+}
+
 func (f *failingUpsertStore) SearchEntities(ctx context.Context, name, entityType string, limit int) ([]models.Entity, error) {
 	return f.inner.SearchEntities(ctx, name, entityType, limit)
 }
@@ -100,6 +136,10 @@ func (f *failingUpsertStore) CountZeroEmbeddingMemories(ctx context.Context) (in
 	return f.inner.CountZeroEmbeddingMemories(ctx)
 }
 
+func (f *failingUpsertStore) Ping(ctx context.Context) error {
+	return f.inner.Ping(ctx)
+}
+
 func (f *failingUpsertStore) Close() error {
 	return f.inner.Close()
 }
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestShingleIndex_CheckAndAdd(t *testing.T) {
+	idx := store.NewShingleIndex(0)
+
+	original := "The deploy pipeline runs integration tests against a fresh Memgraph container before every release tag is pushed."
+	assert.False(t, idx.CheckAndAdd(original), "first occurrence is never a duplicate")
+	assert.True(t, idx.CheckAndAdd(original), "exact repeat is a duplicate")
+	assert.True(t, idx.CheckAndAdd("  the DEPLOY pipeline runs integration tests against a fresh Memgraph container, before every release tag is pushed!"),
+		"case, whitespace and punctuation differences are ignored")
+	assert.False(t, idx.CheckAndAdd("Use table-driven tests with testify assertions for every exported function in the store package."),
+		"unrelated content is not a duplicate")
+	assert.Equal(t, 2, idx.Len())
+}
+
+func TestShingleIndex_EmptyContent(t *testing.T) {
+	idx := store.NewShingleIndex(0.9)
+	assert.False(t, idx.CheckAndAdd(""))
+	assert.False(t, idx.CheckAndAdd("  ...  "))
+	assert.Equal(t, 0, idx.Len())
+}
+
+func TestShingleIndex_ShortContent(t *testing.T) {
+	idx := store.NewShingleIndex(0.9)
+	assert.False(t, idx.CheckAndAdd("Go"))
+	assert.True(t, idx.CheckAndAdd("go."))
+	assert.False(t, idx.CheckAndAdd("Rust"))
+}
+
+func TestShingleIndex_Concurrent(t *testing.T) {
+	idx := store.NewShingleIndex(0.9)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				idx.CheckAndAdd(fmt.Sprintf("memory number %d describes a distinct project convention in detail", j))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 50, idx.Len())
+}
+
+func TestShingleIndex_BoundedForgetsOldest(t *testing.T) {
+	idx := store.NewBoundedShingleIndex(0.9, 2)
+	first := "Staging deploys are promoted to production only after the canary has run for an hour."
+	assert.False(t, idx.CheckAndAdd(first))
+	assert.False(t, idx.CheckAndAdd("The billing service owns the invoices table and nothing else may write to it."))
+	assert.False(t, idx.CheckAndAdd("Feature flags are cleaned up within two sprints of reaching full rollout."))
+	assert.LessOrEqual(t, idx.Len(), 2)
+	assert.False(t, idx.CheckAndAdd(first), "evicted text is no longer remembered")
+}
+
+func TestSharedShingleIndex_SameInstancePerThreshold(t *testing.T) {
+	assert.Same(t, store.SharedShingleIndex(0), store.SharedShingleIndex(store.DefaultFastDedupThreshold))
+	assert.NotSame(t, store.SharedShingleIndex(0.9), store.SharedShingleIndex(0.8))
+}
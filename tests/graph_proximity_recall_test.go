@@ -210,3 +210,53 @@ func TestRecallWithGraph_ProximityMapBuilt(t *testing.T) {
 	assert.Greater(t, hop1Result.GraphProximityScore, hop2Result.GraphProximityScore,
 		"1-hop proximity (1.0) should exceed 2-hop proximity (0.5)")
 }
+
+// depthHopsClient is a hopsTrackingClient that records the requested depth.
+type depthHopsClient struct {
+	hopsTrackingClient
+	requestedDepth int
+}
+
+func (d *depthHopsClient) RecallByGraphWithHops(
+	ctx context.Context, query string, embedding []float32, limit int, depth int,
+) ([]string, map[string]int, error) {
+	d.requestedDepth = depth
+	return d.hopsTrackingClient.RecallByGraphWithHops(ctx, query, embedding, limit, depth)
+}
+
+// TestRecallWithGraphDepth_DecayingBoost verifies that the per-call expansion
+// depth is passed to the graph client and that the proximity boost halves with
+// every additional hop.
+func TestRecallWithGraphDepth_DecayingBoost(t *testing.T) {
+	mems := []models.Memory{
+		{ID: "hop1", Type: models.MemoryTypeFact, Scope: models.ScopePermanent, Content: "direct", Confidence: 0.8},
+		{ID: "hop3", Type: models.MemoryTypeFact, Scope: models.ScopePermanent, Content: "transitive", Confidence: 0.8},
+		{ID: "hop4", Type: models.MemoryTypeFact, Scope: models.ScopePermanent, Content: "far transitive", Confidence: 0.8},
+	}
+	gc := &depthHopsClient{
+		hopsTrackingClient: hopsTrackingClient{
+			hopMap: map[string]int{"hop1": 1, "hop3": 3, "hop4": 4},
+		},
+	}
+	st := populatedStore(t, mems)
+
+	r := recall.NewRecaller(recall.DefaultWeights(), newGraphRecallLogger())
+	r.SetGraphClient(gc, st, 500)
+	r.SetGraphDepth(2)
+
+	results := r.RecallWithGraphDepth(context.Background(), "q", []float32{0.1, 0.2}, nil, "", 4)
+	assert.Equal(t, 4, gc.requestedDepth, "explicit depth must override the configured depth")
+	require.Len(t, results, 3)
+
+	byID := make(map[string]models.RecallResult, len(results))
+	for i := range results {
+		byID[results[i].Memory.ID] = results[i]
+	}
+	assert.InDelta(t, 1.0, byID["hop1"].GraphProximityScore, 0.001)
+	assert.InDelta(t, 0.25, byID["hop3"].GraphProximityScore, 0.001)
+	assert.InDelta(t, 0.125, byID["hop4"].GraphProximityScore, 0.001)
+
+	// depth 0 falls back to the configured depth.
+	_ = r.RecallWithGraphDepth(context.Background(), "q", []float32{0.1, 0.2}, nil, "", 0)
+	assert.Equal(t, 2, gc.requestedDepth)
+}
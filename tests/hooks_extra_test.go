@@ -123,6 +123,31 @@ func TestPreTurnHook_MultipleResults(t *testing.T) {
 	assert.LessOrEqual(t, out.MemoryCount, 3)
 }
 
+// TestPreTurnHook_BroadenWhenEmpty verifies that a project with no memories
+// of its own falls back to global memories only when broadening is enabled.
+func TestPreTurnHook_BroadenWhenEmpty(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+
+	vec := newHookMockVec()
+	_ = ms.Upsert(ctx, newTestMemory("g1", models.MemoryTypeRule, "Always use tests"), vec)
+
+	newHook := func() *hooks.PreTurnHook {
+		recaller := recall.NewRecaller(recall.DefaultWeights(), slog.Default())
+		return hooks.NewPreTurnHook(&hookMockEmbedder{vec: vec}, ms, recaller, slog.Default())
+	}
+	input := hooks.PreTurnInput{Message: "how to test", Project: "brand-new", TokenBudget: 1000}
+
+	out, err := newHook().Execute(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, 0, out.MemoryCount)
+
+	out, err = newHook().WithBroadenWhenEmpty(true).Execute(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, 1, out.MemoryCount)
+	assert.Contains(t, out.Context, "Always use tests")
+}
+
 // TestPreTurnHook_TokenBudgetLimitsMemoryCount verifies that a very small
 // token budget limits how many memories are returned.
 func TestPreTurnHook_TokenBudgetLimitsMemoryCount(t *testing.T) {
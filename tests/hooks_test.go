@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/capture"
+	"github.com/ajitpratap0/openclaw-cortex/internal/embedder"
 	"github.com/ajitpratap0/openclaw-cortex/internal/hooks"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
@@ -128,6 +129,23 @@ func TestPostTurnHook_HappyPath(t *testing.T) {
 	assert.Equal(t, int64(2), stats.TotalMemories)
 }
 
+func TestPostTurnHook_AppliesProject(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+	cap := &hookMockCapturer{
+		memories: []models.CapturedMemory{
+			{Content: "Deploy with kubectl apply", Type: models.MemoryTypeProcedure, Confidence: 0.9},
+		},
+	}
+	hook := hooks.NewPostTurnHook(cap, &hookMockClassifier{}, &hookMockEmbedder{dim: 8}, ms, slog.Default(), 0.95, 1)
+	require.NoError(t, hook.Execute(ctx, hookTestInput()))
+
+	results, err := ms.Search(ctx, make([]float32, 8), 10, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "proj-1", results[0].Memory.Project, "captured memories carry the hook input's project")
+}
+
 func TestPostTurnHook_DedupSkip(t *testing.T) {
 	ctx := context.Background()
 	ms := store.NewMockStore()
@@ -156,6 +174,90 @@ func TestPostTurnHook_DedupSkip(t *testing.T) {
 	assert.Equal(t, int64(1), stats.TotalMemories)
 }
 
+// TestPostTurnHook_ResumesInterruptedTurn verifies that re-running a turn
+// stores only the memories an interrupted earlier run did not get to, even
+// when dedup would not catch the repeats.
+func TestPostTurnHook_ResumesInterruptedTurn(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+	input := hookTestInput()
+	first := models.CapturedMemory{Content: "Deploy with kubectl apply", Type: models.MemoryTypeProcedure, Confidence: 0.9}
+	second := models.CapturedMemory{Content: "Always use --dry-run first", Type: models.MemoryTypeRule, Confidence: 0.85}
+
+	// The interrupted run stored only the first memory.
+	emb := &hookMockEmbedder{dim: 8} // distinct vectors, so dedup never fires
+	partial := hooks.NewPostTurnHook(&hookMockCapturer{memories: []models.CapturedMemory{first}}, &hookMockClassifier{}, emb, ms, slog.Default(), 0.95, 1)
+	require.NoError(t, partial.Execute(ctx, input))
+
+	retry := hooks.NewPostTurnHook(&hookMockCapturer{memories: []models.CapturedMemory{first, second}}, &hookMockClassifier{}, emb, ms, slog.Default(), 0.95, 1)
+	require.NoError(t, retry.Execute(ctx, input))
+
+	stats, err := ms.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.TotalMemories, "the retry must not store the first memory again")
+
+	turnID := capture.TurnID(input.SessionID, input.UserMessage, input.AssistantMessage)
+	mem, err := ms.Get(ctx, capture.MemoryID(turnID, first.Content))
+	require.NoError(t, err)
+	assert.Equal(t, turnID, mem.Metadata["turn_id"])
+	assert.Equal(t, input.SessionID, mem.Metadata["session_id"])
+}
+
+// TestPostTurnHook_RetryWithRewordedExtraction verifies that a retried turn
+// whose extraction words an already-stored memory differently does not store
+// it again, while genuinely new memories from the retry are still stored.
+func TestPostTurnHook_RetryWithRewordedExtraction(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+	input := hookTestInput()
+	first := models.CapturedMemory{Content: "Deploy with kubectl apply", Type: models.MemoryTypeProcedure, Confidence: 0.9}
+	reworded := models.CapturedMemory{Content: "Deployments are applied with kubectl", Type: models.MemoryTypeProcedure, Confidence: 0.9}
+	second := models.CapturedMemory{Content: "Always use --dry-run first", Type: models.MemoryTypeRule, Confidence: 0.85}
+
+	// reworded is similar enough to first to be the same fact, but below the
+	// 0.95 dedup threshold, so only the turn retry check can catch it.
+	emb := newLifecycleMockEmbedder(4)
+	emb.Register(embedder.DocumentText(first.Type, first.Content), []float32{1, 0, 0, 0})
+	emb.Register(embedder.DocumentText(reworded.Type, reworded.Content), []float32{0.9, 0.43589, 0, 0})
+	emb.Register(embedder.DocumentText(second.Type, second.Content), []float32{0, 0, 1, 0})
+
+	partial := hooks.NewPostTurnHook(&hookMockCapturer{memories: []models.CapturedMemory{first}}, &hookMockClassifier{}, emb, ms, slog.Default(), 0.95, 1)
+	require.NoError(t, partial.Execute(ctx, input))
+
+	retry := hooks.NewPostTurnHook(&hookMockCapturer{memories: []models.CapturedMemory{reworded, second}}, &hookMockClassifier{}, emb, ms, slog.Default(), 0.95, 1)
+	require.NoError(t, retry.Execute(ctx, input))
+
+	stats, err := ms.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.TotalMemories, "the reworded memory must not be stored again")
+
+	turnID := capture.TurnID(input.SessionID, input.UserMessage, input.AssistantMessage)
+	_, err = ms.Get(ctx, capture.MemoryID(turnID, reworded.Content))
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, err = ms.Get(ctx, capture.MemoryID(turnID, second.Content))
+	assert.NoError(t, err)
+
+	// A different turn saying the same thing is not a retry, so it is stored.
+	other := input
+	other.UserMessage += " (again)"
+	fresh := hooks.NewPostTurnHook(&hookMockCapturer{memories: []models.CapturedMemory{reworded}}, &hookMockClassifier{}, emb, ms, slog.Default(), 0.95, 1)
+	require.NoError(t, fresh.Execute(ctx, other))
+	stats, err = ms.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalMemories)
+}
+
+// TestTurnID verifies turn and memory IDs are stable and distinguish turns.
+func TestTurnID(t *testing.T) {
+	a := capture.TurnID("s1", "hi", "hello")
+	assert.Equal(t, a, capture.TurnID("s1", "hi", "hello"))
+	assert.NotEqual(t, a, capture.TurnID("s2", "hi", "hello"))
+	assert.NotEqual(t, a, capture.TurnID("s1", "hih", "ello"), "message boundaries are part of the ID")
+
+	assert.Equal(t, capture.MemoryID(a, "fact"), capture.MemoryID(a, " fact\n"))
+	assert.NotEqual(t, capture.MemoryID(a, "fact"), capture.MemoryID(capture.TurnID("s2", "hi", "hello"), "fact"))
+}
+
 func TestPostTurnHook_EmptyExtraction(t *testing.T) {
 	ctx := context.Background()
 	ms := store.NewMockStore()
@@ -244,6 +346,68 @@ func TestPreTurnHook_ZeroBudgetDefaultsToNonZero(t *testing.T) {
 	assert.NotNil(t, out)
 }
 
+func TestPreTurnHook_TrackAccess(t *testing.T) {
+	for _, track := range []bool{true, false} {
+		t.Run(map[bool]string{true: "enabled", false: "disabled"}[track], func(t *testing.T) {
+			ctx := context.Background()
+			ms := store.NewMockStore()
+			vec := newHookMockVec()
+			_ = ms.Upsert(ctx, newTestMemory("pre-track", models.MemoryTypeFact, "Tracked memory content"), vec)
+
+			hook := hooks.NewPreTurnHook(
+				&hookMockEmbedder{vec: vec},
+				ms,
+				newPreTurnRecaller(),
+				slog.Default(),
+			).WithTrackAccess(track)
+
+			out, err := hook.Execute(ctx, hooks.PreTurnInput{Message: "tracked", TokenBudget: 500})
+			require.NoError(t, err)
+			require.Equal(t, 1, out.MemoryCount)
+
+			got, err := ms.Get(ctx, "pre-track")
+			require.NoError(t, err)
+			if track {
+				assert.Equal(t, int64(1), got.AccessCount)
+			} else {
+				assert.Equal(t, int64(0), got.AccessCount)
+			}
+		})
+	}
+}
+
+func TestPreTurnHook_RepeatedRecallRaisesFrequency(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+	vec := newHookMockVec()
+	_ = ms.Upsert(ctx, newTestMemory("pre-freq", models.MemoryTypeFact, "Frequently recalled memory"), vec)
+
+	before, err := ms.Get(ctx, "pre-freq")
+	require.NoError(t, err)
+
+	hook := hooks.NewPreTurnHook(
+		&hookMockEmbedder{vec: vec},
+		ms,
+		newPreTurnRecaller(),
+		slog.Default(),
+	).WithTrackAccess(true)
+
+	for range 5 {
+		out, execErr := hook.Execute(ctx, hooks.PreTurnInput{Message: "frequent", TokenBudget: 500})
+		require.NoError(t, execErr)
+		require.Equal(t, 1, out.MemoryCount)
+	}
+
+	after, err := ms.Get(ctx, "pre-freq")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), after.AccessCount)
+
+	r := newPreTurnRecaller()
+	coldScore := r.Rank([]models.SearchResult{{Memory: *before, Score: 0.9}}, "", "")[0].FrequencyScore
+	warmScore := r.Rank([]models.SearchResult{{Memory: *after, Score: 0.9}}, "", "")[0].FrequencyScore
+	assert.Greater(t, warmScore, coldScore)
+}
+
 func TestPreTurnHook_EmbedError(t *testing.T) {
 	ctx := context.Background()
 	ms := store.NewMockStore()
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestKeywordScore(t *testing.T) {
+	assert.InDelta(t, 1.0, store.KeywordScore("Got ERR_CONN_RESET from proxy", "err_conn_reset"), 1e-9)
+	assert.InDelta(t, 0.5, store.KeywordScore("Got ERR_CONN_RESET from proxy", "err_conn_reset timeout"), 1e-9)
+	assert.Zero(t, store.KeywordScore("unrelated", "err_conn_reset"))
+	assert.Zero(t, store.KeywordScore("anything", "  "))
+}
+
+func TestFuseRRF_BothListsRankFirst(t *testing.T) {
+	vector := []models.SearchResult{
+		{Memory: models.Memory{ID: "a"}, Score: 0.9},
+		{Memory: models.Memory{ID: "b"}, Score: 0.8},
+	}
+	keyword := []models.SearchResult{
+		{Memory: models.Memory{ID: "b"}, Score: 1},
+		{Memory: models.Memory{ID: "c"}, Score: 0.5},
+	}
+
+	got := store.FuseRRF(vector, keyword, 10)
+	require.Len(t, got, 3)
+	assert.Equal(t, "b", got[0].Memory.ID, "found by both rankings")
+	assert.Equal(t, "a", got[1].Memory.ID)
+	assert.Equal(t, "c", got[2].Memory.ID)
+
+	assert.Len(t, store.FuseRRF(vector, keyword, 1), 1)
+}
+
+func TestMockStore_HybridSearchSurfacesExactMatch(t *testing.T) {
+	ctx := context.Background()
+	ms := store.NewMockStore()
+
+	query := []float32{1, 0, 0}
+	require.NoError(t, ms.Upsert(ctx, models.Memory{ID: "near-1", Content: "Connection problems with the proxy"}, []float32{1, 0, 0}))
+	require.NoError(t, ms.Upsert(ctx, models.Memory{ID: "near-2", Content: "Network flakiness in CI"}, []float32{0.9, 0.1, 0}))
+	require.NoError(t, ms.Upsert(ctx, models.Memory{ID: "exact", Content: "ERR_CONN_RESET means the upstream closed the socket"}, []float32{0, 0, 1}))
+
+	plain, err := ms.Search(ctx, query, 2, nil)
+	require.NoError(t, err)
+	for _, r := range plain {
+		assert.NotEqual(t, "exact", r.Memory.ID)
+	}
+
+	hybrid, err := ms.Search(ctx, query, 2, &store.SearchFilters{Keyword: "ERR_CONN_RESET"})
+	require.NoError(t, err)
+	require.Len(t, hybrid, 2)
+	ids := []string{hybrid[0].Memory.ID, hybrid[1].Memory.ID}
+	assert.Contains(t, ids, "exact")
+	assert.Contains(t, ids, "near-1")
+}
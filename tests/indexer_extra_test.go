@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/indexer"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
 )
 
 // uniqueEmbedder generates a random unique vector per call to avoid deduplication in tests.
@@ -205,3 +207,100 @@ func TestIndexer_IndexDirectory_CancelledContext(t *testing.T) {
 	// Either ctx.Err() is returned or zero chunks are processed
 	_ = err // context cancellation may or may not be returned depending on timing
 }
+
+// sizeLimitEmbedder rejects texts longer than maxLen, like a provider whose
+// context window a chunk exceeds; a batch fails if any text is rejected.
+type sizeLimitEmbedder struct {
+	uniqueEmbedder
+	maxLen int
+}
+
+func (e *sizeLimitEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if len(text) > e.maxLen {
+		return nil, errors.New("input exceeds context length")
+	}
+	return e.uniqueEmbedder.Embed(ctx, text)
+}
+
+func (e *sizeLimitEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, len(texts))
+	for i := range texts {
+		vec, err := e.Embed(ctx, texts[i])
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = vec
+	}
+	return vecs, nil
+}
+
+// writeOversizedDoc writes a document whose second section is a single
+// unbreakable 3000-character line that splitBySize cannot cut.
+func writeOversizedDoc(t *testing.T) (string, string) {
+	t.Helper()
+	giant := strings.Repeat("x", 3000)
+	mdPath := filepath.Join(t.TempDir(), "big.md")
+	content := "# Intro\nA short introductory paragraph.\n\n# Blob\n" + giant + "\n"
+	require.NoError(t, os.WriteFile(mdPath, []byte(content), 0644))
+	return mdPath, giant
+}
+
+func TestIndexer_OversizedChunk_Split(t *testing.T) {
+	mdPath, giant := writeOversizedDoc(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	idx := indexer.NewIndexer(&uniqueEmbedder{dimension: 768}, st, 512, 64, logger).
+		WithMaxChunkTokens(200, indexer.OversizeSplit)
+	count, err := idx.IndexFile(context.Background(), mdPath)
+	require.NoError(t, err)
+	assert.Greater(t, count, 2, "the giant line is stored as several pieces")
+	assert.Equal(t, indexer.IndexStats{Split: 1}, idx.Stats())
+
+	mems, _, err := st.List(context.Background(), nil, 100, "")
+	require.NoError(t, err)
+	stored := 0
+	for i := range mems {
+		if strings.HasPrefix(mems[i].Content, "x") {
+			assert.LessOrEqual(t, tokenizer.EstimateTokens(mems[i].Content), 200)
+			stored += len(mems[i].Content)
+		}
+	}
+	assert.Equal(t, len(giant), stored, "no content is lost")
+}
+
+func TestIndexer_OversizedChunk_Truncate(t *testing.T) {
+	mdPath, _ := writeOversizedDoc(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	idx := indexer.NewIndexer(&uniqueEmbedder{dimension: 768}, st, 512, 64, logger).
+		WithMaxChunkTokens(200, indexer.OversizeTruncate)
+	count, err := idx.IndexFile(context.Background(), mdPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, indexer.IndexStats{Truncated: 1}, idx.Stats())
+
+	mems, _, err := st.List(context.Background(), nil, 100, "")
+	require.NoError(t, err)
+	for i := range mems {
+		if strings.HasPrefix(mems[i].Content, "x") {
+			assert.Equal(t, true, mems[i].Metadata["truncated"])
+		}
+	}
+}
+
+func TestIndexer_EmbedFailure_DropsOnlyFailingChunk(t *testing.T) {
+	mdPath, _ := writeOversizedDoc(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	// No token cap: the giant chunk reaches the embedder and is rejected,
+	// but the rest of the file is still indexed.
+	emb := &sizeLimitEmbedder{uniqueEmbedder: uniqueEmbedder{dimension: 768}, maxLen: 1000}
+	idx := indexer.NewIndexer(emb, st, 512, 64, logger)
+	count, err := idx.IndexFile(context.Background(), mdPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, indexer.IndexStats{Dropped: 1}, idx.Stats())
+}
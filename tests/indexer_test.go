@@ -2,15 +2,19 @@ package tests
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/indexer"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
@@ -183,3 +187,122 @@ func (f *fixedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]flo
 func (f *fixedEmbedder) Dimension() int {
 	return f.dimension
 }
+
+// oneHotEmbedder returns orthogonal vectors, one axis per call, so no two
+// chunks look like duplicates.
+type oneHotEmbedder struct {
+	dimension int
+	next      int
+}
+
+func (m *oneHotEmbedder) Embed(_ context.Context, _ string) ([]float32, error) {
+	vec := make([]float32, m.dimension)
+	vec[m.next%m.dimension] = 1
+	m.next++
+	return vec, nil
+}
+
+func (m *oneHotEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	results := make([][]float32, len(texts))
+	for i, t := range texts {
+		results[i], _ = m.Embed(ctx, t)
+	}
+	return results, nil
+}
+
+func (m *oneHotEmbedder) Dimension() int {
+	return m.dimension
+}
+
+// batchCountingStore records the size of every UpsertBatch call.
+type batchCountingStore struct {
+	*store.MockStore
+	batches []int
+}
+
+func (s *batchCountingStore) UpsertBatch(ctx context.Context, memories []models.Memory, vectors [][]float32) error {
+	s.batches = append(s.batches, len(memories))
+	return s.MockStore.UpsertBatch(ctx, memories, vectors)
+}
+
+func TestIndexer_IndexFile_UpsertsInBatches(t *testing.T) {
+	dir := t.TempDir()
+	mdPath := filepath.Join(dir, "big.md")
+
+	var sb strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&sb, "## Section %d\nBody of section %d.\n\n", i, i)
+	}
+	require.NoError(t, os.WriteFile(mdPath, []byte(sb.String()), 0644))
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := &batchCountingStore{MockStore: store.NewMockStore()}
+
+	idx := indexer.NewIndexer(&oneHotEmbedder{dimension: 256}, st, 512, 64, logger)
+	count, err := idx.IndexFile(context.Background(), mdPath)
+	require.NoError(t, err)
+	assert.Equal(t, 100, count)
+	assert.Equal(t, []int{64, 36}, st.batches)
+
+	stats, err := st.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), stats.TotalMemories)
+}
+
+// batchFailingStore fails every UpsertBatch and rejects single upserts of
+// chunks containing "poison".
+type batchFailingStore struct {
+	*store.MockStore
+}
+
+func (s *batchFailingStore) UpsertBatch(context.Context, []models.Memory, [][]float32) error {
+	return errors.New("batch rejected")
+}
+
+func (s *batchFailingStore) Upsert(ctx context.Context, memory models.Memory, vector []float32) error {
+	if strings.Contains(memory.Content, "poison") {
+		return errors.New("chunk rejected")
+	}
+	return s.MockStore.Upsert(ctx, memory, vector)
+}
+
+func TestIndexer_IndexFile_FallsBackToSingleUpserts(t *testing.T) {
+	dir := t.TempDir()
+	mdPath := filepath.Join(dir, "mixed.md")
+	content := "## One\nFirst body.\n\n## Two\nSome poison here.\n\n## Three\nThird body.\n"
+	require.NoError(t, os.WriteFile(mdPath, []byte(content), 0644))
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError + 4}))
+	st := &batchFailingStore{MockStore: store.NewMockStore()}
+
+	idx := indexer.NewIndexer(&oneHotEmbedder{dimension: 256}, st, 512, 64, logger)
+	count, err := idx.IndexFile(context.Background(), mdPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, indexer.IndexStats{Dropped: 1}, idx.Stats())
+
+	stats, err := st.Stats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.TotalMemories)
+}
+
+func TestIndexer_IndexFile_DeduplicatesWithinBatch(t *testing.T) {
+	dir := t.TempDir()
+	mdPath := filepath.Join(dir, "twice.md")
+	content := "# One\nSame text.\n\n# Two\nSame text.\n"
+	require.NoError(t, os.WriteFile(mdPath, []byte(content), 0644))
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	idx := indexer.NewIndexer(&fixedEmbedder{dimension: 8, value: 0.5}, st, 512, 64, logger)
+	count, err := idx.IndexFile(context.Background(), mdPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "second chunk duplicates one still waiting in the batch")
+}
+
+func TestMockStore_UpsertBatch_LengthMismatch(t *testing.T) {
+	st := store.NewMockStore()
+	err := st.UpsertBatch(context.Background(), []models.Memory{{ID: "a"}}, nil)
+	assert.Error(t, err)
+}
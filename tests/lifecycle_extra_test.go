@@ -168,6 +168,76 @@ func TestLifecycle_DryRun_SessionDecay(t *testing.T) {
 	assert.NoError(t, err, "memory should still exist after dry run")
 }
 
+func TestLifecycle_SessionDecay_CustomThreshold(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	now := time.Now().UTC()
+	for id, idle := range map[string]time.Duration{
+		"sess-90m": 90 * time.Minute,
+		"sess-30m": 30 * time.Minute,
+	} {
+		mem := models.Memory{
+			ID:           id,
+			Type:         models.MemoryTypeEpisode,
+			Scope:        models.ScopeSession,
+			Visibility:   models.VisibilityShared,
+			Content:      "Session data " + id,
+			CreatedAt:    now.Add(-idle),
+			LastAccessed: now.Add(-idle),
+		}
+		require.NoError(t, s.Upsert(ctx, mem, testVector(0.5)))
+	}
+
+	lm := lifecycle.NewManager(s, nil, lifecycleLogger())
+	lm.SetSessionDecay(time.Hour)
+	report, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Decayed)
+	_, err = s.Get(ctx, "sess-90m")
+	assert.Error(t, err, "memory idle for 90m should decay with a 1h threshold")
+	_, err = s.Get(ctx, "sess-30m")
+	assert.NoError(t, err, "memory idle for 30m should survive a 1h threshold")
+}
+
+func TestLifecycle_ExpiresAt_AnyScope(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	now := time.Now().UTC()
+	seed := []models.Memory{
+		{ID: "perm-expired", Scope: models.ScopePermanent, ExpiresAt: now.Add(-time.Hour)},
+		{ID: "proj-expired", Scope: models.ScopeProject, ExpiresAt: now.Add(-time.Minute)},
+		{ID: "perm-future", Scope: models.ScopePermanent, ExpiresAt: now.Add(24 * time.Hour)},
+		{ID: "perm-none", Scope: models.ScopePermanent},
+	}
+	for i := range seed {
+		mem := seed[i]
+		mem.Type = models.MemoryTypeFact
+		mem.Visibility = models.VisibilityShared
+		mem.Content = "content " + mem.ID
+		mem.CreatedAt, mem.LastAccessed = now, now
+		require.NoError(t, s.Upsert(ctx, mem, testVector(float32(i+1)*0.1)))
+	}
+
+	lm := lifecycle.NewManager(s, nil, lifecycleLogger())
+	report, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.ExpiredAt)
+	assert.Equal(t, 0, report.Expired, "expires_at deletions are counted separately from TTL expiry")
+	assert.Equal(t, 0, report.Retired)
+	for _, id := range []string{"perm-expired", "proj-expired"} {
+		_, err = s.Get(ctx, id)
+		assert.Error(t, err, "%s should be deleted", id)
+	}
+	for _, id := range []string{"perm-future", "perm-none"} {
+		_, err = s.Get(ctx, id)
+		assert.NoError(t, err, "%s should survive", id)
+	}
+}
+
 func TestLifecycle_EmptyStore(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewMockStore()
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+// seedMergePair stores two near-duplicate permanent memories; perm-merge-b
+// has the higher confidence.
+func seedMergePair(t *testing.T, s *store.MockStore) *lifecycleMockEmbedder {
+	t.Helper()
+	ctx := context.Background()
+	const dim = 768
+	contentA := "Go uses static typing"
+	contentB := "Go uses static typing for safety and reliability"
+	vecA, vecB := nearIdenticalVector(0.8, dim)
+
+	emb := newLifecycleMockEmbedder(dim)
+	emb.Register(contentA, vecA)
+	emb.Register(contentB, vecB)
+
+	now := time.Now().UTC()
+	memA := models.Memory{
+		ID: "perm-merge-a", Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		Visibility: models.VisibilityShared, Content: contentA, Confidence: 0.7,
+		Tags: []string{"go"}, AccessCount: 3, CreatedAt: now, UpdatedAt: now,
+		Attachments: []models.Attachment{{Type: models.AttachmentFile, Ref: "docs/go.md"}},
+	}
+	memB := models.Memory{
+		ID: "perm-merge-b", Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		Visibility: models.VisibilityShared, Content: contentB, Confidence: 0.95,
+		Tags: []string{"typing"}, AccessCount: 4, CreatedAt: now, UpdatedAt: now,
+		Attachments: []models.Attachment{
+			{Type: models.AttachmentFile, Ref: "docs/go.md"},
+			{Type: models.AttachmentURL, Ref: "https://go.dev/ref/spec"},
+		},
+	}
+	require.NoError(t, s.Upsert(ctx, memA, vecA))
+	require.NoError(t, s.Upsert(ctx, memB, vecB))
+	return emb
+}
+
+func TestLifecycle_Consolidate_LLMMerge(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+	emb := seedMergePair(t, s)
+
+	const merged = "Go uses static typing for safety and reliability"
+	lm := lifecycle.NewManager(s, emb, lifecycleLogger())
+	lm.SetMerger(lifecycle.NewMerger(&mockLLMClient{Resp: merged}, "test-model", lifecycleLogger()))
+	report, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Consolidated)
+
+	scope := models.ScopePermanent
+	current, _, err := s.List(ctx, &store.SearchFilters{Scope: &scope}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, current, 1, "only the merged memory should remain current")
+	got := current[0]
+	assert.Equal(t, merged, got.Content)
+	assert.Equal(t, "perm-merge-b", got.SupersedesID)
+	assert.Equal(t, int64(7), got.AccessCount, "access counts should be summed")
+	assert.ElementsMatch(t, []string{"go", "typing"}, got.Tags)
+	assert.ElementsMatch(t, []models.Attachment{
+		{Type: models.AttachmentFile, Ref: "docs/go.md"},
+		{Type: models.AttachmentURL, Ref: "https://go.dev/ref/spec"},
+	}, got.Attachments, "attachments should be the union across members")
+	assert.InDelta(t, 0.95, got.Confidence, 1e-9)
+
+	// Both originals are kept as invalidated history.
+	for _, id := range []string{"perm-merge-a", "perm-merge-b"} {
+		old, getErr := s.Get(ctx, id)
+		require.NoError(t, getErr)
+		assert.NotNil(t, old.ValidTo, "%s should be invalidated", id)
+	}
+}
+
+func TestLifecycle_Consolidate_LLMMergeSettles(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+	emb := seedMergePair(t, s)
+
+	lm := lifecycle.NewManager(s, emb, lifecycleLogger())
+	lm.SetMerger(lifecycle.NewMerger(&mockLLMClient{Resp: "Go uses static typing for safety and reliability"}, "test-model", lifecycleLogger()))
+	first, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, first.Consolidated)
+
+	second, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, second.Consolidated, "invalidated originals must not be merged again")
+}
+
+func TestLifecycle_Consolidate_LLMMergeFailureFallsBackToDelete(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+	emb := seedMergePair(t, s)
+
+	lm := lifecycle.NewManager(s, emb, lifecycleLogger())
+	lm.SetMerger(lifecycle.NewMerger(&mockLLMClient{Err: errors.New("claude down")}, "test-model", lifecycleLogger()))
+	report, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Consolidated)
+
+	_, err = s.Get(ctx, "perm-merge-a")
+	assert.Error(t, err, "lower-confidence duplicate should be deleted")
+	_, err = s.Get(ctx, "perm-merge-b")
+	assert.NoError(t, err)
+}
@@ -2,8 +2,10 @@ package tests
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/lifecycle"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
@@ -488,3 +491,425 @@ func TestLifecycle_ResolveConflicts(t *testing.T) {
 	require.NoError(t, getErr)
 	assert.Equal(t, models.ConflictStatusResolved, loser.ConflictStatus)
 }
+
+func TestLifecycle_PurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	mem := models.Memory{
+		ID: "trashed-1", Content: "Old scratch note", Confidence: 0.9,
+		Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		CreatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, st.Upsert(ctx, mem, make([]float32, 768)))
+	_, err := st.Trash(ctx, "trashed-1")
+	require.NoError(t, err)
+
+	// Without a retention period nothing is purged.
+	report, err := lifecycle.NewManager(st, nil, logger).Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Purged)
+
+	time.Sleep(5 * time.Millisecond)
+	mgr := lifecycle.NewManager(st, nil, logger)
+	mgr.SetTrashRetention(time.Millisecond)
+
+	report, err = mgr.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Purged)
+	_, err = st.Restore(ctx, "trashed-1")
+	require.NoError(t, err, "dry run must not purge")
+	_, err = st.Trash(ctx, "trashed-1")
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	report, err = mgr.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Purged)
+	_, err = st.Restore(ctx, "trashed-1")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestLifecycle_ScanLimits_Truncate(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	for _, id := range []string{"ttl-a", "ttl-b", "ttl-c"} {
+		require.NoError(t, st.Upsert(ctx, models.Memory{
+			ID: id, Content: "expired " + id, Type: models.MemoryTypeFact,
+			Scope: models.ScopeTTL, TTLSeconds: 3600,
+			CreatedAt: old, UpdatedAt: old, LastAccessed: old,
+		}, testVector(0.1)))
+	}
+
+	before := metrics.LifecycleScanTruncated.Value()
+	mgr := lifecycle.NewManager(st, nil, logger)
+	mgr.SetScanLimits(2, 1)
+	report, err := mgr.Run(ctx, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Expired, "scan must stop at max_scan")
+	assert.Greater(t, metrics.LifecycleScanTruncated.Value(), before)
+
+	// The next run picks up the remainder and does not truncate.
+	before = metrics.LifecycleScanTruncated.Value()
+	report, err = mgr.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Expired)
+	assert.Equal(t, before, metrics.LifecycleScanTruncated.Value())
+}
+
+// TestLifecycle_Consolidate_AcrossPages verifies that streaming consolidation
+// still finds near-duplicates that land on different List pages.
+func TestLifecycle_Consolidate_AcrossPages(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	s := store.NewMockStore()
+
+	const dim = 768
+	contentA := "Deploys go through the staging cluster first"
+	contentB := "Deploys always go through the staging cluster first"
+	contentC := "The office is closed on public holidays"
+
+	vecA, vecB := nearIdenticalVector(0.4, dim)
+	vecC := distinctVector(dim)
+
+	emb := newLifecycleMockEmbedder(dim)
+	emb.Register(contentA, vecA)
+	emb.Register(contentB, vecB)
+	emb.Register(contentC, vecC)
+
+	now := time.Now().UTC()
+	for _, tc := range []struct {
+		id         string
+		content    string
+		confidence float64
+		vec        []float32
+	}{
+		{"page-a", contentA, 0.6, vecA},
+		{"page-b", contentC, 0.9, vecC},
+		{"page-c", contentB, 0.9, vecB},
+	} {
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: tc.id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Visibility: models.VisibilityShared, Content: tc.content, Confidence: tc.confidence,
+			CreatedAt: now, UpdatedAt: now,
+		}, tc.vec))
+	}
+
+	lm := lifecycle.NewManager(s, emb, logger)
+	lm.SetScanLimits(0, 1) // one memory per page
+	report, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Consolidated)
+	_, err = s.Get(ctx, "page-a")
+	assert.Error(t, err, "lower-confidence duplicate should be deleted")
+	_, err = s.Get(ctx, "page-b")
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, "page-c")
+	assert.NoError(t, err)
+}
+
+// TestLifecycle_Consolidate_Partition verifies that near-duplicates in
+// different projects are only merged when consolidation is not partitioned
+// by project, both within a page and across pages.
+func TestLifecycle_Consolidate_Partition(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	const dim = 768
+	contentA := "Always squash commits before merging"
+	contentB := "Always squash the commits before merging"
+	vecA, vecB := nearIdenticalVector(0.3, dim)
+
+	for _, tc := range []struct {
+		partition lifecycle.ConsolidationPartition
+		pageSize  int
+		want      int
+	}{
+		{lifecycle.PartitionNone, 0, 1},
+		{lifecycle.PartitionType, 0, 1},
+		{lifecycle.PartitionProject, 0, 0},
+		{lifecycle.PartitionTypeProject, 0, 0},
+		{lifecycle.PartitionProject, 1, 0},
+	} {
+		s := store.NewMockStore()
+		emb := newLifecycleMockEmbedder(dim)
+		emb.Register(contentA, vecA)
+		emb.Register(contentB, vecB)
+
+		now := time.Now().UTC()
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: "proj-a-rule", Type: models.MemoryTypeRule, Scope: models.ScopePermanent, Project: "a",
+			Visibility: models.VisibilityShared, Content: contentA, Confidence: 0.6, CreatedAt: now, UpdatedAt: now,
+		}, vecA))
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: "proj-b-rule", Type: models.MemoryTypeRule, Scope: models.ScopePermanent, Project: "b",
+			Visibility: models.VisibilityShared, Content: contentB, Confidence: 0.9, CreatedAt: now, UpdatedAt: now,
+		}, vecB))
+
+		lm := lifecycle.NewManager(s, emb, logger)
+		lm.SetScanLimits(0, tc.pageSize)
+		lm.SetConsolidationPartition(tc.partition)
+		report, err := lm.Run(ctx, false)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, report.Consolidated, "partition %q, page size %d", tc.partition, tc.pageSize)
+	}
+}
+
+// topTenDuplicatesStore caps FindDuplicates at the ten most similar
+// memories, as the Memgraph vector index does.
+type topTenDuplicatesStore struct{ *store.MockStore }
+
+func (s topTenDuplicatesStore) FindDuplicates(ctx context.Context, vector []float32, threshold float64) ([]models.SearchResult, error) {
+	dupes, err := s.MockStore.FindDuplicates(ctx, vector, threshold)
+	sort.Slice(dupes, func(i, j int) bool { return dupes[i].Score > dupes[j].Score })
+	return dupes[:min(len(dupes), 10)], err
+}
+
+// TestLifecycle_Consolidate_PartitionSearch verifies that a cross-page
+// near-duplicate in the same partition is found even when more than ten
+// closer memories sit in other partitions.
+func TestLifecycle_Consolidate_PartitionSearch(t *testing.T) {
+	ctx := context.Background()
+	const dim = 768
+	vecA, vecB := nearIdenticalVector(0.3, dim)
+	vecB[1] += 0.05 // still a near-duplicate of vecA
+	// The other project's copies sit between the two, closer to each than
+	// they are to one another.
+	mid := make([]float32, dim)
+	for i := range mid {
+		mid[i] = (vecA[i] + vecB[i]) / 2
+	}
+
+	s := topTenDuplicatesStore{store.NewMockStore()}
+	emb := newLifecycleMockEmbedder(dim)
+	now := time.Now().UTC()
+	add := func(id, project, content string, vec []float32) {
+		emb.Register(content, vec)
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: id, Type: models.MemoryTypeRule, Scope: models.ScopePermanent, Project: project,
+			Visibility: models.VisibilityShared, Content: content, Confidence: 0.9, CreatedAt: now, UpdatedAt: now,
+		}, vec))
+	}
+	add("a-1", "a", "Always squash commits before merging", vecA)
+	add("a-2", "a", "Always squash the commits before merging", vecB)
+	for i := 0; i < 12; i++ {
+		add(fmt.Sprintf("b-%02d", i), "b", fmt.Sprintf("Always squash commits before merging (%d)", i), mid)
+	}
+
+	lm := lifecycle.NewManager(s, emb, lifecycleLogger())
+	lm.SetScanLimits(0, 1)
+	lm.SetConsolidationPartition(lifecycle.PartitionProject)
+	_, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+
+	_, errA1 := s.Get(ctx, "a-1")
+	_, errA2 := s.Get(ctx, "a-2")
+	assert.True(t, (errA1 == nil) != (errA2 == nil), "exactly one of the project a pair is consolidated away")
+}
+
+// TestLifecycle_Consolidate_ExcludeTags verifies that near-duplicates are
+// not merged when either carries an excluded tag, within a page and across
+// pages, whichever memory carries it.
+func TestLifecycle_Consolidate_ExcludeTags(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	const dim = 768
+	contentA := "Deploy with make release and wait for the smoke tests"
+	contentB := "Deploy with make release then wait for the smoke tests"
+	vecA, vecB := nearIdenticalVector(0.4, dim)
+
+	for _, tc := range []struct {
+		exclude  []string
+		tagsA    []string
+		tagsB    []string
+		pageSize int
+		want     int
+	}{
+		{nil, []string{"env:prod"}, []string{"env:dev"}, 0, 1},
+		{[]string{"env:prod"}, []string{"env:prod"}, []string{"env:dev"}, 0, 0},
+		{[]string{"ENV:DEV"}, []string{"env:prod"}, []string{"env:dev"}, 0, 0},
+		{[]string{"env:prod"}, []string{"env:prod"}, nil, 1, 0},
+		{[]string{"env:prod"}, nil, []string{"env:prod"}, 1, 0},
+		{[]string{"env:staging"}, []string{"env:prod"}, []string{"env:dev"}, 1, 1},
+	} {
+		s := store.NewMockStore()
+		emb := newLifecycleMockEmbedder(dim)
+		emb.Register(contentA, vecA)
+		emb.Register(contentB, vecB)
+
+		now := time.Now().UTC()
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: "deploy-a", Type: models.MemoryTypeProcedure, Scope: models.ScopePermanent, Tags: tc.tagsA,
+			Visibility: models.VisibilityShared, Content: contentA, Confidence: 0.6, CreatedAt: now, UpdatedAt: now,
+		}, vecA))
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: "deploy-b", Type: models.MemoryTypeProcedure, Scope: models.ScopePermanent, Tags: tc.tagsB,
+			Visibility: models.VisibilityShared, Content: contentB, Confidence: 0.9, CreatedAt: now, UpdatedAt: now,
+		}, vecB))
+
+		lm := lifecycle.NewManager(s, emb, logger)
+		lm.SetScanLimits(0, tc.pageSize)
+		lm.SetConsolidationExcludeTags(tc.exclude)
+		report, err := lm.Run(ctx, false)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, report.Consolidated, "exclude %v, tags %v / %v, page size %d",
+			tc.exclude, tc.tagsA, tc.tagsB, tc.pageSize)
+	}
+}
+
+// TestLifecycle_EvictOverCap verifies that a scope over its memory.max_per_scope
+// cap loses its least recently accessed memories, and that other scopes are
+// left alone.
+func TestLifecycle_EvictOverCap(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	now := time.Now().UTC()
+	s := store.NewMockStore()
+	for i, id := range []string{"oldest", "older", "newer", "newest"} {
+		accessed := now.Add(time.Duration(i-4) * time.Hour)
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: id, Type: models.MemoryTypeFact, Scope: models.ScopeProject, Project: "p",
+			Content: "project fact " + id, Confidence: 0.9, CreatedAt: now.Add(-48 * time.Hour), LastAccessed: accessed,
+		}, testVector(0.1)))
+	}
+	require.NoError(t, s.Upsert(ctx, models.Memory{
+		ID: "rule", Type: models.MemoryTypeRule, Scope: models.ScopePermanent,
+		Content: "permanent rule", Confidence: 0.9, CreatedAt: now, LastAccessed: now.Add(-72 * time.Hour),
+	}, testVector(0.2)))
+
+	lm := lifecycle.NewManager(s, nil, logger)
+	lm.SetMaxPerScope(map[models.MemoryScope]int{models.ScopeProject: 2, models.ScopePermanent: 0})
+
+	report, err := lm.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Evicted)
+	_, err = s.Get(ctx, "oldest")
+	require.NoError(t, err, "dry run must not delete")
+
+	report, err = lm.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Evicted)
+	for _, id := range []string{"oldest", "older"} {
+		_, err = s.Get(ctx, id)
+		assert.Error(t, err, "%s should be evicted", id)
+	}
+	for _, id := range []string{"newer", "newest", "rule"} {
+		_, err = s.Get(ctx, id)
+		assert.NoError(t, err, "%s should be kept", id)
+	}
+}
+
+// TestLifecycle_EvictOverCap_SkipsTruncatedScan verifies that no memory is
+// evicted from a scope the max_scan cap kept the scan from covering.
+func TestLifecycle_EvictOverCap_SkipsTruncatedScan(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	s := store.NewMockStore()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Upsert(ctx, models.Memory{
+			ID: fmt.Sprintf("p%d", i), Type: models.MemoryTypeFact, Scope: models.ScopeProject, Project: "p",
+			Content: fmt.Sprintf("project fact %d", i), Confidence: 0.9,
+			CreatedAt: now.Add(-time.Duration(i) * time.Hour), LastAccessed: now.Add(-time.Duration(i) * time.Hour),
+		}, testVector(0.1)))
+	}
+
+	lm := lifecycle.NewManager(s, nil, lifecycleLogger())
+	lm.SetScanLimits(3, 2)
+	lm.SetMaxPerScope(map[models.MemoryScope]int{models.ScopeProject: 2})
+
+	report, err := lm.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Zero(t, report.Evicted)
+	stats, err := s.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.TotalMemories)
+}
+
+func TestTypeTTL_Apply(t *testing.T) {
+	ttl, err := lifecycle.ParseTypeTTL(map[string]string{"episode": "7d", "fact": "24h"}, false)
+	require.NoError(t, err)
+
+	episode := models.Memory{Type: models.MemoryTypeEpisode, Scope: models.ScopeTTL}
+	ttl.Apply(&episode)
+	assert.Equal(t, int64(7*24*3600), episode.TTLSeconds)
+
+	// Explicit TTLs win.
+	explicit := models.Memory{Type: models.MemoryTypeEpisode, Scope: models.ScopeTTL, TTLSeconds: 60}
+	ttl.Apply(&explicit)
+	assert.Equal(t, int64(60), explicit.TTLSeconds)
+
+	// Unlisted types and non-ttl scopes are untouched without type retention.
+	rule := models.Memory{Type: models.MemoryTypeRule, Scope: models.ScopeTTL}
+	ttl.Apply(&rule)
+	assert.Zero(t, rule.TTLSeconds)
+	permanent := models.Memory{Type: models.MemoryTypeFact, Scope: models.ScopePermanent}
+	ttl.Apply(&permanent)
+	assert.Zero(t, permanent.TTLSeconds)
+
+	retention, err := lifecycle.ParseTypeTTL(map[string]string{"fact": "24h"}, true)
+	require.NoError(t, err)
+	retention.Apply(&permanent)
+	assert.Equal(t, int64(24*3600), permanent.TTLSeconds)
+
+	// A nil policy is a no-op.
+	var none *lifecycle.TypeTTL
+	none.Apply(&rule)
+	assert.Zero(t, rule.TTLSeconds)
+
+	_, err = lifecycle.ParseTypeTTL(map[string]string{"memo": "1d"}, false)
+	assert.Error(t, err)
+	_, err = lifecycle.ParseTypeTTL(map[string]string{"fact": "soon"}, false)
+	assert.Error(t, err)
+}
+
+func TestLifecycle_TypeRetention_ExpiresAllScopes(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	st := store.NewMockStore()
+
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	require.NoError(t, st.Upsert(ctx, models.Memory{
+		ID: "perm-episode", Content: "old episode", Type: models.MemoryTypeEpisode,
+		Scope: models.ScopePermanent, TTLSeconds: 3600,
+		CreatedAt: old, UpdatedAt: old, LastAccessed: old,
+	}, testVector(0.1)))
+
+	mgr := lifecycle.NewManager(st, nil, logger)
+	report, err := mgr.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Expired, "only ttl scope is scanned by default")
+
+	mgr.SetTypeRetention(true)
+	report, err = mgr.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Expired)
+}
+
+// TestClusterPairs verifies that merge chains are grouped under the memory
+// that finally survives.
+func TestClusterPairs(t *testing.T) {
+	pairs := []lifecycle.ConsolidationPair{
+		{Keep: "d", Drop: "e", Similarity: 0.97},
+		{Keep: "a", Drop: "b", Similarity: 0.96},
+		{Keep: "c", Drop: "a", Similarity: 0.98}, // a is kept once, then dropped
+	}
+	clusters := lifecycle.ClusterPairs(pairs)
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, "c", clusters[0].Survivor, "largest cluster first")
+	assert.Equal(t, []string{"c", "a", "b"}, clusters[0].Members)
+	assert.Len(t, clusters[0].Pairs, 2)
+
+	assert.Equal(t, "d", clusters[1].Survivor)
+	assert.Equal(t, []string{"d", "e"}, clusters[1].Members)
+
+	assert.Empty(t, lifecycle.ClusterPairs(nil))
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/logfile"
+)
+
+func TestLogfile_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "cortex.log")
+	w, err := logfile.Open(path, logfile.Options{MaxSizeBytes: 20, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for _, line := range []string{"first line 12345\n", "second line 1234\n", "third line 12345\n", "fourth line 1234\n"} {
+		_, err = w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	read := func(p string) string {
+		b, readErr := os.ReadFile(p)
+		require.NoError(t, readErr)
+		return string(b)
+	}
+	assert.Equal(t, "fourth line 1234\n", read(path))
+	assert.Equal(t, "third line 12345\n", read(path+".1"))
+	assert.Equal(t, "second line 1234\n", read(path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "backups beyond MaxBackups must be removed")
+}
+
+func TestLogfile_AppendsAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cortex.log")
+	for _, line := range []string{"a\n", "b\n"} {
+		w, err := logfile.Open(path, logfile.Options{MaxSizeBytes: 1024})
+		require.NoError(t, err)
+		_, err = w.Write([]byte(line))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(b))
+
+	w, err := logfile.Open(path, logfile.Options{})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("late"))
+	assert.Error(t, err, "writes after Close must fail")
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"os"
 	"testing"
@@ -234,6 +235,24 @@ func TestMCPRecall_EmptyMessageReturnsError(t *testing.T) {
 	assert.True(t, result.IsError)
 }
 
+func TestMCPRecall_TopModeTakesNoMessage(t *testing.T) {
+	srv, _ := newMCPServer(t)
+	ctx := context.Background()
+
+	rememberAndGetID(t, srv, map[string]any{"content": "Always run migrations before deploying", "type": "rule"})
+
+	result, err := srv.HandleRecall(ctx, makeReq("recall", map[string]any{"mode": "top"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, textContent(t, result))
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(textContent(t, result)), &out))
+	assert.Equal(t, float64(1), out["memory_count"])
+
+	result, err = srv.HandleRecall(ctx, makeReq("recall", map[string]any{"mode": "top", "message": "deploy"}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
 func TestMCPRecall_EmptyStoreReturnsZeroCount(t *testing.T) {
 	srv, _ := newMCPServer(t)
 	ctx := context.Background()
@@ -273,6 +292,8 @@ func TestMCPRecall_WithProjectContext(t *testing.T) {
 	memCount, ok := out["memory_count"].(float64)
 	require.True(t, ok, "memory_count should be a float64")
 	assert.GreaterOrEqual(t, memCount, float64(1))
+	assert.Equal(t, memCount, out["total_candidates"])
+	assert.Equal(t, false, out["truncated"])
 }
 
 // --- forget tests ---
@@ -416,6 +437,48 @@ func TestMCPSearch_EmptyStoreReturnsEmptyResults(t *testing.T) {
 
 // --- stats tests ---
 
+func TestMCPCheckDuplicate_ReturnsSimilarWithoutStoring(t *testing.T) {
+	srv, ms := newMCPServer(t)
+	ctx := context.Background()
+
+	id := rememberAndGetID(t, srv, map[string]any{
+		"content": "Qdrant is a vector database",
+	})
+
+	result, err := srv.HandleCheckDuplicate(ctx, makeReq("check_duplicate", map[string]any{
+		"content": "Qdrant is a vector database",
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError, "no default threshold configured")
+
+	srv.SetDedup(0.92, store.DedupScope{})
+	result, err = srv.HandleCheckDuplicate(ctx, makeReq("check_duplicate", map[string]any{
+		"content": "Qdrant is a vector database",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, textContent(t, result))
+
+	var out struct {
+		Duplicates []models.SearchResult `json:"duplicates"`
+		Threshold  float64               `json:"threshold"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(textContent(t, result)), &out))
+	require.Len(t, out.Duplicates, 1)
+	assert.Equal(t, id, out.Duplicates[0].Memory.ID)
+	assert.InDelta(t, 0.92, out.Threshold, 1e-9)
+
+	stats, err := ms.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.TotalMemories, "check_duplicate must not store")
+
+	result, err = srv.HandleCheckDuplicate(ctx, makeReq("check_duplicate", map[string]any{
+		"content":   "Qdrant is a vector database",
+		"threshold": 1.5,
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
 func TestMCPStats_ReturnsCollectionStats(t *testing.T) {
 	srv, _ := newMCPServer(t)
 	ctx := context.Background()
@@ -452,3 +515,56 @@ func TestMCPStats_EmptyStore(t *testing.T) {
 	require.NoError(t, json.Unmarshal([]byte(textContent(t, result)), &stats))
 	assert.Equal(t, int64(0), stats.TotalMemories)
 }
+
+// --- resource tests ---
+
+func readResource(t *testing.T, srv *cortexmcp.Server, uri string) string {
+	t.Helper()
+	msg := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)
+	resp := srv.MCPServer().HandleMessage(context.Background(), json.RawMessage(msg))
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	var out struct {
+		Result struct {
+			Contents []struct {
+				Text string `json:"text"`
+			} `json:"contents"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(b, &out))
+	require.Nil(t, out.Error, "resources/read %s failed", uri)
+	require.Len(t, out.Result.Contents, 1)
+	return out.Result.Contents[0].Text
+}
+
+func TestMCPResource_Stats(t *testing.T) {
+	srv, _ := newMCPServer(t)
+	rememberAndGetID(t, srv, map[string]any{"content": "first fact", "type": "fact"})
+
+	var stats models.CollectionStats
+	require.NoError(t, json.Unmarshal([]byte(readResource(t, srv, "cortex://stats")), &stats))
+	assert.Equal(t, int64(1), stats.TotalMemories)
+
+	// Stats are recomputed on every read.
+	rememberAndGetID(t, srv, map[string]any{"content": "important rule", "type": "rule"})
+	require.NoError(t, json.Unmarshal([]byte(readResource(t, srv, "cortex://stats")), &stats))
+	assert.Equal(t, int64(2), stats.TotalMemories)
+}
+
+func TestMCPResource_Memory(t *testing.T) {
+	srv, _ := newMCPServer(t)
+	id := rememberAndGetID(t, srv, map[string]any{"content": "deploys run on fridays", "type": "fact"})
+
+	var mem models.Memory
+	require.NoError(t, json.Unmarshal([]byte(readResource(t, srv, "cortex://memories/"+id)), &mem))
+	assert.Equal(t, id, mem.ID)
+	assert.Equal(t, "deploys run on fridays", mem.Content)
+
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = "cortex://memories/no-such-id"
+	_, err := srv.HandleMemoryResource(context.Background(), req)
+	assert.Error(t, err)
+}
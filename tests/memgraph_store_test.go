@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"testing"
 	"time"
 
@@ -539,6 +540,27 @@ func TestMemgraphUpdateAccessMetadata(t *testing.T) {
 		"last_accessed should not have gone backwards")
 }
 
+// TestMemgraphUpdateAccessMetadataBatch verifies a single batch call
+// increments access_count on every listed memory.
+func TestMemgraphUpdateAccessMetadataBatch(t *testing.T) {
+	st := newIntegrationMemgraph(t)
+	emb := newTestEmbedder(t)
+	ctx := context.Background()
+
+	a := newMemory(models.MemoryTypeFact, "batch access memory one")
+	b := newMemory(models.MemoryTypeFact, "batch access memory two")
+	require.NoError(t, st.Upsert(ctx, a, mustEmbed(t, emb, a.Content)))
+	require.NoError(t, st.Upsert(ctx, b, mustEmbed(t, emb, b.Content)))
+
+	require.NoError(t, st.UpdateAccessMetadataBatch(ctx, []string{a.ID, b.ID, "does-not-exist"}))
+
+	for _, id := range []string{a.ID, b.ID} {
+		got, err := st.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), got.AccessCount, "access_count for %s", id)
+	}
+}
+
 // ─── Entity Tests ──────────────────────────────────────────────────────────────
 
 // TestMemgraphUpsertEntity_Dedup upserts the same entity name twice with updated
@@ -610,6 +632,44 @@ func TestMemgraphSearchEntities(t *testing.T) {
 	}
 }
 
+// TestMemgraphListEntitiesWithoutEmbedding verifies that only entities lacking
+// a name embedding are listed, in ID order, and that paging resumes after
+// the given ID.
+func TestMemgraphListEntitiesWithoutEmbedding(t *testing.T) {
+	st := newIntegrationMemgraph(t)
+	ctx := context.Background()
+
+	embedded := newEntity("Carol", models.EntityTypePerson)
+	embedded.NameEmbedding = make([]float32, ollamaDimension)
+	embedded.NameEmbedding[0] = 1
+	for _, e := range []models.Entity{
+		newEntity("Bob", models.EntityTypePerson),
+		newEntity("Alice", models.EntityTypePerson),
+		embedded,
+	} {
+		require.NoError(t, st.UpsertEntity(ctx, e))
+	}
+
+	var (
+		names []string
+		ids   []string
+		after string
+	)
+	for {
+		page, err := st.ListEntitiesWithoutEmbedding(ctx, after, 1)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		require.Len(t, page, 1)
+		names = append(names, page[0].Name)
+		ids = append(ids, page[0].ID)
+		after = page[0].ID
+	}
+	assert.ElementsMatch(t, []string{"Alice", "Bob"}, names, "Carol has an embedding and must not be listed")
+	assert.True(t, slices.IsSorted(ids), "entities should be listed in ID order")
+}
+
 // ─── Graph Tests (via GraphAdapter) ────────────────────────────────────────────
 
 // TestMemgraphEnsureSchema verifies that EnsureSchema is idempotent — calling it
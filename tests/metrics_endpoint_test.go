@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/api"
+	"github.com/ajitpratap0/openclaw-cortex/internal/metrics"
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func scrapeMetrics(t *testing.T, url, token string) string {
+	t.Helper()
+	resp := doRequest(t, http.MethodGet, url+"/metrics", nil, token)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "version=0.0.4")
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestAPI_Metrics_CountsRequestsAndErrors(t *testing.T) {
+	ts, _ := newTestServer(t, "secret")
+
+	searchBefore := metrics.APISearchRequests.Value()
+	errorsBefore := metrics.APIRememberErrors.Value()
+	latencyBefore := metrics.SearchLatency.Count()
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/search", jsonBody(t, map[string]any{"message": "go"}), "secret")
+	resp.Body.Close()
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/remember", jsonBody(t, map[string]any{"content": ""}), "secret")
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	assert.Equal(t, searchBefore+1, metrics.APISearchRequests.Value())
+	assert.Equal(t, errorsBefore+1, metrics.APIRememberErrors.Value())
+	assert.Equal(t, latencyBefore+1, metrics.SearchLatency.Count())
+
+	// /metrics stays open even though the API requires a token.
+	body := scrapeMetrics(t, ts.URL, "")
+	assert.Contains(t, body, "# TYPE cortex_api_search_requests_total counter")
+	assert.Contains(t, body, "# TYPE cortex_async_in_flight gauge")
+	assert.Contains(t, body, "# TYPE cortex_search_latency_seconds histogram")
+	assert.Contains(t, body, `cortex_search_latency_seconds_bucket{le="+Inf"}`)
+	assert.Contains(t, body, "cortex_recall_tokens_count")
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		assert.True(t, strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "cortex_"), "unexpected line %q", line)
+	}
+}
+
+func TestAPI_Metrics_Token(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	srv := api.NewServer(store.NewMockStore(), recall.NewRecaller(recall.DefaultWeights(), logger), &apiTestEmbedder{}, logger, "api-token", "")
+	srv.SetMetricsToken("scrape-token")
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	for _, token := range []string{"", "api-token"} {
+		resp := doRequest(t, http.MethodGet, ts.URL+"/metrics", nil, token)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "token %q", token)
+	}
+	scrapeMetrics(t, ts.URL, "scrape-token")
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	h := metrics.NewHistogram("test_histogram_buckets", "test", []float64{1, 5})
+	h.Observe(0.5)
+	h.Observe(3)
+	h.Observe(10)
+
+	var sb strings.Builder
+	require.NoError(t, metrics.WritePrometheus(&sb))
+	out := sb.String()
+	assert.Contains(t, out, "test_histogram_buckets_bucket{le=\"1\"} 1\n")
+	assert.Contains(t, out, "test_histogram_buckets_bucket{le=\"5\"} 2\n")
+	assert.Contains(t, out, "test_histogram_buckets_bucket{le=\"+Inf\"} 3\n")
+	assert.Contains(t, out, "test_histogram_buckets_sum 13.5\n")
+	assert.Contains(t, out, "test_histogram_buckets_count 3\n")
+}
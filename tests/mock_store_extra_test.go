@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,6 +39,38 @@ func TestMockStore_GetNotFound(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMockStore_GetMany(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	mem1 := newTestMemory("many-1", models.MemoryTypeFact, "first")
+	mem1.Tags = []string{"a"}
+	mem2 := newTestMemory("many-2", models.MemoryTypeFact, "second")
+	trashed := newTestMemory("many-trashed", models.MemoryTypeFact, "trashed")
+	require.NoError(t, s.Upsert(ctx, mem1, testVector(0.1)))
+	require.NoError(t, s.Upsert(ctx, mem2, testVector(0.2)))
+	require.NoError(t, s.Upsert(ctx, trashed, testVector(0.3)))
+	_, err := s.Trash(ctx, trashed.ID)
+	require.NoError(t, err)
+
+	got, err := s.GetMany(ctx, []string{"many-1", "many-2", "many-trashed", "nonexistent-id"})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "first", got["many-1"].Content)
+	assert.Equal(t, "second", got["many-2"].Content)
+
+	// Returned memories are copies.
+	m := got["many-1"]
+	m.Tags[0] = "mutated"
+	stored, err := s.Get(ctx, "many-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, stored.Tags)
+
+	empty, err := s.GetMany(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
 func TestMockStore_UpdateAccessMetadata_NotFound(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewMockStore()
@@ -103,6 +136,84 @@ func TestMockStore_SearchWithProjectFilter(t *testing.T) {
 	assert.Equal(t, "proj-a", results[0].Memory.ID)
 }
 
+func TestMockStore_SearchWithProjectsFilter(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	for _, p := range []string{"project-a", "project-b", "project-c", ""} {
+		mem := newTestMemory("mem-"+p, models.MemoryTypeFact, "memory for "+p)
+		mem.Project = p
+		require.NoError(t, s.Upsert(ctx, mem, testVector(0.5)))
+	}
+
+	results, err := s.Search(ctx, testVector(0.5), 10, &store.SearchFilters{Projects: []string{"project-a", "project-b"}})
+	require.NoError(t, err)
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.Memory.ID)
+	}
+	assert.ElementsMatch(t, []string{"mem-project-a", "mem-project-b", "mem-"}, ids, "listed projects plus globals")
+}
+
+func TestProjectFilter(t *testing.T) {
+	assert.Nil(t, store.ProjectFilter("", nil))
+
+	f := store.ProjectFilter("alpha", nil)
+	require.NotNil(t, f)
+	require.NotNil(t, f.Project)
+	assert.Equal(t, "alpha", *f.Project)
+	assert.Empty(t, f.Projects)
+
+	f = store.ProjectFilter("alpha", []string{" beta ", "alpha", ""})
+	require.NotNil(t, f)
+	assert.Nil(t, f.Project)
+	assert.Equal(t, []string{"alpha", "beta"}, f.Projects)
+}
+
+func TestSearchBroadening(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	global := newTestMemory("global", models.MemoryTypeRule, "always write tests")
+	other := newTestMemory("other", models.MemoryTypeFact, "other project fact")
+	other.Project = "other"
+	require.NoError(t, s.Upsert(ctx, global, testVector(0.5)))
+	require.NoError(t, s.Upsert(ctx, other, testVector(0.5)))
+
+	fresh := "fresh"
+	filters := &store.SearchFilters{Project: &fresh}
+
+	results, broadened, err := store.SearchBroadening(ctx, s, testVector(0.5), 10, filters, false, 0)
+	require.NoError(t, err)
+	assert.False(t, broadened)
+	assert.Empty(t, results, "disabled: the project filter stays strict")
+
+	results, broadened, err = store.SearchBroadening(ctx, s, testVector(0.5), 10, filters, true, 0)
+	require.NoError(t, err)
+	assert.True(t, broadened)
+	require.Len(t, results, 1, "only global memories are added, not other projects")
+	assert.Equal(t, "global", results[0].Memory.ID)
+	require.NotNil(t, filters.Project, "the caller's filter is not modified")
+
+	own := newTestMemory("own", models.MemoryTypeFact, "fresh project fact")
+	own.Project = fresh
+	require.NoError(t, s.Upsert(ctx, own, testVector(0.5)))
+
+	results, broadened, err = store.SearchBroadening(ctx, s, testVector(0.5), 10, filters, true, 0)
+	require.NoError(t, err)
+	assert.False(t, broadened, "one project hit is enough without min_memories")
+	assert.Len(t, results, 1)
+
+	results, broadened, err = store.SearchBroadening(ctx, s, testVector(0.5), 10, filters, true, 2)
+	require.NoError(t, err)
+	assert.True(t, broadened, "fewer than min_memories hits broadens")
+	assert.Len(t, results, 2)
+
+	_, broadened, err = store.SearchBroadening(ctx, s, testVector(0.5), 10, nil, true, 5)
+	require.NoError(t, err)
+	assert.False(t, broadened, "an unscoped search has nothing to broaden")
+}
+
 func TestMockStore_SearchWithSourceFilter(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewMockStore()
@@ -284,3 +395,45 @@ func TestMockStore_SearchLimitRespected(t *testing.T) {
 	require.NoError(t, err)
 	assert.LessOrEqual(t, len(results), 3)
 }
+
+func TestMockStore_SearchTieBreak(t *testing.T) {
+	s := store.NewMockStore()
+	ctx := context.Background()
+	vec := testVector(0.1)
+	now := time.Now().UTC()
+
+	// Identical vectors give identical scores, so only the tiebreak orders them.
+	for _, tc := range []struct {
+		id       string
+		accessed time.Time
+	}{
+		{"c", now.Add(-time.Hour)},
+		{"b", now.Add(-time.Hour)},
+		{"a", now.Add(-2 * time.Hour)},
+		{"d", now},
+	} {
+		m := newTestMemory(tc.id, models.MemoryTypeFact, "tied memory "+tc.id)
+		m.LastAccessed = tc.accessed
+		require.NoError(t, s.Upsert(ctx, m, vec))
+	}
+
+	for run := 0; run < 5; run++ {
+		results, err := s.Search(ctx, vec, 10, nil)
+		require.NoError(t, err)
+		ids := make([]string, len(results))
+		for i := range results {
+			ids[i] = results[i].Memory.ID
+		}
+		assert.Equal(t, []string{"d", "b", "c", "a"}, ids, "most recently accessed first, then by ID")
+	}
+}
+
+func TestSortSearchResults_ScoreFirst(t *testing.T) {
+	now := time.Now().UTC()
+	results := []models.SearchResult{
+		{Memory: models.Memory{ID: "recent", LastAccessed: now}, Score: 0.80},
+		{Memory: models.Memory{ID: "relevant", LastAccessed: now.Add(-time.Hour)}, Score: 0.90},
+	}
+	store.SortSearchResults(results)
+	assert.Equal(t, "relevant", results[0].Memory.ID)
+}
@@ -69,3 +69,54 @@ func TestValidMemoryScopesContainsAll(t *testing.T) {
 		assert.Contains(t, models.ValidMemoryScopes, e)
 	}
 }
+
+func TestAppendContent(t *testing.T) {
+	assert.Equal(t, "a\nb", models.AppendContent("a", "b"))
+	assert.Equal(t, "a\nb", models.AppendContent(" a\n", "  b "))
+	assert.Equal(t, "a", models.AppendContent("a", "   "))
+	assert.Equal(t, "b", models.AppendContent("", "b"))
+}
+
+func TestMemoryVisibilityIsValid(t *testing.T) {
+	for _, mv := range models.ValidMemoryVisibilities {
+		assert.True(t, mv.IsValid())
+	}
+	assert.False(t, models.MemoryVisibility("public").IsValid())
+}
+
+func TestParseAttachment(t *testing.T) {
+	a, err := models.ParseAttachment("url:https://example.com/rfc#section-2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Attachment{Type: models.AttachmentURL, Ref: "https://example.com/rfc#section-2"}, a,
+		"only the first colon separates the type")
+	assert.Equal(t, "url:https://example.com/rfc#section-2", a.String())
+
+	a, err = models.ParseAttachment(" File: docs/auth.md ")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Attachment{Type: models.AttachmentFile, Ref: "docs/auth.md"}, a)
+
+	for _, bad := range []string{"docs/auth.md", "commit:", "ticket:JIRA-1"} {
+		_, err = models.ParseAttachment(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPreferenceTriple_NamespacedAndLegacyKeys(t *testing.T) {
+	m := models.Memory{Type: models.MemoryTypePreference, Metadata: map[string]any{"subject": "billing", "object": "unrelated"}}
+	_, ok := m.PreferenceTriple()
+	assert.False(t, ok, "incomplete legacy keys are not a triple")
+
+	m.SetPreference(models.Preference{Subject: " Ajit ", Predicate: "likes", Object: "drone piloting"})
+	assert.Equal(t, "Ajit", m.Metadata[models.MetaPreferenceSubject])
+	assert.NotContains(t, m.Metadata, "subject")
+	p, ok := m.PreferenceTriple()
+	assert.True(t, ok)
+	assert.Equal(t, models.Preference{Subject: "Ajit", Predicate: "likes", Object: "drone piloting"}, p)
+
+	legacy := models.Memory{Type: models.MemoryTypePreference, Metadata: map[string]any{
+		"subject": "Ajit", "predicate": "prefers", "object": "tea",
+	}}
+	p, ok = legacy.PreferenceTriple()
+	assert.True(t, ok, "memories stored before namespacing still read")
+	assert.Equal(t, "tea", p.Object)
+}
@@ -93,6 +93,39 @@ func TestPostStoreExtract_Entities(t *testing.T) {
 	}
 }
 
+// TestPostStoreExtract_EmbedsEntities verifies that with an Embedder set,
+// extracted entities are stored with embeddings and become searchable by vector.
+func TestPostStoreExtract_EmbedsEntities(t *testing.T) {
+	t.Parallel()
+
+	entityJSON := `[{"name":"Alice","type":"person","aliases":["Al"],"description":"A developer"}]`
+	llm := &mockSeqLLM{responses: []string{entityJSON, `[]`}}
+	ms := store.NewMockStore()
+	vec := []float32{0, 1, 0, 0}
+
+	res := extract.Run(context.Background(), extract.Deps{
+		LLMClient:   llm,
+		Model:       "test-model",
+		Store:       ms,
+		GraphClient: graph.NewMockGraphClient(),
+		Embedder:    &hookMockEmbedder{vec: vec},
+	}, []extract.StoredMemory{{ID: "mem-1", Content: "Alice works at Acme Corp"}})
+	if res.EntitiesExtracted != 1 {
+		t.Fatalf("expected 1 entity extracted, got %d", res.EntitiesExtracted)
+	}
+
+	results, err := ms.SearchEntitiesByVector(context.Background(), vec, 5)
+	if err != nil {
+		t.Fatalf("search entities by vector: %v", err)
+	}
+	if len(results) != 1 || results[0].Entity.Name != "Alice" {
+		t.Fatalf("expected Alice, got %+v", results)
+	}
+	if results[0].Score < 0.99 {
+		t.Errorf("expected a near-identical score, got %f", results[0].Score)
+	}
+}
+
 // TestPostStoreExtract_Facts verifies that when the LLM returns entities on the
 // first call and facts on the second, facts end up in the graph client.
 func TestPostStoreExtract_Facts(t *testing.T) {
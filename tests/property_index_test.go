@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/memgraph"
+)
+
+// TestParsePropertyIndexRows verifies that only single-property label indexes
+// are reported, whether the property column is a string or a list.
+func TestParsePropertyIndexRows(t *testing.T) {
+	rows := []map[string]any{
+		{"index type": "label+property", "label": "Memory", "property": "type"},
+		{"index type": "label+property", "label": "Memory", "property": []any{"scope"}},
+		{"index type": "label+property", "label": "Memory", "property": []any{"a", "b"}},
+		{"index type": "label", "label": "Memory", "property": nil},
+		{"index type": "label_text", "label": "Entity", "property": nil},
+	}
+	got := memgraph.ParsePropertyIndexRows(rows)
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 property indexes, got %d: %v", len(got), got)
+	}
+	for _, want := range []memgraph.PropertyIndex{{Label: "Memory", Property: "type"}, {Label: "Memory", Property: "scope"}} {
+		if !got[want] {
+			t.Errorf("expected %s to be reported", want)
+		}
+	}
+}
+
+// TestPropertyIndexString verifies the Cypher notation used in messages.
+func TestPropertyIndexString(t *testing.T) {
+	idx := memgraph.PropertyIndex{Label: "Memory", Property: "valid_to"}
+	if idx.String() != ":Memory(valid_to)" {
+		t.Errorf("unexpected String(): %q", idx.String())
+	}
+}
+
+// TestParseIndexCount verifies the count column is read for the matching
+// index only.
+func TestParseIndexCount(t *testing.T) {
+	rows := []map[string]any{
+		{"index type": "label+property", "label": "Entity", "property": "uuid", "count": int64(7)},
+		{"index type": "label+property", "label": "Memory", "property": "uuid", "count": int64(1200)},
+	}
+	n, ok := memgraph.ParseIndexCount(rows, memgraph.PropertyIndex{Label: "Memory", Property: "uuid"})
+	if !ok || n != 1200 {
+		t.Errorf("expected 1200, got %d (ok=%v)", n, ok)
+	}
+	if _, ok := memgraph.ParseIndexCount(rows, memgraph.PropertyIndex{Label: "Memory", Property: "type"}); ok {
+		t.Error("expected no count for a missing index")
+	}
+}
+
+// TestScaleSample verifies sampled counts are extrapolated to the total.
+func TestScaleSample(t *testing.T) {
+	got := memgraph.ScaleSample(map[string]int64{"fact": 3, "rule": 1}, 4, 1000)
+	if got["fact"] != 750 || got["rule"] != 250 {
+		t.Errorf("unexpected scaled counts: %v", got)
+	}
+	if len(memgraph.ScaleSample(map[string]int64{"fact": 1}, 0, 10)) != 0 {
+		t.Error("expected an empty map for an empty sample")
+	}
+}
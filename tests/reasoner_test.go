@@ -121,3 +121,25 @@ func TestReasoner_ReRank_Integration(t *testing.T) {
 	// The deployment-safety memory should be ranked first by Claude.
 	assert.Equal(t, "id-2", results[0].Memory.ID)
 }
+
+func TestSynthesizer_Synthesize(t *testing.T) {
+	syn := recall.NewSynthesizer(&mockLLMClient{Resp: "\nGo uses goroutines.\n"}, "claude-haiku", slog.Default())
+
+	summary, err := syn.Synthesize(context.Background(), "concurrency", []string{"Go uses goroutines"})
+	require.NoError(t, err)
+	assert.Equal(t, "Go uses goroutines.", summary)
+
+	// No memories: no LLM call, empty summary.
+	summary, err = recall.NewSynthesizer(&mockLLMClient{Err: assert.AnError}, "m", slog.Default()).
+		Synthesize(context.Background(), "q", nil)
+	require.NoError(t, err)
+	assert.Empty(t, summary)
+
+	// LLM failure and blank responses are errors so callers can fall back.
+	_, err = recall.NewSynthesizer(&mockLLMClient{Err: assert.AnError}, "m", slog.Default()).
+		Synthesize(context.Background(), "q", []string{"x"})
+	assert.Error(t, err)
+	_, err = recall.NewSynthesizer(&mockLLMClient{Resp: "   "}, "m", slog.Default()).
+		Synthesize(context.Background(), "q", []string{"x"})
+	assert.Error(t, err)
+}
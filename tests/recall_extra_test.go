@@ -1,6 +1,9 @@
 package tests
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,6 +12,8 @@ import (
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+	"github.com/ajitpratap0/openclaw-cortex/pkg/tokenizer"
 )
 
 func TestRecallRecencyScore_ZeroTime(t *testing.T) {
@@ -194,3 +199,114 @@ func TestRecallRankSortOrder(t *testing.T) {
 			"results should be sorted descending by final score")
 	}
 }
+
+func TestRelevanceCap(t *testing.T) {
+	ranked := []models.RecallResult{{FinalScore: 0.9}, {FinalScore: 0.8}, {FinalScore: 0.5}, {FinalScore: 0.45}}
+
+	assert.Equal(t, 0, recall.RelevanceCap(ranked, 0, 0), "disabled leaves no cap")
+	assert.Equal(t, 3, recall.RelevanceCap(ranked, 0, 3), "disabled keeps max_memories")
+	assert.Equal(t, 2, recall.RelevanceCap(ranked, 0.75, 0), "stops at the first score below 0.75 * top")
+	assert.Equal(t, 4, recall.RelevanceCap(ranked, 0.5, 0))
+	assert.Equal(t, 1, recall.RelevanceCap(ranked, 0.75, 1), "max_memories still caps")
+	assert.Equal(t, 1, recall.RelevanceCap(ranked, 1, 0), "the top hit always fits")
+	assert.Equal(t, 5, recall.RelevanceCap(nil, 0.5, 5))
+}
+
+func TestTruncateEach(t *testing.T) {
+	long := strings.Repeat("word ", 200)
+	ranked := []models.RecallResult{
+		{Memory: models.Memory{ID: "long", Content: long}},
+		{Memory: models.Memory{ID: "short", Content: "short memory"}},
+	}
+
+	assert.Equal(t, ranked, recall.TruncateEach(ranked, 0), "disabled leaves contents untouched")
+
+	cut := recall.TruncateEach(ranked, 20)
+	assert.LessOrEqual(t, tokenizer.EstimateTokens(cut[0].Memory.Content), 21)
+	assert.True(t, strings.HasSuffix(cut[0].Memory.Content, "..."))
+	assert.Equal(t, "short memory", cut[1].Memory.Content)
+	assert.Equal(t, long, ranked[0].Memory.Content, "input is not modified")
+
+	// More truncated memories fit in the same budget.
+	_, full := recall.FormatContext(recall.FormatPlain, []models.RecallResult{ranked[0], ranked[0], ranked[0]}, 150, 0, 0)
+	_, truncated := recall.FormatContext(recall.FormatPlain, recall.TruncateEach([]models.RecallResult{ranked[0], ranked[0], ranked[0]}, 20), 150, 0, 0)
+	assert.Greater(t, truncated, full)
+}
+
+func TestWithAttachmentRefs(t *testing.T) {
+	ranked := []models.RecallResult{
+		{Memory: models.Memory{ID: "a", Content: "Auth uses JWT", Attachments: []models.Attachment{
+			{Type: models.AttachmentFile, Ref: "docs/auth.md"},
+			{Type: models.AttachmentCommit, Ref: "3f2a9c1"},
+		}}},
+		{Memory: models.Memory{ID: "b", Content: "no refs"}},
+	}
+
+	out := recall.WithAttachmentRefs(ranked)
+	assert.Equal(t, "Auth uses JWT\nRefs: file:docs/auth.md, commit:3f2a9c1", out[0].Memory.Content)
+	assert.Equal(t, "no refs", out[1].Memory.Content)
+	assert.Equal(t, "Auth uses JWT", ranked[0].Memory.Content, "input is not modified")
+}
+
+func TestContextItems(t *testing.T) {
+	shown := []models.RecallResult{
+		{Memory: models.Memory{ID: "a", Type: models.MemoryTypeRule, Content: "Always run the linter"}, FinalScore: 0.9},
+		{Memory: models.Memory{ID: "b", Type: models.MemoryTypeFact, Content: "CI runs on Linux"}, FinalScore: 0.7},
+		{Memory: models.Memory{ID: "c", Type: models.MemoryTypeFact, Content: "left out by the budget"}, FinalScore: 0.5},
+	}
+
+	items := recall.ContextItems(shown, 2)
+	require.Len(t, items, 2)
+	assert.Equal(t, recall.ContextItem{
+		ID: "a", Type: models.MemoryTypeRule, Content: "Always run the linter", Score: 0.9,
+		Tokens: tokenizer.EstimateTokens("Always run the linter"),
+	}, items[0])
+	assert.Equal(t, "b", items[1].ID)
+
+	assert.Len(t, recall.ContextItems(shown, 10), 3, "count is capped at len(shown)")
+	assert.Empty(t, recall.ContextItems(nil, 0))
+}
+
+func TestTopCandidates(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMockStore()
+	now := time.Now().UTC()
+	for i := range 5 {
+		require.NoError(t, st.Upsert(ctx, models.Memory{
+			ID: fmt.Sprintf("top-%d", i), Type: models.MemoryTypeFact, Content: "memory",
+			CreatedAt: now.Add(time.Duration(i) * time.Minute),
+		}, []float32{0.1, 0.2}))
+	}
+
+	results, err := recall.TopCandidates(ctx, st, nil, 3)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i := range results {
+		assert.Zero(t, results[i].Score, "no similarity component")
+	}
+
+	all, err := recall.TopCandidates(ctx, st, nil, recall.TopCandidateLimit)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+}
+
+func TestSynonyms_Expand(t *testing.T) {
+	syn := recall.Synonyms{"RBAC": "role-based access control", "k8s": "kubernetes"}
+
+	assert.Equal(t, "How is RBAC configured? (role-based access control)", syn.Expand("How is RBAC configured?"))
+	assert.Equal(t, "Where do we set up Role-Based Access Control (RBAC)", syn.Expand("Where do we set up Role-Based Access Control"),
+		"the expansion maps back to the acronym")
+	assert.Equal(t, "rbac and k8s (role-based access control, kubernetes)", syn.Expand("rbac and k8s"))
+	assert.Equal(t, "RBAC means role-based access control", syn.Expand("RBAC means role-based access control"),
+		"nothing to add when both forms are present")
+	assert.Equal(t, "the rbacs table", syn.Expand("the rbacs table"), "matches whole words only")
+	assert.Equal(t, "plain query", recall.Synonyms(nil).Expand("plain query"))
+}
+
+func TestRecaller_ExpandQuery(t *testing.T) {
+	r := recall.NewRecaller(recall.DefaultWeights(), newTestLogger(t))
+	assert.Equal(t, "deploy to k8s", r.ExpandQuery("deploy to k8s"), "off by default")
+
+	r.SetSynonyms(map[string]string{"k8s": "kubernetes"})
+	assert.Equal(t, "deploy to k8s (kubernetes)", r.ExpandQuery("deploy to k8s"))
+}
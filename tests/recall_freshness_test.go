@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+// TestFreshness_Buckets verifies that "recent" and "established" select the
+// expected memories from the mock store and that "any" applies no filter.
+func TestFreshness_Buckets(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+	now := time.Now().UTC()
+	day := 24 * time.Hour
+
+	aged := func(id string, age, sinceAccess time.Duration) models.Memory {
+		m := newTestMemory(id, models.MemoryTypeFact, "content for "+id)
+		m.CreatedAt = now.Add(-age)
+		m.UpdatedAt = m.CreatedAt
+		m.LastAccessed = now.Add(-sinceAccess)
+		return m
+	}
+	vec := testVector(0.5)
+	require.NoError(t, s.Upsert(ctx, aged("fresh", 2*day, 0), vec))
+	require.NoError(t, s.Upsert(ctx, aged("middle", 14*day, 0), vec))
+	require.NoError(t, s.Upsert(ctx, aged("established", 60*day, 3*day), vec))
+	require.NoError(t, s.Upsert(ctx, aged("stale", 60*day, 90*day), vec))
+
+	f := recall.NewFreshness(7, 30, 30)
+	ids := func(bucket string) []string {
+		filters := f.ApplyFilters(bucket, nil, now)
+		results, err := s.Search(ctx, vec, 10, filters)
+		require.NoError(t, err)
+		results = f.FilterResults(bucket, results, now)
+		out := make([]string, 0, len(results))
+		for i := range results {
+			out = append(out, results[i].Memory.ID)
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []string{"fresh"}, ids(recall.FreshnessRecent))
+	assert.ElementsMatch(t, []string{"established"}, ids(recall.FreshnessEstablished))
+	assert.Len(t, ids(recall.FreshnessAny), 4)
+	assert.Len(t, ids(""), 4)
+}
+
+// TestFreshness_ApplyFiltersKeepsTighterBound verifies that an explicit
+// ValidAfter that is already tighter than the bucket is left untouched.
+func TestFreshness_ApplyFiltersKeepsTighterBound(t *testing.T) {
+	now := time.Now().UTC()
+	tight := now.Add(-time.Hour)
+	filters := recall.Freshness{}.ApplyFilters(recall.FreshnessRecent, &store.SearchFilters{ValidAfter: &tight}, now)
+	require.NotNil(t, filters.ValidAfter)
+	assert.True(t, filters.ValidAfter.Equal(tight))
+
+	assert.True(t, recall.ValidFreshness("recent"))
+	assert.False(t, recall.ValidFreshness("ancient"))
+}
@@ -681,6 +681,40 @@ func TestSupersessionOnlyInResultSet(t *testing.T) {
 		"superseding memory should not be penalized when superseded is absent")
 }
 
+func TestCollapseSupersessions(t *testing.T) {
+	now := time.Now().UTC()
+
+	// A supersedes B supersedes C; D is unrelated. B is the most similar hit,
+	// so before collapsing an old version would outrank the current one.
+	memA := baseMemory("A", now)
+	memA.SupersedesID = "B"
+	memB := baseMemory("B", now)
+	memB.SupersedesID = "C"
+	memC := baseMemory("C", now)
+	memD := baseMemory("D", now)
+	results := []models.SearchResult{
+		{Memory: memA, Score: 0.5},
+		{Memory: memB, Score: 0.99},
+		{Memory: memC, Score: 0.6},
+		{Memory: memD, Score: 0.7},
+	}
+
+	r := recall.NewRecaller(recall.DefaultWeights(), newTestLoggerRecall())
+	require.Len(t, r.Rank(results, "", ""), 4, "collapsing is off by default")
+
+	r.SetCollapseSupersessions(true)
+	ranked := r.Rank(results, "", "")
+	ids := make([]string, len(ranked))
+	for i := range ranked {
+		ids[i] = ranked[i].Memory.ID
+	}
+	assert.ElementsMatch(t, []string{"A", "D"}, ids, "only the newest version of the chain survives")
+
+	// A chain missing its middle version cannot be linked, so both ends stay.
+	gapped := recall.CollapseSupersessions([]models.RecallResult{{Memory: memA}, {Memory: memC}})
+	assert.Len(t, gapped, 2)
+}
+
 func TestConflictPenalty(t *testing.T) {
 	r := recall.NewRecaller(recall.DefaultWeights(), newTestLoggerRecall())
 	now := time.Now().UTC()
@@ -716,6 +750,29 @@ func TestConflictPenalty(t *testing.T) {
 		"resolved conflict should have 1.0 penalty (no penalty)")
 }
 
+func TestManualBoost(t *testing.T) {
+	r := recall.NewRecaller(recall.DefaultWeights(), newTestLoggerRecall())
+	now := time.Now().UTC()
+
+	plain := baseMemory("plain", now)
+	pinned := baseMemory("pinned", now)
+	pinned.Boost = 1.5
+
+	// The pinned memory is a little less similar but its boost lifts it.
+	ranked := r.Rank([]models.SearchResult{
+		{Memory: plain, Score: 0.9},
+		{Memory: pinned, Score: 0.8},
+	}, "", "")
+	require.Len(t, ranked, 2)
+
+	assert.Equal(t, "pinned", ranked[0].Memory.ID)
+	assert.InDelta(t, 1.5, ranked[0].ManualBoost, 0.001)
+	assert.InDelta(t, models.DefaultBoost, ranked[1].ManualBoost, 0.001, "unset boost ranks as 1.0")
+
+	unboosted := r.Rank([]models.SearchResult{{Memory: baseMemory("pinned", now), Score: 0.8}}, "", "")
+	assert.InDelta(t, unboosted[0].FinalScore*1.5, ranked[0].FinalScore, 0.0001, "boost multiplies the final score")
+}
+
 func TestPenaltiesStack(t *testing.T) {
 	r := recall.NewRecaller(recall.DefaultWeights(), newTestLoggerRecall())
 	now := time.Now().UTC()
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/ajitpratap0/openclaw-cortex/internal/models"
 	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
 )
 
 func TestRecaller_Rank(t *testing.T) {
@@ -139,6 +141,77 @@ func TestRecaller_RecencyDecay(t *testing.T) {
 	}
 }
 
+func TestRecaller_WriteRecencyBoost(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	now := time.Now().UTC()
+	accessed := now.Add(-48 * time.Hour)
+	results := []models.SearchResult{
+		{Memory: models.Memory{ID: "old", Type: models.MemoryTypeFact, CreatedAt: now.Add(-30 * 24 * time.Hour), LastAccessed: accessed}, Score: 0.8},
+		{Memory: models.Memory{ID: "new", Type: models.MemoryTypeFact, CreatedAt: now.Add(-time.Hour), LastAccessed: accessed}, Score: 0.78},
+	}
+
+	r := recall.NewRecaller(recall.DefaultWeights(), logger)
+	ranked := r.Rank(results, "", "")
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "old", ranked[0].Memory.ID, "without the boost similarity decides")
+	assert.Zero(t, ranked[1].WriteRecencyBoost)
+
+	r.SetWriteRecencyBoost(0.1)
+	ranked = r.Rank(results, "", "")
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "new", ranked[0].Memory.ID, "a just-written memory gets a temporary bump")
+	assert.InDelta(t, 0.1, ranked[0].WriteRecencyBoost, 0.01)
+	assert.Less(t, ranked[1].WriteRecencyBoost, 0.0001, "the bonus decays away over days")
+}
+
+func TestRecaller_QueryTypeBoost(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	now := time.Now().UTC()
+	results := []models.SearchResult{
+		{Memory: models.Memory{ID: "rule", Type: models.MemoryTypeRule, CreatedAt: now, LastAccessed: now}, Score: 0.8},
+		{Memory: models.Memory{ID: "proc", Type: models.MemoryTypeProcedure, CreatedAt: now, LastAccessed: now}, Score: 0.8},
+	}
+
+	r := recall.NewRecaller(recall.DefaultWeights(), logger)
+	ranked := r.Rank(results, "", "how do I deploy the api?")
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "rule", ranked[0].Memory.ID, "without the boost the global type priority decides")
+
+	r.SetQueryTypeBoost(0.1)
+	ranked = r.Rank(results, "", "how do I deploy the api?")
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "proc", ranked[0].Memory.ID, "a how-to query favours procedures")
+	assert.InDelta(t, 0.1, ranked[0].QueryTypeBoost, 1e-9)
+	assert.Zero(t, ranked[1].QueryTypeBoost)
+
+	ranked = r.Rank(results, "", "deploy api")
+	assert.Equal(t, "rule", ranked[0].Memory.ID, "no detected intent, no bonus")
+}
+
+func TestDetectQueryType(t *testing.T) {
+	tests := []struct {
+		query string
+		want  models.MemoryType
+		ok    bool
+	}{
+		{"How do I rotate the API keys?", models.MemoryTypeProcedure, true},
+		{"what are the steps to cut a release", models.MemoryTypeProcedure, true},
+		{"Am I allowed to push to main?", models.MemoryTypeRule, true},
+		{"which editor do I prefer", models.MemoryTypePreference, true},
+		{"what happened with the outage last week", models.MemoryTypeEpisode, true},
+		{"What is the staging database host?", models.MemoryTypeFact, true},
+		{"showhow toggles", "", false},
+		{"database migrations", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, ok := recall.DetectQueryType(tt.query)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestRecaller_TypePriority(t *testing.T) {
 	tests := []struct {
 		memType  models.MemoryType
@@ -176,3 +249,99 @@ func TestFormatWithConflictAnnotations(t *testing.T) {
 	require.GreaterOrEqual(t, len(lines), 3)
 	assert.NotContains(t, lines[2], "[conflicts with:")
 }
+
+func TestResolveConflicts(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+	ranked := []models.RecallResult{
+		{Memory: models.Memory{ID: "aaa00000-old", Content: "Python is fast", CreatedAt: older, ConflictGroupID: "g1", ConflictStatus: models.ConflictStatusActive}},
+		{Memory: models.Memory{ID: "bbb00000-new", Content: "Python is slow", CreatedAt: newer, ConflictGroupID: "g1", ConflictStatus: models.ConflictStatusActive}},
+		{Memory: models.Memory{ID: "ccc00000-solo", Content: "Go is great", ConflictGroupID: "g2", ConflictStatus: models.ConflictStatusActive}},
+	}
+
+	kept, conflicts := recall.ResolveConflicts(ranked, recall.ConflictBoth)
+	assert.Len(t, kept, 3, "both keeps every memory")
+	require.Len(t, conflicts, 1, "a group with one member in the results is not a conflict")
+	assert.Equal(t, recall.Conflict{GroupID: "g1", IDs: []string{"aaa00000-old", "bbb00000-new"}, Kept: "bbb00000-new"}, conflicts[0])
+	marked := recall.MarkConflicts(kept, conflicts, recall.ConflictBoth)
+	assert.Equal(t, "Python is fast [conflicts with: bbb00000]", marked[0].Memory.Content)
+	assert.Equal(t, "Python is slow [conflicts with: aaa00000]", marked[1].Memory.Content)
+	assert.Equal(t, "Go is great", marked[2].Memory.Content)
+	assert.Equal(t, "Python is fast", ranked[0].Memory.Content, "input is not modified")
+
+	kept, conflicts = recall.ResolveConflicts(ranked, recall.ConflictNewest)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "bbb00000-new", kept[0].Memory.ID)
+	assert.Equal(t, "Python is slow", recall.MarkConflicts(kept, conflicts, recall.ConflictNewest)[0].Memory.Content)
+
+	kept, conflicts = recall.ResolveConflicts(ranked, recall.ConflictFlag)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "Python is slow [disputed: 2 competing memories, newest shown]",
+		recall.MarkConflicts(kept, conflicts, recall.ConflictFlag)[0].Memory.Content)
+
+	assert.Len(t, recall.ConflictsIn(conflicts, kept[1:]), 0, "conflict outside the context is not reported")
+	assert.Len(t, recall.ConflictsIn(conflicts, kept[:1]), 1)
+}
+
+func TestResolveConflicts_CollapsesSupersededVersions(t *testing.T) {
+	ranked := []models.RecallResult{
+		{Memory: models.Memory{ID: "v2", Content: "Deploys run on Thursdays", SupersedesID: "v1"}},
+		{Memory: models.Memory{ID: "v1", Content: "Deploys run on Fridays"}},
+	}
+	kept, _ := recall.ResolveConflicts(ranked, recall.ConflictNewest)
+	assert.Equal(t, []string{"v2"}, resultIDs(kept))
+	kept, _ = recall.ResolveConflicts(ranked, recall.ConflictBoth)
+	assert.Len(t, kept, 2, "both keeps superseded versions")
+}
+
+// stubContradictionChecker reports a contradiction between content and
+// target when target is among the candidates.
+type stubContradictionChecker struct {
+	content, target string
+	calls           int
+}
+
+func (c *stubContradictionChecker) Detect(_ context.Context, content string, candidates []models.Memory) (bool, string, string, error) {
+	c.calls++
+	if content != c.content {
+		return false, "", "", nil
+	}
+	for i := range candidates {
+		if candidates[i].ID == c.target {
+			return true, c.target, "different day", nil
+		}
+	}
+	return false, "", "", nil
+}
+
+func TestDetectConflicts(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMockStore()
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := func(id, content string, created time.Time, vec []float32) models.RecallResult {
+		m := models.Memory{ID: id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent, Content: content, CreatedAt: created}
+		require.NoError(t, st.Upsert(ctx, m, vec))
+		return models.RecallResult{Memory: m}
+	}
+	ranked := []models.RecallResult{
+		seed("fri", "Deploys run on Fridays", older, []float32{1, 0, 0}),
+		seed("thu", "Deploys run on Thursdays", older.Add(time.Hour), []float32{0.95, 0.1, 0}),
+		seed("go", "The service is written in Go", older, []float32{0, 0, 1}),
+	}
+	checker := &stubContradictionChecker{content: "Deploys run on Thursdays", target: "fri"}
+
+	got := recall.DetectConflicts(ctx, checker, st, ranked, slog.Default())
+	assert.Equal(t, 1, checker.calls, "only the similar pair is sent to the checker")
+	assert.Empty(t, ranked[0].Memory.ConflictGroupID, "input is not modified")
+
+	kept, conflicts := recall.ResolveConflicts(got, recall.ConflictNewest)
+	assert.Equal(t, []string{"thu", "go"}, resultIDs(kept))
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, []string{"fri", "thu"}, conflicts[0].IDs)
+
+	checker = &stubContradictionChecker{}
+	got = recall.DetectConflicts(ctx, checker, st, ranked, slog.Default())
+	assert.Equal(t, resultIDs(ranked), resultIDs(got))
+	_, conflicts = recall.ResolveConflicts(got, recall.ConflictBoth)
+	assert.Empty(t, conflicts)
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+)
+
+// tuneLog builds a feedback log in which the useful memory always has the
+// higher recency but the lower similarity, so the default weights rank it
+// second.
+func tuneLog(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		id := string(rune('a' + i))
+		b.WriteString(`{"event":"recall","recall_id":"` + id + `","results":[` +
+			`{"id":"noise","penalty":1,"signals":{"similarity":0.95,"recency":0.0}},` +
+			`{"id":"good","penalty":1,"signals":{"similarity":0.7,"recency":0.9}}]}` + "\n")
+		b.WriteString(`{"event":"feedback","recall_id":"` + id + `","useful":["good"],"not_useful":["noise"]}` + "\n")
+	}
+	// A recall without feedback carries no signal and is skipped.
+	b.WriteString(`{"event":"recall","recall_id":"orphan","results":[{"id":"x","penalty":1}]}` + "\n")
+	return b.String()
+}
+
+func TestLoadTuneExamples(t *testing.T) {
+	examples, err := recall.LoadTuneExamples(strings.NewReader(tuneLog(3)))
+	require.NoError(t, err)
+	require.Len(t, examples, 3)
+	assert.True(t, examples[0].Useful["good"])
+	assert.False(t, examples[0].Useful["noise"])
+
+	_, err = recall.LoadTuneExamples(strings.NewReader("not json\n"))
+	assert.Error(t, err)
+}
+
+func TestTune_ImprovesRanking(t *testing.T) {
+	examples, err := recall.LoadTuneExamples(strings.NewReader(tuneLog(4)))
+	require.NoError(t, err)
+
+	res := recall.Tune(recall.DefaultWeights(), examples, 1)
+	assert.Equal(t, 4, res.Examples)
+	assert.Equal(t, 0.0, res.Baseline.HitRate, "default weights favour similarity")
+	assert.Equal(t, 1.0, res.Best.HitRate)
+	assert.Greater(t, res.Weights.Recency, recall.DefaultWeights().Recency)
+	require.NoError(t, res.Weights.Validate(), "tuned weights must still sum to 1")
+	assert.Equal(t, res.Best, recall.Evaluate(res.Weights, examples, 1))
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/recall"
+)
+
+func filterInput() []models.RecallResult {
+	return []models.RecallResult{
+		newTestRecallResult("a", "deploys run on Fridays", 0.9),
+		newTestRecallResult("b", "the deploy team sits on floor 3", 0.8),
+		newTestRecallResult("c", "deploy with kubectl apply", 0.7),
+	}
+}
+
+func resultIDs(results []models.RecallResult) []string {
+	ids := make([]string, len(results))
+	for i := range results {
+		ids[i] = results[i].Memory.ID
+	}
+	return ids
+}
+
+// TestRelevanceFilter_DropsIrrelevant verifies that only the indices Claude
+// judges relevant survive, in their original order.
+func TestRelevanceFilter_DropsIrrelevant(t *testing.T) {
+	f := recall.NewRelevanceFilter(&mockLLMClient{Resp: "[2, 0]"}, "test-model", slog.Default())
+	got := f.Filter(context.Background(), "how do I deploy?", filterInput(), 10)
+	assert.Equal(t, []string{"a", "c"}, resultIDs(got))
+}
+
+// TestRelevanceFilter_NoneRelevant verifies that an empty verdict drops every
+// result, including those beyond the first batch of maxCandidates.
+func TestRelevanceFilter_NoneRelevant(t *testing.T) {
+	f := recall.NewRelevanceFilter(&mockLLMClient{Resp: "[]"}, "test-model", slog.Default())
+	got := f.Filter(context.Background(), "how do I deploy?", filterInput(), 2)
+	assert.Empty(t, got)
+}
+
+// TestRelevanceFilter_JudgesTailInBatches verifies that results beyond
+// maxCandidates are judged in further batches, with indices relative to each
+// batch, and that code-fenced verdicts are accepted.
+func TestRelevanceFilter_JudgesTailInBatches(t *testing.T) {
+	f := recall.NewRelevanceFilter(&mockLLMClient{Resp: "```json\n[0]\n```"}, "test-model", slog.Default())
+	got := f.Filter(context.Background(), "how do I deploy?", filterInput(), 2)
+	assert.Equal(t, []string{"a", "c"}, resultIDs(got))
+}
+
+// TestRelevanceFilter_FencedResponse verifies that a verdict wrapped in a
+// markdown code fence is parsed rather than treated as an error.
+func TestRelevanceFilter_FencedResponse(t *testing.T) {
+	f := recall.NewRelevanceFilter(&mockLLMClient{Resp: "```\n[1]\n```"}, "test-model", slog.Default())
+	got := f.Filter(context.Background(), "how do I deploy?", filterInput(), 10)
+	assert.Equal(t, []string{"b"}, resultIDs(got))
+}
+
+// TestRelevanceFilter_DegradesGracefully verifies that API errors and
+// unparseable responses keep every result, and that an empty query skips the
+// filter.
+func TestRelevanceFilter_DegradesGracefully(t *testing.T) {
+	for name, mock := range map[string]*mockLLMClient{
+		"api error":    {Err: errors.New("upstream unavailable")},
+		"invalid json": {Resp: "the first two"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := recall.NewRelevanceFilter(mock, "test-model", slog.Default())
+			got := f.Filter(context.Background(), "how do I deploy?", filterInput(), 10)
+			assert.Equal(t, []string{"a", "b", "c"}, resultIDs(got))
+		})
+	}
+
+	f := recall.NewRelevanceFilter(&mockLLMClient{Resp: "[]"}, "test-model", slog.Default())
+	require.Len(t, f.Filter(context.Background(), "", filterInput(), 10), 3)
+}
+
+// countingLLMClient answers every call with resp and tracks the number of
+// calls and the peak number in flight.
+type countingLLMClient struct {
+	resp     string
+	mu       sync.Mutex
+	calls    int
+	inFlight int
+	peak     int
+}
+
+func (c *countingLLMClient) Complete(_ context.Context, _, _, _ string, _ int) (string, error) {
+	c.mu.Lock()
+	c.calls++
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	c.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return c.resp, nil
+}
+
+// TestRelevanceFilter_JudgesOnlyTopResults verifies that only the configured
+// number of top results is judged, the tail passes through unchanged, and the
+// Claude calls are capped in concurrency.
+func TestRelevanceFilter_JudgesOnlyTopResults(t *testing.T) {
+	results := make([]models.RecallResult, 100)
+	for i := range results {
+		results[i] = newTestRecallResult(fmt.Sprintf("m%02d", i), fmt.Sprintf("deploy note %d", i), 1-float64(i)/100)
+	}
+	client := &countingLLMClient{resp: "[]"}
+	f := recall.NewRelevanceFilter(client, "test-model", slog.Default())
+	f.SetJudged(60)
+
+	got := f.Filter(context.Background(), "how do I deploy?", results, 10)
+	assert.Equal(t, resultIDs(results[60:]), resultIDs(got), "judged results are dropped, the tail is kept")
+	assert.Equal(t, 6, client.calls)
+	assert.LessOrEqual(t, client.peak, 4)
+
+	client = &countingLLMClient{resp: "[]"}
+	f = recall.NewRelevanceFilter(client, "test-model", slog.Default())
+	assert.Len(t, f.Filter(context.Background(), "how do I deploy?", results, 10), 100-recall.DefaultFilterJudged)
+	assert.Equal(t, recall.DefaultFilterJudged/10, client.calls)
+}
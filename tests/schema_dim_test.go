@@ -10,7 +10,7 @@ import (
 // TestBuildVectorIndexDDL_UsesProvidedDimension verifies that the DDL generated
 // by EnsureSchema injects the configured vector dimension, not a hardcoded 768.
 func TestBuildVectorIndexDDL_UsesProvidedDimension(t *testing.T) {
-	ddl := memgraph.BuildMemoryVectorIndexDDL(1024)
+	ddl := memgraph.BuildMemoryVectorIndexDDL(1024, memgraph.MetricCosine)
 	if !strings.Contains(ddl, `"dimension": 1024`) {
 		t.Errorf("expected dimension 1024 in memory DDL, got: %s", ddl)
 	}
@@ -22,7 +22,7 @@ func TestBuildVectorIndexDDL_UsesProvidedDimension(t *testing.T) {
 // TestBuildEntityVectorIndexDDL_UsesProvidedDimension verifies that the entity
 // DDL helper also injects the configured dimension.
 func TestBuildEntityVectorIndexDDL_UsesProvidedDimension(t *testing.T) {
-	ddl := memgraph.BuildEntityVectorIndexDDL(1024)
+	ddl := memgraph.BuildEntityVectorIndexDDL(1024, memgraph.MetricCosine)
 	if !strings.Contains(ddl, `"dimension": 1024`) {
 		t.Errorf("expected dimension 1024 in entity DDL, got: %s", ddl)
 	}
@@ -30,3 +30,81 @@ func TestBuildEntityVectorIndexDDL_UsesProvidedDimension(t *testing.T) {
 		t.Error("entity DDL still contains hardcoded 768")
 	}
 }
+
+// TestBuildVectorIndexDDL_UsesMetric verifies that each configured metric maps
+// to the Memgraph metric name in both index DDLs.
+func TestBuildVectorIndexDDL_UsesMetric(t *testing.T) {
+	for metric, want := range map[memgraph.VectorMetric]string{
+		memgraph.MetricCosine: `"metric": "cos"`,
+		memgraph.MetricDot:    `"metric": "ip"`,
+		memgraph.MetricEuclid: `"metric": "l2sq"`,
+		"":                    `"metric": "cos"`,
+	} {
+		for _, ddl := range []string{memgraph.BuildMemoryVectorIndexDDL(768, metric), memgraph.BuildEntityVectorIndexDDL(768, metric)} {
+			if !strings.Contains(ddl, want) {
+				t.Errorf("metric %q: expected %s in DDL, got: %s", metric, want, ddl)
+			}
+		}
+	}
+}
+
+// TestBuildVectorScoreClause verifies cosine keeps the index similarity and the
+// other metrics convert the distance to the cosine scale.
+func TestBuildVectorScoreClause(t *testing.T) {
+	if got := memgraph.BuildVectorScoreClause(memgraph.MetricCosine); !strings.Contains(got, "similarity AS score") {
+		t.Errorf("cosine clause: %s", got)
+	}
+	if got := memgraph.BuildVectorScoreClause(memgraph.MetricDot); !strings.Contains(got, "1.0 - distance AS score") {
+		t.Errorf("dot clause: %s", got)
+	}
+	if got := memgraph.BuildVectorScoreClause(memgraph.MetricEuclid); !strings.Contains(got, "1.0 - distance / 2.0 AS score") {
+		t.Errorf("euclid clause: %s", got)
+	}
+}
+
+// TestCheckVectorIndexMetric verifies that an index built with another metric
+// is reported, and that a matching or unreported metric is accepted.
+func TestCheckVectorIndexMetric(t *testing.T) {
+	if err := memgraph.CheckVectorIndexMetric("memory_embedding", "cos", memgraph.MetricCosine); err != nil {
+		t.Errorf("matching metric: %v", err)
+	}
+	if err := memgraph.CheckVectorIndexMetric("memory_embedding", "", memgraph.MetricDot); err != nil {
+		t.Errorf("unreported metric: %v", err)
+	}
+	err := memgraph.CheckVectorIndexMetric("memory_embedding", "cos", memgraph.MetricDot)
+	if err == nil || !strings.Contains(err.Error(), "DROP VECTOR INDEX memory_embedding") {
+		t.Errorf("expected a mismatch error naming the fix, got %v", err)
+	}
+
+	metrics := memgraph.ParseVectorIndexMetrics([]map[string]any{
+		{"index_name": "memory_embedding", "property_name": "embedding", "metric": "ip"},
+		{"index_name": "entity_name_embedding", "property_name": "name_embedding"},
+	})
+	if len(metrics) != 1 || metrics["memory_embedding"] != "ip" {
+		t.Errorf("unexpected parsed metrics: %v", metrics)
+	}
+}
+
+// TestCheckVectorIndexDimension verifies that an index built for another
+// dimension is reported with the reembed fix, and that a matching or
+// unreported dimension is accepted.
+func TestCheckVectorIndexDimension(t *testing.T) {
+	if err := memgraph.CheckVectorIndexDimension("memory_embedding", 768, 768); err != nil {
+		t.Errorf("matching dimension: %v", err)
+	}
+	if err := memgraph.CheckVectorIndexDimension("memory_embedding", 0, 1024); err != nil {
+		t.Errorf("unreported dimension: %v", err)
+	}
+	err := memgraph.CheckVectorIndexDimension("memory_embedding", 768, 1024)
+	if err == nil || !strings.Contains(err.Error(), "reembed --recreate-index") {
+		t.Errorf("expected a mismatch error naming the fix, got %v", err)
+	}
+
+	dims := memgraph.ParseVectorIndexDimensions([]map[string]any{
+		{"index_name": "memory_embedding", "property_name": "embedding", "dimension": int64(768)},
+		{"index_name": "entity_name_embedding", "property_name": "name_embedding"},
+	})
+	if len(dims) != 1 || dims["memory_embedding"] != 768 {
+		t.Errorf("unexpected parsed dimensions: %v", dims)
+	}
+}
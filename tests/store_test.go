@@ -257,6 +257,26 @@ func TestMockStore_UpdateAccessMetadata(t *testing.T) {
 	assert.Equal(t, int64(2), got.AccessCount)
 }
 
+func TestMockStore_UpdateAccessMetadataBatch(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	_ = s.Upsert(ctx, newTestMemory("batch-1", models.MemoryTypeFact, "first"), testVector(0.1))
+	_ = s.Upsert(ctx, newTestMemory("batch-2", models.MemoryTypeFact, "second"), testVector(0.2))
+	_ = s.Upsert(ctx, newTestMemory("batch-3", models.MemoryTypeFact, "third"), testVector(0.3))
+
+	// Unknown IDs are skipped rather than failing the whole batch.
+	require.NoError(t, s.UpdateAccessMetadataBatch(ctx, []string{"batch-1", "batch-2", "missing"}))
+	require.NoError(t, s.UpdateAccessMetadataBatch(ctx, nil))
+
+	got1, _ := s.Get(ctx, "batch-1")
+	got2, _ := s.Get(ctx, "batch-2")
+	got3, _ := s.Get(ctx, "batch-3")
+	assert.Equal(t, int64(1), got1.AccessCount)
+	assert.Equal(t, int64(1), got2.AccessCount)
+	assert.Equal(t, int64(0), got3.AccessCount)
+}
+
 func TestMockStore_Stats(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewMockStore()
@@ -271,3 +291,40 @@ func TestMockStore_Stats(t *testing.T) {
 	assert.Equal(t, int64(2), stats.ByType["fact"])
 	assert.Equal(t, int64(1), stats.ByType["rule"])
 }
+
+func TestMockStore_TrashAndRestore(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMockStore()
+
+	require.NoError(t, s.Upsert(ctx, newTestMemory("trash-me-1", models.MemoryTypeFact, "trash me"), testVector(0.1)))
+	require.NoError(t, s.Upsert(ctx, newTestMemory("keep-me-1", models.MemoryTypeFact, "keep me"), testVector(0.2)))
+
+	fullID, err := s.Trash(ctx, "trash-me")
+	require.NoError(t, err)
+	assert.Equal(t, "trash-me-1", fullID)
+
+	// Trashed memories are invisible to normal reads.
+	_, err = s.Get(ctx, "trash-me-1")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	listed, _, err := s.List(ctx, nil, 10, "")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "keep-me-1", listed[0].ID)
+
+	trashed, err := s.ListTrashed(ctx, time.Now().UTC().Add(time.Second), 0)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.NotNil(t, trashed[0].DeletedAt)
+
+	// Trashing twice fails; restoring brings the memory back.
+	_, err = s.Trash(ctx, "trash-me-1")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, err = s.Restore(ctx, "trash-me-1")
+	require.NoError(t, err)
+	got, err := s.Get(ctx, "trash-me-1")
+	require.NoError(t, err)
+	assert.Nil(t, got.DeletedAt)
+
+	_, err = s.Restore(ctx, "keep-me-1")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -9,6 +9,7 @@ package tests
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -310,3 +311,50 @@ func TestConfig_Validate_DedupThresholdHookZero(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "dedup_threshold_hook")
 }
+
+// TestFindDuplicatesInScope verifies that a dedup scope restricts the
+// comparison set by type and creation window.
+func TestFindDuplicatesInScope(t *testing.T) {
+	ctx := context.Background()
+	st := store.NewMockStore()
+	vec := validationTestVec(0.8)
+
+	old := time.Now().UTC().Add(-30 * 24 * time.Hour)
+	require.NoError(t, st.Upsert(ctx, models.Memory{
+		ID: "old-fact", Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+		Visibility: models.VisibilityShared, Content: "old", CreatedAt: old, UpdatedAt: old,
+	}, vec))
+
+	// Unscoped: the month-old fact is a duplicate.
+	dupes, err := store.FindDuplicatesInScope(ctx, st, vec, 0.92, models.MemoryTypeFact, store.DedupScope{})
+	require.NoError(t, err)
+	assert.Len(t, dupes, 1)
+
+	// A 7-day window excludes it.
+	week := store.DedupScope{Window: 7 * 24 * time.Hour}
+	dupes, err = store.FindDuplicatesInScope(ctx, st, vec, 0.92, models.MemoryTypeFact, week)
+	require.NoError(t, err)
+	assert.Empty(t, dupes)
+
+	// Same-type scope excludes it for a different type.
+	sameType := store.DedupScope{SameType: true}
+	dupes, err = store.FindDuplicatesInScope(ctx, st, vec, 0.92, models.MemoryTypeRule, sameType)
+	require.NoError(t, err)
+	assert.Empty(t, dupes)
+	dupes, err = store.FindDuplicatesInScope(ctx, st, vec, 0.92, models.MemoryTypeFact, sameType)
+	require.NoError(t, err)
+	assert.Len(t, dupes, 1)
+}
+
+// TestValidateMaxContentLength verifies the upper bound applied to memories
+// stored from a whole file.
+func TestValidateMaxContentLength(t *testing.T) {
+	require.NoError(t, store.ValidateMaxContentLength(strings.Repeat("a", store.MaxContentLen)))
+	require.NoError(t, store.ValidateMaxContentLength("  "+strings.Repeat("a", store.MaxContentLen)+"\n"),
+		"surrounding whitespace is not counted")
+
+	err := store.ValidateMaxContentLength(strings.Repeat("一", store.MaxContentLen+1))
+	var e *store.ErrContentTooLong
+	require.True(t, errors.As(err, &e), "error should be *store.ErrContentTooLong")
+	assert.Equal(t, store.MaxContentLen+1, e.Actual)
+}
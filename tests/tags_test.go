@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	synonyms := map[string]string{"golang": "go", "JS": "javascript"}
+
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"nil input", nil, nil},
+		{"lowercase and trim", []string{" Go ", "Testing"}, []string{"go", "testing"}},
+		{"synonyms converge", []string{"Go", "go", "golang"}, []string{"go"}},
+		{"synonym keys are case-insensitive", []string{"js"}, []string{"javascript"}},
+		{"empty tags dropped", []string{"", "  ", "db"}, []string{"db"}},
+		{"all empty returns nil", []string{" ", ""}, nil},
+		{"order preserved", []string{"b", "a", "B"}, []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, store.NormalizeTags(tt.in, synonyms))
+		})
+	}
+}
+
+func TestNormalizeTags_NilSynonyms(t *testing.T) {
+	assert.Equal(t, []string{"golang"}, store.NormalizeTags([]string{"GoLang"}, nil))
+}
+
+func TestTagNormalizer_Apply(t *testing.T) {
+	n := store.NewTagNormalizer(map[string]string{"golang": "go"})
+	m := models.Memory{Tags: []string{"GoLang", "CI"}}
+	n.Apply(&m)
+	assert.Equal(t, []string{"go", "ci"}, m.Tags)
+	assert.Equal(t, []string{"GoLang", "CI"}, m.Metadata["raw_tags"])
+
+	// Already-normalized tags leave metadata untouched; a nil normalizer is a no-op.
+	clean := models.Memory{Tags: []string{"go"}}
+	n.Apply(&clean)
+	assert.Nil(t, clean.Metadata)
+	var none *store.TagNormalizer
+	raw := models.Memory{Tags: []string{"GoLang"}}
+	none.Apply(&raw)
+	assert.Equal(t, []string{"GoLang"}, raw.Tags)
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/models"
+	"github.com/ajitpratap0/openclaw-cortex/internal/store"
+)
+
+func seedDatedMemories(t *testing.T, st *store.MockStore) time.Time {
+	t.Helper()
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, m := range []struct {
+		id       string
+		created  time.Time
+		accessed time.Time
+	}{
+		{"old", base.AddDate(0, 0, -30), base.AddDate(0, 0, -1)},
+		{"last-week", base.AddDate(0, 0, -5), base.AddDate(0, 0, -5)},
+		{"today", base, base},
+	} {
+		mem := models.Memory{
+			ID:           m.id,
+			Type:         models.MemoryTypeFact,
+			Scope:        models.ScopePermanent,
+			Visibility:   models.VisibilityShared,
+			Content:      m.id,
+			CreatedAt:    m.created,
+			UpdatedAt:    m.created,
+			LastAccessed: m.accessed,
+		}
+		require.NoError(t, st.Upsert(context.Background(), mem, make([]float32, 768)))
+	}
+	return base
+}
+
+func listIDs(t *testing.T, st store.Store, f *store.SearchFilters) []string {
+	t.Helper()
+	mems, _, err := st.List(context.Background(), f, 100, "")
+	require.NoError(t, err)
+	ids := make([]string, 0, len(mems))
+	for i := range mems {
+		ids = append(ids, mems[i].ID)
+	}
+	return ids
+}
+
+func TestMockStore_CreatedAndAccessedRange(t *testing.T) {
+	st := store.NewMockStore()
+	base := seedDatedMemories(t, st)
+
+	weekAgo := base.AddDate(0, 0, -7)
+	yesterday := base.AddDate(0, 0, -1)
+
+	assert.ElementsMatch(t, []string{"last-week", "today"},
+		listIDs(t, st, &store.SearchFilters{CreatedAfter: &weekAgo}))
+	assert.ElementsMatch(t, []string{"old", "last-week"},
+		listIDs(t, st, &store.SearchFilters{CreatedBefore: &yesterday}))
+	assert.ElementsMatch(t, []string{"last-week"},
+		listIDs(t, st, &store.SearchFilters{CreatedAfter: &weekAgo, CreatedBefore: &yesterday}))
+	assert.ElementsMatch(t, []string{"old", "today"},
+		listIDs(t, st, &store.SearchFilters{AccessedAfter: &yesterday}), "bounds are inclusive")
+}
+
+func TestAPI_ListMemories_TimeWindow(t *testing.T) {
+	ts, st := newTestServer(t, "")
+	seedDatedMemories(t, st)
+
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/memories?created_after=2026-03-03T00:00:00Z&created_before=2026-03-09T00:00:00Z", nil, "")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out struct {
+		Memories []models.Memory `json:"memories"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.Len(t, out.Memories, 1)
+	assert.Equal(t, "last-week", out.Memories[0].ID)
+
+	bad := doRequest(t, http.MethodGet, ts.URL+"/v1/memories?accessed_after=last-tuesday", nil, "")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
+func TestMockStore_ExpiresBefore(t *testing.T) {
+	st := store.NewMockStore()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, m := range []struct {
+		id      string
+		expires time.Time
+	}{
+		{"expired", now.Add(-time.Hour)},
+		{"future", now.Add(time.Hour)},
+		{"never", time.Time{}},
+	} {
+		mem := models.Memory{
+			ID: m.id, Type: models.MemoryTypeFact, Scope: models.ScopePermanent,
+			Visibility: models.VisibilityShared, Content: m.id,
+			CreatedAt: now, UpdatedAt: now, ExpiresAt: m.expires,
+		}
+		require.NoError(t, st.Upsert(context.Background(), mem, make([]float32, 768)))
+	}
+
+	assert.Equal(t, []string{"expired"}, listIDs(t, st, &store.SearchFilters{ExpiresBefore: &now}))
+}
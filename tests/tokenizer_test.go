@@ -142,6 +142,22 @@ func TestFormatMemoriesWithBudget(t *testing.T) {
 		// Allow a small overage for the separator tokens that are counted separately.
 		assert.LessOrEqual(t, tokenizer.EstimateTokens(result), budget+10)
 	})
+
+	t.Run("hard guard — output never exceeds budget", func(t *testing.T) {
+		// Short word-heavy memories make the per-memory estimates undercount
+		// the joined output; the post-formatting check must trim the tail.
+		short := make([]string, 0, 50)
+		for i := 0; i < 50; i++ {
+			short = append(short, "a b c d e f g")
+		}
+		for budget := 1; budget <= 200; budget++ {
+			result, count := tokenizer.FormatMemoriesWithBudget(short, budget)
+			assert.LessOrEqual(t, tokenizer.EstimateTokens(result), budget, "budget=%d", budget)
+			if count == 0 {
+				assert.Equal(t, "", result)
+			}
+		}
+	})
 }
 
 func TestTruncateToTokenBudgetSmallBudget(t *testing.T) {
@@ -153,3 +169,91 @@ func TestTruncateToTokenBudgetSmallBudget(t *testing.T) {
 		assert.LessOrEqual(t, tokenizer.EstimateTokens(result), budget*3)
 	})
 }
+
+func TestFormatMemoriesWithMinimum(t *testing.T) {
+	memories := []string{
+		strings.Repeat("alpha ", 40),
+		strings.Repeat("bravo ", 40),
+		strings.Repeat("charlie ", 40),
+	}
+
+	t.Run("zero minimum matches strict budget", func(t *testing.T) {
+		strict, strictCount := tokenizer.FormatMemoriesWithBudget(memories, 10)
+		result, count := tokenizer.FormatMemoriesWithMinimum(memories, 10, 0)
+		assert.Equal(t, strictCount, count)
+		assert.Equal(t, strict, result)
+	})
+
+	t.Run("tiny budget still returns minimum count", func(t *testing.T) {
+		result, count := tokenizer.FormatMemoriesWithMinimum(memories, 10, 2)
+		assert.Equal(t, 2, count)
+		assert.Contains(t, result, "alpha")
+		assert.Contains(t, result, "bravo")
+		assert.NotContains(t, result, "charlie")
+		assert.Less(t, len(result), len(memories[0])+len(memories[1]))
+	})
+
+	t.Run("minimum capped at available memories", func(t *testing.T) {
+		_, count := tokenizer.FormatMemoriesWithMinimum(memories, 10, 10)
+		assert.Equal(t, len(memories), count)
+	})
+
+	t.Run("budget already satisfies minimum", func(t *testing.T) {
+		strict, strictCount := tokenizer.FormatMemoriesWithBudget(memories, 10000)
+		result, count := tokenizer.FormatMemoriesWithMinimum(memories, 10000, 2)
+		assert.Equal(t, strictCount, count)
+		assert.Equal(t, strict, result)
+	})
+}
+
+func TestFormatMemoriesWithLimits(t *testing.T) {
+	memories := []string{"alpha", "bravo", "charlie", "delta"}
+
+	t.Run("cap applies within a large budget", func(t *testing.T) {
+		result, count := tokenizer.FormatMemoriesWithLimits(memories, 10000, 0, 2)
+		assert.Equal(t, 2, count)
+		assert.Contains(t, result, "bravo")
+		assert.NotContains(t, result, "charlie")
+	})
+
+	t.Run("zero cap matches minimum formatter", func(t *testing.T) {
+		want, wantCount := tokenizer.FormatMemoriesWithMinimum(memories, 10000, 0)
+		result, count := tokenizer.FormatMemoriesWithLimits(memories, 10000, 0, 0)
+		assert.Equal(t, wantCount, count)
+		assert.Equal(t, want, result)
+	})
+
+	t.Run("cap wins over minimum", func(t *testing.T) {
+		_, count := tokenizer.FormatMemoriesWithLimits(memories, 1, 3, 1)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestFormatGroupedWithLimits(t *testing.T) {
+	memories := []string{"use tabs", "prod is eu-west-1", "run make lint", "staging is us-east-1"}
+	headings := []string{"Rules:", "Facts:", "Rules:", "Facts:"}
+
+	t.Run("groups in first-appearance order keeping rank within groups", func(t *testing.T) {
+		result, count := tokenizer.FormatGroupedWithLimits(memories, headings, 10000, 0, 0)
+		assert.Equal(t, 4, count)
+		assert.Equal(t, "Rules:\nuse tabs\n---\nrun make lint\n\nFacts:\nprod is eu-west-1\n---\nstaging is us-east-1", result)
+	})
+
+	t.Run("headings count against the budget", func(t *testing.T) {
+		for budget := 1; budget < 40; budget++ {
+			result, count := tokenizer.FormatGroupedWithLimits(memories, headings, budget, 0, 0)
+			assert.LessOrEqual(t, tokenizer.EstimateTokens(result), budget, "budget %d", budget)
+			_, flat := tokenizer.FormatMemoriesWithBudget(memories, budget)
+			assert.LessOrEqual(t, count, flat, "budget %d", budget)
+		}
+	})
+
+	t.Run("cap and minimum", func(t *testing.T) {
+		result, count := tokenizer.FormatGroupedWithLimits(memories, headings, 10000, 0, 1)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "Rules:\nuse tabs", result)
+
+		_, count = tokenizer.FormatGroupedWithLimits(memories, headings, 1, 3, 0)
+		assert.Equal(t, 3, count)
+	})
+}
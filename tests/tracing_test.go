@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ajitpratap0/openclaw-cortex/internal/config"
+	"github.com/ajitpratap0/openclaw-cortex/internal/tracing"
+)
+
+// newOTLPCollector starts a fake OTLP/HTTP collector that records received spans.
+func newOTLPCollector(t *testing.T) (*httptest.Server, func() []*tracepb.Span) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		spans []*tracepb.Span
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		var req coltracepb.ExportTraceServiceRequest
+		if err != nil || proto.Unmarshal(body, &req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		for _, rs := range req.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				spans = append(spans, ss.GetSpans()...)
+			}
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []*tracepb.Span {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*tracepb.Span(nil), spans...)
+	}
+}
+
+func TestTracing_DisabledIsNoop(t *testing.T) {
+	shutdown := tracing.Init(config.TracingConfig{Enabled: false}, slog.Default())
+	defer func() { _ = shutdown(context.Background()) }()
+
+	assert.False(t, tracing.Enabled())
+	ctx, span := tracing.Start(context.Background(), "noop")
+	assert.Nil(t, span)
+	assert.Equal(t, context.Background(), ctx)
+	// Methods on a nil span must not panic.
+	span.SetAttributes("k", 1)
+	span.RecordError(errors.New("boom"))
+	span.End()
+}
+
+func TestTracing_ExportsParentChildSpans(t *testing.T) {
+	srv, received := newOTLPCollector(t)
+	shutdown := tracing.Init(config.TracingConfig{
+		Enabled:     true,
+		Endpoint:    srv.URL,
+		ServiceName: "cortex-test",
+		SampleRate:  1,
+	}, slog.Default())
+	require.True(t, tracing.Enabled())
+
+	ctx, root := tracing.Start(context.Background(), "recall", "recall.budget", 2000)
+	_, child := tracing.Start(ctx, "store.search", "db.system", "memgraph")
+	child.SetAttributes("result.count", 7)
+	child.RecordError(errors.New("timeout"))
+	child.End()
+	root.End()
+	root.End() // second End is ignored
+
+	require.NoError(t, shutdown(context.Background()))
+	assert.False(t, tracing.Enabled())
+
+	spans := received()
+	require.Len(t, spans, 2)
+	byName := map[string]*tracepb.Span{}
+	for _, s := range spans {
+		byName[s.GetName()] = s
+	}
+	rootRec, childRec := byName["recall"], byName["store.search"]
+	require.NotNil(t, rootRec)
+	require.NotNil(t, childRec)
+	assert.Len(t, rootRec.GetTraceId(), 16)
+	assert.Len(t, rootRec.GetSpanId(), 8)
+	assert.Empty(t, rootRec.GetParentSpanId())
+	assert.Equal(t, rootRec.GetTraceId(), childRec.GetTraceId())
+	assert.Equal(t, rootRec.GetSpanId(), childRec.GetParentSpanId())
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, childRec.GetStatus().GetCode())
+	assert.Equal(t, "timeout", childRec.GetStatus().GetMessage())
+
+	attrs := map[string]*commonpb.AnyValue{}
+	for _, a := range childRec.GetAttributes() {
+		attrs[a.GetKey()] = a.GetValue()
+	}
+	assert.Equal(t, "memgraph", attrs["db.system"].GetStringValue())
+	assert.Equal(t, int64(7), attrs["result.count"].GetIntValue())
+}
+
+func TestTracing_APIJoinsTraceparent(t *testing.T) {
+	srv, received := newOTLPCollector(t)
+	shutdown := tracing.Init(config.TracingConfig{Enabled: true, Endpoint: srv.URL, SampleRate: 0}, slog.Default())
+
+	ts, _ := newTestServer(t, "")
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/v1/search", strings.NewReader(`{"message":"deploy"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	// A sampled remote parent overrides the zero root sample rate.
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.NoError(t, shutdown(context.Background()))
+	spans := received()
+	require.NotEmpty(t, spans)
+	var apiSpan *tracepb.Span
+	for _, s := range spans {
+		if s.GetName() == "api.search" {
+			apiSpan = s
+		}
+	}
+	require.NotNil(t, apiSpan)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(apiSpan.GetTraceId()))
+	assert.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(apiSpan.GetParentSpanId()))
+}
+
+func TestTracing_ZeroSampleRateDropsRootSpans(t *testing.T) {
+	srv, received := newOTLPCollector(t)
+	shutdown := tracing.Init(config.TracingConfig{Enabled: true, Endpoint: srv.URL, SampleRate: 0}, slog.Default())
+
+	_, span := tracing.Start(context.Background(), "unsampled")
+	assert.Nil(t, span)
+	span.End()
+
+	require.NoError(t, shutdown(context.Background()))
+	assert.Empty(t, received())
+}
+
+func TestTracing_UnsampledRootDropsChildren(t *testing.T) {
+	srv, received := newOTLPCollector(t)
+	shutdown := tracing.Init(config.TracingConfig{Enabled: true, Endpoint: srv.URL, SampleRate: 0}, slog.Default())
+
+	ctx, root := tracing.Start(context.Background(), "recall")
+	assert.Nil(t, root)
+	childCtx, child := tracing.Start(ctx, "store.search")
+	assert.Nil(t, child)
+	_, grandchild := tracing.Start(childCtx, "memgraph.query")
+	assert.Nil(t, grandchild)
+	grandchild.End()
+	child.End()
+	root.End()
+
+	require.NoError(t, shutdown(context.Background()))
+	assert.Empty(t, received())
+
+	// The unsampled decision travels with the context: a child must not
+	// re-roll sampling even when the current rate would keep it.
+	shutdown = tracing.Init(config.TracingConfig{Enabled: true, Endpoint: srv.URL, SampleRate: 1}, slog.Default())
+	_, late := tracing.Start(ctx, "late.child")
+	assert.Nil(t, late)
+	late.End()
+	require.NoError(t, shutdown(context.Background()))
+	assert.Empty(t, received())
+}
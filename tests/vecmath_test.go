@@ -0,0 +1,28 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajitpratap0/openclaw-cortex/pkg/vecmath"
+)
+
+func TestBlend(t *testing.T) {
+	query := []float32{1, 0, 0}
+	anchor := []float32{0, 10, 0} // larger magnitude must not dominate
+
+	mid := vecmath.Blend(query, anchor, 0.5)
+	require.Len(t, mid, 3)
+	assert.InDelta(t, vecmath.CosineSimilarity(mid, query), vecmath.CosineSimilarity(mid, anchor), 1e-6)
+
+	assert.InDelta(t, 1.0, vecmath.CosineSimilarity(vecmath.Blend(query, anchor, 0), query), 1e-6)
+	assert.InDelta(t, 1.0, vecmath.CosineSimilarity(vecmath.Blend(query, anchor, 1), anchor), 1e-6)
+
+	leaning := vecmath.Blend(query, anchor, 0.8)
+	assert.Greater(t, vecmath.CosineSimilarity(leaning, anchor), vecmath.CosineSimilarity(leaning, query))
+
+	assert.Nil(t, vecmath.Blend(query, []float32{1, 0}, 0.5), "dimension mismatch")
+	assert.Nil(t, vecmath.Blend(query, []float32{0, 0, 0}, 0.5), "zero anchor")
+}